@@ -45,13 +45,19 @@ type SchedulerConfig struct {
 // - a list of placement rule definition objects
 // - a list of users specifying limits on the partition
 // - the preemption configuration for the partition
+// - the starvation detection configuration for the partition
+// - a list of advance reservations for the partition
+// - the node reservation configuration for the partition
 type PartitionConfig struct {
-	Name           string
-	Queues         []QueueConfig
-	PlacementRules []PlacementRule           `yaml:",omitempty" json:",omitempty"`
-	Limits         []Limit                   `yaml:",omitempty" json:",omitempty"`
-	Preemption     PartitionPreemptionConfig `yaml:",omitempty" json:",omitempty"`
-	NodeSortPolicy NodeSortingPolicy         `yaml:",omitempty" json:",omitempty"`
+	Name            string
+	Queues          []QueueConfig
+	PlacementRules  []PlacementRule                `yaml:",omitempty" json:",omitempty"`
+	Limits          []Limit                        `yaml:",omitempty" json:",omitempty"`
+	Preemption      PartitionPreemptionConfig      `yaml:",omitempty" json:",omitempty"`
+	NodeSortPolicy  NodeSortingPolicy              `yaml:",omitempty" json:",omitempty"`
+	Starvation      PartitionStarvationConfig      `yaml:",omitempty" json:",omitempty"`
+	Reservations    []AdvanceReservationConfig     `yaml:",omitempty" json:",omitempty"`
+	NodeReservation PartitionNodeReservationConfig `yaml:",omitempty" json:",omitempty"`
 }
 
 // The partition preemption configuration
@@ -59,6 +65,44 @@ type PartitionPreemptionConfig struct {
 	Enabled *bool `yaml:",omitempty" json:",omitempty"`
 }
 
+// The partition starvation detection configuration:
+// - enabled flag, detection is disabled if not set
+// - threshold: the time the oldest pending ask of an application must have waited to be considered starved
+// - priority boost: the value added to the priority of a starved application until it gets an allocation
+type PartitionStarvationConfig struct {
+	Enabled       *bool  `yaml:",omitempty" json:",omitempty"`
+	Threshold     string `yaml:",omitempty" json:",omitempty"`
+	PriorityBoost *int32 `yaml:",omitempty" json:",omitempty"`
+}
+
+// The partition node reservation configuration:
+// - timeout: the time after which a node reservation for an ask is removed, reservations do not time out if not set
+// - max reservations: the maximum number of node reservations in the partition, not limited if not set
+// - cooldown: the time an ask must wait before it can reserve a node again after its reservation timed out
+type PartitionNodeReservationConfig struct {
+	Timeout         string `yaml:",omitempty" json:",omitempty"`
+	MaxReservations *int   `yaml:",omitempty" json:",omitempty"`
+	Cooldown        string `yaml:",omitempty" json:",omitempty"`
+}
+
+// The advance reservation configuration, capacity booked for a queue during a time window:
+// - name: unique name of the reservation in the partition
+// - queue: the fully qualified queue that may use the booked capacity, including its descendants
+// - nodes: the nodes the capacity is booked on, the capacity is booked on each node. If not set the capacity is
+// booked in the partition.
+// - resources: the booked capacity
+// - start and end: the time window in RFC3339 format
+// - lead: the time before the start from which allocations without a runtime estimate are kept off the capacity
+type AdvanceReservationConfig struct {
+	Name      string
+	Queue     string
+	Nodes     []string          `yaml:",omitempty" json:",omitempty"`
+	Resources map[string]string `yaml:",omitempty" json:",omitempty"`
+	Start     string
+	End       string
+	Lead      string `yaml:",omitempty" json:",omitempty"`
+}
+
 // The queue object for each queue:
 // - the name of the queue
 // - a resources object to specify resource limits on the queue
@@ -67,6 +111,7 @@ type PartitionPreemptionConfig struct {
 // - ACL for submit and or admin access
 // - a list of sub or child queues
 // - a list of users specifying limits on a queue
+// - a list of schedules that change the resources and properties of the queue during a time window
 type QueueConfig struct {
 	Name            string
 	Parent          bool              `yaml:",omitempty" json:",omitempty"`
@@ -78,6 +123,22 @@ type QueueConfig struct {
 	ChildTemplate   ChildTemplate     `yaml:",omitempty" json:",omitempty"`
 	Queues          []QueueConfig     `yaml:",omitempty" json:",omitempty"`
 	Limits          []Limit           `yaml:",omitempty" json:",omitempty"`
+	Schedules       []QueueSchedule   `yaml:",omitempty" json:",omitempty"`
+}
+
+// The queue schedule, a recurring time window during which other resources and properties apply to the queue:
+// - name: unique name of the schedule in the queue
+// - start: cron expression for the start of the window, evaluated in the local time of the scheduler
+// - duration: the length of the window
+// - resources: the guaranteed and max resources during the window, a value that is not set is not changed
+// - properties: the properties during the window, merged with the properties of the queue
+// If windows of multiple schedules overlap the first schedule in the list is applied.
+type QueueSchedule struct {
+	Name       string
+	Start      string
+	Duration   string
+	Resources  Resources         `yaml:",omitempty" json:",omitempty"`
+	Properties map[string]string `yaml:",omitempty" json:",omitempty"`
 }
 
 type ChildTemplate struct {
@@ -143,9 +204,47 @@ type Limit struct {
 
 // Global Node Sorting Policy section
 // - type: different type of policies supported (binpacking, fair etc)
+// - resource weights: weight of each resource type when calculating the node usage
+// - aging: settings for the idle time part of the score (fairwithaging only)
 type NodeSortingPolicy struct {
 	Type            string
-	ResourceWeights map[string]float64 `yaml:",omitempty" json:",omitempty"`
+	ResourceWeights map[string]float64  `yaml:",omitempty" json:",omitempty"`
+	Aging           NodeSortingAging    `yaml:",omitempty" json:",omitempty"`
+	Scorers         []NodeSortingScorer `yaml:",omitempty" json:",omitempty"`
+	Topology        NodeSortingTopology `yaml:",omitempty" json:",omitempty"`
+}
+
+// The scorers combined by the weighted node sorting policy:
+// - name of a built-in node sorting policy or of a node scorer registered in the plugins
+// - weight of the scorer compared to the other scorers
+type NodeSortingScorer struct {
+	Name   string
+	Weight float64
+}
+
+// The topology settings used to place the allocations of an application over the nodes:
+// - node attribute keys that define the topology domains, most important first, i.e. zone and rack
+// - default mode: spread or colocate the allocations of an application, or none to ignore the topology
+// - maximum difference in the number of allocations of an application between two domains when spreading, 0 means no limit
+// Applications can override the mode using a tag.
+type NodeSortingTopology struct {
+	Keys    []string `yaml:",omitempty" json:",omitempty"`
+	Mode    string   `yaml:",omitempty" json:",omitempty"`
+	MaxSkew int32    `yaml:",omitempty" json:",omitempty"`
+}
+
+// The aging settings for the fairwithaging node sorting policy:
+// - weight of the aging factor compared to the node usage share
+// - cap on the weighted aging factor, limits how much a node can gain from being idle
+// - curve used to convert the idle time into an aging factor: linear, logarithmic or exponential
+// - period after which the linear and logarithmic curves reach the maximum factor
+// - half-life of the exponential curve
+type NodeSortingAging struct {
+	Weight   *float64 `yaml:",omitempty" json:",omitempty"`
+	Cap      *float64 `yaml:",omitempty" json:",omitempty"`
+	Curve    string   `yaml:",omitempty" json:",omitempty"`
+	Period   string   `yaml:",omitempty" json:",omitempty"`
+	HalfLife string   `yaml:",omitempty" json:",omitempty"`
 }
 
 func LoadSchedulerConfigFromByteArray(content []byte) (*SchedulerConfig, error) {
@@ -23,6 +23,7 @@ import (
 	"math"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -47,6 +48,38 @@ const (
 	PriorityOffset          = "priority.offset"
 	PreemptionPolicy        = "preemption.policy"
 	PreemptionDelay         = "preemption.delay"
+	QueueAgingPeriod        = "queue.aging.period"
+	ApplicationAgingPeriod  = "application.aging.period"
+
+	// weight of a queue compared to its siblings: the drf weight is used when the parent sorts using drf, the fair
+	// share weight when the spare capacity of the parent is shared. Setting one sets both, if both are set they must be equal.
+	DRFWeight       = "drf.weight"
+	FairShareWeight = "fairshare.weight"
+
+	// preemption disruption budget
+	PreemptionBudgetAllocations = "preemption.budget.allocations"
+	PreemptionBudgetPercentage  = "preemption.budget.percentage"
+	PreemptionBudgetWindow      = "preemption.budget.window"
+
+	// preemption victim cost weights
+	PreemptionCostRuntime     = "preemption.policy.cost.runtime"
+	PreemptionCostPriority    = "preemption.policy.cost.priority"
+	PreemptionCostOriginator  = "preemption.policy.cost.originator"
+	PreemptionCostPlaceholder = "preemption.policy.cost.placeholder"
+
+	// preempt the members of a task group of a gang scheduled application as one unit
+	PreemptionGang = "preemption.policy.gang"
+
+	// maximum number of allocations moved off a node to make room for an ask, 0 disables cross node preemption
+	PreemptionCrossNodeDepth = "preemption.policy.crossnode.depth"
+
+	// time a queue gets to give back the guarantee it borrowed from a sibling before the sibling preempts without delay, 0 disables it
+	PreemptionReclaimDeadline = "preemption.reclaim.deadline"
+
+	// default quota of the applications in the queue, overridden by the application tags
+	ApplicationMaxResource    = "application.max.resource"
+	ApplicationMaxAllocations = "application.max.allocations"
+	ApplicationMaxLifetime    = "application.max.lifetime"
 
 	// app sort priority values
 	ApplicationSortPriorityEnabled  = "enabled"
@@ -67,6 +100,37 @@ var MaxPriority int32 = math.MaxInt32
 
 var DefaultPreemptionDelay = 30 * time.Second
 
+// DefaultApplicationAgingPeriod is the time an application waits for the full aging credit if not set
+var DefaultApplicationAgingPeriod = 5 * time.Minute
+
+// DefaultPreemptionBudgetWindow is the rolling window of a preemption budget if not set
+var DefaultPreemptionBudgetWindow = 10 * time.Minute
+
+// Preemption victim cost weight defaults: an originator costs about as much as a victim running for a week
+var DefaultPreemptionCostRuntime = 1.0 // per full minute running
+var DefaultPreemptionCostPriority = 60.0
+var DefaultPreemptionCostOriginator = 10000.0
+var DefaultPreemptionCostPlaceholder = 0.0
+
+// Node sorting aging defaults
+var DefaultNodeAgingWeight = 0.3
+var DefaultNodeAgingCap = 1.0
+var DefaultNodeAgingPeriod = 5 * time.Minute
+var DefaultNodeAgingHalfLife = time.Minute
+
+// Starvation detection defaults
+var DefaultStarvationThreshold = 5 * time.Minute
+var DefaultStarvationPriorityBoost int32 = 100
+
+// Advance reservation defaults
+var DefaultAdvanceReservationLead = time.Hour
+
+// Node reservation defaults
+var DefaultNodeReservationCooldown = time.Minute
+
+// MaxQueueScheduleDuration is the longest window a queue schedule can have
+const MaxQueueScheduleDuration = 7 * 24 * time.Hour
+
 // A queue can be a username with the dot replaced. Most systems allow a 32 character user name.
 // The queue name must thus allow for at least that length with the replacement of dots.
 var QueueNameRegExp = regexp.MustCompile(`^[a-zA-Z0-9_:#/@-]{1,64}$`)
@@ -627,9 +691,280 @@ func checkNodeSortingPolicy(partition *PartitionConfig) error {
 		}
 	}
 
+	if err = checkNodeSortingScorers(policy); err != nil {
+		return err
+	}
+	if err = checkNodeSortingTopology(policy.Topology); err != nil {
+		return err
+	}
+	return checkNodeSortingAging(policy.Aging)
+}
+
+// Check the topology settings of the node sorting policy, all settings are optional.
+// The keys must be unique and not empty, a mode other than none requires at least one key.
+func checkNodeSortingTopology(topology NodeSortingTopology) error {
+	mode, err := policies.TopologyModeFromString(topology.Mode)
+	if err != nil {
+		return err
+	}
+	if topology.MaxSkew < 0 {
+		return fmt.Errorf("node sorting topology max skew must not be negative: %d", topology.MaxSkew)
+	}
+	keys := make(map[string]bool, len(topology.Keys))
+	for _, key := range topology.Keys {
+		if key == "" {
+			return fmt.Errorf("node sorting topology key must not be empty")
+		}
+		if keys[key] {
+			return fmt.Errorf("duplicate node sorting topology key: '%s'", key)
+		}
+		keys[key] = true
+	}
+	if topology.Mode != "" && mode != policies.NoTopologyMode && len(keys) == 0 {
+		return fmt.Errorf("node sorting topology mode '%s' requires at least one key", mode)
+	}
+	return nil
+}
+
+// Check the scorers of the node sorting policy: scorers must be set for, and only for, the weighted policy.
+// Each scorer must be a known, not weighted, policy with a positive weight and can only be used once.
+func checkNodeSortingScorers(policy NodeSortingPolicy) error {
+	if policy.Type != policies.WeightedNodePolicy.String() {
+		if len(policy.Scorers) != 0 {
+			return fmt.Errorf("node sorting scorers are only supported for the '%s' policy", policies.WeightedNodePolicy)
+		}
+		return nil
+	}
+	if len(policy.Scorers) == 0 {
+		return fmt.Errorf("node sorting policy '%s' requires at least one scorer", policies.WeightedNodePolicy)
+	}
+	names := make(map[string]bool, len(policy.Scorers))
+	for _, scorer := range policy.Scorers {
+		pType, err := policies.SortingPolicyFromString(scorer.Name)
+		if err != nil || scorer.Name == "" {
+			return fmt.Errorf("undefined node sorting scorer: '%s'", scorer.Name)
+		}
+		if pType == policies.WeightedNodePolicy {
+			return fmt.Errorf("node sorting scorer cannot be '%s'", policies.WeightedNodePolicy)
+		}
+		if names[scorer.Name] {
+			return fmt.Errorf("duplicate node sorting scorer: '%s'", scorer.Name)
+		}
+		names[scorer.Name] = true
+		if scorer.Weight <= 0 || math.IsNaN(scorer.Weight) || math.IsInf(scorer.Weight, 0) {
+			return fmt.Errorf("node sorting scorer weight must be positive: %s %f", scorer.Name, scorer.Weight)
+		}
+	}
+	return nil
+}
+
+// Check the aging settings of the node sorting policy, all settings are optional.
+func checkNodeSortingAging(aging NodeSortingAging) error {
+	if aging.Weight != nil && (*aging.Weight < 0 || math.IsNaN(*aging.Weight)) {
+		return fmt.Errorf("node sorting aging weight must not be negative: %f", *aging.Weight)
+	}
+	if aging.Cap != nil && (*aging.Cap < 0 || math.IsNaN(*aging.Cap)) {
+		return fmt.Errorf("node sorting aging cap must not be negative: %f", *aging.Cap)
+	}
+	if _, err := policies.AgingCurveFromString(aging.Curve); err != nil {
+		return err
+	}
+	if _, err := agingDuration(aging.Period, "period"); err != nil {
+		return err
+	}
+	if _, err := agingDuration(aging.HalfLife, "half-life"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetNodeSortingAgingPeriod returns the configured aging period, or the default if not set or invalid.
+func GetNodeSortingAgingPeriod(aging NodeSortingAging) time.Duration {
+	if period, err := agingDuration(aging.Period, "period"); err == nil && period > 0 {
+		return period
+	}
+	return DefaultNodeAgingPeriod
+}
+
+// GetNodeSortingAgingHalfLife returns the configured aging half-life, or the default if not set or invalid.
+func GetNodeSortingAgingHalfLife(aging NodeSortingAging) time.Duration {
+	if halfLife, err := agingDuration(aging.HalfLife, "half-life"); err == nil && halfLife > 0 {
+		return halfLife
+	}
+	return DefaultNodeAgingHalfLife
+}
+
+// agingDuration parses an aging duration, an empty value returns 0 without an error.
+func agingDuration(value, name string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid node sorting aging %s: %w", name, err)
+	}
+	if result <= 0 {
+		return 0, fmt.Errorf("node sorting aging %s must be positive: %s", name, value)
+	}
+	return result, nil
+}
+
+// Check the starvation detection settings of the partition, all settings are optional.
+func checkStarvation(partition *PartitionConfig) error {
+	starvation := partition.Starvation
+	if starvation.Threshold != "" {
+		threshold, err := time.ParseDuration(starvation.Threshold)
+		if err != nil {
+			return fmt.Errorf("invalid starvation threshold: %w", err)
+		}
+		if threshold <= 0 {
+			return fmt.Errorf("starvation threshold must be positive: %s", starvation.Threshold)
+		}
+	}
+	if starvation.PriorityBoost != nil && *starvation.PriorityBoost <= 0 {
+		return fmt.Errorf("starvation priority boost must be positive: %d", *starvation.PriorityBoost)
+	}
+	return nil
+}
+
+// GetStarvationThreshold returns the configured starvation threshold, or the default if not set or invalid.
+func GetStarvationThreshold(starvation PartitionStarvationConfig) time.Duration {
+	if threshold, err := time.ParseDuration(starvation.Threshold); err == nil && threshold > 0 {
+		return threshold
+	}
+	return DefaultStarvationThreshold
+}
+
+// GetStarvationPriorityBoost returns the configured starvation priority boost, or the default if not set or invalid.
+func GetStarvationPriorityBoost(starvation PartitionStarvationConfig) int32 {
+	if starvation.PriorityBoost != nil && *starvation.PriorityBoost > 0 {
+		return *starvation.PriorityBoost
+	}
+	return DefaultStarvationPriorityBoost
+}
+
+// Check the node reservation settings of the partition, all settings are optional.
+func checkNodeReservation(partition *PartitionConfig) error {
+	reservation := partition.NodeReservation
+	for _, setting := range []struct {
+		name  string
+		value string
+	}{{"timeout", reservation.Timeout}, {"cooldown", reservation.Cooldown}} {
+		if setting.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(setting.value)
+		if err != nil {
+			return fmt.Errorf("invalid node reservation %s: %w", setting.name, err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("node reservation %s must be positive: %s", setting.name, setting.value)
+		}
+	}
+	if reservation.MaxReservations != nil && *reservation.MaxReservations <= 0 {
+		return fmt.Errorf("node reservation max reservations must be positive: %d", *reservation.MaxReservations)
+	}
+	return nil
+}
+
+// GetNodeReservationTimeout returns the configured node reservation timeout, or 0 if not set or invalid.
+func GetNodeReservationTimeout(reservation PartitionNodeReservationConfig) time.Duration {
+	if timeout, err := time.ParseDuration(reservation.Timeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return 0
+}
+
+// GetNodeReservationCooldown returns the configured node reservation cooldown, or the default if not set or invalid.
+func GetNodeReservationCooldown(reservation PartitionNodeReservationConfig) time.Duration {
+	if cooldown, err := time.ParseDuration(reservation.Cooldown); err == nil && cooldown > 0 {
+		return cooldown
+	}
+	return DefaultNodeReservationCooldown
+}
+
+// GetNodeReservationMax returns the configured maximum number of node reservations, or 0 if not set or invalid.
+func GetNodeReservationMax(reservation PartitionNodeReservationConfig) int {
+	if reservation.MaxReservations != nil && *reservation.MaxReservations > 0 {
+		return *reservation.MaxReservations
+	}
+	return 0
+}
+
+// Check the advance reservations of the partition: each reservation must be valid and have a unique name.
+func checkAdvanceReservations(partition *PartitionConfig) error {
+	names := make(map[string]bool)
+	for _, reservation := range partition.Reservations {
+		if err := CheckAdvanceReservation(reservation); err != nil {
+			return err
+		}
+		if names[reservation.Name] {
+			return fmt.Errorf("duplicate advance reservation name found: %s", reservation.Name)
+		}
+		names[reservation.Name] = true
+	}
 	return nil
 }
 
+// CheckAdvanceReservation checks the settings of an advance reservation. The name, the fully qualified queue, the
+// resources and the time window are required, the lead time is optional.
+func CheckAdvanceReservation(reservation AdvanceReservationConfig) error {
+	if reservation.Name == "" {
+		return fmt.Errorf("advance reservation name must be set")
+	}
+	if reservation.Queue != RootQueue && !strings.HasPrefix(reservation.Queue, RootQueue+DOT) {
+		return fmt.Errorf("advance reservation %s queue must be fully qualified: '%s'", reservation.Name, reservation.Queue)
+	}
+	res, err := resources.NewResourceFromConf(reservation.Resources)
+	if err != nil {
+		return fmt.Errorf("invalid advance reservation %s resources: %w", reservation.Name, err)
+	}
+	if !resources.StrictlyGreaterThanZero(res) {
+		return fmt.Errorf("advance reservation %s resources must be set", reservation.Name)
+	}
+	nodes := make(map[string]bool, len(reservation.Nodes))
+	for _, nodeID := range reservation.Nodes {
+		if strings.TrimSpace(nodeID) == "" {
+			return fmt.Errorf("advance reservation %s node must not be empty", reservation.Name)
+		}
+		if nodes[nodeID] {
+			return fmt.Errorf("advance reservation %s has duplicate node: %s", reservation.Name, nodeID)
+		}
+		nodes[nodeID] = true
+	}
+	if _, _, _, err = GetAdvanceReservationWindow(reservation); err != nil {
+		return fmt.Errorf("invalid advance reservation %s: %w", reservation.Name, err)
+	}
+	return nil
+}
+
+// GetAdvanceReservationWindow returns the start, end and lead time of the advance reservation. The lead time is set
+// to the default if not configured.
+func GetAdvanceReservationWindow(reservation AdvanceReservationConfig) (time.Time, time.Time, time.Duration, error) {
+	start, err := time.Parse(time.RFC3339, reservation.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("start: %w", err)
+	}
+	end, err := time.Parse(time.RFC3339, reservation.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("end: %w", err)
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("end must be after start: %s", reservation.End)
+	}
+	lead := DefaultAdvanceReservationLead
+	if reservation.Lead != "" {
+		lead, err = time.ParseDuration(reservation.Lead)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("lead: %w", err)
+		}
+		if lead < 0 {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("lead must not be negative: %s", reservation.Lead)
+		}
+	}
+	return start, end, lead, nil
+}
+
 // Check the queue names configured for compliance and uniqueness
 // - no duplicate names at each branched level in the tree
 // - queue name is alphanumeric (case ignore) with - and _
@@ -651,6 +986,112 @@ func checkQueues(queue *QueueConfig, level int) error {
 		return err
 	}
 
+	// check the application sort policy for this queue and its child template (if defined)
+	err = checkApplicationSortPolicy(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkApplicationSortPolicy(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the preemption budget for this queue and its child template (if defined)
+	err = checkPreemptionBudget(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkPreemptionBudget(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the preemption cost weights for this queue and its child template (if defined)
+	err = checkPreemptionCost(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkPreemptionCost(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the gang preemption flag for this queue and its child template (if defined)
+	err = checkPreemptionGang(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkPreemptionGang(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the cross node preemption depth for this queue and its child template (if defined)
+	err = checkPreemptionCrossNode(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkPreemptionCrossNode(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the reclaim deadline for this queue and its child template (if defined)
+	err = checkPreemptionReclaim(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkPreemptionReclaim(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the aging period for this queue and its child template (if defined)
+	err = checkQueueAgingPeriod(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkQueueAgingPeriod(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the weights for this queue and its child template (if defined)
+	err = checkQueueWeight(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkQueueWeight(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the application aging period for this queue and its child template (if defined)
+	err = checkApplicationAgingPeriod(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkApplicationAgingPeriod(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the application quota for this queue and its child template (if defined)
+	err = checkApplicationQuota(queue.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+	err = checkApplicationQuota(queue.ChildTemplate.Properties, queue.Name)
+	if err != nil {
+		return err
+	}
+
+	// check the schedules for this queue (if defined)
+	err = checkQueueSchedules(queue)
+	if err != nil {
+		return err
+	}
+
 	// check this level for name compliance and uniqueness
 	queueMap := make(map[string]bool)
 	for _, child := range queue.Queues {
@@ -675,6 +1116,206 @@ func checkQueues(queue *QueueConfig, level int) error {
 	return nil
 }
 
+// Check the application sort policy property if set. An unknown sort policy is not rejected to keep existing
+// configurations valid, a warning is logged and the queue falls back to the default fifo policy.
+func checkApplicationSortPolicy(properties map[string]string, queueName string) error {
+	value, ok := properties[ApplicationSortPolicy]
+	if !ok {
+		return nil
+	}
+	if _, err := policies.SortPolicyFromString(value); err != nil {
+		log.Log(log.Config).Warn("unknown application sort policy, falling back to fifo",
+			zap.String("queue", queueName),
+			zap.String("policy", value))
+	}
+	return nil
+}
+
+// Check the preemption budget properties if set:
+// - the number of allocations must be a non negative integer
+// - the percentage must be larger than 0 and at most 100
+// - the window must be a positive duration
+func checkPreemptionBudget(properties map[string]string, queueName string) error {
+	if value, ok := properties[PreemptionBudgetAllocations]; ok {
+		if count, err := strconv.Atoi(value); err != nil || count < 0 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", PreemptionBudgetAllocations, value, queueName)
+		}
+	}
+	if value, ok := properties[PreemptionBudgetPercentage]; ok {
+		if percentage, err := strconv.ParseFloat(value, 64); err != nil || percentage <= 0 || percentage > 100 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", PreemptionBudgetPercentage, value, queueName)
+		}
+	}
+	if value, ok := properties[PreemptionBudgetWindow]; ok {
+		if window, err := time.ParseDuration(value); err != nil || window <= 0 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", PreemptionBudgetWindow, value, queueName)
+		}
+	}
+	return nil
+}
+
+// Check the preemption cost weight properties if set: each weight must be a non negative number.
+func checkPreemptionCost(properties map[string]string, queueName string) error {
+	for _, key := range []string{PreemptionCostRuntime, PreemptionCostPriority, PreemptionCostOriginator, PreemptionCostPlaceholder} {
+		value, ok := properties[key]
+		if !ok {
+			continue
+		}
+		if weight, err := strconv.ParseFloat(value, 64); err != nil || weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return fmt.Errorf("invalid %s '%s' for queue %s", key, value, queueName)
+		}
+	}
+	return nil
+}
+
+// Check the gang preemption property if set: the value must be a boolean.
+func checkPreemptionGang(properties map[string]string, queueName string) error {
+	if value, ok := properties[PreemptionGang]; ok {
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid %s '%s' for queue %s", PreemptionGang, value, queueName)
+		}
+	}
+	return nil
+}
+
+// Check the schedules of the queue: names must be unique, the window and the resources must be valid and the
+// properties of each schedule are checked as the queue properties.
+func checkQueueSchedules(queue *QueueConfig) error {
+	names := make(map[string]bool)
+	for _, schedule := range queue.Schedules {
+		if schedule.Name == "" {
+			return fmt.Errorf("schedule name must be set for queue %s", queue.Name)
+		}
+		if names[schedule.Name] {
+			return fmt.Errorf("duplicate schedule name '%s' for queue %s", schedule.Name, queue.Name)
+		}
+		names[schedule.Name] = true
+		if _, _, err := GetQueueScheduleWindow(schedule); err != nil {
+			return fmt.Errorf("invalid schedule '%s' for queue %s: %w", schedule.Name, queue.Name, err)
+		}
+		if _, _, err := checkResourceConfig(QueueConfig{Name: queue.Name, Resources: schedule.Resources}); err != nil {
+			return fmt.Errorf("invalid schedule '%s' resources for queue %s: %w", schedule.Name, queue.Name, err)
+		}
+		for _, check := range []func(map[string]string, string) error{
+			checkApplicationSortPolicy,
+			checkPreemptionBudget,
+			checkPreemptionCost,
+			checkPreemptionGang,
+			checkPreemptionCrossNode,
+			checkPreemptionReclaim,
+			checkQueueAgingPeriod,
+			checkQueueWeight,
+			checkApplicationAgingPeriod,
+			checkApplicationQuota,
+		} {
+			if err := check(schedule.Properties, queue.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetQueueScheduleWindow returns the parsed start and the duration of the schedule window.
+func GetQueueScheduleWindow(schedule QueueSchedule) (*CronSchedule, time.Duration, error) {
+	start, err := ParseCron(schedule.Start)
+	if err != nil {
+		return nil, 0, err
+	}
+	duration, err := time.ParseDuration(schedule.Duration)
+	if err != nil {
+		return nil, 0, fmt.Errorf("duration: %w", err)
+	}
+	if duration < time.Minute || duration > MaxQueueScheduleDuration {
+		return nil, 0, fmt.Errorf("duration must be between %s and %s: %s", time.Minute, MaxQueueScheduleDuration, schedule.Duration)
+	}
+	return start, duration, nil
+}
+
+// Check the cross node preemption depth property if set: the value must be a non negative integer.
+func checkPreemptionCrossNode(properties map[string]string, queueName string) error {
+	if value, ok := properties[PreemptionCrossNodeDepth]; ok {
+		if depth, err := strconv.Atoi(value); err != nil || depth < 0 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", PreemptionCrossNodeDepth, value, queueName)
+		}
+	}
+	return nil
+}
+
+// Check the reclaim deadline property if set: the value must be a duration that is not negative.
+func checkPreemptionReclaim(properties map[string]string, queueName string) error {
+	if value, ok := properties[PreemptionReclaimDeadline]; ok {
+		if deadline, err := time.ParseDuration(value); err != nil || deadline < 0 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", PreemptionReclaimDeadline, value, queueName)
+		}
+	}
+	return nil
+}
+
+// Check the queue aging period property if set: the value must be a positive duration.
+func checkQueueAgingPeriod(properties map[string]string, queueName string) error {
+	if value, ok := properties[QueueAgingPeriod]; ok {
+		if period, err := time.ParseDuration(value); err != nil || period <= 0 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", QueueAgingPeriod, value, queueName)
+		}
+	}
+	return nil
+}
+
+// Check the queue weight properties if set: each weight must be a positive number. The drf and fair share
+// weight are the same weight of the queue, if both are set they must be equal.
+func checkQueueWeight(properties map[string]string, queueName string) error {
+	weights := make(map[string]float64, 2)
+	for _, key := range []string{DRFWeight, FairShareWeight} {
+		value, ok := properties[key]
+		if !ok {
+			continue
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight <= 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return fmt.Errorf("invalid %s '%s' for queue %s", key, value, queueName)
+		}
+		weights[key] = weight
+	}
+	if len(weights) == 2 && weights[DRFWeight] != weights[FairShareWeight] {
+		return fmt.Errorf("%s and %s must be equal for queue %s", DRFWeight, FairShareWeight, queueName)
+	}
+	return nil
+}
+
+// Check the application aging period property if set: the value must be a positive duration.
+func checkApplicationAgingPeriod(properties map[string]string, queueName string) error {
+	if value, ok := properties[ApplicationAgingPeriod]; ok {
+		if period, err := time.ParseDuration(value); err != nil || period <= 0 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", ApplicationAgingPeriod, value, queueName)
+		}
+	}
+	return nil
+}
+
+// Check the application quota properties if set:
+// - the maximum resource must be a JSON resource with all quantities larger than 0
+// - the maximum number of allocations must be a positive integer
+// - the maximum lifetime must be a positive duration
+func checkApplicationQuota(properties map[string]string, queueName string) error {
+	if value, ok := properties[ApplicationMaxResource]; ok {
+		if res, err := resources.NewResourceFromString(value); err != nil || !resources.StrictlyGreaterThanZero(res) {
+			return fmt.Errorf("invalid %s '%s' for queue %s", ApplicationMaxResource, value, queueName)
+		}
+	}
+	if value, ok := properties[ApplicationMaxAllocations]; ok {
+		if count, err := strconv.ParseUint(value, 10, 64); err != nil || count == 0 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", ApplicationMaxAllocations, value, queueName)
+		}
+	}
+	if value, ok := properties[ApplicationMaxLifetime]; ok {
+		if lifetime, err := time.ParseDuration(value); err != nil || lifetime <= 0 {
+			return fmt.Errorf("invalid %s '%s' for queue %s", ApplicationMaxLifetime, value, queueName)
+		}
+	}
+	return nil
+}
+
 func IsQueueNameValid(queueName string) error {
 	if !QueueNameRegExp.MatchString(queueName) {
 		return common.InvalidQueueName
@@ -783,6 +1424,18 @@ func Validate(newConfig *SchedulerConfig) error {
 		if err != nil {
 			return err
 		}
+		err = checkStarvation(&partition)
+		if err != nil {
+			return err
+		}
+		err = checkAdvanceReservations(&partition)
+		if err != nil {
+			return err
+		}
+		err = checkNodeReservation(&partition)
+		if err != nil {
+			return err
+		}
 
 		err = checkQueueMaxApplications(partition.Queues[0])
 		if err != nil {
@@ -22,11 +22,13 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/plugins"
 )
 
 //nolint:funlen
@@ -2346,6 +2348,8 @@ func TestCheckQueues(t *testing.T) { //nolint:funlen
 }
 
 func TestCheckNodeSortingPolicy(t *testing.T) { //nolint:funlen
+	positive := 0.5
+	negative := -0.5
 	testCases := []struct {
 		name             string
 		partition        *PartitionConfig
@@ -2398,6 +2402,70 @@ func TestCheckNodeSortingPolicy(t *testing.T) { //nolint:funlen
 				assert.Equal(t, 2, len(p.NodeSortPolicy.ResourceWeights), "Expected two resource weights")
 			},
 		},
+		{
+			name: "Valid Aging Settings",
+			partition: &PartitionConfig{
+				NodeSortPolicy: NodeSortingPolicy{
+					Type: "fairwithaging",
+					Aging: NodeSortingAging{
+						Weight:   &positive,
+						Cap:      &positive,
+						Curve:    "exponential",
+						HalfLife: "90s",
+					},
+				},
+			},
+		},
+		{
+			name: "Negative Aging Weight",
+			partition: &PartitionConfig{
+				NodeSortPolicy: NodeSortingPolicy{
+					Type:  "fairwithaging",
+					Aging: NodeSortingAging{Weight: &negative},
+				},
+			},
+			expectedErrorMsg: "node sorting aging weight must not be negative",
+		},
+		{
+			name: "Negative Aging Cap",
+			partition: &PartitionConfig{
+				NodeSortPolicy: NodeSortingPolicy{
+					Type:  "fairwithaging",
+					Aging: NodeSortingAging{Cap: &negative},
+				},
+			},
+			expectedErrorMsg: "node sorting aging cap must not be negative",
+		},
+		{
+			name: "Undefined Aging Curve",
+			partition: &PartitionConfig{
+				NodeSortPolicy: NodeSortingPolicy{
+					Type:  "fairwithaging",
+					Aging: NodeSortingAging{Curve: "quadratic"},
+				},
+			},
+			expectedErrorMsg: "undefined aging curve: quadratic",
+		},
+		{
+			name: "Invalid Aging Period",
+			partition: &PartitionConfig{
+				NodeSortPolicy: NodeSortingPolicy{
+					Type:  "fairwithaging",
+					Aging: NodeSortingAging{Period: "five minutes"},
+				},
+			},
+			expectedErrorMsg: "invalid node sorting aging period",
+		},
+		{
+			name: "Zero Aging Half-life",
+			partition: &PartitionConfig{
+				NodeSortPolicy: NodeSortingPolicy{
+					Type:  "fairwithaging",
+					Aging: NodeSortingAging{HalfLife: "0s"},
+				},
+			},
+			expectedErrorMsg: "node sorting aging half-life must be positive",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2416,6 +2484,481 @@ func TestCheckNodeSortingPolicy(t *testing.T) { //nolint:funlen
 	}
 }
 
+func TestCheckNodeSortingScorers(t *testing.T) {
+	err := plugins.RegisterNodeScorer("test-scorer", func(_ map[string]float64) plugins.NodeScorer { return nil })
+	assert.NilError(t, err, "node scorer registration failed")
+	defer plugins.UnregisterNodeScorer("test-scorer")
+
+	testCases := []struct {
+		name             string
+		policy           NodeSortingPolicy
+		expectedErrorMsg string
+	}{
+		{"registered type", NodeSortingPolicy{Type: "test-scorer"}, ""},
+		{"unregistered type", NodeSortingPolicy{Type: "other-scorer"}, "undefined policy: other-scorer"},
+		{"weighted", NodeSortingPolicy{Type: "weighted", Scorers: []NodeSortingScorer{{Name: "binpacking", Weight: 0.7}, {Name: "fairwithaging", Weight: 0.3}}}, ""},
+		{"weighted plugin", NodeSortingPolicy{Type: "weighted", Scorers: []NodeSortingScorer{{Name: "fair", Weight: 1}, {Name: "test-scorer", Weight: 2}}}, ""},
+		{"weighted no scorers", NodeSortingPolicy{Type: "weighted"}, "node sorting policy 'weighted' requires at least one scorer"},
+		{"scorers without weighted", NodeSortingPolicy{Type: "fair", Scorers: []NodeSortingScorer{{Name: "fair", Weight: 1}}}, "node sorting scorers are only supported for the 'weighted' policy"},
+		{"unknown scorer", NodeSortingPolicy{Type: "weighted", Scorers: []NodeSortingScorer{{Name: "other-scorer", Weight: 1}}}, "undefined node sorting scorer: 'other-scorer'"},
+		{"empty scorer", NodeSortingPolicy{Type: "weighted", Scorers: []NodeSortingScorer{{Weight: 1}}}, "undefined node sorting scorer: ''"},
+		{"nested weighted", NodeSortingPolicy{Type: "weighted", Scorers: []NodeSortingScorer{{Name: "weighted", Weight: 1}}}, "node sorting scorer cannot be 'weighted'"},
+		{"duplicate scorer", NodeSortingPolicy{Type: "weighted", Scorers: []NodeSortingScorer{{Name: "fair", Weight: 1}, {Name: "fair", Weight: 2}}}, "duplicate node sorting scorer: 'fair'"},
+		{"zero weight", NodeSortingPolicy{Type: "weighted", Scorers: []NodeSortingScorer{{Name: "fair", Weight: 0}}}, "node sorting scorer weight must be positive"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkNodeSortingPolicy(&PartitionConfig{NodeSortPolicy: tc.policy})
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+		})
+	}
+}
+
+func TestCheckNodeSortingTopology(t *testing.T) {
+	testCases := []struct {
+		name             string
+		topology         NodeSortingTopology
+		expectedErrorMsg string
+	}{
+		{"not set", NodeSortingTopology{}, ""},
+		{"keys only", NodeSortingTopology{Keys: []string{"zone", "rack"}}, ""},
+		{"colocate", NodeSortingTopology{Keys: []string{"rack"}, Mode: "colocate"}, ""},
+		{"spread with skew", NodeSortingTopology{Keys: []string{"zone"}, Mode: "spread", MaxSkew: 1}, ""},
+		{"none without keys", NodeSortingTopology{Mode: "none"}, ""},
+		{"unknown mode", NodeSortingTopology{Keys: []string{"zone"}, Mode: "pack"}, "undefined topology mode: pack"},
+		{"negative skew", NodeSortingTopology{Keys: []string{"zone"}, MaxSkew: -1}, "node sorting topology max skew must not be negative"},
+		{"empty key", NodeSortingTopology{Keys: []string{""}}, "node sorting topology key must not be empty"},
+		{"duplicate key", NodeSortingTopology{Keys: []string{"zone", "zone"}}, "duplicate node sorting topology key: 'zone'"},
+		{"mode without keys", NodeSortingTopology{Mode: "spread"}, "node sorting topology mode 'spread' requires at least one key"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkNodeSortingPolicy(&PartitionConfig{NodeSortPolicy: NodeSortingPolicy{Topology: tc.topology}})
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+		})
+	}
+}
+
+func TestCheckStarvation(t *testing.T) {
+	positive := int32(50)
+	zero := int32(0)
+	testCases := []struct {
+		name             string
+		starvation       PartitionStarvationConfig
+		expectedErrorMsg string
+		threshold        time.Duration
+		boost            int32
+	}{
+		{"defaults", PartitionStarvationConfig{}, "", DefaultStarvationThreshold, DefaultStarvationPriorityBoost},
+		{"valid settings", PartitionStarvationConfig{Threshold: "90s", PriorityBoost: &positive}, "", 90 * time.Second, positive},
+		{"invalid threshold", PartitionStarvationConfig{Threshold: "ten minutes"}, "invalid starvation threshold", DefaultStarvationThreshold, DefaultStarvationPriorityBoost},
+		{"negative threshold", PartitionStarvationConfig{Threshold: "-1m"}, "starvation threshold must be positive", DefaultStarvationThreshold, DefaultStarvationPriorityBoost},
+		{"zero boost", PartitionStarvationConfig{PriorityBoost: &zero}, "starvation priority boost must be positive", DefaultStarvationThreshold, DefaultStarvationPriorityBoost},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkStarvation(&PartitionConfig{Starvation: tc.starvation})
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+			assert.Equal(t, GetStarvationThreshold(tc.starvation), tc.threshold, "unexpected threshold")
+			assert.Equal(t, GetStarvationPriorityBoost(tc.starvation), tc.boost, "unexpected priority boost")
+		})
+	}
+}
+
+func TestCheckNodeReservation(t *testing.T) {
+	positive := 10
+	zero := 0
+	testCases := []struct {
+		name             string
+		reservation      PartitionNodeReservationConfig
+		expectedErrorMsg string
+		timeout          time.Duration
+		cooldown         time.Duration
+		maxReservations  int
+	}{
+		{"defaults", PartitionNodeReservationConfig{}, "", 0, DefaultNodeReservationCooldown, 0},
+		{"valid settings", PartitionNodeReservationConfig{Timeout: "5m", Cooldown: "30s", MaxReservations: &positive}, "", 5 * time.Minute, 30 * time.Second, positive},
+		{"invalid timeout", PartitionNodeReservationConfig{Timeout: "five minutes"}, "invalid node reservation timeout", 0, DefaultNodeReservationCooldown, 0},
+		{"negative timeout", PartitionNodeReservationConfig{Timeout: "-1m"}, "node reservation timeout must be positive", 0, DefaultNodeReservationCooldown, 0},
+		{"invalid cooldown", PartitionNodeReservationConfig{Cooldown: "soon"}, "invalid node reservation cooldown", 0, DefaultNodeReservationCooldown, 0},
+		{"zero cooldown", PartitionNodeReservationConfig{Cooldown: "0s"}, "node reservation cooldown must be positive", 0, DefaultNodeReservationCooldown, 0},
+		{"zero max reservations", PartitionNodeReservationConfig{MaxReservations: &zero}, "node reservation max reservations must be positive", 0, DefaultNodeReservationCooldown, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkNodeReservation(&PartitionConfig{NodeReservation: tc.reservation})
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+			assert.Equal(t, GetNodeReservationTimeout(tc.reservation), tc.timeout, "unexpected timeout")
+			assert.Equal(t, GetNodeReservationCooldown(tc.reservation), tc.cooldown, "unexpected cooldown")
+			assert.Equal(t, GetNodeReservationMax(tc.reservation), tc.maxReservations, "unexpected max reservations")
+		})
+	}
+}
+
+func TestCheckQueueSchedules(t *testing.T) {
+	valid := QueueSchedule{
+		Name:       "nightly",
+		Start:      "0 22 * * *",
+		Duration:   "8h",
+		Resources:  Resources{Guaranteed: map[string]string{"vcore": "80"}, Max: map[string]string{"vcore": "100"}},
+		Properties: map[string]string{PreemptionCrossNodeDepth: "1"},
+	}
+	update := func(change func(*QueueSchedule)) QueueSchedule {
+		schedule := valid
+		change(&schedule)
+		return schedule
+	}
+	testCases := []struct {
+		name             string
+		schedules        []QueueSchedule
+		expectedErrorMsg string
+	}{
+		{"no schedules", nil, ""},
+		{"valid schedules", []QueueSchedule{valid, update(func(s *QueueSchedule) { s.Name = "weekend" })}, ""},
+		{"missing name", []QueueSchedule{update(func(s *QueueSchedule) { s.Name = "" })}, "schedule name must be set"},
+		{"duplicate name", []QueueSchedule{valid, valid}, "duplicate schedule name 'nightly'"},
+		{"invalid start", []QueueSchedule{update(func(s *QueueSchedule) { s.Start = "22:00" })}, "cron expression must have 5 fields"},
+		{"invalid duration", []QueueSchedule{update(func(s *QueueSchedule) { s.Duration = "all night" })}, "invalid schedule 'nightly' for queue batch: duration"},
+		{"short duration", []QueueSchedule{update(func(s *QueueSchedule) { s.Duration = "30s" })}, "duration must be between"},
+		{"long duration", []QueueSchedule{update(func(s *QueueSchedule) { s.Duration = "169h" })}, "duration must be between"},
+		{"invalid resources", []QueueSchedule{update(func(s *QueueSchedule) { s.Resources.Max = map[string]string{"vcore": "x"} })}, "invalid schedule 'nightly' resources"},
+		{"guaranteed over max", []QueueSchedule{update(func(s *QueueSchedule) { s.Resources.Guaranteed = map[string]string{"vcore": "200"} })}, "guaranteed resource"},
+		{"invalid property", []QueueSchedule{update(func(s *QueueSchedule) { s.Properties = map[string]string{PreemptionCrossNodeDepth: "-1"} })}, "invalid " + PreemptionCrossNodeDepth},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkQueueSchedules(&QueueConfig{Name: "batch", Schedules: tc.schedules})
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+		})
+	}
+}
+
+func TestCheckAdvanceReservations(t *testing.T) {
+	valid := AdvanceReservationConfig{
+		Name:      "training",
+		Queue:     "root.training",
+		Resources: map[string]string{"vcore": "10"},
+		Start:     "2026-11-01T08:00:00Z",
+		End:       "2026-11-01T20:00:00Z",
+	}
+	update := func(change func(*AdvanceReservationConfig)) AdvanceReservationConfig {
+		reservation := valid
+		change(&reservation)
+		return reservation
+	}
+	testCases := []struct {
+		name             string
+		reservations     []AdvanceReservationConfig
+		expectedErrorMsg string
+	}{
+		{"none", nil, ""},
+		{"valid", []AdvanceReservationConfig{valid, update(func(r *AdvanceReservationConfig) { r.Name = "other"; r.Nodes = []string{"node-1"}; r.Lead = "0s" })}, ""},
+		{"duplicate name", []AdvanceReservationConfig{valid, valid}, "duplicate advance reservation name found: training"},
+		{"no name", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.Name = "" })}, "advance reservation name must be set"},
+		{"short queue name", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.Queue = "training" })}, "queue must be fully qualified"},
+		{"no resources", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.Resources = nil })}, "resources must be set"},
+		{"invalid resources", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.Resources = map[string]string{"vcore": "x"} })}, "invalid advance reservation training resources"},
+		{"invalid start", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.Start = "tomorrow" })}, "invalid advance reservation training: start"},
+		{"end before start", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.End = r.Start })}, "end must be after start"},
+		{"negative lead", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.Lead = "-1h" })}, "lead must not be negative"},
+		{"empty node", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.Nodes = []string{"node-1", " "} })}, "advance reservation training node must not be empty"},
+		{"duplicate node", []AdvanceReservationConfig{update(func(r *AdvanceReservationConfig) { r.Nodes = []string{"node-1", "node-1"} })}, "advance reservation training has duplicate node: node-1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkAdvanceReservations(&PartitionConfig{Reservations: tc.reservations})
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+		})
+	}
+
+	start, end, lead, err := GetAdvanceReservationWindow(valid)
+	assert.NilError(t, err)
+	assert.Equal(t, start, time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, end, time.Date(2026, 11, 1, 20, 0, 0, 0, time.UTC))
+	assert.Equal(t, lead, DefaultAdvanceReservationLead, "lead should default")
+}
+
+func TestCheckApplicationSortPolicy(t *testing.T) {
+	testCases := []struct {
+		name             string
+		properties       map[string]string
+		expectedErrorMsg string
+	}{
+		{"no properties", nil, ""},
+		{"not set", map[string]string{PriorityOffset: "10"}, ""},
+		{"empty value", map[string]string{ApplicationSortPolicy: ""}, ""},
+		{"fifo", map[string]string{ApplicationSortPolicy: "fifo"}, ""},
+		{"sjf", map[string]string{ApplicationSortPolicy: "sjf"}, ""},
+		{"edf", map[string]string{ApplicationSortPolicy: "edf"}, ""},
+		{"unknown", map[string]string{ApplicationSortPolicy: "shortest"}, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkApplicationSortPolicy(tc.properties, "test")
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+		})
+	}
+
+	// unknown policies in the queues and child templates do not fail the queue checks
+	queue := &QueueConfig{
+		Name:   "root",
+		Parent: true,
+		Queues: []QueueConfig{
+			{Name: "interactive", Properties: map[string]string{ApplicationSortPolicy: "sjf"}},
+		},
+		ChildTemplate: ChildTemplate{Properties: map[string]string{ApplicationSortPolicy: "latest"}},
+	}
+	assert.NilError(t, checkQueues(queue, 1))
+	queue.Queues[0].Properties[ApplicationSortPolicy] = "fastest"
+	assert.NilError(t, checkQueues(queue, 1))
+}
+
+func TestCheckPreemptionBudget(t *testing.T) {
+	testCases := []struct {
+		name             string
+		properties       map[string]string
+		expectedErrorMsg string
+	}{
+		{"no properties", nil, ""},
+		{"not set", map[string]string{PriorityOffset: "10"}, ""},
+		{"all set", map[string]string{PreemptionBudgetAllocations: "0", PreemptionBudgetPercentage: "12.5", PreemptionBudgetWindow: "1h"}, ""},
+		{"negative allocations", map[string]string{PreemptionBudgetAllocations: "-1"}, "invalid preemption.budget.allocations '-1' for queue test"},
+		{"invalid allocations", map[string]string{PreemptionBudgetAllocations: "many"}, "invalid preemption.budget.allocations 'many' for queue test"},
+		{"zero percentage", map[string]string{PreemptionBudgetPercentage: "0"}, "invalid preemption.budget.percentage '0' for queue test"},
+		{"large percentage", map[string]string{PreemptionBudgetPercentage: "100.1"}, "invalid preemption.budget.percentage '100.1' for queue test"},
+		{"zero window", map[string]string{PreemptionBudgetWindow: "0s"}, "invalid preemption.budget.window '0s' for queue test"},
+		{"invalid window", map[string]string{PreemptionBudgetWindow: "1 hour"}, "invalid preemption.budget.window '1 hour' for queue test"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkPreemptionBudget(tc.properties, "test")
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+		})
+	}
+
+	// child template properties are checked as part of the queue checks
+	queue := &QueueConfig{
+		Name:          "root",
+		Parent:        true,
+		ChildTemplate: ChildTemplate{Properties: map[string]string{PreemptionBudgetAllocations: "x"}},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "invalid preemption.budget.allocations 'x' for queue root")
+}
+
+func TestCheckPreemptionCost(t *testing.T) {
+	testCases := []struct {
+		name             string
+		properties       map[string]string
+		expectedErrorMsg string
+	}{
+		{"no properties", nil, ""},
+		{"not set", map[string]string{PriorityOffset: "10"}, ""},
+		{"all set", map[string]string{PreemptionCostRuntime: "0.5", PreemptionCostPriority: "0", PreemptionCostOriginator: "100", PreemptionCostPlaceholder: "1e3"}, ""},
+		{"negative weight", map[string]string{PreemptionCostRuntime: "-1"}, "invalid preemption.policy.cost.runtime '-1' for queue test"},
+		{"invalid weight", map[string]string{PreemptionCostPriority: "high"}, "invalid preemption.policy.cost.priority 'high' for queue test"},
+		{"infinite weight", map[string]string{PreemptionCostOriginator: "Inf"}, "invalid preemption.policy.cost.originator 'Inf' for queue test"},
+		{"NaN weight", map[string]string{PreemptionCostPlaceholder: "NaN"}, "invalid preemption.policy.cost.placeholder 'NaN' for queue test"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkPreemptionCost(tc.properties, "test")
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+		})
+	}
+}
+
+func TestCheckPreemptionGang(t *testing.T) {
+	assert.NilError(t, checkPreemptionGang(nil, "test"))
+	assert.NilError(t, checkPreemptionGang(map[string]string{PreemptionGang: "true"}, "test"))
+	assert.NilError(t, checkPreemptionGang(map[string]string{PreemptionGang: "false"}, "test"))
+	assert.ErrorContains(t, checkPreemptionGang(map[string]string{PreemptionGang: "yes"}, "test"), "invalid preemption.policy.gang 'yes' for queue test")
+
+	// child template properties are checked as part of the queue checks
+	queue := &QueueConfig{
+		Name:          "root",
+		Parent:        true,
+		ChildTemplate: ChildTemplate{Properties: map[string]string{PreemptionGang: "gang"}},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "invalid preemption.policy.gang 'gang' for queue root")
+}
+
+func TestCheckPreemptionCrossNode(t *testing.T) {
+	assert.NilError(t, checkPreemptionCrossNode(nil, "test"))
+	assert.NilError(t, checkPreemptionCrossNode(map[string]string{PreemptionCrossNodeDepth: "0"}, "test"))
+	assert.NilError(t, checkPreemptionCrossNode(map[string]string{PreemptionCrossNodeDepth: "2"}, "test"))
+	assert.ErrorContains(t, checkPreemptionCrossNode(map[string]string{PreemptionCrossNodeDepth: "-1"}, "test"), "invalid preemption.policy.crossnode.depth '-1' for queue test")
+	assert.ErrorContains(t, checkPreemptionCrossNode(map[string]string{PreemptionCrossNodeDepth: "deep"}, "test"), "invalid preemption.policy.crossnode.depth 'deep' for queue test")
+
+	// child template properties are checked as part of the queue checks
+	queue := &QueueConfig{
+		Name:          "root",
+		Parent:        true,
+		ChildTemplate: ChildTemplate{Properties: map[string]string{PreemptionCrossNodeDepth: "1.5"}},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "invalid preemption.policy.crossnode.depth '1.5' for queue root")
+}
+
+func TestCheckPreemptionReclaim(t *testing.T) {
+	assert.NilError(t, checkPreemptionReclaim(nil, "test"))
+	assert.NilError(t, checkPreemptionReclaim(map[string]string{PreemptionReclaimDeadline: "0s"}, "test"))
+	assert.NilError(t, checkPreemptionReclaim(map[string]string{PreemptionReclaimDeadline: "5m"}, "test"))
+	assert.ErrorContains(t, checkPreemptionReclaim(map[string]string{PreemptionReclaimDeadline: "-1m"}, "test"), "invalid preemption.reclaim.deadline '-1m' for queue test")
+	assert.ErrorContains(t, checkPreemptionReclaim(map[string]string{PreemptionReclaimDeadline: "soon"}, "test"), "invalid preemption.reclaim.deadline 'soon' for queue test")
+
+	// child template properties are checked as part of the queue checks
+	queue := &QueueConfig{
+		Name:          "root",
+		Parent:        true,
+		ChildTemplate: ChildTemplate{Properties: map[string]string{PreemptionReclaimDeadline: "10"}},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "invalid preemption.reclaim.deadline '10' for queue root")
+}
+
+func TestCheckQueueAgingPeriod(t *testing.T) {
+	assert.NilError(t, checkQueueAgingPeriod(nil, "test"))
+	assert.NilError(t, checkQueueAgingPeriod(map[string]string{QueueAgingPeriod: "10m"}, "test"))
+	assert.ErrorContains(t, checkQueueAgingPeriod(map[string]string{QueueAgingPeriod: "0s"}, "test"), "invalid queue.aging.period '0s' for queue test")
+	assert.ErrorContains(t, checkQueueAgingPeriod(map[string]string{QueueAgingPeriod: "-1m"}, "test"), "invalid queue.aging.period '-1m' for queue test")
+	assert.ErrorContains(t, checkQueueAgingPeriod(map[string]string{QueueAgingPeriod: "later"}, "test"), "invalid queue.aging.period 'later' for queue test")
+
+	// child template properties are checked as part of the queue checks
+	queue := &QueueConfig{
+		Name:          "root",
+		Parent:        true,
+		ChildTemplate: ChildTemplate{Properties: map[string]string{QueueAgingPeriod: "10"}},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "invalid queue.aging.period '10' for queue root")
+}
+
+func TestCheckQueueWeight(t *testing.T) {
+	testCases := []struct {
+		name       string
+		properties map[string]string
+		errMsg     string
+	}{
+		{"nil properties", nil, ""},
+		{"drf weight only", map[string]string{DRFWeight: "2"}, ""},
+		{"fair share weight only", map[string]string{FairShareWeight: "0.5"}, ""},
+		{"equal weights", map[string]string{DRFWeight: "2", FairShareWeight: "2.0"}, ""},
+		{"different weights", map[string]string{DRFWeight: "2", FairShareWeight: "3"}, "drf.weight and fairshare.weight must be equal for queue test"},
+		{"zero drf weight", map[string]string{DRFWeight: "0"}, "invalid drf.weight '0' for queue test"},
+		{"negative fair share weight", map[string]string{FairShareWeight: "-1"}, "invalid fairshare.weight '-1' for queue test"},
+		{"infinite weight", map[string]string{FairShareWeight: "+Inf"}, "invalid fairshare.weight '+Inf' for queue test"},
+		{"not a number", map[string]string{DRFWeight: "two"}, "invalid drf.weight 'two' for queue test"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkQueueWeight(tc.properties, "test")
+			if tc.errMsg == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+
+	// child template properties are checked as part of the queue checks
+	queue := &QueueConfig{
+		Name:          "root",
+		Parent:        true,
+		ChildTemplate: ChildTemplate{Properties: map[string]string{DRFWeight: "1", FairShareWeight: "2"}},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "drf.weight and fairshare.weight must be equal for queue root")
+}
+
+func TestCheckApplicationAgingPeriod(t *testing.T) {
+	assert.NilError(t, checkApplicationAgingPeriod(nil, "test"))
+	assert.NilError(t, checkApplicationAgingPeriod(map[string]string{ApplicationAgingPeriod: "10m"}, "test"))
+	assert.ErrorContains(t, checkApplicationAgingPeriod(map[string]string{ApplicationAgingPeriod: "0s"}, "test"), "invalid application.aging.period '0s' for queue test")
+	assert.ErrorContains(t, checkApplicationAgingPeriod(map[string]string{ApplicationAgingPeriod: "-1m"}, "test"), "invalid application.aging.period '-1m' for queue test")
+	assert.ErrorContains(t, checkApplicationAgingPeriod(map[string]string{ApplicationAgingPeriod: "later"}, "test"), "invalid application.aging.period 'later' for queue test")
+
+	// queue and child template properties are checked as part of the queue checks
+	queue := &QueueConfig{
+		Name:       "root",
+		Parent:     true,
+		Properties: map[string]string{ApplicationAgingPeriod: "-5m"},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "invalid application.aging.period '-5m' for queue root")
+	queue = &QueueConfig{
+		Name:          "root",
+		Parent:        true,
+		ChildTemplate: ChildTemplate{Properties: map[string]string{ApplicationAgingPeriod: "10"}},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "invalid application.aging.period '10' for queue root")
+}
+
+func TestCheckApplicationQuota(t *testing.T) {
+	testCases := []struct {
+		name       string
+		properties map[string]string
+		errMsg     string
+	}{
+		{"nil properties", nil, ""},
+		{"all set", map[string]string{ApplicationMaxResource: `{"resources":{"memory":{"value":1000}}}`, ApplicationMaxAllocations: "5", ApplicationMaxLifetime: "1h"}, ""},
+		{"invalid resource", map[string]string{ApplicationMaxResource: "memory=1000"}, "invalid application.max.resource 'memory=1000' for queue test"},
+		{"zero resource", map[string]string{ApplicationMaxResource: `{"resources":{"memory":{"value":0}}}`}, "invalid application.max.resource"},
+		{"zero allocations", map[string]string{ApplicationMaxAllocations: "0"}, "invalid application.max.allocations '0' for queue test"},
+		{"invalid allocations", map[string]string{ApplicationMaxAllocations: "-1"}, "invalid application.max.allocations '-1' for queue test"},
+		{"zero lifetime", map[string]string{ApplicationMaxLifetime: "0s"}, "invalid application.max.lifetime '0s' for queue test"},
+		{"invalid lifetime", map[string]string{ApplicationMaxLifetime: "1 day"}, "invalid application.max.lifetime '1 day' for queue test"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkApplicationQuota(tc.properties, "test")
+			if tc.errMsg == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.errMsg)
+			}
+		})
+	}
+
+	// child template properties are checked as part of the queue checks
+	queue := &QueueConfig{
+		Name:          "root",
+		Parent:        true,
+		ChildTemplate: ChildTemplate{Properties: map[string]string{ApplicationMaxAllocations: "none"}},
+	}
+	assert.ErrorContains(t, checkQueues(queue, 1), "invalid application.max.allocations 'none' for queue root")
+}
+
 func TestIsQueueNameValid(t *testing.T) {
 	assert.NilError(t, IsQueueNameValid("parent_Child_test-a_b_#_c_#_d_/_e@dom:ain"))
 	err := IsQueueNameValid("invalid!queue")
@@ -0,0 +1,145 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package configs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed cron expression with five fields: minute, hour, day of month, month and day of week.
+// Each field is either "*", a value, a range "a-b", a step "*/n" or "a-b/n", or a comma separated list of these.
+// Day of week 0 and 7 are both Sunday. If both day fields are restricted a time matches if either of them matches.
+type CronSchedule struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+	anyDay     bool // day of month is "*"
+	anyWeekday bool // day of week is "*"
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseCron parses a cron expression with five fields.
+func ParseCron(expr string) (*CronSchedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression must have %d fields: '%s'", len(cronFields), expr)
+	}
+	values := make([]uint64, len(cronFields))
+	for i, field := range cronFields {
+		bits, err := parseCronField(parts[i], field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = bits
+	}
+	// Sunday can be set as 0 or 7
+	if values[4]&(1<<7) != 0 {
+		values[4] |= 1
+	}
+	return &CronSchedule{
+		minute:     values[0],
+		hour:       values[1],
+		dayOfMonth: values[2],
+		month:      values[3],
+		dayOfWeek:  values[4],
+		anyDay:     parts[2] == "*",
+		anyWeekday: parts[4] == "*",
+	}, nil
+}
+
+// parseCronField parses one field of a cron expression into a bit set of the allowed values.
+func parseCronField(value string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(value, ",") {
+		rangeValue, stepValue, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepValue)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid cron %s step: '%s'", field.name, item)
+			}
+		}
+		low, high := field.min, field.max
+		if rangeValue != "*" {
+			lowValue, highValue, isRange := strings.Cut(rangeValue, "-")
+			var err error
+			if low, err = strconv.Atoi(lowValue); err != nil {
+				return 0, fmt.Errorf("invalid cron %s: '%s'", field.name, item)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(highValue); err != nil {
+					return 0, fmt.Errorf("invalid cron %s: '%s'", field.name, item)
+				}
+			} else if hasStep {
+				// "a/n" means from a to the end of the field
+				high = field.max
+			}
+		}
+		if low < field.min || high > field.max || low > high {
+			return 0, fmt.Errorf("cron %s out of range %d-%d: '%s'", field.name, field.min, field.max, item)
+		}
+		for i := low; i <= high; i += step {
+			bits |= 1 << i
+		}
+	}
+	return bits, nil
+}
+
+// Matches returns true if the minute of the time matches the schedule.
+func (c *CronSchedule) Matches(t time.Time) bool {
+	if c.minute&(1<<t.Minute()) == 0 || c.hour&(1<<t.Hour()) == 0 || c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	dayMatch := c.dayOfMonth&(1<<t.Day()) != 0
+	weekdayMatch := c.dayOfWeek&(1<<int(t.Weekday())) != 0
+	if c.anyDay || c.anyWeekday {
+		return dayMatch && weekdayMatch
+	}
+	return dayMatch || weekdayMatch
+}
+
+// LastStart returns the latest time at or before t that matches the schedule and is less than the given duration
+// before t. The zero time is returned if there is no such time.
+func (c *CronSchedule) LastStart(t time.Time, within time.Duration) time.Time {
+	for start := t.Truncate(time.Minute); t.Sub(start) < within; start = start.Add(-time.Minute) {
+		if c.Matches(start) {
+			return start
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,99 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package configs
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	testCases := []struct {
+		name             string
+		expr             string
+		expectedErrorMsg string
+	}{
+		{"every minute", "* * * * *", ""},
+		{"lists ranges and steps", "0,30 22-23/1 1-15 */2 1-5", ""},
+		{"sunday as 7", "0 0 * * 7", ""},
+		{"step from value", "5/15 * * * *", ""},
+		{"too few fields", "0 22 * *", "cron expression must have 5 fields"},
+		{"too many fields", "0 22 * * * 2026", "cron expression must have 5 fields"},
+		{"invalid value", "x * * * *", "invalid cron minute"},
+		{"invalid range", "0 22-x * * *", "invalid cron hour"},
+		{"invalid step", "*/0 * * * *", "invalid cron minute step"},
+		{"out of range", "0 24 * * *", "cron hour out of range"},
+		{"zero day of month", "0 0 0 * *", "cron day of month out of range"},
+		{"reversed range", "0 0 * 12-1 *", "cron month out of range"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseCron(tc.expr)
+			if tc.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.expectedErrorMsg, "Error message mismatch")
+				assert.Assert(t, schedule == nil, "schedule should not be returned on error")
+			} else {
+				assert.NilError(t, err, "No error is expected")
+			}
+		})
+	}
+}
+
+func TestCronMatches(t *testing.T) {
+	// Monday 2 November 2026
+	monday := time.Date(2026, time.November, 2, 22, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name    string
+		expr    string
+		t       time.Time
+		matches bool
+	}{
+		{"every minute", "* * * * *", monday.Add(17 * time.Minute), true},
+		{"exact time", "0 22 * * *", monday, true},
+		{"wrong minute", "0 22 * * *", monday.Add(time.Minute), false},
+		{"weekday range", "0 22 * * 1-5", monday, true},
+		{"weekend only", "0 22 * * 0,6", monday, false},
+		{"sunday as 7", "0 22 * * 7", monday.AddDate(0, 0, 6), true},
+		{"minute step", "*/15 * * * *", monday.Add(45 * time.Minute), true},
+		{"minute step miss", "*/15 * * * *", monday.Add(50 * time.Minute), false},
+		{"month", "0 22 * 11 *", monday, true},
+		{"wrong month", "0 22 * 12 *", monday, false},
+		{"day of month or weekday", "0 22 15 * 1", monday, true},
+		{"day of month and any weekday", "0 22 15 * *", monday, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := ParseCron(tc.expr)
+			assert.NilError(t, err, "cron parse failed")
+			assert.Equal(t, schedule.Matches(tc.t), tc.matches)
+		})
+	}
+}
+
+func TestCronLastStart(t *testing.T) {
+	schedule, err := ParseCron("0 22 * * *")
+	assert.NilError(t, err, "cron parse failed")
+	start := time.Date(2026, time.November, 2, 22, 0, 0, 0, time.UTC)
+	assert.Equal(t, schedule.LastStart(start, 8*time.Hour), start, "start itself should be found")
+	assert.Equal(t, schedule.LastStart(start.Add(7*time.Hour+59*time.Minute+30*time.Second), 8*time.Hour), start, "start within the window should be found")
+	assert.Assert(t, schedule.LastStart(start.Add(8*time.Hour), 8*time.Hour).IsZero(), "start should not be found after the window")
+	assert.Assert(t, schedule.LastStart(start.Add(-time.Minute), 8*time.Hour).IsZero(), "start should not be found before the window")
+}
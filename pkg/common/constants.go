@@ -29,4 +29,26 @@ const (
 	RecoveryQueue         = "@recovery@"
 	RecoveryQueueFull     = "root." + RecoveryQueue
 	DefaultPlacementQueue = "root.default"
+
+	// application tags used by the sjf and edf application sort policies
+	AppTagRuntimeEstimate = "application.runtime.estimate" // expected run time as a duration, i.e. 10m
+	AppTagDeadline        = "application.deadline"         // completion deadline as an RFC3339 timestamp
+
+	// application tag to override the node topology mode of the partition: spread, colocate or none
+	AppTagTopologyMode = "application.topology.mode"
+
+	// application tags that set the preemption disruption budget of the application
+	AppTagPreemptionBudgetAllocations = "application.preemption.budget.allocations" // allocations preempted per window
+	AppTagPreemptionBudgetPercentage  = "application.preemption.budget.percentage"  // percentage of the allocated resources preempted per window
+	AppTagPreemptionBudgetWindow      = "application.preemption.budget.window"      // rolling window as a duration, i.e. 10m
+
+	// application tag with a comma separated list of application IDs that must complete before the application is scheduled
+	AppTagDependencies = "application.dependencies"
+	// application tag with the time after submission the application fails if it still waits on its dependencies as a duration, i.e. 1h
+	AppTagDependenciesTimeout = "application.dependencies.timeout"
+
+	// application tags that cap the application, override the defaults set in the queue properties
+	AppTagMaxResource    = "application.max.resource"    // maximum allocated resources as a JSON resource
+	AppTagMaxAllocations = "application.max.allocations" // maximum number of concurrent allocations, placeholders do not count
+	AppTagMaxLifetime    = "application.max.lifetime"    // time after submission the application fails as a duration, i.e. 1h
 )
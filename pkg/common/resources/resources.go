@@ -539,6 +539,12 @@ func getFairShare(allocated, guaranteed, fair *Resource) float64 {
 	return maxShare
 }
 
+// FairShare returns the fair share usage ratio of the allocated resources: the highest share of any resource
+// type compared to the guaranteed resources, or the fair max resources if the type has no guarantee.
+func FairShare(allocated, guaranteed, fair *Resource) float64 {
+	return getFairShare(allocated, guaranteed, fair)
+}
+
 // Get the share of each resource quantity when compared to the total
 // resources quantity
 // NOTE: shares can be negative and positive in the current assumptions
@@ -622,18 +628,8 @@ func CompUsageRatioSeparately(leftAllocated, leftGuaranteed, leftFairMax, rightA
 // highest share for right resource from total.
 // If highest share for the right resource is 0 fairness is 1
 func FairnessRatio(left, right, total *Resource) float64 {
-	lshares := getShares(left, total)
-	rshares := getShares(right, total)
-
-	// Get the largest value from the shares
-	lshare := float64(0)
-	if shareLen := len(lshares); shareLen != 0 {
-		lshare = lshares[shareLen-1]
-	}
-	rshare := float64(0)
-	if shareLen := len(rshares); shareLen != 0 {
-		rshare = rshares[shareLen-1]
-	}
+	lshare := DominantShare(left, total)
+	rshare := DominantShare(right, total)
 	// calculate the ratio
 	ratio := lshare / rshare
 	// divide by zero gives special NaN back change it to 1
@@ -643,6 +639,18 @@ func FairnessRatio(left, right, total *Resource) float64 {
 	return ratio
 }
 
+// Get the highest share of the resource compared to the total.
+// Shares are calculated in the same way as for CompUsageRatio: if the total is nil or zero for a
+// resource type the quantity itself is used as the share.
+// An empty or nil resource has a share of 0.
+func DominantShare(res, total *Resource) float64 {
+	shares := getShares(res, total)
+	if shareLen := len(shares); shareLen != 0 {
+		return shares[shareLen-1]
+	}
+	return 0
+}
+
 // Compare the shares and return the compared value
 // 0 for equal shares
 // 1 if the left share is larger
@@ -1862,6 +1862,27 @@ func TestFairnessRatio(t *testing.T) {
 	}
 }
 
+func TestDominantShare(t *testing.T) {
+	tests := []struct {
+		res      *Resource
+		total    *Resource
+		expected float64
+		message  string
+	}{
+		{nil, nil, 0, "nil resource"},
+		{NewResource(), &Resource{Resources: map[string]Quantity{"first": 10}}, 0, "empty resource"},
+		{&Resource{Resources: map[string]Quantity{"first": 5}}, nil, 5, "nil total"},
+		{&Resource{Resources: map[string]Quantity{"first": 5}}, &Resource{Resources: map[string]Quantity{"first": 0}}, 5, "zero total"},
+		{&Resource{Resources: map[string]Quantity{"first": 5, "second": 5}}, &Resource{Resources: map[string]Quantity{"first": 10, "second": 20}}, 0.5, "multiple types"},
+		{&Resource{Resources: map[string]Quantity{"first": -5}}, &Resource{Resources: map[string]Quantity{"first": 10}}, -0.5, "negative usage"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.message, func(t *testing.T) {
+			assert.Equal(t, tc.expected, DominantShare(tc.res, tc.total), "unexpected dominant share")
+		})
+	}
+}
+
 // This tests just to cover code in the CompUsageRatioSeparately
 func TestCompUsageRatioSeparately(t *testing.T) {
 	tests := []struct {
@@ -36,6 +36,7 @@ const (
 	AppFailed     = "failed"
 	AppRejected   = "rejected"
 	AppResuming   = "resuming"
+	AppSuspended  = "suspended"
 	AppCompleting = "completing"
 	AppCompleted  = "completed"
 	AppExpired    = "expired"
@@ -76,7 +77,7 @@ func InitQueueMetrics(name string) *QueueMetrics {
 			Namespace:   Namespace,
 			Name:        "queue_app",
 			ConstLabels: prometheus.Labels{"queue": name},
-			Help:        "Queue application metrics. State of the application includes `new`, `accepted`, `rejected`, `running`, `failing`, `failed`, `resuming`, `completing`, `completed`.",
+			Help:        "Queue application metrics. State of the application includes `new`, `accepted`, `rejected`, `running`, `failing`, `failed`, `resuming`, `suspended`, `completing`, `completed`.",
 		}, []string{"state"})
 
 	q.appMetricsSubsystem = prometheus.NewGaugeVec(
@@ -84,7 +85,7 @@ func InitQueueMetrics(name string) *QueueMetrics {
 			Namespace: Namespace,
 			Subsystem: replaceStr,
 			Name:      "queue_app",
-			Help:      "Queue application metrics. State of the application includes `new`, `accepted`, `rejected`, `running`, `failing`, `failed`, `resuming`, `completing`, `completed`.",
+			Help:      "Queue application metrics. State of the application includes `new`, `accepted`, `rejected`, `running`, `failing`, `failed`, `resuming`, `suspended`, `completing`, `completed`.",
 		}, []string{"state"})
 
 	q.containerMetrics = prometheus.NewCounterVec(
@@ -254,6 +255,23 @@ func (m *QueueMetrics) GetQueueApplicationsResuming() (int, error) {
 	return -1, err
 }
 
+func (m *QueueMetrics) IncQueueApplicationsSuspended() {
+	m.incQueueApplications(AppSuspended)
+}
+
+func (m *QueueMetrics) DecQueueApplicationsSuspended() {
+	m.decQueueApplications(AppSuspended)
+}
+
+func (m *QueueMetrics) GetQueueApplicationsSuspended() (int, error) {
+	metricDto := &dto.Metric{}
+	err := m.appMetricsLabel.WithLabelValues(AppSuspended).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
 func (m *QueueMetrics) IncQueueApplicationsFailing() {
 	m.incQueueApplications(AppFailing)
 }
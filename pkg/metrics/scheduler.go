@@ -65,6 +65,8 @@ type SchedulerMetrics struct {
 	sortingLatency        *prometheus.HistogramVec
 	tryNodeLatency        prometheus.Histogram
 	tryPreemptionLatency  prometheus.Histogram
+	nodeRescoringLatency  prometheus.Histogram
+	nodeRescored          prometheus.Counter
 	lock                  locking.RWMutex
 }
 
@@ -97,7 +99,7 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 			Namespace: Namespace,
 			Subsystem: SchedulerSubsystem,
 			Name:      "application_total",
-			Help:      "Total number of applications. State of the application includes `running`, `resuming`, `failing`, `completing`, `completed` and `failed`.",
+			Help:      "Total number of applications. State of the application includes `running`, `resuming`, `suspended`, `failing`, `completing`, `completed` and `failed`.",
 		}, []string{"state"})
 
 	s.node = prometheus.NewGaugeVec(
@@ -146,6 +148,25 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 		},
 	)
 
+	s.nodeRescoringLatency = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "node_rescoring_latency_milliseconds",
+			Help:      "Latency of rescoring nodes for time dependent node sorting policies, in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 10, 8), // start from 0.1ms
+		},
+	)
+
+	s.nodeRescored = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "node_rescored_total",
+			Help:      "Total number of nodes rescored for time dependent node sorting policies.",
+		},
+	)
+
 	// Register the metrics
 	var metricsList = []prometheus.Collector{
 		s.containerAllocation,
@@ -156,6 +177,8 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 		s.sortingLatency,
 		s.tryNodeLatency,
 		s.tryPreemptionLatency,
+		s.nodeRescoringLatency,
+		s.nodeRescored,
 	}
 	for _, metric := range metricsList {
 		if err := prometheus.Register(metric); err != nil {
@@ -198,6 +221,14 @@ func (m *SchedulerMetrics) ObserveTryPreemptionLatency(start time.Time) {
 	m.tryPreemptionLatency.Observe(SinceInSeconds(start))
 }
 
+func (m *SchedulerMetrics) ObserveNodeRescoringLatency(start time.Time) {
+	m.nodeRescoringLatency.Observe(SinceInSeconds(start))
+}
+
+func (m *SchedulerMetrics) AddNodesRescored(value int) {
+	m.nodeRescored.Add(float64(value))
+}
+
 func (m *SchedulerMetrics) AddAllocatedContainers(value int) {
 	m.containerAllocation.WithLabelValues(ContainerAllocated).Add(float64(value))
 }
@@ -334,6 +365,23 @@ func (m *SchedulerMetrics) GetTotalApplicationsResuming() (int, error) {
 	return -1, err
 }
 
+func (m *SchedulerMetrics) IncTotalApplicationsSuspended() {
+	m.application.WithLabelValues(AppSuspended).Inc()
+}
+
+func (m *SchedulerMetrics) DecTotalApplicationsSuspended() {
+	m.application.WithLabelValues(AppSuspended).Dec()
+}
+
+func (m *SchedulerMetrics) GetTotalApplicationsSuspended() (int, error) {
+	metricDto := &dto.Metric{}
+	err := m.application.WithLabelValues(AppSuspended).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
 func (m *SchedulerMetrics) IncTotalApplicationsCompleted() {
 	m.application.WithLabelValues(AppCompleted).Inc()
 }
@@ -60,6 +60,27 @@ func TestTryPreemptionLatency(t *testing.T) {
 	verifyHistogram(t, "trypreemption_latency_milliseconds", 60, 1)
 }
 
+func TestNodeRescoring(t *testing.T) {
+	sm = getSchedulerMetrics(t)
+	defer unregisterMetrics()
+
+	sm.ObserveNodeRescoringLatency(time.Now().Add(-1 * time.Minute))
+	verifyHistogram(t, "node_rescoring_latency_milliseconds", 60, 1)
+
+	sm.AddNodesRescored(5)
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	assert.NilError(t, err)
+	var found bool
+	for _, metric := range mfs {
+		if metric.GetName() == "yunikorn_scheduler_node_rescored_total" {
+			assert.Equal(t, 1, len(metric.Metric))
+			assert.Equal(t, 5.0, metric.Metric[0].GetCounter().GetValue())
+			found = true
+		}
+	}
+	assert.Assert(t, found, "node rescored metric not found")
+}
+
 func TestSchedulerApplicationsNew(t *testing.T) {
 	sm = getSchedulerMetrics(t)
 	defer unregisterMetrics()
@@ -137,6 +158,21 @@ func TestSchedulerApplicationsResuming(t *testing.T) {
 	verifyMetric(t, 0, "resuming", "yunikorn_scheduler_application_total", dto.MetricType_GAUGE, "state")
 }
 
+func TestSchedulerApplicationsSuspended(t *testing.T) {
+	sm = getSchedulerMetrics(t)
+	defer unregisterMetrics()
+
+	sm.IncTotalApplicationsSuspended()
+	verifyMetric(t, 1, "suspended", "yunikorn_scheduler_application_total", dto.MetricType_GAUGE, "state")
+
+	curr, err := sm.GetTotalApplicationsSuspended()
+	assert.NilError(t, err)
+	assert.Equal(t, curr, 1)
+
+	sm.DecTotalApplicationsSuspended()
+	verifyMetric(t, 0, "suspended", "yunikorn_scheduler_application_total", dto.MetricType_GAUGE, "state")
+}
+
 func TestSchedulerApplicationsFailing(t *testing.T) {
 	sm = getSchedulerMetrics(t)
 	defer unregisterMetrics()
@@ -226,4 +262,6 @@ func unregisterMetrics() {
 	prometheus.Unregister(sm.sortingLatency)
 	prometheus.Unregister(sm.tryNodeLatency)
 	prometheus.Unregister(sm.tryPreemptionLatency)
+	prometheus.Unregister(sm.nodeRescoringLatency)
+	prometheus.Unregister(sm.nodeRescored)
 }
@@ -19,10 +19,18 @@
 package plugins
 
 import (
+	"fmt"
+
+	"go.uber.org/zap"
+
 	"github.com/apache/yunikorn-core/pkg/log"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/api"
 )
 
+// names of the built-in node sorting policies, these names cannot be used to register a node scorer.
+// The names are reserved by the policies package that defines the node sorting policies.
+var builtinNodeScorers = make(map[string]bool)
+
 var plugins SchedulerPlugins
 
 func init() {
@@ -42,6 +50,10 @@ func RegisterSchedulerPlugin(plugin interface{}) {
 		log.Log(log.RMProxy).Info("register scheduler plugin: StateDumpPlugin")
 		plugins.StateDumpPlugin = sdp
 	}
+	if vcm, ok := plugin.(VictimCostModel); ok {
+		log.Log(log.RMProxy).Info("register scheduler plugin: VictimCostModel")
+		plugins.VictimCostModel = vcm
+	}
 }
 
 // UnregisterSchedulerPlugins removes all earlier set plugins
@@ -51,6 +63,8 @@ func UnregisterSchedulerPlugins() {
 	defer plugins.Unlock()
 	plugins.ResourceManagerCallbackPlugin = nil
 	plugins.StateDumpPlugin = nil
+	plugins.NodeScorers = nil
+	plugins.VictimCostModel = nil
 }
 
 // GetResourceManagerCallbackPlugin returns the registered callback plugin or nil if none was registered.
@@ -66,3 +80,64 @@ func GetStateDumpPlugin() api.StateDumpPlugin {
 	defer plugins.RUnlock()
 	return plugins.StateDumpPlugin
 }
+
+// GetVictimCostModel returns the registered victim cost model or nil if none was registered.
+func GetVictimCostModel() VictimCostModel {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	return plugins.VictimCostModel
+}
+
+// RegisterNodeScorer registers the node scorer factory under the name. The name can be used as the node
+// sorting policy type, or as one of the scorers of a weighted node sorting policy, in the partition configuration.
+// The name must not be empty, must not be used by a built-in node sorting policy and must not be registered already.
+func RegisterNodeScorer(name string, factory NodeScorerFactory) error {
+	if name == "" || factory == nil {
+		return fmt.Errorf("node scorer registration needs a name and a factory")
+	}
+	plugins.Lock()
+	defer plugins.Unlock()
+	if builtinNodeScorers[name] {
+		return fmt.Errorf("node scorer name '%s' is reserved for a built-in node sorting policy", name)
+	}
+	if _, ok := plugins.NodeScorers[name]; ok {
+		return fmt.Errorf("node scorer '%s' is already registered", name)
+	}
+	if plugins.NodeScorers == nil {
+		plugins.NodeScorers = make(map[string]NodeScorerFactory)
+	}
+	log.Log(log.RMProxy).Info("register scheduler plugin: NodeScorer",
+		zap.String("name", name))
+	plugins.NodeScorers[name] = factory
+	return nil
+}
+
+// ReserveNodeScorerNames reserves the names of the built-in node sorting policies: a node scorer cannot be
+// registered under a reserved name.
+func ReserveNodeScorerNames(names ...string) {
+	plugins.Lock()
+	defer plugins.Unlock()
+	for _, name := range names {
+		builtinNodeScorers[name] = true
+	}
+}
+
+// UnregisterNodeScorer removes the node scorer registered under the name.
+// Partitions that already use the scorer keep using it until the configuration is reloaded.
+func UnregisterNodeScorer(name string) {
+	plugins.Lock()
+	defer plugins.Unlock()
+	delete(plugins.NodeScorers, name)
+}
+
+// GetNodeScorerFactory returns the node scorer factory registered under the name or nil if none was registered.
+func GetNodeScorerFactory(name string) NodeScorerFactory {
+	plugins.RLock()
+	defer plugins.RUnlock()
+	return plugins.NodeScorers[name]
+}
+
+// IsNodeScorerRegistered returns true if a node scorer is registered under the name.
+func IsNodeScorerRegistered(name string) bool {
+	return GetNodeScorerFactory(name) != nil
+}
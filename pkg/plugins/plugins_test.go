@@ -20,6 +20,7 @@ package plugins
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -96,3 +97,51 @@ func TestRegisterPlugins(t *testing.T) {
 	assert.Assert(t, GetStateDumpPlugin() != nil, "StateDumpCallback plugin should have been registered")
 	UnregisterSchedulerPlugins()
 }
+
+type VictimCostImplemented struct{}
+
+func (f *VictimCostImplemented) VictimCost(_ VictimCostInfo, _ VictimCostInfo, _ time.Time) float64 {
+	return 0
+}
+
+func TestRegisterVictimCostModel(t *testing.T) {
+	plugins = SchedulerPlugins{}
+	RegisterSchedulerPlugin(&RMPluginImplemented{})
+	assert.Assert(t, GetVictimCostModel() == nil, "VictimCostModel plugin should not have been registered")
+	RegisterSchedulerPlugin(&VictimCostImplemented{})
+	assert.Assert(t, GetVictimCostModel() != nil, "VictimCostModel plugin should have been registered")
+	assert.Assert(t, GetResourceManagerCallbackPlugin() != nil, "ResourceManagerCallback plugin should still be registered")
+	UnregisterSchedulerPlugins()
+	assert.Assert(t, GetVictimCostModel() == nil, "VictimCostModel plugin should have been removed")
+}
+
+type testNodeScorer struct{}
+
+func (testNodeScorer) ScoreNode(_ NodeScoreInfo) float64 {
+	return 0
+}
+
+func (testNodeScorer) IsTimeDependent() bool {
+	return false
+}
+
+func TestRegisterNodeScorer(t *testing.T) {
+	plugins = SchedulerPlugins{}
+	ReserveNodeScorerNames("binpacking")
+	factory := func(_ map[string]float64) NodeScorer { return testNodeScorer{} }
+	assert.Assert(t, !IsNodeScorerRegistered("test"), "node scorer should not have been registered")
+	assert.ErrorContains(t, RegisterNodeScorer("", factory), "needs a name and a factory")
+	assert.ErrorContains(t, RegisterNodeScorer("test", nil), "needs a name and a factory")
+	assert.ErrorContains(t, RegisterNodeScorer("binpacking", factory), "reserved for a built-in node sorting policy")
+
+	assert.NilError(t, RegisterNodeScorer("test", factory), "node scorer registration failed")
+	assert.Assert(t, IsNodeScorerRegistered("test"), "node scorer should have been registered")
+	assert.Assert(t, GetNodeScorerFactory("test") != nil, "node scorer factory should have been returned")
+	assert.ErrorContains(t, RegisterNodeScorer("test", factory), "already registered")
+
+	UnregisterNodeScorer("test")
+	assert.Assert(t, !IsNodeScorerRegistered("test"), "node scorer should have been unregistered")
+	assert.NilError(t, RegisterNodeScorer("test", factory), "node scorer registration failed")
+	UnregisterSchedulerPlugins()
+	assert.Assert(t, !IsNodeScorerRegistered("test"), "node scorers should have been removed")
+}
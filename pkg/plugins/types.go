@@ -19,6 +19,9 @@
 package plugins
 
 import (
+	"time"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/api"
 )
@@ -26,6 +29,50 @@ import (
 type SchedulerPlugins struct {
 	ResourceManagerCallbackPlugin api.ResourceManagerCallback
 	StateDumpPlugin               api.StateDumpPlugin
+	NodeScorers                   map[string]NodeScorerFactory
+	VictimCostModel               VictimCostModel
 
 	locking.RWMutex
 }
+
+// NodeScorer calculates the score of a node for the node sorting policy.
+// Nodes are sorted in ascending score order: the node with the lowest score is tried first.
+type NodeScorer interface {
+	// ScoreNode returns the score for the node.
+	ScoreNode(node NodeScoreInfo) float64
+	// IsTimeDependent returns true if the score changes over time even if the node does not change,
+	// for example when the score depends on the waiting time of the node.
+	IsTimeDependent() bool
+}
+
+// NodeScorerFactory creates a node scorer using the resource weights configured for the partition.
+type NodeScorerFactory func(resourceWeights map[string]float64) NodeScorer
+
+// NodeScoreInfo is the read only view of a node that is passed to a NodeScorer.
+type NodeScoreInfo interface {
+	GetAttribute(key string) string
+	GetCapacity() *resources.Resource
+	GetAllocatedResource() *resources.Resource
+	GetAvailableResource() *resources.Resource
+	GetResourceUsageShares() map[string]float64
+	GetWaitingTime() time.Duration
+}
+
+// VictimCostModel calculates the cost of preempting an allocation. Preemption selects the victims with the lowest
+// total cost. A registered model replaces the weighted model that is configured using the queue properties.
+type VictimCostModel interface {
+	// VictimCost returns the cost of preempting the victim to make room for the ask, the cost must not be negative.
+	VictimCost(victim VictimCostInfo, ask VictimCostInfo, now time.Time) float64
+}
+
+// VictimCostInfo is the read only view of an allocation that is passed to a VictimCostModel.
+type VictimCostInfo interface {
+	GetApplicationID() string
+	GetPriority() int32
+	IsOriginator() bool
+	IsPlaceholder() bool
+	IsAllowPreemptSelf() bool
+	GetCreateTime() time.Time
+	GetBindTime() time.Time
+	GetAllocatedResource() *resources.Resource
+}
@@ -0,0 +1,255 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/locking"
+)
+
+// AdvanceReservation books capacity for a queue during a time window. Allocations from other queues that are expected
+// to still be running when the window starts are not placed on the booked capacity.
+type AdvanceReservation struct {
+	Name       string
+	QueuePath  string              // queue that may use the booked capacity, including its descendants
+	Nodes      []string            // nodes the capacity is booked on, booked in the partition if empty
+	Resource   *resources.Resource // booked capacity, booked on each node if nodes are set
+	Start      time.Time
+	End        time.Time
+	Lead       time.Duration // time before the start from which allocations without a runtime estimate are kept off
+	FromConfig bool          // reservation is set in the partition configuration
+}
+
+// NewAdvanceReservation creates an advance reservation from the configuration. The configuration is checked first.
+func NewAdvanceReservation(conf configs.AdvanceReservationConfig, fromConfig bool) (*AdvanceReservation, error) {
+	if err := configs.CheckAdvanceReservation(conf); err != nil {
+		return nil, err
+	}
+	res, err := resources.NewResourceFromConf(conf.Resources)
+	if err != nil {
+		return nil, err
+	}
+	start, end, lead, err := configs.GetAdvanceReservationWindow(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &AdvanceReservation{
+		Name:       conf.Name,
+		QueuePath:  strings.ToLower(conf.Queue),
+		Nodes:      slices.Clone(conf.Nodes),
+		Resource:   res,
+		Start:      start,
+		End:        end,
+		Lead:       lead,
+		FromConfig: fromConfig,
+	}, nil
+}
+
+// holds returns true if the capacity must be kept free for an allocation placed now that is expected to end at the
+// given time. A zero expected end means the runtime of the allocation is not known: the capacity is then only kept
+// free from the lead time before the start. No capacity is kept free after the end of the window.
+func (ar *AdvanceReservation) holds(now, expectedEnd time.Time) bool {
+	if !now.Before(ar.End) {
+		return false
+	}
+	if expectedEnd.IsZero() {
+		return !now.Before(ar.Start.Add(-ar.Lead))
+	}
+	return expectedEnd.After(ar.Start)
+}
+
+// allows returns true if the queue may use the booked capacity.
+func (ar *AdvanceReservation) allows(queuePath string) bool {
+	return queuePath == ar.QueuePath || strings.HasPrefix(queuePath, ar.QueuePath+configs.DOT)
+}
+
+// onNode returns true if the capacity is booked on the node.
+func (ar *AdvanceReservation) onNode(nodeID string) bool {
+	return slices.Contains(ar.Nodes, nodeID)
+}
+
+// allowedApps returns the IDs of the applications in the queues that may use the booked capacity.
+func (ar *AdvanceReservation) allowedApps(root *Queue) map[string]bool {
+	apps := make(map[string]bool)
+	queue := findQueueByPath(root, ar.QueuePath)
+	if queue == nil {
+		return apps
+	}
+	for _, q := range queue.getSubtree() {
+		for appID := range q.GetCopyOfApps() {
+			apps[appID] = true
+		}
+	}
+	return apps
+}
+
+// AdvanceReservations keeps the advance reservations of a partition by name.
+type AdvanceReservations struct {
+	reservations map[string]*AdvanceReservation
+
+	locking.RWMutex
+}
+
+func NewAdvanceReservations() *AdvanceReservations {
+	return &AdvanceReservations{
+		reservations: make(map[string]*AdvanceReservation),
+	}
+}
+
+// Add adds the reservation. Returns an error if a reservation with the same name exists.
+// Reservations with a window that has ended are removed.
+func (ars *AdvanceReservations) Add(reservation *AdvanceReservation) error {
+	ars.Lock()
+	defer ars.Unlock()
+	ars.removeExpired(time.Now())
+	if _, ok := ars.reservations[reservation.Name]; ok {
+		return fmt.Errorf("advance reservation already exists: %s", reservation.Name)
+	}
+	ars.reservations[reservation.Name] = reservation
+	return nil
+}
+
+// Remove removes the reservation by name. Returns false if the reservation does not exist.
+func (ars *AdvanceReservations) Remove(name string) bool {
+	ars.Lock()
+	defer ars.Unlock()
+	if _, ok := ars.reservations[name]; !ok {
+		return false
+	}
+	delete(ars.reservations, name)
+	return true
+}
+
+// SetConfigured replaces the reservations set in the configuration. Reservations added at runtime are kept, unless
+// the configuration has a reservation with the same name.
+func (ars *AdvanceReservations) SetConfigured(reservations []*AdvanceReservation) {
+	ars.Lock()
+	defer ars.Unlock()
+	for name, reservation := range ars.reservations {
+		if reservation.FromConfig {
+			delete(ars.reservations, name)
+		}
+	}
+	for _, reservation := range reservations {
+		ars.reservations[reservation.Name] = reservation
+	}
+	ars.removeExpired(time.Now())
+}
+
+// GetReservations returns the reservations sorted by start time, then by name.
+func (ars *AdvanceReservations) GetReservations() []*AdvanceReservation {
+	if ars == nil {
+		return nil
+	}
+	ars.RLock()
+	defer ars.RUnlock()
+	reservations := make([]*AdvanceReservation, 0, len(ars.reservations))
+	for _, reservation := range ars.reservations {
+		reservations = append(reservations, reservation)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		if !reservations[i].Start.Equal(reservations[j].Start) {
+			return reservations[i].Start.Before(reservations[j].Start)
+		}
+		return reservations[i].Name < reservations[j].Name
+	})
+	return reservations
+}
+
+// removeExpired removes the reservations with a window that has ended.
+// Lock free call, must be called holding the lock.
+func (ars *AdvanceReservations) removeExpired(now time.Time) {
+	for name, reservation := range ars.reservations {
+		if !now.Before(reservation.End) {
+			delete(ars.reservations, name)
+		}
+	}
+}
+
+// fits returns true if the resource can be placed on the node for the queue without using capacity that is booked
+// for other queues. Capacity booked for a queue that is already used by the queue is not kept free again.
+func (ars *AdvanceReservations) fits(root *Queue, queuePath string, node *Node, res *resources.Resource, expectedEnd, now time.Time) bool {
+	if ars == nil {
+		return true
+	}
+	ars.RLock()
+	holding := make([]*AdvanceReservation, 0)
+	for _, reservation := range ars.reservations {
+		if !reservation.allows(queuePath) && reservation.holds(now, expectedEnd) {
+			holding = append(holding, reservation)
+		}
+	}
+	ars.RUnlock()
+	if len(holding) == 0 {
+		return true
+	}
+
+	nodeBooked := resources.NewResource()
+	partitionBooked := resources.NewResource()
+	for _, reservation := range holding {
+		if len(reservation.Nodes) == 0 {
+			var used *resources.Resource
+			if queue := findQueueByPath(root, reservation.QueuePath); queue != nil {
+				used = queue.GetAllocatedResource()
+			}
+			partitionBooked.AddTo(resources.SubEliminateNegative(reservation.Resource, used))
+		} else if reservation.onNode(node.NodeID) {
+			// the applications are looked up once, not for each allocation on the node
+			allowed := reservation.allowedApps(root)
+			used := resources.NewResource()
+			for _, alloc := range node.GetYunikornAllocations() {
+				if allowed[alloc.GetApplicationID()] {
+					used.AddTo(alloc.GetAllocatedResource())
+				}
+			}
+			nodeBooked.AddTo(resources.SubEliminateNegative(reservation.Resource, used))
+		}
+	}
+	if !resources.Sub(node.GetAvailableResource(), res).FitInMaxUndef(nodeBooked) {
+		return false
+	}
+	// the partition size is only known after nodes have registered
+	if maxResource := root.GetMaxResource(); maxResource != nil && !resources.IsZero(partitionBooked) {
+		available := resources.Sub(maxResource, root.GetAllocatedResource())
+		return resources.Sub(available, res).FitInMaxUndef(partitionBooked)
+	}
+	return true
+}
+
+// findQueueByPath returns the queue with the fully qualified path, nil if the queue does not exist.
+func findQueueByPath(root *Queue, queuePath string) *Queue {
+	parts := strings.Split(queuePath, configs.DOT)
+	if root == nil || len(parts) == 0 || parts[0] != configs.RootQueue {
+		return nil
+	}
+	queue := root
+	for _, part := range parts[1:] {
+		if queue = queue.GetChildQueue(part); queue == nil {
+			return nil
+		}
+	}
+	return queue
+}
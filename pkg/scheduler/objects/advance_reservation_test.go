@@ -0,0 +1,228 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func newAdvanceReservation(t *testing.T, name, queue string, nodes []string, size string, start, end time.Time) *AdvanceReservation {
+	reservation, err := NewAdvanceReservation(configs.AdvanceReservationConfig{
+		Name:      name,
+		Queue:     queue,
+		Nodes:     nodes,
+		Resources: map[string]string{"first": size},
+		Start:     start.Format(time.RFC3339),
+		End:       end.Format(time.RFC3339),
+	}, false)
+	assert.NilError(t, err, "advance reservation create failed")
+	return reservation
+}
+
+func TestNewAdvanceReservation(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	conf := configs.AdvanceReservationConfig{
+		Name:      "training",
+		Queue:     "root.Parent.Child",
+		Nodes:     []string{nodeID1},
+		Resources: map[string]string{"first": "5"},
+		Start:     start.Format(time.RFC3339),
+		End:       start.Add(time.Hour).Format(time.RFC3339),
+		Lead:      "30m",
+	}
+	reservation, err := NewAdvanceReservation(conf, true)
+	assert.NilError(t, err, "advance reservation create failed")
+	assert.Equal(t, reservation.QueuePath, "root.parent.child", "queue path should be lower case")
+	assert.DeepEqual(t, reservation.Nodes, []string{nodeID1})
+	assert.Assert(t, resources.Equals(reservation.Resource, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})))
+	assert.Assert(t, reservation.Start.Equal(start), "wrong start")
+	assert.Assert(t, reservation.End.Equal(start.Add(time.Hour)), "wrong end")
+	assert.Equal(t, reservation.Lead, 30*time.Minute, "wrong lead")
+	assert.Assert(t, reservation.FromConfig, "reservation should be from config")
+
+	conf.End = conf.Start
+	_, err = NewAdvanceReservation(conf, true)
+	assert.ErrorContains(t, err, "end must be after start")
+}
+
+func TestAdvanceReservationHolds(t *testing.T) {
+	now := time.Now()
+	reservation := &AdvanceReservation{Start: now.Add(2 * time.Hour), End: now.Add(4 * time.Hour), Lead: time.Hour}
+	testCases := []struct {
+		name        string
+		now         time.Time
+		expectedEnd time.Time
+		holds       bool
+	}{
+		{"unknown runtime before lead", now, time.Time{}, false},
+		{"unknown runtime within lead", now.Add(90 * time.Minute), time.Time{}, true},
+		{"unknown runtime in window", now.Add(3 * time.Hour), time.Time{}, true},
+		{"ends before start", now, now.Add(time.Hour), false},
+		{"ends in window", now, now.Add(3 * time.Hour), true},
+		{"placed in window", now.Add(3 * time.Hour), now.Add(200 * time.Minute), true},
+		{"window ended", now.Add(5 * time.Hour), time.Time{}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, reservation.holds(tc.now, tc.expectedEnd), tc.holds)
+		})
+	}
+}
+
+func TestAdvanceReservationAllows(t *testing.T) {
+	reservation := &AdvanceReservation{QueuePath: "root.a"}
+	assert.Assert(t, reservation.allows("root.a"), "queue itself should be allowed")
+	assert.Assert(t, reservation.allows("root.a.child"), "descendant should be allowed")
+	assert.Assert(t, !reservation.allows("root.ab"), "sibling with same prefix should not be allowed")
+	assert.Assert(t, !reservation.allows("root"), "parent should not be allowed")
+}
+
+func TestAdvanceReservations(t *testing.T) {
+	now := time.Now()
+	reservations := NewAdvanceReservations()
+	later := newAdvanceReservation(t, "later", "root.a", nil, "1", now.Add(2*time.Hour), now.Add(3*time.Hour))
+	sooner := newAdvanceReservation(t, "sooner", "root.a", nil, "1", now.Add(time.Hour), now.Add(3*time.Hour))
+	assert.NilError(t, reservations.Add(later))
+	assert.NilError(t, reservations.Add(sooner))
+	assert.ErrorContains(t, reservations.Add(later), "already exists")
+	assert.DeepEqual(t, reservationNames(reservations.GetReservations()), []string{"sooner", "later"})
+
+	// configured reservations are replaced, runtime reservations are kept
+	configured := newAdvanceReservation(t, "configured", "root.b", nil, "1", now.Add(time.Hour), now.Add(2*time.Hour))
+	configured.FromConfig = true
+	reservations.SetConfigured([]*AdvanceReservation{configured})
+	assert.DeepEqual(t, reservationNames(reservations.GetReservations()), []string{"configured", "sooner", "later"})
+	reservations.SetConfigured(nil)
+	assert.DeepEqual(t, reservationNames(reservations.GetReservations()), []string{"sooner", "later"})
+
+	assert.Assert(t, reservations.Remove("sooner"), "remove of existing reservation failed")
+	assert.Assert(t, !reservations.Remove("sooner"), "remove of unknown reservation should fail")
+	assert.DeepEqual(t, reservationNames(reservations.GetReservations()), []string{"later"})
+
+	// ended reservations are removed when a reservation is added
+	later.End = now.Add(-time.Minute)
+	assert.NilError(t, reservations.Add(sooner))
+	assert.DeepEqual(t, reservationNames(reservations.GetReservations()), []string{"sooner"})
+
+	var nilReservations *AdvanceReservations
+	assert.Assert(t, nilReservations.GetReservations() == nil, "nil reservations should return nil")
+	assert.Assert(t, nilReservations.fits(nil, "root.a", nil, nil, time.Time{}, now), "nil reservations should always fit")
+}
+
+func reservationNames(reservations []*AdvanceReservation) []string {
+	names := make([]string, 0, len(reservations))
+	for _, reservation := range reservations {
+		names = append(names, reservation.Name)
+	}
+	return names
+}
+
+// createAdvanceReservationTest creates a root queue with two leaf queues: root.a that has capacity booked and
+// root.b that runs other work, and a node with 10 "first" available.
+func createAdvanceReservationTest(t *testing.T) (*Queue, *Queue, *Queue, *Node) {
+	root, err := createRootQueue(map[string]string{"first": "10"})
+	assert.NilError(t, err, "root queue create failed")
+	queueA, err := createManagedQueue(root, "a", false, nil)
+	assert.NilError(t, err, "queue a create failed")
+	queueB, err := createManagedQueue(root, "b", false, nil)
+	assert.NilError(t, err, "queue b create failed")
+	return root, queueA, queueB, newNode(nodeID1, map[string]resources.Quantity{"first": 10})
+}
+
+func TestAdvanceReservationsFitsNode(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	root, queueA, _, node := createAdvanceReservationTest(t)
+	now := time.Now()
+	start := now.Add(2 * time.Hour)
+	reservations := NewAdvanceReservations()
+	assert.NilError(t, reservations.Add(newAdvanceReservation(t, "node", "root.a", []string{nodeID1}, "6", start, start.Add(time.Hour))))
+	ask := func(size resources.Quantity) *resources.Resource {
+		return resources.NewResourceFromMap(map[string]resources.Quantity{"first": size})
+	}
+	inWindow := start.Add(time.Minute)
+
+	assert.Assert(t, reservations.fits(root, "root.b", node, ask(4), inWindow, now), "unbooked capacity should fit")
+	assert.Assert(t, !reservations.fits(root, "root.b", node, ask(5), inWindow, now), "booked capacity should not fit")
+	assert.Assert(t, reservations.fits(root, "root.b", node, ask(5), start.Add(-time.Minute), now), "allocation ending before start should fit")
+	assert.Assert(t, reservations.fits(root, "root.b", node, ask(5), time.Time{}, now), "unknown runtime outside lead should fit")
+	assert.Assert(t, reservations.fits(root, "root.a", node, ask(8), inWindow, now), "booking queue should fit")
+	assert.Assert(t, reservations.fits(root, "root.b", newNode(nodeID2, map[string]resources.Quantity{"first": 10}), ask(8), inWindow, now), "other node should fit")
+
+	// capacity used by the booking queue is not kept free twice
+	app := newApplication(appID1, "default", "root.a")
+	app.SetQueue(queueA)
+	queueA.AddApplication(app)
+	alloc := newAllocation(appID1, nodeID1, ask(3))
+	app.AddAllocation(alloc)
+	node.AddAllocation(alloc)
+	assert.Assert(t, reservations.fits(root, "root.b", node, ask(4), inWindow, now), "unbooked capacity should fit")
+	assert.Assert(t, !reservations.fits(root, "root.b", node, ask(5), inWindow, now), "booked capacity should not fit")
+}
+
+func TestAdvanceReservationsFitsPartition(t *testing.T) {
+	root, queueA, _, node := createAdvanceReservationTest(t)
+	now := time.Now()
+	start := now.Add(time.Hour)
+	reservations := NewAdvanceReservations()
+	assert.NilError(t, reservations.Add(newAdvanceReservation(t, "queue", "root.a", nil, "6", start, start.Add(time.Hour))))
+	ask := func(size resources.Quantity) *resources.Resource {
+		return resources.NewResourceFromMap(map[string]resources.Quantity{"first": size})
+	}
+	inWindow := start.Add(time.Minute)
+
+	assert.Assert(t, reservations.fits(root, "root.b", node, ask(4), inWindow, now), "unbooked capacity should fit")
+	assert.Assert(t, !reservations.fits(root, "root.b", node, ask(5), inWindow, now), "booked capacity should not fit")
+	assert.Assert(t, reservations.fits(root, "root.a", node, ask(8), inWindow, now), "booking queue should fit")
+
+	assert.NilError(t, queueA.TryIncAllocatedResource(ask(3)))
+	assert.Assert(t, reservations.fits(root, "root.b", node, ask(4), inWindow, now), "unbooked capacity should fit")
+	assert.Assert(t, !reservations.fits(root, "root.b", node, ask(5), inWindow, now), "booked capacity should not fit")
+}
+
+func TestTryNodeAdvanceReservation(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	root, _, queueB, node := createAdvanceReservationTest(t)
+	reservations := NewAdvanceReservations()
+	// start within the default lead time: allocations with an unknown runtime are kept off
+	start := time.Now().Add(10 * time.Minute)
+	assert.NilError(t, reservations.Add(newAdvanceReservation(t, "node", "root.a", []string{nodeID1}, "8", start, start.Add(time.Hour))))
+	root.SetAdvanceReservations(reservations)
+
+	app := newApplication(appID1, "default", "root.b")
+	app.SetQueue(queueB)
+	queueB.AddApplication(app)
+	ask := newAllocationAsk(aKey, appID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5}))
+	assert.NilError(t, app.AddAllocationAsk(ask), "ask should have been added to app")
+	result, err := app.tryNode(node, ask)
+	assert.NilError(t, err)
+	assert.Assert(t, result == nil, "allocation should not use booked capacity")
+
+	assert.Assert(t, reservations.Remove("node"), "remove of reservation failed")
+	result, err = app.tryNode(node, ask)
+	assert.NilError(t, err)
+	assert.Assert(t, result != nil, "allocation should fit without reservation")
+}
@@ -62,6 +62,8 @@ type Allocation struct {
 	askEvents            *schedEvt.AskEvents
 	userQuotaCheckFailed bool
 	headroomCheckFailed  bool
+	appQuotaCheckFailed  bool
+	reserveBlockedUntil  time.Time // the allocation cannot reserve a node before this time
 
 	// Fields used once an allocation is bound
 	nodeID                string      // the node this allocation is bound to
@@ -415,6 +417,20 @@ func (a *Allocation) UpdatePreemptCheckTime() {
 	a.preemptCheckTime = time.Now()
 }
 
+// BlockReservation prevents the allocation from reserving a node until the given time.
+func (a *Allocation) BlockReservation(until time.Time) {
+	a.Lock()
+	defer a.Unlock()
+	a.reserveBlockedUntil = until
+}
+
+// canReserve returns true if the allocation is allowed to reserve a node at the given time.
+func (a *Allocation) canReserve(now time.Time) bool {
+	a.RLock()
+	defer a.RUnlock()
+	return !now.Before(a.reserveBlockedUntil)
+}
+
 // GetRequiredNode gets the node (if any) required by this allocation.
 func (a *Allocation) GetRequiredNode() string {
 	return a.requiredNode
@@ -586,6 +602,24 @@ func (a *Allocation) setUserQuotaCheckPassed() {
 	}
 }
 
+func (a *Allocation) setAppQuotaCheckFailed(reason string) {
+	a.Lock()
+	defer a.Unlock()
+	if !a.appQuotaCheckFailed {
+		a.appQuotaCheckFailed = true
+		a.askEvents.SendRequestExceedsApplicationQuota(a.allocationKey, a.applicationID, reason, a.allocatedResource)
+	}
+}
+
+func (a *Allocation) setAppQuotaCheckPassed() {
+	a.Lock()
+	defer a.Unlock()
+	if a.appQuotaCheckFailed {
+		a.appQuotaCheckFailed = false
+		a.askEvents.SendRequestFitsInApplicationQuota(a.allocationKey, a.applicationID, a.allocatedResource)
+	}
+}
+
 func (a *Allocation) IsForeign() bool {
 	return a.foreign
 }
@@ -108,6 +108,16 @@ func TestPreemptCheckTime(t *testing.T) {
 	assert.Check(t, now.Before(ask.GetPreemptCheckTime()), "preemptCheckTime was not current")
 }
 
+func TestBlockReservation(t *testing.T) {
+	ask := newAllocationAsk("ask1", "app1", resources.NewResource())
+	now := time.Now()
+	assert.Assert(t, ask.canReserve(now), "new allocation should be able to reserve")
+
+	ask.BlockReservation(now.Add(time.Minute))
+	assert.Assert(t, !ask.canReserve(now), "blocked allocation should not be able to reserve")
+	assert.Assert(t, ask.canReserve(now.Add(time.Minute)), "allocation should be able to reserve after the block")
+}
+
 func TestPlaceHolder(t *testing.T) {
 	siAsk := &si.Allocation{
 		AllocationKey: "ask1",
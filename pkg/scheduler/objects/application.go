@@ -41,6 +41,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
 	schedEvt "github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
+	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
 	"github.com/apache/yunikorn-core/pkg/scheduler/ugm"
 	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
@@ -61,6 +62,7 @@ const (
 
 	NotEnoughUserQuota  = "Not enough user quota"
 	NotEnoughQueueQuota = "Not enough queue quota"
+	NotEnoughAppQuota   = "Not enough application quota"
 )
 
 type PlaceholderData struct {
@@ -69,6 +71,7 @@ type PlaceholderData struct {
 	MinResource   *resources.Resource
 	Replaced      int64
 	TimedOut      int64
+	Stopped       int64
 }
 
 type StateLogEntry struct {
@@ -104,6 +107,7 @@ type Application struct {
 	stateTimer           *time.Timer                 // timer for state time
 	execTimeout          time.Duration               // execTimeout for the application run
 	placeholderTimer     *time.Timer                 // placeholder replace timer
+	deadlineTimer        *time.Timer                 // timer to check the deadline from the application tags
 	gangSchedulingStyle  string                      // gang scheduling style can be hard (after timeout we fail the application), or soft (after timeeout we schedule it as a normal application)
 	startTime            time.Time                   // the time that the application starts running. Default is zero.
 	finishedTime         time.Time                   // the time of finishing this application. the default value is zero time
@@ -114,6 +118,19 @@ type Application struct {
 	hasPlaceholderAlloc  bool                        // Whether there is at least one allocated placeholder
 	runnableInQueue      bool                        // whether the application is runnable/schedulable in the queue. Default is true.
 	runnableByUserLimit  bool                        // whether the application is runnable/schedulable based on user/group quota. Default is true.
+	pendingSince         time.Time                   // start of the current wait for pending asks, zero if nothing is waiting
+	maxWaitingTime       time.Duration               // longest completed wait for pending asks
+	priorityBoost        int32                       // temporary priority increase for a starved application
+	priorityBoostTime    time.Time                   // the time the priority boost was applied, zero if not boosted
+	runtimeEstimate      time.Duration               // declared runtime estimate from the application tags, zero if not set
+	deadline             time.Time                   // declared deadline from the application tags, zero if not set
+	deadlineMissed       bool                        // whether the deadline missed event has been sent
+	topologyMode         *policies.TopologyMode      // node topology mode from the application tags, nil if not set
+	preemptionBudget     *preemptionBudget           // preemption budget from the application tags, nil if not limited
+	quota                *applicationQuota           // quota from the application tags, nil if not limited
+	dependencies         map[string]string           // state of the upstream applications from the application tags by ID
+	dependencyTimeout    time.Duration               // time after submission the application fails waiting on its dependencies, 0 if not limited
+	suspendedRunning     bool                        // the application was running when suspended and keeps its running slot in the queue
 
 	rmEventHandler        handler.EventHandler
 	rmID                  string
@@ -121,8 +138,6 @@ type Application struct {
 	appEvents             *schedEvt.ApplicationEvents
 	sendStateChangeEvents bool // whether to send state-change events or not (simplifies testing)
 
-	// waitingTime    time.Duration
-	// maxWaitingTime time.Duration
 	locking.RWMutex
 }
 
@@ -164,6 +179,13 @@ func NewApplication(siApp *si.AddApplicationRequest, ugi security.UserGroup, eve
 	}
 	app.gangSchedulingStyle = gangSchedStyle
 	app.execTimeout = placeholderTimeout
+	app.runtimeEstimate = app.getDurationTag(common.AppTagRuntimeEstimate)
+	app.deadline = app.getTimeTag(common.AppTagDeadline)
+	app.topologyMode = app.getTopologyModeTag(common.AppTagTopologyMode)
+	app.preemptionBudget = app.getPreemptionBudgetTags()
+	app.quota = app.getQuotaTags()
+	app.dependencies = newDependencies(app.ApplicationID, app.GetTag(common.AppTagDependencies))
+	app.dependencyTimeout = app.getDurationTag(common.AppTagDependenciesTimeout)
 	app.user = ugi
 	app.rmEventHandler = eventHandler
 	app.rmID = rmID
@@ -252,6 +274,10 @@ func (sa *Application) IsResuming() bool {
 	return sa.stateMachine.Is(Resuming.String())
 }
 
+func (sa *Application) IsSuspended() bool {
+	return sa.stateMachine.Is(Suspended.String())
+}
+
 // HandleApplicationEvent handles the state event for the application.
 // The application lock is expected to be held.
 func (sa *Application) HandleApplicationEvent(event applicationEvent) error {
@@ -463,6 +489,17 @@ func (sa *Application) GetReservations() []string {
 	return keys
 }
 
+// GetReservationTimes returns the time each reservation of the application was made, keyed by reservation key.
+func (sa *Application) GetReservationTimes() map[string]time.Time {
+	sa.RLock()
+	defer sa.RUnlock()
+	times := make(map[string]time.Time, len(sa.reservations))
+	for key, reserve := range sa.reservations {
+		times[key] = reserve.GetCreateTime()
+	}
+	return times
+}
+
 // GetAllocationAsk returns the allocation alloc for the key, nil if not found
 func (sa *Application) GetAllocationAsk(allocationKey string) *Allocation {
 	sa.RLock()
@@ -541,7 +578,8 @@ func (sa *Application) removeAsksInternal(allocKey string, detail si.EventRecord
 		sa.requests = make(map[string]*Allocation)
 		sa.sortedRequests = sortedRequests{}
 		sa.askMaxPriority = configs.MinPriority
-		sa.queue.UpdateApplicationPriority(sa.ApplicationID, sa.askMaxPriority)
+		sa.clearPriorityBoost()
+		sa.queue.UpdateApplicationPriority(sa.ApplicationID, sa.getEffectivePriority())
 	} else {
 		// cleanup the reservation for this allocation
 		if reserve, ok := sa.reservations[allocKey]; ok {
@@ -559,20 +597,24 @@ func (sa *Application) removeAsksInternal(allocKey string, detail si.EventRecord
 			delete(sa.requests, allocKey)
 			sa.sortedRequests.remove(ask)
 			sa.appEvents.SendRemoveAskEvent(sa.ApplicationID, ask.allocationKey, ask.GetAllocatedResource(), detail)
-			if priority := ask.GetPriority(); priority >= sa.askMaxPriority {
+			// nothing left to wait for: a starvation boost is no longer needed
+			boostCleared := resources.IsZero(sa.pending) && sa.clearPriorityBoost()
+			if priority := ask.GetPriority(); boostCleared || priority >= sa.askMaxPriority {
 				sa.updateAskMaxPriority()
 			}
 		}
 	}
 	// clean up the queue pending resources
 	sa.queue.decPendingResource(deltaPendingResource)
+	sa.updateWaitingTime(false)
 	// Check if we need to change state based on the removal:
 	// 1) if pending is zero (no more asks left)
 	// 2) if confirmed allocations is zero (no real tasks running)
 	// Change the state to completing.
 	// When the resource trackers are zero we should not expect anything to come in later.
+	// A suspended application stays suspended, the state is updated on resume.
 	hasPlaceHolderAllocations := len(sa.getPlaceholderAllocations()) > 0
-	if resources.IsZero(sa.pending) && resources.IsZero(sa.allocatedResource) && !sa.IsFailing() && !sa.IsCompleting() && !hasPlaceHolderAllocations {
+	if resources.IsZero(sa.pending) && resources.IsZero(sa.allocatedResource) && !sa.IsFailing() && !sa.IsCompleting() && !sa.IsSuspended() && !hasPlaceHolderAllocations {
 		if err := sa.HandleApplicationEvent(CompleteApplication); err != nil {
 			log.Log(log.SchedApplication).Warn("Application state not changed to Completing while updating ask(s)",
 				zap.String("currentState", sa.CurrentState()),
@@ -624,6 +666,8 @@ func (sa *Application) AddAllocationAsk(ask *Allocation) error {
 	sa.pending = resources.Add(sa.pending, delta)
 	sa.pending.Prune()
 	sa.queue.incPendingResource(delta)
+	sa.updateWaitingTime(false)
+	sa.updateDeadlineState(time.Now())
 
 	log.Log(log.SchedApplication).Info("ask added successfully to application",
 		zap.String("appID", sa.ApplicationID),
@@ -683,6 +727,7 @@ func (sa *Application) UpdateAllocationResources(alloc *Allocation) error {
 		sa.pending = resources.Add(sa.pending, delta)
 		sa.pending.Prune()
 		sa.queue.incPendingResource(delta)
+		sa.updateWaitingTime(false)
 		log.Log(log.SchedApplication).Info("updated pending resources for application",
 			zap.String("appID", sa.ApplicationID),
 			zap.String("user", sa.user.User),
@@ -726,7 +771,7 @@ func (sa *Application) addAllocationAskInternal(ask *Allocation) {
 	priority := ask.GetPriority()
 	if !allocated && priority > sa.askMaxPriority {
 		sa.askMaxPriority = priority
-		sa.queue.UpdateApplicationPriority(sa.ApplicationID, sa.askMaxPriority)
+		sa.queue.UpdateApplicationPriority(sa.ApplicationID, sa.getEffectivePriority())
 	}
 
 	if ask.IsPlaceholder() {
@@ -757,7 +802,8 @@ func (sa *Application) allocateAsk(ask *Allocation) (*resources.Resource, error)
 		return nil, fmt.Errorf("unable to allocate previously allocated ask %s on app %s", ask.GetAllocationKey(), sa.ApplicationID)
 	}
 
-	if ask.GetPriority() >= sa.askMaxPriority {
+	// the application got an allocation: a starvation boost is no longer needed
+	if sa.clearPriorityBoost() || ask.GetPriority() >= sa.askMaxPriority {
 		// recalculate downward
 		sa.updateAskMaxPriority()
 	}
@@ -767,6 +813,8 @@ func (sa *Application) allocateAsk(ask *Allocation) (*resources.Resource, error)
 	sa.pending.Prune()
 	// update the pending of the queue with the same delta
 	sa.queue.decPendingResource(delta)
+	// the application got served: any wait restarts from now
+	sa.updateWaitingTime(true)
 
 	return delta, nil
 }
@@ -780,13 +828,14 @@ func (sa *Application) deallocateAsk(ask *Allocation) (*resources.Resource, erro
 	if askPriority > sa.askMaxPriority {
 		// increase app priority
 		sa.askMaxPriority = askPriority
-		sa.queue.UpdateApplicationPriority(sa.ApplicationID, askPriority)
+		sa.queue.UpdateApplicationPriority(sa.ApplicationID, sa.getEffectivePriority())
 	}
 
 	delta := ask.GetAllocatedResource()
 	sa.pending = resources.Add(sa.pending, delta)
 	// update the pending of the queue with the same delta
 	sa.queue.incPendingResource(delta)
+	sa.updateWaitingTime(false)
 
 	return delta, nil
 }
@@ -945,19 +994,34 @@ func (sa *Application) getOutstandingRequests(headRoom *resources.Resource, user
 	if sa.sortedRequests == nil {
 		return
 	}
+	// requests that do not fit in the application quota can never be allocated
+	quota := sa.getQuota()
+	if quota.expired(sa.SubmissionTime, time.Now()) {
+		return
+	}
+	appHeadRoom := quota.headroom(resources.Add(sa.allocatedResource, sa.allocatedPlaceholder))
+	allocationsLeft := quota.allocationsLeft(sa.countRealAllocations())
 	for _, request := range sa.sortedRequests {
 		if request.IsAllocated() || !request.IsSchedulingAttempted() {
 			continue
 		}
+		// placeholders do not count towards the maximum allocations
+		if allocationsLeft == 0 && !request.IsPlaceholder() {
+			continue
+		}
 
 		// ignore nil checks resource function calls are nil safe
-		if headRoom.FitInMaxUndef(request.GetAllocatedResource()) && userHeadRoom.FitInMaxUndef(request.GetAllocatedResource()) {
+		if headRoom.FitInMaxUndef(request.GetAllocatedResource()) && userHeadRoom.FitInMaxUndef(request.GetAllocatedResource()) && appHeadRoom.FitInMaxUndef(request.GetAllocatedResource()) {
 			if !request.HasTriggeredScaleUp() && request.requiredNode == common.Empty && !sa.canReplace(request) {
 				// if headroom is still enough for the resources
 				*total = append(*total, request)
 			}
 			headRoom = resources.SubOnlyExisting(headRoom, request.GetAllocatedResource())
 			userHeadRoom = resources.SubOnlyExisting(userHeadRoom, request.GetAllocatedResource())
+			appHeadRoom = resources.SubOnlyExisting(appHeadRoom, request.GetAllocatedResource())
+			if allocationsLeft > 0 && !request.IsPlaceholder() {
+				allocationsLeft--
+			}
 		}
 	}
 }
@@ -971,7 +1035,7 @@ func (sa *Application) canReplace(request *Allocation) bool {
 	}
 	// get the tracked placeholder data and check if there are still placeholder that can be replaced
 	if phData, ok := sa.placeholderData[request.GetTaskGroup()]; ok {
-		return phData.Count > (phData.Replaced + phData.TimedOut)
+		return phData.Count > (phData.Replaced + phData.TimedOut + phData.Stopped)
 	}
 	return false
 }
@@ -985,6 +1049,8 @@ func (sa *Application) tryAllocate(headRoom *resources.Resource, allowPreemption
 	}
 	// calculate the users' headroom, includes group check which requires the applicationID
 	userHeadroom := ugm.GetUserManager().Headroom(sa.queuePath, sa.ApplicationID, sa.user)
+	quota := sa.getQuota()
+	now := time.Now()
 	// get all the requests from the app sorted in order
 	for _, request := range sa.sortedRequests {
 		if request.IsAllocated() {
@@ -994,6 +1060,13 @@ func (sa *Application) tryAllocate(headRoom *resources.Resource, allowPreemption
 		if sa.canReplace(request) {
 			continue
 		}
+		// the application quota is never changed by preemption
+		if reason := sa.checkQuota(quota, request, now); reason != "" {
+			request.LogAllocationFailure(NotEnoughAppQuota, true) // error message MUST be constant!
+			request.setAppQuotaCheckFailed(reason)
+			continue
+		}
+		request.setAppQuotaCheckPassed()
 		// check if this fits in the users' headroom first, if that fits check the queues' headroom
 		// NOTE: preemption most likely will not help in this case. The chance that preemption helps is mall
 		// as the preempted allocation must be for the same user in a different queue in the hierarchy...
@@ -1039,6 +1112,7 @@ func (sa *Application) tryAllocate(headRoom *resources.Resource, allowPreemption
 
 		iterator := nodeIterator()
 		if iterator != nil {
+			iterator = sa.topologyIterator(iterator, getNodeFn)
 			if result := sa.tryNodes(request, iterator); result != nil {
 				// have a candidate return it
 				return result
@@ -1157,6 +1231,8 @@ func (sa *Application) tryPlaceholderAllocate(nodeIterator func() NodeIterator,
 	// keep the first fits for later
 	var phFit *Allocation
 	var reqFit *Allocation
+	// a replacement adds a real allocation: the lifetime and the number of allocations of the quota apply
+	quotaReason := sa.checkReplaceQuota(sa.getQuota(), time.Now())
 	// get all the requests from the app sorted in order
 	for _, request := range sa.sortedRequests {
 		// skip placeholders they follow standard allocation
@@ -1164,6 +1240,11 @@ func (sa *Application) tryPlaceholderAllocate(nodeIterator func() NodeIterator,
 		if request.IsPlaceholder() || request.GetTaskGroup() == "" || request.IsAllocated() {
 			continue
 		}
+		if quotaReason != "" {
+			request.LogAllocationFailure(NotEnoughAppQuota, true) // error message MUST be constant!
+			request.setAppQuotaCheckFailed(quotaReason)
+			continue
+		}
 		// walk over the placeholders, allow for processing all as we can have multiple task groups
 		phAllocs := sa.getPlaceholderAllocations()
 		for _, ph := range phAllocs {
@@ -1294,6 +1375,8 @@ func (sa *Application) tryReservedAllocate(headRoom *resources.Resource, nodeIte
 	defer sa.Unlock()
 	// calculate the users' headroom, includes group check which requires the applicationID
 	userHeadroom := ugm.GetUserManager().Headroom(sa.queuePath, sa.ApplicationID, sa.user)
+	quota := sa.getQuota()
+	now := time.Now()
 
 	// process all outstanding reservations and pick the first one that fits
 	for _, reserve := range sa.reservations {
@@ -1318,11 +1401,16 @@ func (sa *Application) tryReservedAllocate(headRoom *resources.Resource, nodeIte
 		if !sa.checkHeadRooms(ask, userHeadroom, headRoom) {
 			continue
 		}
+		if reason := sa.checkQuota(quota, ask, now); reason != "" {
+			ask.LogAllocationFailure(NotEnoughAppQuota, true) // error message MUST be constant!
+			ask.setAppQuotaCheckFailed(reason)
+			continue
+		}
 
 		// Do we need a specific node?
 		if ask.GetRequiredNode() != "" {
 			if !reserve.node.CanAllocate(ask.GetAllocatedResource()) && !ask.HasTriggeredPreemption() {
-				sa.tryRequiredNodePreemption(reserve, ask)
+				sa.tryRequiredNodePreemption(reserve, ask, headRoom, nodeIterator)
 				continue
 			}
 		}
@@ -1346,7 +1434,7 @@ func (sa *Application) tryReservedAllocate(headRoom *resources.Resource, nodeIte
 		}
 		iterator := nodeIterator()
 		if iterator != nil {
-			if !sa.checkHeadRooms(alloc, userHeadroom, headRoom) {
+			if !sa.checkHeadRooms(alloc, userHeadroom, headRoom) || sa.checkQuota(quota, alloc, now) != "" {
 				continue
 			}
 			result := sa.tryNodesNoReserve(alloc, iterator, reserve.nodeID)
@@ -1376,11 +1464,37 @@ func (sa *Application) tryPreemption(headRoom *resources.Resource, preemptionDel
 	return preemptor.TryPreemption()
 }
 
-func (sa *Application) tryRequiredNodePreemption(reserve *reservation, ask *Allocation) bool {
+// PreemptionDryRun runs the preemption checks for a pending ask of the application against the nodes of
+// the iterator. No allocations are preempted and the state of the ask and the application is not changed:
+// the preemptor only reads the application, a read lock is enough.
+func (sa *Application) PreemptionDryRun(allocationKey string, iterator NodeIterator) (*PreemptionDryRun, error) {
+	queue := sa.GetQueue()
+	if queue == nil {
+		return nil, fmt.Errorf("application %s is not assigned to a queue", sa.ApplicationID)
+	}
+	preemptionDelay := queue.getAskPreemptionDelay()
+	// use the same headroom as the scheduling cycle so the dry run matches a real preemption attempt
+	headRoom := queue.getHeadRoom()
+
+	sa.RLock()
+	defer sa.RUnlock()
+	ask := sa.requests[allocationKey]
+	if ask == nil {
+		return nil, fmt.Errorf("ask %s not found for application %s", allocationKey, sa.ApplicationID)
+	}
+	if ask.IsAllocated() {
+		return nil, fmt.Errorf("ask %s of application %s is already allocated", allocationKey, sa.ApplicationID)
+	}
+	preemptor := NewPreemptor(sa, headRoom, preemptionDelay, ask, iterator, true)
+	return preemptor.DryRun(), nil
+}
+
+func (sa *Application) tryRequiredNodePreemption(reserve *reservation, ask *Allocation, headRoom *resources.Resource, nodeIterator func() NodeIterator) bool {
 	// try preemption and see if we can free up resource
 	preemptor := NewRequiredNodePreemptor(reserve.node, ask)
 	preemptor.filterAllocations()
 	preemptor.sortAllocations()
+	preemptor.setBudgets(sa.victimBudgets(time.Now()))
 
 	// Are there any victims/asks to preempt?
 	victims := preemptor.GetVictims()
@@ -1388,22 +1502,81 @@ func (sa *Application) tryRequiredNodePreemption(reserve *reservation, ask *Allo
 		log.Log(log.SchedApplication).Info("Found victims for required node preemption",
 			zap.String("ds allocation key", ask.GetAllocationKey()),
 			zap.Int("no.of victims", len(victims)))
+		record := newPreemptionRecord(ask, sa.queuePath, reserve.node.NodeID, true)
 		for _, victim := range victims {
 			if victimQueue := sa.queue.FindQueueByAppID(victim.GetApplicationID()); victimQueue != nil {
 				victimQueue.IncPreemptingResource(victim.GetAllocatedResource())
+				sa.recordVictimPreemption(victimQueue, victim)
+				record.addVictim(victim, victimQueue.QueuePath)
+			} else {
+				record.addVictim(victim, "")
 			}
 			victim.MarkPreempted()
 		}
+		sa.queue.recordPreemptionDecision(record)
 		ask.MarkTriggeredPreemption()
 		sa.notifyRMAllocationReleased(victims, si.TerminationType_PREEMPTED_BY_SCHEDULER,
 			"preempting allocations to free up resources to run daemon set ask: "+ask.GetAllocationKey())
 		return true
 	}
+	// no victims on the node: try to move allocations off the node if the queue allows it
+	if sa.queue.getCrossNodeDepth() > 0 && nodeIterator != nil {
+		if iterator := nodeIterator(); iterator != nil {
+			preemptor := NewPreemptor(sa, headRoom, 0, ask, iterator, false)
+			if preemptor.tryRequiredNodeCrossNode(reserve.nodeID) {
+				log.Log(log.SchedApplication).Info("Found cross node preemption plan for required node preemption",
+					zap.String("ds allocation key", ask.GetAllocationKey()),
+					zap.String("nodeID", reserve.nodeID))
+				return true
+			}
+		}
+	}
 	ask.LogAllocationFailure(common.NoVictimForRequiredNode, true)
 	ask.SendRequiredNodePreemptionFailedEvent(reserve.node.NodeID)
 	return false
 }
 
+// victimBudgets returns a function that returns the preemption budgets left for the queue and the application of a
+// victim. The budgets are looked up once per queue and application so the victims are tracked against the same
+// headroom.
+// NOTE: this is a lock free call. It must only be called holding the application lock.
+func (sa *Application) victimBudgets(now time.Time) func(*Allocation) []*budgetHeadroom {
+	queueBudgets := make(map[string]*budgetHeadroom)
+	appBudgets := make(map[string]*budgetHeadroom)
+	return func(victim *Allocation) []*budgetHeadroom {
+		appID := victim.GetApplicationID()
+		victimQueue := sa.queue.FindQueueByAppID(appID)
+		if victimQueue == nil {
+			return nil
+		}
+		queueBudget, ok := queueBudgets[victimQueue.QueuePath]
+		if !ok {
+			queueBudget = victimQueue.getPreemptionBudget().headroom(now, victimQueue.GetAllocatedResource())
+			queueBudgets[victimQueue.QueuePath] = queueBudget
+		}
+		appBudget, ok := appBudgets[appID]
+		if !ok {
+			if appID == sa.ApplicationID {
+				appBudget = sa.preemptionBudget.headroom(now, sa.allocatedResource)
+			} else if app := victimQueue.GetApplication(appID); app != nil {
+				appBudget = app.preemptionBudget.headroom(now, app.GetAllocatedResource())
+			}
+			appBudgets[appID] = appBudget
+		}
+		return []*budgetHeadroom{queueBudget, appBudget}
+	}
+}
+
+// recordVictimPreemption registers the victim against the preemption budgets of its queue and application.
+// NOTE: this is a lock free call. It must only be called holding the application lock.
+func (sa *Application) recordVictimPreemption(victimQueue *Queue, victim *Allocation) {
+	if victim.GetApplicationID() == sa.ApplicationID {
+		victimQueue.recordAppPreemption(victim, sa, sa.allocatedResource)
+		return
+	}
+	victimQueue.recordPreemption(victim)
+}
+
 // tryNodesNoReserve tries all the nodes for a reserved request that have not been tried yet.
 // This should never result in a reservation as the allocation is already reserved
 func (sa *Application) tryNodesNoReserve(ask *Allocation, iterator NodeIterator, reservedNode string) *AllocationResult {
@@ -1435,6 +1608,33 @@ func (sa *Application) tryNodesNoReserve(ask *Allocation, iterator NodeIterator,
 	return allocResult
 }
 
+// topologyIterator returns an iterator that places the allocations of the application based on the topology
+// of the nodes. The mode set in the application tags overrides the mode of the topology.
+// The iterator is returned unchanged if the nodes have no topology or the topology is ignored.
+// NOTE: this is a lock free call. It must only be called holding the application lock.
+func (sa *Application) topologyIterator(iterator NodeIterator, getNodeFn func(string) *Node) NodeIterator {
+	ti, ok := iterator.(TopologyNodeIterator)
+	if !ok || getNodeFn == nil {
+		return iterator
+	}
+	topology := ti.GetNodeTopology()
+	if topology == nil {
+		return iterator
+	}
+	mode := topology.Mode
+	if sa.topologyMode != nil {
+		mode = *sa.topologyMode
+	}
+	if mode == policies.NoTopologyMode {
+		return iterator
+	}
+	nodeIDs := make([]string, 0, len(sa.allocations))
+	for _, alloc := range sa.allocations {
+		nodeIDs = append(nodeIDs, alloc.GetNodeID())
+	}
+	return newTopologyIterator(iterator, topology, mode, nodeIDs, getNodeFn)
+}
+
 // Try all the nodes for a request. The resultType is an allocation or reservation of a node.
 // New allocations can only be reserved after a delay.
 func (sa *Application) tryNodes(ask *Allocation, iterator NodeIterator) *AllocationResult {
@@ -1494,7 +1694,7 @@ func (sa *Application) tryNodes(ask *Allocation, iterator NodeIterator) *Allocat
 		}
 		// nothing allocated should we look at a reservation?
 		askAge := time.Since(ask.GetCreateTime())
-		if reserved == nil && askAge > reservationDelay {
+		if reserved == nil && askAge > reservationDelay && ask.canReserve(time.Now()) {
 			log.Log(log.SchedApplication).Debug("app reservation check",
 				zap.String("allocationKey", allocKey),
 				zap.Time("createTime", ask.GetCreateTime()),
@@ -1546,6 +1746,10 @@ func (sa *Application) tryNode(node *Node, ask *Allocation) (*AllocationResult,
 		// skip schedule onto node
 		return nil, nil
 	}
+	// skip the node if the allocation would use capacity booked for another queue
+	if !sa.queue.fitsAdvanceReservations(node, toAllocate, sa.expectedEnd(time.Now())) {
+		return nil, nil
+	}
 	// skip the node if conditions can not be satisfied
 	if err := node.preAllocateConditions(ask); err != nil {
 		return nil, err
@@ -1828,12 +2032,16 @@ func (sa *Application) removeAllocationInternal(allocationKey string, releaseTyp
 	// update correct allocation tracker
 	if alloc.IsPlaceholder() {
 		// make sure we account for the placeholders being removed in the tracking data
-		// update based on termination type: everything is counted as a timeout except for a real replace
+		// update based on termination type: everything is counted as a timeout except for a real replace and a
+		// placeholder stopped by the scheduler without a timeout, like on application suspend
 		if sa.placeholderData != nil {
 			if phData, ok := sa.placeholderData[alloc.taskGroupName]; ok {
-				if releaseType == si.TerminationType_PLACEHOLDER_REPLACED {
+				switch {
+				case releaseType == si.TerminationType_PLACEHOLDER_REPLACED:
 					phData.Replaced++
-				} else {
+				case releaseType == si.TerminationType_STOPPED_BY_RM && alloc.IsReleased():
+					phData.Stopped++
+				default:
 					phData.TimedOut++
 				}
 			}
@@ -1857,6 +2065,10 @@ func (sa *Application) removeAllocationInternal(allocationKey string, releaseTyp
 					event = RunApplication
 					removeApp = false
 				}
+				// a suspended application stays suspended, the state is updated on resume
+				if sa.IsSuspended() {
+					event = EventNotNeeded
+				}
 				eventWarning = "Application state not changed while removing a placeholder allocation"
 			}
 		}
@@ -1876,9 +2088,21 @@ func (sa *Application) removeAllocationInternal(allocationKey string, releaseTyp
 			removeApp = true
 			event = CompleteApplication
 			eventWarning = "Application state not changed to Completing while removing an allocation"
+			if sa.IsFailing() && resources.IsZero(sa.allocatedPlaceholder) {
+				event = FailApplication
+				eventWarning = "Application state not changed to Failed while removing an allocation"
+			}
+			// a suspended application stays suspended, the state is updated on resume
+			if sa.IsSuspended() {
+				event = EventNotNeeded
+			}
 		}
 		sa.decUserResourceUsage(alloc.GetAllocatedResource(), removeApp)
 	}
+	// a suspended application without allocations does not run anymore
+	if sa.IsSuspended() && resources.IsZero(sa.allocatedResource) && resources.IsZero(sa.allocatedPlaceholder) {
+		sa.releaseSuspendedRunning()
+	}
 	if event != EventNotNeeded {
 		if err := sa.HandleApplicationEvent(event); err != nil {
 			log.Log(log.SchedApplication).Warn(eventWarning,
@@ -1901,7 +2125,7 @@ func (sa *Application) updateAskMaxPriority() {
 		value = max(value, v.GetPriority())
 	}
 	sa.askMaxPriority = value
-	sa.queue.UpdateApplicationPriority(sa.ApplicationID, value)
+	sa.queue.UpdateApplicationPriority(sa.ApplicationID, sa.getEffectivePriority())
 }
 
 func (sa *Application) hasZeroAllocations() bool {
@@ -2079,6 +2303,87 @@ func (sa *Application) GetAskMaxPriority() int32 {
 	return sa.askMaxPriority
 }
 
+// GetEffectivePriority returns the priority used to sort the application: the highest priority of the
+// outstanding asks increased by the starvation boost, if any.
+func (sa *Application) GetEffectivePriority() int32 {
+	sa.RLock()
+	defer sa.RUnlock()
+	return sa.getEffectivePriority()
+}
+
+func (sa *Application) getEffectivePriority() int32 {
+	if sa.priorityBoost == 0 {
+		return sa.askMaxPriority
+	}
+	// do not wrap around
+	if sa.askMaxPriority > configs.MaxPriority-sa.priorityBoost {
+		return configs.MaxPriority
+	}
+	return sa.askMaxPriority + sa.priorityBoost
+}
+
+// GetPriorityBoost returns the starvation priority boost and the time it was applied.
+// The boost is 0 and the time is zero if the application is not boosted.
+func (sa *Application) GetPriorityBoost() (int32, time.Time) {
+	sa.RLock()
+	defer sa.RUnlock()
+	return sa.priorityBoost, sa.priorityBoostTime
+}
+
+// BoostPriority temporarily increases the priority of a starved application. The boost is removed when
+// the application gets an allocation or has no outstanding asks left.
+// Returns true if the boost was applied, false if the application was already boosted or has nothing pending.
+func (sa *Application) BoostPriority(boost int32, waited time.Duration) bool {
+	sa.Lock()
+	defer sa.Unlock()
+	if boost <= 0 || sa.priorityBoost != 0 || !resources.StrictlyGreaterThanZero(sa.pending) {
+		return false
+	}
+	sa.priorityBoost = boost
+	sa.priorityBoostTime = time.Now()
+	sa.queue.UpdateApplicationPriority(sa.ApplicationID, sa.getEffectivePriority())
+	log.Log(log.SchedApplication).Info("priority boosted for starved application",
+		zap.String("applicationID", sa.ApplicationID),
+		zap.Int32("boost", boost),
+		zap.Stringer("waited", waited))
+	sa.appEvents.SendPriorityBoostEvent(sa.ApplicationID, boost, waited)
+	return true
+}
+
+// clearPriorityBoost removes the starvation boost, the queue priority is not updated.
+// Returns true if the application was boosted.
+// NOTE: this is a lock free call. It must only be called holding the application lock.
+func (sa *Application) clearPriorityBoost() bool {
+	if sa.priorityBoost == 0 {
+		return false
+	}
+	log.Log(log.SchedApplication).Info("priority boost removed",
+		zap.String("applicationID", sa.ApplicationID),
+		zap.Int32("boost", sa.priorityBoost),
+		zap.Stringer("boosted", time.Since(sa.priorityBoostTime)))
+	sa.appEvents.SendPriorityRestoreEvent(sa.ApplicationID, sa.priorityBoost)
+	sa.priorityBoost = 0
+	sa.priorityBoostTime = time.Time{}
+	return true
+}
+
+// GetOldestPendingAskTime returns the creation time of the oldest outstanding ask,
+// zero if the application has no outstanding asks.
+func (sa *Application) GetOldestPendingAskTime() time.Time {
+	sa.RLock()
+	defer sa.RUnlock()
+	var oldest time.Time
+	for _, ask := range sa.requests {
+		if ask.IsAllocated() {
+			continue
+		}
+		if createTime := ask.GetCreateTime(); oldest.IsZero() || createTime.Before(oldest) {
+			oldest = createTime
+		}
+	}
+	return oldest
+}
+
 func (sa *Application) cleanupAsks() {
 	sa.requests = make(map[string]*Allocation)
 	sa.sortedRequests = nil
@@ -2244,6 +2549,149 @@ func (sa *Application) getUint64Tag(tag string) uint64 {
 	return uintValue
 }
 
+// GetRuntimeEstimate returns the runtime estimate that is set in the application tags.
+// Tags do not change after the application is created: no locking needed.
+func (sa *Application) GetRuntimeEstimate() time.Duration {
+	return sa.runtimeEstimate
+}
+
+// expectedEnd returns the time an allocation placed at the given time is expected to end, based on the runtime
+// estimate. A zero time is returned if the application has no runtime estimate.
+func (sa *Application) expectedEnd(now time.Time) time.Time {
+	if sa.runtimeEstimate <= 0 {
+		return time.Time{}
+	}
+	return now.Add(sa.runtimeEstimate)
+}
+
+// GetDeadline returns the deadline that is set in the application tags.
+// Tags do not change after the application is created: no locking needed.
+func (sa *Application) GetDeadline() time.Time {
+	return sa.deadline
+}
+
+// updateDeadlineState checks the deadline when the pending resources of the application are updated. If the
+// deadline has not passed a timer is started to check it when it passes.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) updateDeadlineState(now time.Time) {
+	if sa.deadline.IsZero() || sa.deadlineMissed {
+		return
+	}
+	if now.Before(sa.deadline) {
+		if sa.deadlineTimer == nil {
+			sa.deadlineTimer = time.AfterFunc(sa.deadline.Sub(now), func() {
+				sa.checkDeadline(time.Now())
+			})
+		}
+		return
+	}
+	sa.checkDeadlineInternal(now)
+}
+
+// clearDeadlineTimer stops the deadline timer of the application.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) clearDeadlineTimer() {
+	if sa == nil || sa.deadlineTimer == nil {
+		return
+	}
+	sa.deadlineTimer.Stop()
+	sa.deadlineTimer = nil
+}
+
+// checkDeadline sends a deadline missed event if the application has a deadline that has passed and still has
+// pending resources. The event is sent only once for the application. Returns true if the event was sent.
+func (sa *Application) checkDeadline(now time.Time) bool {
+	sa.Lock()
+	defer sa.Unlock()
+	return sa.checkDeadlineInternal(now)
+}
+
+// checkDeadlineInternal is the lock free version of checkDeadline.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) checkDeadlineInternal(now time.Time) bool {
+	if sa.deadline.IsZero() || now.Before(sa.deadline) || sa.deadlineMissed || !resources.StrictlyGreaterThanZero(sa.pending) {
+		return false
+	}
+	sa.deadlineMissed = true
+	log.Log(log.SchedApplication).Info("application deadline missed",
+		zap.String("appID", sa.ApplicationID),
+		zap.Time("deadline", sa.deadline))
+	sa.appEvents.SendDeadlineMissedEvent(sa.ApplicationID, sa.deadline)
+	return true
+}
+
+func (sa *Application) getTopologyModeTag(tag string) *policies.TopologyMode {
+	value := sa.GetTag(tag)
+	if value == "" {
+		return nil
+	}
+	mode, err := policies.TopologyModeFromString(value)
+	if err != nil {
+		log.Log(log.SchedApplication).Warn("application tag conversion failure",
+			zap.String("tag", tag),
+			zap.String("mode string", value),
+			zap.Error(err))
+		return nil
+	}
+	return &mode
+}
+
+func (sa *Application) getPreemptionBudgetTags() *preemptionBudget {
+	budget, err := newPreemptionBudget(sa.GetTag(common.AppTagPreemptionBudgetAllocations),
+		sa.GetTag(common.AppTagPreemptionBudgetPercentage), sa.GetTag(common.AppTagPreemptionBudgetWindow))
+	if err != nil {
+		log.Log(log.SchedApplication).Warn("application preemption budget tag conversion failure",
+			zap.String("appID", sa.ApplicationID),
+			zap.Error(err))
+		return nil
+	}
+	return budget
+}
+
+func (sa *Application) getQuotaTags() *applicationQuota {
+	quota, err := newApplicationQuota(sa.GetTag(common.AppTagMaxResource),
+		sa.GetTag(common.AppTagMaxAllocations), sa.GetTag(common.AppTagMaxLifetime))
+	if err != nil {
+		log.Log(log.SchedApplication).Warn("application quota tag conversion failure",
+			zap.String("appID", sa.ApplicationID),
+			zap.Error(err))
+		return nil
+	}
+	return quota
+}
+
+func (sa *Application) getDurationTag(tag string) time.Duration {
+	value := sa.GetTag(tag)
+	if value == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Log(log.SchedApplication).Warn("application tag conversion failure",
+			zap.String("tag", tag),
+			zap.String("duration string", value),
+			zap.Error(err))
+		return 0
+	}
+	return duration
+}
+
+func (sa *Application) getTimeTag(tag string) time.Time {
+	value := sa.GetTag(tag)
+	if value == "" {
+		return time.Time{}
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Log(log.SchedApplication).Warn("application tag conversion failure",
+			zap.String("tag", tag),
+			zap.String("time string", value),
+			zap.Error(err))
+		return time.Time{}
+	}
+	return timestamp
+}
+
 func (sa *Application) getResourceFromTags(tag string) *resources.Resource {
 	value := sa.GetTag(tag)
 	if value == "" {
@@ -2265,26 +2713,43 @@ func (sa *Application) getResourceFromTags(tag string) *resources.Resource {
 	return resource
 }
 
-// // UpdateWaitingTime 更新應用程式的等待時間
-// func (sa *Application) UpdateWaitingTime() {
-// 	sa.Lock()
-// 	defer sa.Unlock()
-// 	sa.waitingTime = time.Since(sa.SubmissionTime)
-// 	if sa.waitingTime > sa.maxWaitingTime {
-// 		sa.maxWaitingTime = sa.waitingTime
-// 	}
-// }
-
-// // GetWaitingTime 獲取應用程式的等待時間
-// func (sa *Application) GetWaitingTime() time.Duration {
-// 	sa.RLock()
-// 	defer sa.RUnlock()
-// 	return sa.waitingTime
-// }
-
-// // GetMaxWaitingTime 獲取應用程式的最大等待時間
-// func (sa *Application) GetMaxWaitingTime() time.Duration {
-// 	sa.RLock()
-// 	defer sa.RUnlock()
-// 	return sa.maxWaitingTime
-// }
+// updateWaitingTime tracks the time the application waits for pending asks to be allocated.
+// A wait starts when the application has pending resources and none is in progress. The wait
+// ends when no resources are pending anymore or when an allocation is made for the application.
+// Should be called after the pending resources have changed, holding the application lock.
+func (sa *Application) updateWaitingTime(allocated bool) {
+	now := time.Now()
+	if !sa.pendingSince.IsZero() && (allocated || !resources.StrictlyGreaterThanZero(sa.pending)) {
+		if waited := now.Sub(sa.pendingSince); waited > sa.maxWaitingTime {
+			sa.maxWaitingTime = waited
+		}
+		sa.pendingSince = time.Time{}
+	}
+	if sa.pendingSince.IsZero() && resources.StrictlyGreaterThanZero(sa.pending) {
+		sa.pendingSince = now
+	}
+}
+
+// GetWaitingTime returns how long the application has been waiting for its pending asks to be
+// allocated, since it last received an allocation. Returns 0 if nothing is pending.
+func (sa *Application) GetWaitingTime() time.Duration {
+	sa.RLock()
+	defer sa.RUnlock()
+	if sa.pendingSince.IsZero() {
+		return 0
+	}
+	return time.Since(sa.pendingSince)
+}
+
+// GetMaxWaitingTime returns the longest wait for pending asks of the application, including the
+// current wait.
+func (sa *Application) GetMaxWaitingTime() time.Duration {
+	sa.RLock()
+	defer sa.RUnlock()
+	if !sa.pendingSince.IsZero() {
+		if waited := time.Since(sa.pendingSince); waited > sa.maxWaitingTime {
+			return waited
+		}
+	}
+	return sa.maxWaitingTime
+}
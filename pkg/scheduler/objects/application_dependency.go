@@ -0,0 +1,165 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+// DependencyWaiting is the state of an upstream application that has not completed, failed or was rejected yet. An
+// upstream application that is not known in the partition is waiting too.
+const DependencyWaiting = "Waiting"
+
+// newDependencies creates the dependency states from the comma separated list of application IDs. All dependencies
+// start out waiting. A dependency on the application itself is ignored. Returns nil if there are no dependencies.
+func newDependencies(appID, value string) map[string]string {
+	var dependencies map[string]string
+	for _, id := range strings.Split(value, common.Separator) {
+		id = strings.TrimSpace(id)
+		if id == "" || id == appID {
+			continue
+		}
+		if dependencies == nil {
+			dependencies = make(map[string]string)
+		}
+		dependencies[id] = DependencyWaiting
+	}
+	return dependencies
+}
+
+// GetDependencyIDs returns the sorted IDs of the upstream applications.
+func (sa *Application) GetDependencyIDs() []string {
+	sa.RLock()
+	defer sa.RUnlock()
+	ids := make([]string, 0, len(sa.dependencies))
+	for id := range sa.dependencies {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// GetDependencies returns a copy of the state of the upstream applications by ID.
+func (sa *Application) GetDependencies() map[string]string {
+	sa.RLock()
+	defer sa.RUnlock()
+	return maps.Clone(sa.dependencies)
+}
+
+// IsWaitingOnDependencies returns true if not all upstream applications have completed. An application that is
+// waiting on its dependencies is not scheduled.
+func (sa *Application) IsWaitingOnDependencies() bool {
+	sa.RLock()
+	defer sa.RUnlock()
+	return sa.isWaitingOnDependencies()
+}
+
+// GetWaitingDependencyIDs returns the sorted IDs of the upstream applications that are still waiting.
+func (sa *Application) GetWaitingDependencyIDs() []string {
+	sa.RLock()
+	defer sa.RUnlock()
+	var ids []string
+	for id, state := range sa.dependencies {
+		if state == DependencyWaiting {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// isWaitingOnDependencies is the lock free version of IsWaitingOnDependencies.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) isWaitingOnDependencies() bool {
+	for _, state := range sa.dependencies {
+		if state != Completed.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateDependency records the state of an upstream application. Only the Completed, Failed and Rejected states are
+// recorded, the dependency keeps waiting for all other states. The application fails if the upstream application
+// failed or was rejected.
+func (sa *Application) UpdateDependency(appID, state string) {
+	if state != Completed.String() && state != Failed.String() && state != Rejected.String() {
+		return
+	}
+	sa.Lock()
+	defer sa.Unlock()
+	if current, ok := sa.dependencies[appID]; !ok || current != DependencyWaiting {
+		return
+	}
+	sa.dependencies[appID] = state
+	log.Log(log.SchedApplication).Info("application dependency updated",
+		zap.String("appID", sa.ApplicationID),
+		zap.String("dependency", appID),
+		zap.String("state", state))
+	if state == Completed.String() {
+		return
+	}
+	sa.failOnDependencies(fmt.Sprintf("dependency %s %s", appID, strings.ToLower(state)))
+}
+
+// CheckDependencyTimeout fails the application if it still waits on its dependencies after the dependency timeout
+// from the application tags has passed since the submission. Returns true if the application was failed.
+func (sa *Application) CheckDependencyTimeout(now time.Time) bool {
+	sa.Lock()
+	defer sa.Unlock()
+	if sa.dependencyTimeout <= 0 || now.Sub(sa.SubmissionTime) <= sa.dependencyTimeout || !sa.isWaitingOnDependencies() {
+		return false
+	}
+	return sa.failOnDependencies(fmt.Sprintf("dependencies not completed within %s", sa.dependencyTimeout))
+}
+
+// failOnDependencies fails the application with the reason. Returns true if the application was failed.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) failOnDependencies(reason string) bool {
+	// an application that is already failing releases its allocations first
+	if sa.IsFailing() {
+		return false
+	}
+	if err := sa.HandleApplicationEventWithInfo(FailApplication, reason); err != nil {
+		log.Log(log.SchedApplication).Debug("application not failed on dependency failure",
+			zap.String("appID", sa.ApplicationID),
+			zap.String("currentState", sa.CurrentState()),
+			zap.Error(err))
+		return false
+	}
+	// nothing left to release: move on to failed
+	if resources.IsZero(sa.allocatedResource) && resources.IsZero(sa.allocatedPlaceholder) {
+		if err := sa.HandleApplicationEvent(FailApplication); err != nil {
+			log.Log(log.SchedApplication).Warn("Application state not changed to Failed on dependency failure",
+				zap.String("currentState", sa.CurrentState()),
+				zap.Error(err))
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+)
+
+func TestNewDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{"empty", "", nil},
+		{"separators only", " , ,", nil},
+		{"self only", appID1, nil},
+		{"single", appID2, map[string]string{appID2: DependencyWaiting}},
+		{"multiple", " app-2 ,app-3,,app-1", map[string]string{appID2: DependencyWaiting, appID3: DependencyWaiting}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, newDependencies(appID1, tt.value), tt.expected)
+		})
+	}
+}
+
+func TestUpdateDependency(t *testing.T) {
+	app := newApplicationWithTags(appID1, "default", "root.default", map[string]string{common.AppTagDependencies: "app-2,app-3"})
+	assert.DeepEqual(t, app.GetDependencyIDs(), []string{appID2, appID3})
+	assert.Assert(t, app.IsWaitingOnDependencies(), "application should wait on dependencies")
+
+	// unknown dependencies and non terminal states are ignored
+	app.UpdateDependency("unknown", Completed.String())
+	app.UpdateDependency(appID2, Running.String())
+	assert.DeepEqual(t, app.GetDependencies(), map[string]string{appID2: DependencyWaiting, appID3: DependencyWaiting})
+
+	app.UpdateDependency(appID2, Completed.String())
+	assert.Assert(t, app.IsWaitingOnDependencies(), "application should still wait on app-3")
+	app.UpdateDependency(appID3, Completed.String())
+	assert.Assert(t, !app.IsWaitingOnDependencies(), "application should not wait on dependencies")
+	assert.Assert(t, app.IsNew(), "application state should not change: %s", app.CurrentState())
+
+	// a completed dependency cannot change anymore
+	app.UpdateDependency(appID2, Failed.String())
+	assert.Equal(t, app.GetDependencies()[appID2], Completed.String())
+	assert.Assert(t, app.IsNew(), "application state should not change: %s", app.CurrentState())
+}
+
+func TestUpdateDependencyFailed(t *testing.T) {
+	app := newApplicationWithTags(appID1, "default", "root.default", map[string]string{common.AppTagDependencies: appID2})
+	app.UpdateDependency(appID2, Rejected.String())
+	assert.Equal(t, app.GetDependencies()[appID2], Rejected.String())
+	assert.Assert(t, app.IsFailed(), "application without allocations should be failed: %s", app.CurrentState())
+	assert.Assert(t, app.IsWaitingOnDependencies(), "failed dependency should never be satisfied")
+}
+
+func TestCheckDependencyTimeout(t *testing.T) {
+	app := newApplicationWithTags(appID1, "default", "root.default", map[string]string{common.AppTagDependencies: appID2})
+	assert.Assert(t, !app.CheckDependencyTimeout(app.SubmissionTime.Add(time.Hour)), "application without timeout should not fail")
+	assert.Assert(t, app.IsNew(), "application state should not change: %s", app.CurrentState())
+
+	tags := map[string]string{common.AppTagDependencies: appID2, common.AppTagDependenciesTimeout: "10m"}
+	app = newApplicationWithTags(appID1, "default", "root.default", tags)
+	assert.Assert(t, !app.CheckDependencyTimeout(app.SubmissionTime.Add(5*time.Minute)), "application should not fail before the timeout")
+	assert.Assert(t, app.CheckDependencyTimeout(app.SubmissionTime.Add(11*time.Minute)), "application should fail after the timeout")
+	assert.Assert(t, app.IsFailed(), "application without allocations should be failed: %s", app.CurrentState())
+	assert.Assert(t, !app.CheckDependencyTimeout(app.SubmissionTime.Add(12*time.Minute)), "failed application should not fail again")
+
+	// completed dependencies do not time out
+	app = newApplicationWithTags(appID1, "default", "root.default", tags)
+	app.UpdateDependency(appID2, Completed.String())
+	assert.Assert(t, !app.CheckDependencyTimeout(app.SubmissionTime.Add(11*time.Minute)), "application should not wait on dependencies")
+	assert.Assert(t, app.IsNew(), "application state should not change: %s", app.CurrentState())
+}
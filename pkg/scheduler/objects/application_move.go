@@ -0,0 +1,276 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
+	"github.com/apache/yunikorn-core/pkg/scheduler/ugm"
+)
+
+// MoveQueue moves the application with all its asks and allocations to the target leaf queue. The submit ACL, the
+// maximum resources and running applications of the target queue hierarchy, and the user and group limits are
+// checked before anything is changed. Queues shared by the current and the target queue are not checked as the
+// usage of the application is already part of those queues.
+// The maximum resources are checked again while the usage is added to the target queues, a concurrent allocation
+// could have used the headroom after the first check.
+// If dryRun is set only the checks are performed.
+func (sa *Application) MoveQueue(target *Queue, dryRun bool) error {
+	sa.Lock()
+	defer sa.Unlock()
+	source := sa.queue
+	if err := sa.checkMoveQueue(source, target); err != nil {
+		return err
+	}
+	usage := resources.Add(sa.allocatedResource, sa.allocatedPlaceholder)
+	if dryRun {
+		return ugm.GetUserManager().MoveTrackedResource(sa.queuePath, target.QueuePath, sa.ApplicationID, usage, sa.user, true)
+	}
+	targetQueues := target.unsharedAncestors(source)
+	if err := sa.incMovedResource(targetQueues, usage); err != nil {
+		return err
+	}
+	if err := ugm.GetUserManager().MoveTrackedResource(sa.queuePath, target.QueuePath, sa.ApplicationID, usage, sa.user, false); err != nil {
+		for _, queue := range targetQueues {
+			queue.decMovedResource(usage)
+		}
+		return err
+	}
+
+	preempting := resources.NewResource()
+	for _, alloc := range sa.allocations {
+		if alloc.IsPreempted() {
+			preempting.AddTo(alloc.GetAllocatedResource())
+		}
+	}
+	running := sa.stateMachine.Is(Running.String())
+	allocatingAccepted := source.isAllocatingAccepted(sa.ApplicationID)
+
+	// remove the application from the current queue
+	source.removeMovedApplication(sa.ApplicationID)
+	source.UnReserve(sa.ApplicationID, len(sa.reservations))
+	source.decPendingResource(sa.pending)
+	for _, queue := range source.unsharedAncestors(target) {
+		queue.decMovedResource(usage)
+	}
+	if !resources.IsZero(preempting) {
+		source.DecPreemptingResource(preempting)
+	}
+	if running {
+		source.decRunningApps()
+	}
+	if allocatingAccepted {
+		source.clearAllocatingAccepted(sa.ApplicationID)
+	}
+
+	// add the application to the target queue
+	target.AddApplication(sa)
+	for range sa.reservations {
+		target.Reserve(sa.ApplicationID)
+	}
+	target.incPendingResource(sa.pending)
+	if !resources.IsZero(preempting) {
+		target.IncPreemptingResource(preempting)
+	}
+	if running {
+		target.incRunningApps(sa.ApplicationID)
+	}
+	if allocatingAccepted {
+		target.setAllocatingAccepted(sa.ApplicationID)
+	}
+	target.UpdateApplicationPriority(sa.ApplicationID, sa.getEffectivePriority())
+	sa.moveQueueMetrics(source.QueuePath, target.QueuePath)
+	sa.queuePath = target.QueuePath
+	sa.queue = target
+
+	log.Log(log.SchedApplication).Info("Application moved to queue",
+		zap.String("appID", sa.ApplicationID),
+		zap.String("fromQueue", source.QueuePath),
+		zap.String("toQueue", target.QueuePath),
+		zap.Stringer("allocated", usage))
+	return nil
+}
+
+// checkMoveQueue checks if the application can be moved from the source to the target queue.
+// Does not check the user and group limits.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) checkMoveQueue(source, target *Queue) error {
+	if source == nil {
+		return fmt.Errorf("application %s is not assigned to a queue", sa.ApplicationID)
+	}
+	if target == nil {
+		return fmt.Errorf("target queue for application %s does not exist", sa.ApplicationID)
+	}
+	if source == target {
+		return fmt.Errorf("application %s is already in queue %s", sa.ApplicationID, target.QueuePath)
+	}
+	if state := sa.stateMachine.Current(); state != New.String() && state != Accepted.String() && state != Running.String() {
+		return fmt.Errorf("application %s in state %s cannot be moved", sa.ApplicationID, state)
+	}
+	if !target.IsLeafQueue() || common.IsRecoveryQueue(target.QueuePath) {
+		return fmt.Errorf("queue %s is not a leaf queue that can run applications", target.QueuePath)
+	}
+	if !target.IsRunning() {
+		return fmt.Errorf("queue %s is not running", target.QueuePath)
+	}
+	if !target.CheckSubmitAccess(sa.user) {
+		return fmt.Errorf("user %s is not allowed to submit to queue %s", sa.user.User, target.QueuePath)
+	}
+	if !resources.IsZero(sa.placeholderAsk) && !target.SupportTaskGroup() {
+		return fmt.Errorf("queue %s cannot run application %s with task group request: unsupported sort type", target.QueuePath, sa.ApplicationID)
+	}
+	usage := resources.Add(sa.allocatedResource, sa.allocatedPlaceholder)
+	// only an application that is counted as running needs to fit in the maximum running applications
+	countsRunning := sa.stateMachine.Is(Running.String()) || source.isAllocatingAccepted(sa.ApplicationID)
+	for _, queue := range target.unsharedAncestors(source) {
+		if !queue.allocatedResFits(usage) {
+			return fmt.Errorf("allocated resources %s of application %s do not fit in the maximum of queue %s", usage, sa.ApplicationID, queue.QueuePath)
+		}
+		if countsRunning && !queue.canRunMovedApp() {
+			return fmt.Errorf("maximum running applications reached in queue %s", queue.QueuePath)
+		}
+	}
+	return nil
+}
+
+// incMovedResource adds the usage of the application to the queues. The maximum of each queue is checked while the
+// usage is added, the queues already updated are reverted if the usage does not fit.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) incMovedResource(queues []*Queue, usage *resources.Resource) error {
+	for i, queue := range queues {
+		if !queue.tryIncMovedResource(usage) {
+			for _, updated := range queues[:i] {
+				updated.decMovedResource(usage)
+			}
+			return fmt.Errorf("allocated resources %s of application %s do not fit in the maximum of queue %s", usage, sa.ApplicationID, queue.QueuePath)
+		}
+	}
+	return nil
+}
+
+// moveQueueMetrics moves the application state metrics from the source to the target queue.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) moveQueueMetrics(sourcePath, targetPath string) {
+	source := metrics.GetQueueMetrics(sourcePath)
+	target := metrics.GetQueueMetrics(targetPath)
+	switch sa.stateMachine.Current() {
+	case New.String():
+		source.DecQueueApplicationsNew()
+		target.IncQueueApplicationsNew()
+	case Accepted.String():
+		source.DecQueueApplicationsAccepted()
+		target.IncQueueApplicationsAccepted()
+	case Running.String():
+		source.DecQueueApplicationsRunning()
+		target.IncQueueApplicationsRunning()
+	}
+}
+
+// unsharedAncestors returns this queue and its ancestors that are not an ancestor of the other queue.
+func (sq *Queue) unsharedAncestors(other *Queue) []*Queue {
+	shared := make(map[*Queue]bool)
+	for queue := other; queue != nil; queue = queue.parent {
+		shared[queue] = true
+	}
+	var queues []*Queue
+	for queue := sq; queue != nil && !shared[queue]; queue = queue.parent {
+		queues = append(queues, queue)
+	}
+	return queues
+}
+
+// canRunMovedApp returns true if one more running application fits in the maximum running applications of this
+// queue. Does not check the parent queues.
+func (sq *Queue) canRunMovedApp() bool {
+	sq.RLock()
+	defer sq.RUnlock()
+	if sq.maxRunningApps == 0 {
+		return true
+	}
+	running := sq.runningApps + uint64(len(sq.allocatingAcceptedApps)+1) //nolint: gosec
+	return running <= sq.maxRunningApps
+}
+
+// tryIncMovedResource adds the usage of a moved application to this queue if it fits in the maximum.
+// Does not update the parent queues.
+func (sq *Queue) tryIncMovedResource(usage *resources.Resource) bool {
+	if resources.IsZero(usage) {
+		return true
+	}
+	sq.Lock()
+	defer sq.Unlock()
+	if !sq.allocatedResFitsInternal(usage) {
+		return false
+	}
+	sq.allocatedResource = resources.Add(sq.allocatedResource, usage)
+	sq.updateAllocatedResourceMetrics()
+	sq.updateWaitingTime(true)
+	return true
+}
+
+// decMovedResource removes the usage of a moved application from this queue. Does not update the parent queues.
+func (sq *Queue) decMovedResource(usage *resources.Resource) {
+	if resources.IsZero(usage) {
+		return
+	}
+	sq.Lock()
+	defer sq.Unlock()
+	sq.allocatedResource = resources.Sub(sq.allocatedResource, usage)
+	sq.updateAllocatedResourceMetrics()
+	sq.allocatedResource.Prune()
+}
+
+// isAllocatingAccepted returns true if the application is tracked as an accepted application with allocations.
+func (sq *Queue) isAllocatingAccepted(appID string) bool {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.allocatingAcceptedApps[appID]
+}
+
+// clearAllocatingAccepted removes the tracking of an accepted application with allocations.
+// For this queue (recursively).
+func (sq *Queue) clearAllocatingAccepted(appID string) {
+	if sq == nil {
+		return
+	}
+	if sq.parent != nil {
+		sq.parent.clearAllocatingAccepted(appID)
+	}
+	sq.Lock()
+	defer sq.Unlock()
+	delete(sq.allocatingAcceptedApps, appID)
+}
+
+// removeMovedApplication removes an application that is moved to another queue. Unlike RemoveApplication the
+// resources tracked for the application are not changed.
+func (sq *Queue) removeMovedApplication(appID string) {
+	sq.Lock()
+	delete(sq.applications, appID)
+	delete(sq.appPriorities, appID)
+	priority := sq.recalculatePriority()
+	sq.Unlock()
+	sq.queueEvents.SendRemoveApplicationEvent(sq.QueuePath, appID)
+	sq.parent.UpdateQueuePriority(sq.Name, priority)
+}
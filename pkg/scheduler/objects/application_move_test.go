@@ -0,0 +1,155 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/common/security"
+	"github.com/apache/yunikorn-core/pkg/scheduler/ugm"
+)
+
+// createMoveTest creates a running application in root.source with one allocation and one pending ask:
+// root (max 100)
+// root.source
+// root.target (max 10)
+// root.target.leaf
+// root.denied (no submit access)
+func createMoveTest(t *testing.T) (*Application, map[string]*Queue) {
+	root, err := createRootQueue(map[string]string{"first": "100"})
+	assert.NilError(t, err)
+	source, err := createManagedQueue(root, "source", false, nil)
+	assert.NilError(t, err)
+	target, err := createManagedQueue(root, "target", true, map[string]string{"first": "10"})
+	assert.NilError(t, err)
+	leaf, err := createManagedQueue(target, "leaf", false, nil)
+	assert.NilError(t, err)
+	denied, err := createManagedQueue(root, "denied", false, nil)
+	assert.NilError(t, err)
+	for _, queue := range []*Queue{source, leaf} {
+		queue.submitACL, err = security.NewACL("*", false)
+		assert.NilError(t, err)
+	}
+
+	app := newApplication(appID1, "default", source.QueuePath)
+	app.SetQueue(source)
+	source.AddApplication(app)
+	assert.NilError(t, app.AddAllocationAsk(newAllocationAsk(aKey, appID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 3}))))
+	assert.NilError(t, app.handleApplicationEventWithLocking(RunApplication))
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	assert.NilError(t, source.TryIncAllocatedResource(res))
+	app.AddAllocation(newAllocation(appID1, nodeID1, res))
+	assert.Assert(t, app.IsRunning(), "application should be running")
+	return app, map[string]*Queue{"root": root, "source": source, "target": target, "leaf": leaf, "denied": denied}
+}
+
+func TestMoveQueueChecks(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	app, queues := createMoveTest(t)
+	assert.ErrorContains(t, app.MoveQueue(nil, false), "does not exist")
+	assert.ErrorContains(t, app.MoveQueue(queues["source"], false), "already in queue")
+	assert.ErrorContains(t, app.MoveQueue(queues["target"], false), "not a leaf queue")
+	assert.ErrorContains(t, app.MoveQueue(queues["denied"], false), "not allowed to submit")
+
+	queues["target"].maxResource = resources.NewResourceFromMap(map[string]resources.Quantity{"first": 4})
+	assert.ErrorContains(t, app.MoveQueue(queues["leaf"], false), "do not fit in the maximum of queue root.target")
+	queues["target"].maxResource = resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})
+
+	queues["target"].SetMaxRunningApps(1)
+	queues["target"].runningApps = 1
+	assert.ErrorContains(t, app.MoveQueue(queues["leaf"], false), "maximum running applications reached in queue root.target")
+	// an application that is not counted as running is not checked
+	newApp := newApplication(appID2, "default", queues["source"].QueuePath)
+	newApp.SetQueue(queues["source"])
+	queues["source"].AddApplication(newApp)
+	assert.NilError(t, newApp.MoveQueue(queues["leaf"], true))
+	// an accepted application with allocations is counted as running
+	queues["source"].setAllocatingAccepted(appID2)
+	assert.ErrorContains(t, newApp.MoveQueue(queues["leaf"], true), "maximum running applications reached in queue root.target")
+	queues["source"].clearAllocatingAccepted(appID2)
+	queues["target"].runningApps = 0
+	// the shared root is not checked: the application is already running in it
+	queues["root"].SetMaxRunningApps(1)
+
+	assert.NilError(t, app.MoveQueue(queues["leaf"], true))
+	assert.Equal(t, app.GetQueue(), queues["source"], "dry run should not move the application")
+	assert.Assert(t, queues["source"].GetApplication(appID1) != nil, "dry run should not remove the application")
+	assert.Assert(t, resources.IsZero(queues["leaf"].GetAllocatedResource()), "dry run should not move the usage")
+}
+
+func TestMoveQueueUsedHeadroom(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	app, queues := createMoveTest(t)
+	leaf := queues["leaf"]
+	target := queues["target"]
+	// the headroom is used by another allocation after the move checks passed
+	used := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 6})
+	assert.NilError(t, target.TryIncAllocatedResource(used))
+	usage := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	err := app.incMovedResource(leaf.unsharedAncestors(queues["source"]), usage)
+	assert.ErrorContains(t, err, "do not fit in the maximum of queue root.target")
+	assert.Assert(t, resources.IsZero(leaf.GetAllocatedResource()), "usage not reverted in the leaf")
+	assert.Assert(t, resources.Equals(target.GetAllocatedResource(), used), "usage changed in the parent")
+
+	assert.NilError(t, target.DecAllocatedResource(used))
+	assert.NilError(t, app.incMovedResource(leaf.unsharedAncestors(queues["source"]), usage))
+	assert.Assert(t, resources.Equals(leaf.GetAllocatedResource(), usage), "usage not added to the leaf")
+	assert.Assert(t, resources.Equals(target.GetAllocatedResource(), usage), "usage not added to the parent")
+}
+
+func TestMoveQueue(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	app, queues := createMoveTest(t)
+	source := queues["source"]
+	leaf := queues["leaf"]
+	allocated := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	pending := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 3})
+
+	assert.NilError(t, app.MoveQueue(leaf, false))
+	assert.Equal(t, app.GetQueue(), leaf, "application not moved")
+	assert.Equal(t, app.GetQueuePath(), "root.target.leaf", "application queue path not updated")
+	assert.Assert(t, source.GetApplication(appID1) == nil, "application not removed from the source")
+	assert.Assert(t, leaf.GetApplication(appID1) != nil, "application not added to the target")
+
+	assert.Assert(t, resources.IsZero(source.GetAllocatedResource()), "allocated not removed from the source")
+	assert.Assert(t, resources.IsZero(source.GetPendingResource()), "pending not removed from the source")
+	assert.Equal(t, source.runningApps, uint64(0), "running app not removed from the source")
+	for _, queue := range []*Queue{leaf, queues["target"], queues["root"]} {
+		assert.Assert(t, resources.Equals(queue.GetAllocatedResource(), allocated), "wrong allocated in %s", queue.QueuePath)
+		assert.Assert(t, resources.Equals(queue.GetPendingResource(), pending), "wrong pending in %s", queue.QueuePath)
+		assert.Equal(t, queue.runningApps, uint64(1), "wrong running apps in %s", queue.QueuePath)
+	}
+
+	usage := ugm.GetUserManager().GetUserTracker(app.user.User).GetResourceUsageDAOInfo().Queues
+	assert.DeepEqual(t, usage.ResourceUsage, allocated.DAOMap())
+	for _, child := range usage.Children {
+		if child.QueuePath == "root.target" {
+			assert.DeepEqual(t, child.ResourceUsage, allocated.DAOMap())
+			assert.DeepEqual(t, child.RunningApplications, []string{appID1})
+		} else {
+			assert.Equal(t, len(child.ResourceUsage), 0, "user usage left in %s", child.QueuePath)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// applicationQuota caps the resources, the number of concurrent allocations and the lifetime of an application.
+type applicationQuota struct {
+	maxResource    *resources.Resource // maximum allocated resources including placeholders, nil if not limited
+	maxAllocations uint64              // maximum number of concurrent allocations excluding placeholders, 0 if not limited
+	maxLifetime    time.Duration       // time after submission the application fails, 0 if not limited
+}
+
+// newApplicationQuota creates a quota from the string values of the configuration or the tags. Empty values are not
+// set. Returns nil if nothing is limited.
+func newApplicationQuota(maxResource, maxAllocations, maxLifetime string) (*applicationQuota, error) {
+	quota := &applicationQuota{}
+	if maxResource != "" {
+		value, err := resources.NewResourceFromString(maxResource)
+		if err != nil {
+			return nil, err
+		}
+		if !resources.StrictlyGreaterThanZero(value) {
+			return nil, fmt.Errorf("%s quantities must be larger than 0: %s", configs.ApplicationMaxResource, maxResource)
+		}
+		quota.maxResource = value
+	}
+	if maxAllocations != "" {
+		value, err := strconv.ParseUint(maxAllocations, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if value == 0 {
+			return nil, fmt.Errorf("%s must be positive: %s", configs.ApplicationMaxAllocations, maxAllocations)
+		}
+		quota.maxAllocations = value
+	}
+	if maxLifetime != "" {
+		value, err := time.ParseDuration(maxLifetime)
+		if err != nil {
+			return nil, err
+		}
+		if value <= 0 {
+			return nil, fmt.Errorf("%s must be positive: %s", configs.ApplicationMaxLifetime, maxLifetime)
+		}
+		quota.maxLifetime = value
+	}
+	if quota.maxResource == nil && quota.maxAllocations == 0 && quota.maxLifetime == 0 {
+		return nil, nil
+	}
+	return quota, nil
+}
+
+// merge returns the quota with the limits that are not set in this quota taken from the defaults.
+// Returns nil if neither quota limits anything.
+func (aq *applicationQuota) merge(defaults *applicationQuota) *applicationQuota {
+	if aq == nil {
+		return defaults
+	}
+	if defaults == nil {
+		return aq
+	}
+	merged := *aq
+	if merged.maxResource == nil {
+		merged.maxResource = defaults.maxResource
+	}
+	if merged.maxAllocations == 0 {
+		merged.maxAllocations = defaults.maxAllocations
+	}
+	if merged.maxLifetime == 0 {
+		merged.maxLifetime = defaults.maxLifetime
+	}
+	return &merged
+}
+
+// headroom returns the resources that can still be allocated given the current usage, nil if not limited.
+func (aq *applicationQuota) headroom(usage *resources.Resource) *resources.Resource {
+	if aq == nil || aq.maxResource == nil {
+		return nil
+	}
+	return resources.SubOnlyExisting(aq.maxResource, usage)
+}
+
+// allocationsLeft returns the number of allocations that can still be made given the current number of
+// allocations, negative if not limited.
+func (aq *applicationQuota) allocationsLeft(count int) int {
+	if aq == nil || aq.maxAllocations == 0 {
+		return -1
+	}
+	left := int(aq.maxAllocations) - count //nolint: gosec
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// expired returns true if the lifetime of an application submitted at the given time has passed.
+func (aq *applicationQuota) expired(submitted, now time.Time) bool {
+	return aq != nil && aq.maxLifetime > 0 && now.Sub(submitted) > aq.maxLifetime
+}
+
+// getQuota returns the quota of the application: the limits set in the application tags override the defaults set
+// on the queue of the application. Returns nil if the application is not limited.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) getQuota() *applicationQuota {
+	var defaults *applicationQuota
+	if sa.queue != nil {
+		defaults = sa.queue.getApplicationQuota()
+	}
+	return sa.quota.merge(defaults)
+}
+
+// checkQuota checks if the request fits in the quota of the application. Returns an empty string if it fits, the
+// reason the request does not fit otherwise.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) checkQuota(quota *applicationQuota, request *Allocation, now time.Time) string {
+	if quota == nil {
+		return ""
+	}
+	if quota.expired(sa.SubmissionTime, now) {
+		return fmt.Sprintf("maximum lifetime of %s passed", quota.maxLifetime)
+	}
+	// a placeholder is replaced by a real allocation: only count the real allocations
+	if !request.IsPlaceholder() && quota.allocationsLeft(sa.countRealAllocations()) == 0 {
+		return fmt.Sprintf("maximum of %d allocations reached", quota.maxAllocations)
+	}
+	headroom := quota.headroom(resources.Add(sa.allocatedResource, sa.allocatedPlaceholder))
+	if !headroom.FitInMaxUndef(request.GetAllocatedResource()) {
+		return fmt.Sprintf("available %s", headroom)
+	}
+	return ""
+}
+
+// checkReplaceQuota checks if a placeholder can be replaced by a real allocation within the quota of the application.
+// The replacement does not change the allocated resources: only the lifetime and the number of allocations are
+// checked. Returns an empty string if the replacement is allowed, the reason otherwise.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) checkReplaceQuota(quota *applicationQuota, now time.Time) string {
+	if quota == nil {
+		return ""
+	}
+	if quota.expired(sa.SubmissionTime, now) {
+		return fmt.Sprintf("maximum lifetime of %s passed", quota.maxLifetime)
+	}
+	if quota.allocationsLeft(sa.countRealAllocations()) == 0 {
+		return fmt.Sprintf("maximum of %d allocations reached", quota.maxAllocations)
+	}
+	return ""
+}
+
+// countRealAllocations returns the number of allocations of the application that are not placeholders. A placeholder
+// that is being replaced counts as the real allocation that replaces it.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) countRealAllocations() int {
+	count := len(sa.allocations)
+	for _, ph := range sa.getPlaceholderAllocations() {
+		if !ph.IsReleased() || ph.GetRelease() == nil {
+			count--
+		}
+	}
+	return count
+}
+
+// CheckLifetime fails the application if the maximum lifetime from the quota of the application has passed. The asks
+// of the application are removed and the allocations are released back to the RM. Returns the number of
+// reservations removed.
+func (sa *Application) CheckLifetime(now time.Time) int {
+	sa.Lock()
+	defer sa.Unlock()
+	quota := sa.getQuota()
+	if !quota.expired(sa.SubmissionTime, now) || !sa.stateMachine.Can(FailApplication.String()) || sa.IsFailing() {
+		return 0
+	}
+	message := fmt.Sprintf("maximum lifetime of %s passed", quota.maxLifetime)
+	if err := sa.HandleApplicationEventWithInfo(FailApplication, message); err != nil {
+		log.Log(log.SchedApplication).Warn("Application state not changed to Failing on lifetime expiry",
+			zap.String("currentState", sa.CurrentState()),
+			zap.Error(err))
+		return 0
+	}
+	sa.clearPlaceholderTimer()
+	toRelease := sa.removeAsksInternal("", si.EventRecord_REQUEST_TIMEOUT)
+	var released []*Allocation
+	for _, alloc := range sa.allocations {
+		if alloc.IsReleased() || alloc.IsPreempted() {
+			continue
+		}
+		alloc.SetReleased(true)
+		released = append(released, alloc)
+	}
+	log.Log(log.SchedApplication).Info("Application lifetime passed, failing application",
+		zap.String("appID", sa.ApplicationID),
+		zap.Duration("maxLifetime", quota.maxLifetime),
+		zap.Int("allocationsReleased", len(released)))
+	sa.notifyRMAllocationReleased(released, si.TerminationType_TIMEOUT, "releasing allocations on application lifetime expiry")
+	// nothing left to release: move on to failed
+	if resources.IsZero(sa.allocatedResource) && resources.IsZero(sa.allocatedPlaceholder) {
+		if err := sa.HandleApplicationEvent(FailApplication); err != nil {
+			log.Log(log.SchedApplication).Warn("Application state not changed to Failed on lifetime expiry",
+				zap.String("currentState", sa.CurrentState()),
+				zap.Error(err))
+		}
+	}
+	return toRelease
+}
+
+// getApplicationQuota returns the default quota of the applications in the queue, nil if not limited.
+func (sq *Queue) getApplicationQuota() *applicationQuota {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.appQuota
+}
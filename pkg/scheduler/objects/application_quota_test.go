@@ -0,0 +1,246 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+const quotaResource = `{"resources":{"first":{"value":5}}}`
+
+func TestNewApplicationQuota(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxResource    string
+		maxAllocations string
+		maxLifetime    string
+		expected       *applicationQuota
+		errMsg         string
+	}{
+		{"not limited", "", "", "", nil, ""},
+		{"all set", quotaResource, "2", "1h", &applicationQuota{maxResource: resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5}), maxAllocations: 2, maxLifetime: time.Hour}, ""},
+		{"allocations only", "", "3", "", &applicationQuota{maxAllocations: 3}, ""},
+		{"invalid resource", "first=5", "", "", nil, "invalid character"},
+		{"zero resource", `{"resources":{"first":{"value":0}}}`, "", "", nil, "quantities must be larger than 0"},
+		{"zero allocations", "", "0", "", nil, "must be positive"},
+		{"negative allocations", "", "-1", "", nil, "invalid syntax"},
+		{"zero lifetime", "", "", "0s", nil, "must be positive"},
+		{"invalid lifetime", "", "", "soon", nil, "invalid duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quota, err := newApplicationQuota(tt.maxResource, tt.maxAllocations, tt.maxLifetime)
+			if tt.errMsg != "" {
+				assert.ErrorContains(t, err, tt.errMsg)
+				return
+			}
+			assert.NilError(t, err)
+			if tt.expected == nil {
+				assert.Assert(t, quota == nil, "quota should not be set")
+				return
+			}
+			assert.Assert(t, resources.Equals(quota.maxResource, tt.expected.maxResource), "wrong max resource")
+			assert.Equal(t, quota.maxAllocations, tt.expected.maxAllocations)
+			assert.Equal(t, quota.maxLifetime, tt.expected.maxLifetime)
+		})
+	}
+}
+
+func TestApplicationQuotaMerge(t *testing.T) {
+	var quota *applicationQuota
+	assert.Assert(t, quota.merge(nil) == nil, "nil quotas should not be merged")
+	defaults := &applicationQuota{maxResource: resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5}), maxAllocations: 2}
+	assert.Equal(t, quota.merge(defaults), defaults)
+
+	quota = &applicationQuota{maxAllocations: 4, maxLifetime: time.Minute}
+	merged := quota.merge(defaults)
+	assert.Assert(t, resources.Equals(merged.maxResource, defaults.maxResource), "max resource should come from the defaults")
+	assert.Equal(t, merged.maxAllocations, uint64(4))
+	assert.Equal(t, merged.maxLifetime, time.Minute)
+	assert.Equal(t, quota.maxResource, (*resources.Resource)(nil), "merge should not change the quota")
+
+	assert.Assert(t, merged.headroom(nil) != nil, "headroom should be limited")
+	assert.Equal(t, merged.allocationsLeft(5), 0)
+	assert.Equal(t, (*applicationQuota)(nil).allocationsLeft(5), -1)
+	assert.Assert(t, !merged.expired(time.Now(), time.Now()), "quota should not be expired")
+	assert.Assert(t, merged.expired(time.Now().Add(-2*time.Minute), time.Now()), "quota should be expired")
+}
+
+func TestTryAllocateAppQuota(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 20})
+	iterator := getNodeIteratorFn(node)
+	getNode := func(nodeID string) *Node {
+		return node
+	}
+	root, err := createRootQueue(map[string]string{"first": "20"})
+	assert.NilError(t, err)
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err)
+	leaf.properties = map[string]string{configs.ApplicationMaxAllocations: "1"}
+	leaf.UpdateQueueProperties()
+
+	app := newApplicationWithTags(appID1, "default", leaf.QueuePath, map[string]string{common.AppTagMaxResource: quotaResource})
+	app.SetQueue(leaf)
+	leaf.AddApplication(app)
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2})
+	app.AddAllocation(newAllocation(appID1, nodeID1, res))
+	ask := newAllocationAsk(aKey, appID1, res)
+	assert.NilError(t, app.AddAllocationAsk(ask))
+
+	// queue default: one allocation
+	preemptionAttemptsRemaining := 0
+	result := app.tryAllocate(node.GetAvailableResource(), false, 0, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	assert.Assert(t, result == nil, "allocation should exceed the maximum allocations")
+	assert.Equal(t, ask.GetAllocationLog()[0].Message, NotEnoughAppQuota)
+
+	// tag overrides the default: placeholders do not count and resources still fit
+	app.AddAllocation(newPlaceholderAlloc(appID1, nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1}), "tg"))
+	app.quota.maxAllocations = 2
+	result = app.tryAllocate(node.GetAvailableResource(), false, 0, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	assert.Assert(t, result != nil && result.Request == ask, "allocation expected within the quota")
+
+	// resources do not fit in the maximum of the tag
+	ask.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"first": 4})
+	result = app.tryAllocate(node.GetAvailableResource(), false, 0, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	assert.Assert(t, result == nil, "allocation should exceed the maximum resource")
+	var total []*Allocation
+	ask.SetSchedulingAttempted(true)
+	app.getOutstandingRequests(nil, nil, &total)
+	assert.Equal(t, len(total), 0, "request outside the quota should not be outstanding")
+
+	// lifetime passed
+	ask.allocatedResource = res
+	app.quota.maxLifetime = time.Minute
+	app.SubmissionTime = time.Now().Add(-2 * time.Minute)
+	result = app.tryAllocate(node.GetAvailableResource(), false, 0, &preemptionAttemptsRemaining, iterator, iterator, getNode)
+	assert.Assert(t, result == nil, "allocation should exceed the maximum lifetime")
+}
+
+func TestTryPlaceholderAllocateAppQuota(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 20})
+	iterator := getNodeIteratorFn(node)
+	getNode := func(nodeID string) *Node {
+		return node
+	}
+	root, err := createRootQueue(map[string]string{"first": "20"})
+	assert.NilError(t, err)
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err)
+
+	// a gang of five placeholders with a maximum of two allocations
+	app := newApplicationWithTags(appID1, "default", leaf.QueuePath, map[string]string{common.AppTagMaxAllocations: "2"})
+	app.SetQueue(leaf)
+	leaf.AddApplication(app)
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	var asks []*Allocation
+	for i := 0; i < 5; i++ {
+		ph := newPlaceholderAlloc(appID1, nodeID1, res, tg1)
+		ph.allocationKey = fmt.Sprintf("ph-%d", i)
+		app.AddAllocation(ph)
+		app.addPlaceholderData(ph)
+		ask := newAllocationAskTG(fmt.Sprintf("alloc-%d", i), appID1, tg1, res)
+		ask.placeholder = false
+		assert.NilError(t, app.AddAllocationAsk(ask))
+		asks = append(asks, ask)
+	}
+
+	// replacements in progress count as real allocations
+	for i := 0; i < 2; i++ {
+		result := app.tryPlaceholderAllocate(iterator, getNode)
+		assert.Assert(t, result != nil && result.ResultType == Replaced, "replacement %d expected within the quota", i)
+	}
+	assert.Assert(t, app.tryPlaceholderAllocate(iterator, getNode) == nil, "replacement should exceed the maximum allocations")
+	for _, ask := range asks[2:] {
+		assert.Assert(t, !ask.IsAllocated(), "ask %s should not be allocated", ask.GetAllocationKey())
+		assert.Equal(t, ask.GetAllocationLog()[0].Message, NotEnoughAppQuota)
+	}
+
+	// lifetime passed
+	app.quota.maxAllocations = 5
+	app.quota.maxLifetime = time.Minute
+	app.SubmissionTime = time.Now().Add(-2 * time.Minute)
+	assert.Assert(t, app.tryPlaceholderAllocate(iterator, getNode) == nil, "replacement should exceed the maximum lifetime")
+	app.quota.maxLifetime = 0
+	assert.Assert(t, app.tryPlaceholderAllocate(iterator, getNode) != nil, "replacement expected without limits")
+}
+
+func TestCheckLifetime(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	root, err := createRootQueue(map[string]string{"first": "20"})
+	assert.NilError(t, err)
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err)
+	leaf.properties = map[string]string{configs.ApplicationMaxLifetime: "1m"}
+	leaf.UpdateQueueProperties()
+
+	app, rmProxy := newApplicationWithHandler(appID1, "default", leaf.QueuePath)
+	app.SetQueue(leaf)
+	leaf.AddApplication(app)
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2})
+	assert.NilError(t, app.AddAllocationAsk(newAllocationAsk(aKey, appID1, res)))
+	assert.NilError(t, leaf.TryIncAllocatedResource(res))
+	app.AddAllocation(newAllocationWithKey(aKey2, appID1, nodeID1, res))
+	assert.Assert(t, app.IsRunning(), "application should be running")
+
+	// lifetime not passed
+	assert.Equal(t, app.CheckLifetime(app.SubmissionTime.Add(time.Minute)), 0)
+	assert.Assert(t, app.IsRunning(), "application should still be running")
+
+	// lifetime passed: the asks are removed and the allocations are released
+	assert.Equal(t, app.CheckLifetime(app.SubmissionTime.Add(2*time.Minute)), 0)
+	assert.Assert(t, app.IsFailing(), "application should be failing: %s", app.CurrentState())
+	assert.Assert(t, resources.IsZero(app.GetPendingResource()), "asks should have been removed")
+	assert.Assert(t, app.GetAllocationAsk(aKey) == nil, "ask should have been removed")
+	released := make(map[string]si.TerminationType)
+	for _, event := range rmProxy.GetEvents() {
+		if releaseEvent, ok := event.(*rmevent.RMReleaseAllocationEvent); ok {
+			for _, alloc := range releaseEvent.ReleasedAllocations {
+				released[alloc.AllocationKey] = alloc.TerminationType
+			}
+		}
+	}
+	assert.DeepEqual(t, released, map[string]si.TerminationType{aKey2: si.TerminationType_TIMEOUT})
+
+	// the application fails when the released allocation is removed
+	app.RemoveAllocation(aKey2, si.TerminationType_TIMEOUT)
+	assert.Assert(t, app.IsFailed(), "application should have failed: %s", app.CurrentState())
+
+	// an application without allocations fails straight away
+	app2 := newApplication(appID2, "default", leaf.QueuePath)
+	app2.SetQueue(leaf)
+	leaf.AddApplication(app2)
+	assert.NilError(t, app2.AddAllocationAsk(newAllocationAsk(aKey3, appID2, res)))
+	assert.Equal(t, app2.CheckLifetime(app2.SubmissionTime.Add(2*time.Minute)), 0)
+	assert.Assert(t, app2.IsFailed(), "application should have failed: %s", app2.CurrentState())
+}
@@ -45,6 +45,8 @@ const (
 	FailApplication
 	ExpireApplication
 	ResumeApplication
+	SuspendApplication
+	UnsuspendApplication
 )
 
 const (
@@ -52,7 +54,7 @@ const (
 )
 
 func (ae applicationEvent) String() string {
-	return [...]string{"runApplication", "rejectApplication", "completeApplication", "failApplication", "expireApplication", "resumeApplication"}[ae]
+	return [...]string{"runApplication", "rejectApplication", "completeApplication", "failApplication", "expireApplication", "resumeApplication", "suspendApplication", "unsuspendApplication"}[ae]
 }
 
 // ----------------------------------
@@ -72,6 +74,7 @@ const (
 	Failed
 	Expired
 	Resuming
+	Suspended
 )
 
 var stateEvents = map[string]si.EventRecord_ChangeDetail{
@@ -85,10 +88,12 @@ var stateEvents = map[string]si.EventRecord_ChangeDetail{
 	Failed.String():     si.EventRecord_APP_FAILED,
 	Resuming.String():   si.EventRecord_APP_RESUMING,
 	Expired.String():    si.EventRecord_APP_EXPIRED,
+	// the scheduler interface has no change detail for a suspended application
+	Suspended.String(): si.EventRecord_DETAILS_NONE,
 }
 
 func (as applicationState) String() string {
-	return [...]string{"New", "Accepted", "Running", "Rejected", "Completing", "Completed", "Failing", "Failed", "Expired", "Resuming"}[as]
+	return [...]string{"New", "Accepted", "Running", "Rejected", "Completing", "Completed", "Failing", "Failed", "Expired", "Resuming", "Suspended"}[as]
 }
 
 func eventDesc() fsm.Events {
@@ -115,7 +120,7 @@ func eventDesc() fsm.Events {
 			Dst:  Completed.String(),
 		}, {
 			Name: FailApplication.String(),
-			Src:  []string{New.String(), Accepted.String(), Running.String()},
+			Src:  []string{New.String(), Accepted.String(), Running.String(), Suspended.String()},
 			Dst:  Failing.String(),
 		}, {
 			Name: FailApplication.String(),
@@ -125,6 +130,14 @@ func eventDesc() fsm.Events {
 			Name: ResumeApplication.String(),
 			Src:  []string{New.String(), Accepted.String()},
 			Dst:  Resuming.String(),
+		}, {
+			Name: SuspendApplication.String(),
+			Src:  []string{Accepted.String(), Running.String()},
+			Dst:  Suspended.String(),
+		}, {
+			Name: UnsuspendApplication.String(),
+			Src:  []string{Suspended.String()},
+			Dst:  Accepted.String(),
 		}, {
 			Name: ExpireApplication.String(),
 			Src:  []string{Completed.String(), Failed.String(), Rejected.String()},
@@ -178,7 +191,10 @@ func callbacks() fsm.Callbacks {
 		fmt.Sprintf("enter_%s", Accepted.String()): func(_ context.Context, event *fsm.Event) {
 			app := event.Args[0].(*Application) //nolint:errcheck
 			metrics.GetQueueMetrics(app.queuePath).IncQueueApplicationsAccepted()
-			metrics.GetSchedulerMetrics().IncTotalApplicationsAccepted()
+			// a resumed application was accepted before
+			if event.Src != Suspended.String() {
+				metrics.GetSchedulerMetrics().IncTotalApplicationsAccepted()
+			}
 		},
 		fmt.Sprintf("leave_%s", Accepted.String()): func(_ context.Context, event *fsm.Event) {
 			app := event.Args[0].(*Application) //nolint:errcheck
@@ -200,8 +216,13 @@ func callbacks() fsm.Callbacks {
 		fmt.Sprintf("enter_%s", Running.String()): func(_ context.Context, event *fsm.Event) {
 			if event.Src != Running.String() {
 				app := event.Args[0].(*Application) //nolint:errcheck
-				app.startTime = time.Now()
-				app.queue.incRunningApps(app.ApplicationID)
+				// a resumed application kept its running slot and start time while suspended
+				if app.suspendedRunning {
+					app.suspendedRunning = false
+				} else {
+					app.startTime = time.Now()
+					app.queue.incRunningApps(app.ApplicationID)
+				}
 				metrics.GetQueueMetrics(app.queuePath).IncQueueApplicationsRunning()
 				metrics.GetSchedulerMetrics().IncTotalApplicationsRunning()
 			}
@@ -209,7 +230,12 @@ func callbacks() fsm.Callbacks {
 		fmt.Sprintf("leave_%s", Running.String()): func(_ context.Context, event *fsm.Event) {
 			if event.Dst != Running.String() {
 				app := event.Args[0].(*Application) //nolint:errcheck
-				app.queue.decRunningApps()
+				// a suspended application with allocations keeps its running slot
+				if event.Dst == Suspended.String() {
+					app.suspendedRunning = true
+				} else {
+					app.queue.decRunningApps()
+				}
 				metrics.GetQueueMetrics(app.queuePath).DecQueueApplicationsRunning()
 				metrics.GetSchedulerMetrics().DecTotalApplicationsRunning()
 			}
@@ -224,6 +250,20 @@ func callbacks() fsm.Callbacks {
 			metrics.GetQueueMetrics(app.queuePath).DecQueueApplicationsResuming()
 			metrics.GetSchedulerMetrics().DecTotalApplicationsResuming()
 		},
+		fmt.Sprintf("enter_%s", Suspended.String()): func(_ context.Context, event *fsm.Event) {
+			app := event.Args[0].(*Application) //nolint:errcheck
+			metrics.GetQueueMetrics(app.queuePath).IncQueueApplicationsSuspended()
+			metrics.GetSchedulerMetrics().IncTotalApplicationsSuspended()
+		},
+		fmt.Sprintf("leave_%s", Suspended.String()): func(_ context.Context, event *fsm.Event) {
+			app := event.Args[0].(*Application) //nolint:errcheck
+			metrics.GetQueueMetrics(app.queuePath).DecQueueApplicationsSuspended()
+			metrics.GetSchedulerMetrics().DecTotalApplicationsSuspended()
+			// a resumed application runs again straight away if it has allocations
+			if event.Dst != Accepted.String() {
+				app.releaseSuspendedRunning()
+			}
+		},
 		fmt.Sprintf("enter_%s", Failing.String()): func(_ context.Context, event *fsm.Event) {
 			app := event.Args[0].(*Application) //nolint:errcheck
 			metrics.GetQueueMetrics(app.queuePath).IncQueueApplicationsFailing()
@@ -252,6 +292,7 @@ func callbacks() fsm.Callbacks {
 			app.setStateTimer(terminatedTimeout, app.stateMachine.Current(), ExpireApplication)
 			app.executeTerminatedCallback()
 			app.clearPlaceholderTimer()
+			app.clearDeadlineTimer()
 			app.cleanupAsks()
 		},
 		fmt.Sprintf("enter_%s", Failed.String()): func(_ context.Context, event *fsm.Event) {
@@ -260,6 +301,7 @@ func callbacks() fsm.Callbacks {
 			metrics.GetQueueMetrics(app.queuePath).IncQueueApplicationsFailed()
 			app.setStateTimer(terminatedTimeout, app.stateMachine.Current(), ExpireApplication)
 			app.executeTerminatedCallback()
+			app.clearDeadlineTimer()
 			app.cleanupAsks()
 		},
 	}
@@ -210,6 +210,37 @@ func TestFailedStateTransition(t *testing.T) {
 	assert.NilError(t, err, "App should be in Failed state")
 }
 
+func TestSuspendedStateTransition(t *testing.T) {
+	appInfo := newApplication("app-00001", "default", "root.a")
+	// new cannot be suspended
+	err := appInfo.HandleApplicationEvent(SuspendApplication)
+	assert.Assert(t, err != nil, "error expected new to suspended")
+
+	// accepted to suspended and back
+	err = appInfo.HandleApplicationEvent(RunApplication)
+	assert.NilError(t, err, "no error expected new to accepted")
+	err = appInfo.HandleApplicationEvent(SuspendApplication)
+	assert.NilError(t, err, "no error expected accepted to suspended")
+	assert.Assert(t, appInfo.IsSuspended(), "App should be in Suspended state")
+	err = appInfo.HandleApplicationEvent(RunApplication)
+	assert.Assert(t, err != nil, "error expected suspended to running")
+	err = appInfo.HandleApplicationEvent(UnsuspendApplication)
+	assert.NilError(t, err, "no error expected suspended to accepted")
+	assert.Assert(t, appInfo.IsAccepted(), "App should be in Accepted state")
+
+	// running to suspended
+	err = appInfo.HandleApplicationEvent(RunApplication)
+	assert.NilError(t, err, "no error expected accepted to running")
+	err = appInfo.HandleApplicationEvent(SuspendApplication)
+	assert.NilError(t, err, "no error expected running to suspended")
+	assert.Assert(t, appInfo.IsSuspended(), "App should be in Suspended state")
+
+	// suspended to failing
+	err = appInfo.HandleApplicationEvent(FailApplication)
+	assert.NilError(t, err, "no error expected suspended to failing")
+	assert.Assert(t, appInfo.IsFailing(), "App should be in Failing state")
+}
+
 func TestAppStateTransitionEvents(t *testing.T) {
 	events.Init()
 	eventSystem := events.GetEventSystem().(*events.EventSystemImpl) //nolint:errcheck
@@ -0,0 +1,124 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/scheduler/ugm"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// Suspend stops the scheduling of the application. The application stays in its queue with all its asks. The
+// reservations of the application are removed and the allocated placeholders are released: they cannot be replaced
+// while the application is suspended. If release is set the allocations of the application are released back to
+// the RM, otherwise the allocations keep running and the application keeps its running slot in the queue.
+// The return value is the number of reservations removed.
+func (sa *Application) Suspend(release bool, reason string) (int, error) {
+	sa.Lock()
+	defer sa.Unlock()
+	if err := sa.HandleApplicationEventWithInfo(SuspendApplication, reason); err != nil {
+		return 0, err
+	}
+	sa.clearPlaceholderTimer()
+	var toRelease int
+	for _, reserve := range sa.reservations {
+		toRelease += sa.unReserveInternal(reserve)
+	}
+	sa.queue.UnReserve(sa.ApplicationID, toRelease)
+
+	var placeholders, released []*Allocation
+	for _, alloc := range sa.allocations {
+		if alloc.IsReleased() || alloc.IsPreempted() {
+			continue
+		}
+		if alloc.IsPlaceholder() {
+			alloc.SetReleased(true)
+			placeholders = append(placeholders, alloc)
+		} else if release {
+			alloc.SetReleased(true)
+			released = append(released, alloc)
+		}
+	}
+	// the placeholders and allocations are stopped on request: they did not time out and are not preempted
+	sa.notifyRMAllocationReleased(placeholders, si.TerminationType_STOPPED_BY_RM, "releasing placeholders on application suspend")
+	sa.notifyRMAllocationReleased(released, si.TerminationType_STOPPED_BY_RM, "releasing allocations on application suspend")
+	log.Log(log.SchedApplication).Info("Application suspended",
+		zap.String("appID", sa.ApplicationID),
+		zap.String("queue", sa.queuePath),
+		zap.String("reason", reason),
+		zap.Int("reservationsRemoved", toRelease),
+		zap.Int("placeholdersReleased", len(placeholders)),
+		zap.Int("allocationsReleased", len(released)))
+	return toRelease, nil
+}
+
+// Resume puts a suspended application back in its queue for scheduling. An application that gave up its running slot
+// must fit in the maximum running applications of the queue hierarchy and the user and group limits. The application
+// is running again if it still has allocations, completing if it has no asks and allocations left.
+func (sa *Application) Resume() error {
+	sa.Lock()
+	defer sa.Unlock()
+	if !sa.IsSuspended() {
+		return fmt.Errorf("application %s in state %s cannot be resumed", sa.ApplicationID, sa.CurrentState())
+	}
+	if !sa.suspendedRunning && !sa.queue.canRunApp(sa.ApplicationID) {
+		return fmt.Errorf("maximum running applications reached in queue %s", sa.queuePath)
+	}
+	if !ugm.GetUserManager().CanRunApp(sa.queuePath, sa.ApplicationID, sa.user) {
+		return fmt.Errorf("user and group maximum applications reached in queue %s", sa.queuePath)
+	}
+	if err := sa.HandleApplicationEvent(UnsuspendApplication); err != nil {
+		return err
+	}
+	var event applicationEvent = EventNotNeeded
+	switch {
+	case !resources.IsZero(sa.allocatedResource) || !resources.IsZero(sa.allocatedPlaceholder):
+		event = RunApplication
+	case resources.IsZero(sa.pending):
+		event = CompleteApplication
+	}
+	if event != EventNotNeeded {
+		if err := sa.HandleApplicationEvent(event); err != nil {
+			log.Log(log.SchedApplication).Warn("Application state not changed while resuming",
+				zap.String("currentState", sa.CurrentState()),
+				zap.Stringer("event", event),
+				zap.Error(err))
+		}
+	}
+	log.Log(log.SchedApplication).Info("Application resumed",
+		zap.String("appID", sa.ApplicationID),
+		zap.String("queue", sa.queuePath),
+		zap.String("state", sa.CurrentState()))
+	return nil
+}
+
+// releaseSuspendedRunning gives up the running slot in the queue kept by a suspended application.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) releaseSuspendedRunning() {
+	if !sa.suspendedRunning {
+		return
+	}
+	sa.suspendedRunning = false
+	sa.queue.decRunningApps()
+}
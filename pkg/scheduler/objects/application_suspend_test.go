@@ -0,0 +1,149 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/rmproxy/rmevent"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func TestSuspendResume(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	root, err := createRootQueue(map[string]string{"first": "10"})
+	assert.NilError(t, err)
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err)
+	app, rmProxy := newApplicationWithHandler(appID1, "default", leaf.QueuePath)
+	app.SetQueue(leaf)
+	leaf.AddApplication(app)
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	ask := newAllocationAsk(aKey, appID1, res)
+	assert.NilError(t, app.AddAllocationAsk(ask))
+	assert.NilError(t, leaf.TryIncAllocatedResource(res))
+	app.AddAllocation(newAllocationWithKey(aKey2, appID1, nodeID1, res))
+	assert.Assert(t, app.IsRunning(), "application should be running")
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 5})
+	assert.NilError(t, app.Reserve(node, ask))
+	leaf.Reserve(appID1)
+
+	// suspend without release: reservations are removed, allocations are kept
+	num, err := app.Suspend(false, "maintenance")
+	assert.NilError(t, err)
+	assert.Equal(t, num, 1, "reservation should have been removed")
+	assert.Assert(t, app.IsSuspended(), "application should be suspended")
+	assert.Equal(t, len(app.GetReservations()), 0, "application should not have reservations")
+	assert.Assert(t, !node.IsReserved(), "node should not be reserved")
+	assert.Equal(t, len(leaf.GetReservedApps()), 0, "queue should not have reserved apps")
+	assert.Equal(t, leaf.runningApps, uint64(1), "suspended application with allocations should keep its running slot")
+	assert.Equal(t, len(leaf.sortApplications(false)), 0, "suspended application should not be scheduled")
+	assert.Assert(t, !app.allocations[aKey2].IsReleased(), "allocation should not be released")
+	_, err = app.Suspend(false, "")
+	assert.ErrorContains(t, err, "inappropriate in current state Suspended")
+
+	// resume with allocations left
+	assert.NilError(t, app.Resume())
+	assert.Assert(t, app.IsRunning(), "application should be running after resume")
+	assert.Equal(t, leaf.runningApps, uint64(1), "application should be running in the queue")
+	assert.Equal(t, len(leaf.sortApplications(false)), 1, "application should be scheduled")
+	assert.ErrorContains(t, app.Resume(), "cannot be resumed")
+
+	// suspend with release: the placeholders are always released
+	ph := newPlaceholderAlloc(appID1, nodeID1, res, "tg")
+	assert.NilError(t, leaf.TryIncAllocatedResource(res))
+	app.AddAllocation(ph)
+	_, err = app.Suspend(true, "")
+	assert.NilError(t, err)
+	assert.Assert(t, app.allocations[aKey2].IsReleased(), "allocation should be released")
+	assert.Assert(t, ph.IsReleased(), "placeholder should be released")
+	released := make(map[string]si.TerminationType)
+	for _, event := range rmProxy.GetEvents() {
+		if releaseEvent, ok := event.(*rmevent.RMReleaseAllocationEvent); ok {
+			for _, alloc := range releaseEvent.ReleasedAllocations {
+				released[alloc.AllocationKey] = alloc.TerminationType
+			}
+		}
+	}
+	assert.DeepEqual(t, released, map[string]si.TerminationType{
+		aKey2:                 si.TerminationType_STOPPED_BY_RM,
+		ph.GetAllocationKey(): si.TerminationType_STOPPED_BY_RM,
+	})
+
+	// the running slot is given up when the allocations are gone
+	app.RemoveAllocation(aKey2, si.TerminationType_STOPPED_BY_RM)
+	assert.Equal(t, leaf.runningApps, uint64(1), "placeholder still allocated")
+	app.RemoveAllocation(ph.GetAllocationKey(), si.TerminationType_STOPPED_BY_RM)
+	assert.Assert(t, app.IsSuspended(), "application should stay suspended when allocations are removed")
+	assert.Equal(t, leaf.runningApps, uint64(0), "suspended application without allocations should not be running")
+
+	// resume checks the running applications of the queue
+	leaf.SetMaxRunningApps(1)
+	leaf.runningApps = 1
+	assert.ErrorContains(t, app.Resume(), "maximum running applications reached in queue root.leaf")
+	assert.Assert(t, app.IsSuspended(), "application should stay suspended")
+	leaf.runningApps = 0
+
+	// resume without asks and allocations left
+	app.RemoveAllocationAsk(aKey)
+	assert.NilError(t, app.Resume())
+	assert.Assert(t, app.IsCompleting(), "application without asks and allocations should be completing")
+}
+
+func TestSuspendPlaceholders(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	root, err := createRootQueue(map[string]string{"first": "10"})
+	assert.NilError(t, err)
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err)
+	app, _ := newApplicationWithHandler(appID1, "default", leaf.QueuePath)
+	app.SetQueue(leaf)
+	leaf.AddApplication(app)
+
+	// a gang application with only an allocated placeholder left
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	assert.NilError(t, app.AddAllocationAsk(newAllocationAskTG(aKey, appID1, "tg", res)))
+	ph := newPlaceholderAlloc(appID1, nodeID1, res, "tg")
+	assert.NilError(t, leaf.TryIncAllocatedResource(res))
+	app.AddAllocation(ph)
+	app.RemoveAllocationAsk(aKey)
+	assert.NilError(t, app.handleApplicationEventWithLocking(RunApplication))
+	assert.Assert(t, app.IsRunning(), "application should be running")
+
+	_, err = app.Suspend(false, "")
+	assert.NilError(t, err)
+	assert.Assert(t, ph.IsReleased(), "placeholder should be released")
+
+	// removing the last placeholder does not change the state of the suspended application
+	app.RemoveAllocation(ph.GetAllocationKey(), si.TerminationType_STOPPED_BY_RM)
+	assert.Assert(t, app.IsSuspended(), "application should stay suspended")
+	assert.Equal(t, leaf.runningApps, uint64(0), "suspended application without allocations should not be running")
+	assertPlaceholderData(t, app, "tg", 1, 0, 0, res)
+	assert.Equal(t, app.placeholderData["tg"].Stopped, int64(1), "stopped count does not match")
+	assert.Assert(t, !app.canReplace(newAllocationAskAll(aKey2, appID1, "tg", res, false, 0)), "stopped placeholder should not be replaceable")
+
+	assert.NilError(t, app.Resume())
+	assert.Assert(t, app.IsCompleting(), "application without asks and allocations should be completing")
+}
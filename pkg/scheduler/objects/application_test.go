@@ -426,6 +426,47 @@ func TestAllocateDeallocate(t *testing.T) {
 	}
 }
 
+func TestWaitingTime(t *testing.T) {
+	app := newApplication(appID1, "default", "root.unknown")
+	queue, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	app.queue = queue
+	assert.Equal(t, app.GetWaitingTime(), time.Duration(0), "new app should not be waiting")
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	ask := newAllocationAsk(aKey, appID1, res)
+	err = app.AddAllocationAsk(ask)
+	assert.NilError(t, err, "ask should have been added to app")
+	assert.Assert(t, !app.pendingSince.IsZero(), "wait should have started when ask was added")
+
+	// fake a wait and allocate: wait ends as nothing is pending anymore
+	app.pendingSince = time.Now().Add(-time.Minute)
+	assert.Assert(t, app.GetWaitingTime() >= time.Minute, "app should have been waiting for at least a minute")
+	_, err = app.AllocateAsk(aKey)
+	assert.NilError(t, err, "ask should have been allocated")
+	assert.Equal(t, app.GetWaitingTime(), time.Duration(0), "allocated app should not be waiting")
+	assert.Assert(t, app.GetMaxWaitingTime() >= time.Minute, "max waiting time should be tracked")
+
+	// deallocate starts a new wait
+	_, err = app.DeallocateAsk(aKey)
+	assert.NilError(t, err, "ask should have been deallocated")
+	assert.Assert(t, !app.pendingSince.IsZero(), "wait should have started when ask was deallocated")
+
+	// allocate with more asks pending: wait restarts
+	ask2 := newAllocationAsk(aKey2, appID1, res)
+	err = app.AddAllocationAsk(ask2)
+	assert.NilError(t, err, "ask should have been added to app")
+	start := time.Now().Add(-time.Minute)
+	app.pendingSince = start
+	_, err = app.AllocateAsk(aKey)
+	assert.NilError(t, err, "ask should have been allocated")
+	assert.Assert(t, app.pendingSince.After(start), "wait should have restarted after allocation")
+
+	// removing all asks stops the wait
+	app.RemoveAllocationAsk("")
+	assert.Equal(t, app.GetWaitingTime(), time.Duration(0), "app without pending asks should not be waiting")
+}
+
 // test pending calculation and ask addition
 //
 //nolint:funlen
@@ -2416,6 +2457,60 @@ func TestMaxAskPriority(t *testing.T) {
 	assert.Equal(t, app.GetAskMaxPriority(), int32(15), "wrong priority after updating p=15 to unallocated")
 }
 
+func TestPriorityBoost(t *testing.T) {
+	app := newApplication(appID1, "default", "root.leaf")
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err, "queue create failed")
+	app.queue = leaf
+	leaf.AddApplication(app)
+
+	// nothing pending: no boost
+	assert.Assert(t, !app.BoostPriority(100, time.Minute), "app without pending asks should not be boosted")
+	assert.Assert(t, app.GetOldestPendingAskTime().IsZero(), "app without asks should not have a pending ask time")
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	ask1 := newAllocationAskPriority("prio-10", appID1, res, 10)
+	assert.NilError(t, app.AddAllocationAsk(ask1), "ask should have been added to app")
+	ask2 := newAllocationAskPriority("prio-5", appID1, res, 5)
+	ask2.createTime = ask1.GetCreateTime().Add(-time.Minute)
+	assert.NilError(t, app.AddAllocationAsk(ask2), "ask should have been added to app")
+	assert.Equal(t, app.GetOldestPendingAskTime(), ask2.GetCreateTime(), "oldest pending ask not found")
+	assert.Equal(t, app.GetEffectivePriority(), int32(10), "unboosted priority should be the max ask priority")
+
+	// boost is applied once and propagated to the queue
+	assert.Assert(t, !app.BoostPriority(0, time.Minute), "zero boost should not be applied")
+	assert.Assert(t, app.BoostPriority(100, time.Minute), "starved app should have been boosted")
+	assert.Assert(t, !app.BoostPriority(100, time.Minute), "boosted app should not be boosted again")
+	boost, boostTime := app.GetPriorityBoost()
+	assert.Equal(t, boost, int32(100), "wrong boost")
+	assert.Assert(t, !boostTime.IsZero(), "boost time should be set")
+	assert.Equal(t, app.GetAskMaxPriority(), int32(10), "boost should not change the max ask priority")
+	assert.Equal(t, app.GetEffectivePriority(), int32(110), "wrong boosted priority")
+	assert.Equal(t, leaf.GetCurrentPriority(), int32(110), "queue priority should include the boost")
+
+	// an allocation restores the priority
+	_, err = app.AllocateAsk(ask1.GetAllocationKey())
+	assert.NilError(t, err, "ask should have been allocated")
+	boost, boostTime = app.GetPriorityBoost()
+	assert.Equal(t, boost, int32(0), "boost should be removed after allocation")
+	assert.Assert(t, boostTime.IsZero(), "boost time should be reset")
+	assert.Equal(t, app.GetEffectivePriority(), int32(5), "wrong priority after allocation")
+	assert.Equal(t, leaf.GetCurrentPriority(), int32(5), "queue priority should be restored")
+
+	// boost does not overflow
+	app.askMaxPriority = configs.MaxPriority - 10
+	assert.Assert(t, app.BoostPriority(100, time.Minute), "starved app should have been boosted")
+	assert.Equal(t, app.GetEffectivePriority(), configs.MaxPriority, "boosted priority should be capped")
+
+	// removing the last pending ask restores the priority
+	app.RemoveAllocationAsk(ask2.GetAllocationKey())
+	boost, _ = app.GetPriorityBoost()
+	assert.Equal(t, boost, int32(0), "boost should be removed without pending asks")
+	assert.Equal(t, app.GetEffectivePriority(), configs.MinPriority, "wrong priority without pending asks")
+}
+
 func TestAskEvents(t *testing.T) {
 	app := newApplication(appID1, "default", "root.default")
 	// Create event system after new application to avoid new app event.
@@ -3138,6 +3233,8 @@ func TestRequiredNodePreemption(t *testing.T) {
 	childQ, err := createManagedQueue(rootQ, "default", false, map[string]string{"first": "20"})
 	assert.NilError(t, err)
 	app.SetQueue(childQ)
+	history := NewPreemptionHistory(10)
+	rootQ.SetPreemptionHistory(history)
 
 	// add an ask
 	mockEvents := mock.NewEventSystem()
@@ -3175,6 +3272,14 @@ func TestRequiredNodePreemption(t *testing.T) {
 	assert.Equal(t, 1, len(releaseEvents), "unexpected number of release events")
 	assert.Equal(t, 1, len(releaseEvents[0].ReleasedAllocations), "unexpected number of release allocations")
 	assert.Equal(t, "ask-1", releaseEvents[0].ReleasedAllocations[0].AllocationKey, "allocation key")
+	records := history.GetRecords("", time.Time{}, time.Time{})
+	assert.Equal(t, len(records), 1, "preemption decision not recorded")
+	assert.Equal(t, records[0].AllocationKey, "ask-2")
+	assert.Equal(t, records[0].NodeID, nodeID1)
+	assert.Assert(t, records[0].RequiredNode, "required node preemption not recorded")
+	assert.Equal(t, len(records[0].Victims), 1)
+	assert.Equal(t, records[0].Victims[0].AllocationKey, "ask-1")
+	assert.Assert(t, resources.Equals(records[0].Reclaimed, askRes), "reclaimed resources not recorded")
 
 	// 2nd attempt - no preemption this time
 	releaseEvents = nil
@@ -3650,3 +3755,140 @@ func TestTryNodesNoReserve(t *testing.T) {
 	assert.Equal(t, result.ResultType, AllocatedReserved, "result type should be AllocatedReserved")
 	assert.Equal(t, result.ReservedNodeID, node1.NodeID, "reserved node should be node1")
 }
+
+func TestTryNodesReservationBlocked(t *testing.T) {
+	SetReservationDelay(10 * time.Nanosecond)
+	defer SetReservationDelay(2 * time.Second)
+	app := newApplication(appID0, "default", "root.default")
+	queue, err := createRootQueue(map[string]string{"first": "10"})
+	assert.NilError(t, err, "queue create failed")
+	app.queue = queue
+
+	ask := newAllocationAsk(aKey, appID0, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5}))
+	assert.NilError(t, app.AddAllocationAsk(ask), "ask should have been added to app")
+	// the node is large enough for the ask but is occupied
+	node := newNodeInternal(nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10}),
+		resources.NewResourceFromMap(map[string]resources.Quantity{"first": 8}))
+	iterator := getNodeIteratorFn(node)
+	time.Sleep(time.Millisecond)
+
+	// the ask is blocked from reserving after a reservation timed out
+	ask.BlockReservation(time.Now().Add(time.Minute))
+	result := app.tryNodes(ask, iterator())
+	assert.Assert(t, result == nil, "blocked ask should not reserve a node")
+
+	ask.BlockReservation(time.Time{})
+	result = app.tryNodes(ask, iterator())
+	assert.Assert(t, result != nil, "ask should reserve a node")
+	assert.Equal(t, result.ResultType, Reserved, "result type should be Reserved")
+	assert.Equal(t, result.NodeID, nodeID1, "wrong node reserved")
+}
+
+func TestGetReservationTimes(t *testing.T) {
+	app := newApplication(appID1, "default", "root.default")
+	queue, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	app.queue = queue
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 10})
+	assert.Equal(t, len(app.GetReservationTimes()), 0, "new app should not have reservation times")
+	assert.Equal(t, len(node.GetReservationTimes()), 0, "new node should not have reservation times")
+
+	ask := newAllocationAsk(aKey, appID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5}))
+	assert.NilError(t, app.AddAllocationAsk(ask), "ask should have been added to app")
+	before := time.Now()
+	assert.NilError(t, app.Reserve(node, ask), "reservation failed")
+	appTimes := app.GetReservationTimes()
+	assert.Equal(t, len(appTimes), 1, "app should have one reservation time")
+	assert.Assert(t, !appTimes[aKey].Before(before), "app reservation time not set")
+	nodeTimes := node.GetReservationTimes()
+	assert.Equal(t, len(nodeTimes), 1, "node should have one reservation time")
+	assert.Assert(t, !nodeTimes[aKey].Before(before), "node reservation time not set")
+}
+
+func TestPreemptionBudgetTags(t *testing.T) {
+	app := newApplication(appID1, "default", "root.leaf")
+	assert.Assert(t, app.preemptionBudget == nil, "unexpected budget without tags")
+
+	tags := map[string]string{
+		common.AppTagPreemptionBudgetAllocations: "3",
+		common.AppTagPreemptionBudgetPercentage:  "20",
+		common.AppTagPreemptionBudgetWindow:      "5m",
+	}
+	app = newApplicationWithTags(appID1, "default", "root.leaf", tags)
+	assert.Assert(t, app.preemptionBudget != nil, "budget should be set from tags")
+	assert.Equal(t, app.preemptionBudget.maxAllocations, 3)
+	assert.Equal(t, app.preemptionBudget.maxPercentage, float64(20))
+	assert.Equal(t, app.preemptionBudget.window, 5*time.Minute)
+
+	tags[common.AppTagPreemptionBudgetPercentage] = "200"
+	app = newApplicationWithTags(appID1, "default", "root.leaf", tags)
+	assert.Assert(t, app.preemptionBudget == nil, "invalid budget should be ignored")
+}
+
+func TestRuntimeEstimateAndDeadlineTags(t *testing.T) {
+	app := newApplication(appID1, "default", "root.leaf")
+	assert.Equal(t, app.GetRuntimeEstimate(), time.Duration(0), "unexpected estimate without tag")
+	assert.Assert(t, app.GetDeadline().IsZero(), "unexpected deadline without tag")
+	assert.Assert(t, !app.checkDeadline(time.Now()), "app without deadline cannot miss it")
+
+	tags := map[string]string{
+		common.AppTagRuntimeEstimate: "invalid",
+		common.AppTagDeadline:        "tomorrow",
+	}
+	app = newApplicationWithTags(appID1, "default", "root.leaf", tags)
+	assert.Equal(t, app.GetRuntimeEstimate(), time.Duration(0), "invalid estimate should be ignored")
+	assert.Assert(t, app.GetDeadline().IsZero(), "invalid deadline should be ignored")
+
+	tags[common.AppTagRuntimeEstimate] = "-10m"
+	app = newApplicationWithTags(appID1, "default", "root.leaf", tags)
+	assert.Equal(t, app.GetRuntimeEstimate(), time.Duration(0), "negative estimate should be ignored")
+
+	deadline := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tags = map[string]string{
+		"Application.Runtime.Estimate": "10m",
+		common.AppTagDeadline:          deadline.Format(time.RFC3339),
+	}
+	app = newApplicationWithTags(appID1, "default", "root.leaf", tags)
+	assert.Equal(t, app.GetRuntimeEstimate(), 10*time.Minute, "unexpected estimate")
+	assert.Assert(t, app.GetDeadline().Equal(deadline), "unexpected deadline")
+
+	// the deadline missed event is sent only once and only with resources pending
+	eventSystem := mock.NewEventSystem()
+	app.appEvents = schedEvt.NewApplicationEvents(eventSystem)
+	assert.Assert(t, !app.checkDeadline(deadline), "deadline cannot be missed without pending resources")
+	app.pending = resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	assert.Assert(t, !app.checkDeadline(deadline.Add(-time.Second)), "deadline should not be missed yet")
+	assert.Equal(t, len(eventSystem.Events), 0, "unexpected event")
+	assert.Assert(t, app.checkDeadline(deadline), "deadline should have been missed")
+	assert.Assert(t, !app.checkDeadline(deadline.Add(time.Minute)), "missed deadline should be reported once")
+	assert.Equal(t, len(eventSystem.Events), 1, "expected one deadline missed event")
+	assert.Equal(t, eventSystem.Events[0].ObjectID, appID1)
+
+	// the deadline is checked when an ask is added: a future deadline starts a timer
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err)
+	leaf, err := createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err)
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	tags[common.AppTagDeadline] = time.Now().Add(time.Hour).Format(time.RFC3339)
+	app = newApplicationWithTags(appID1, "default", "root.leaf", tags)
+	app.SetQueue(leaf)
+	assert.NilError(t, app.AddAllocationAsk(newAllocationAsk(aKey, appID1, res)))
+	assert.Assert(t, app.deadlineTimer != nil, "deadline timer should have been started")
+	app.clearDeadlineTimer()
+	assert.Assert(t, app.deadlineTimer == nil, "deadline timer should have been cleared")
+	// a passed deadline sends the event
+	app = newApplicationWithTags(appID1, "default", "root.leaf", map[string]string{common.AppTagDeadline: deadline.Format(time.RFC3339)})
+	app.SetQueue(leaf)
+	eventSystem = mock.NewEventSystem()
+	app.appEvents = schedEvt.NewApplicationEvents(eventSystem)
+	assert.NilError(t, app.AddAllocationAsk(newAllocationAsk(aKey, appID1, res)))
+	assert.Assert(t, app.deadlineMissed, "deadline should have been missed")
+	missed := 0
+	for _, event := range eventSystem.Events {
+		if strings.Contains(event.Message, "deadline") {
+			missed++
+		}
+	}
+	assert.Equal(t, missed, 1, "expected one deadline missed event")
+}
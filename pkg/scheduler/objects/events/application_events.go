@@ -20,6 +20,7 @@ package events
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
@@ -143,6 +144,33 @@ func (ae *ApplicationEvents) SendAppRunnableQuotaEvent(appID string) {
 	ae.eventSystem.AddEvent(event)
 }
 
+func (ae *ApplicationEvents) SendPriorityBoostEvent(appID string, boost int32, waited time.Duration) {
+	if !ae.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("Application priority boosted by %d: oldest pending ask waited %s", boost, waited)
+	event := events.CreateAppEventRecord(appID, message, common.Empty, si.EventRecord_SET, si.EventRecord_DETAILS_NONE, nil)
+	ae.eventSystem.AddEvent(event)
+}
+
+func (ae *ApplicationEvents) SendPriorityRestoreEvent(appID string, boost int32) {
+	if !ae.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("Application priority boost of %d removed", boost)
+	event := events.CreateAppEventRecord(appID, message, common.Empty, si.EventRecord_SET, si.EventRecord_DETAILS_NONE, nil)
+	ae.eventSystem.AddEvent(event)
+}
+
+func (ae *ApplicationEvents) SendDeadlineMissedEvent(appID string, deadline time.Time) {
+	if !ae.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("Application deadline %s missed with resources still pending", deadline.Format(time.RFC3339))
+	event := events.CreateAppEventRecord(appID, message, common.Empty, si.EventRecord_NONE, si.EventRecord_DETAILS_NONE, nil)
+	ae.eventSystem.AddEvent(event)
+}
+
 func NewApplicationEvents(es events.EventSystem) *ApplicationEvents {
 	return &ApplicationEvents{
 		eventSystem: es,
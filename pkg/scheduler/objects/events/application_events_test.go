@@ -20,6 +20,7 @@ package events
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -337,3 +338,56 @@ func TestSendAppRunnableByQuotaEvent(t *testing.T) {
 	assert.Equal(t, "", event.ReferenceID)
 	assert.Equal(t, "", event.Message)
 }
+
+func TestSendPriorityBoostEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	appEvents := NewApplicationEvents(eventSystem)
+	appEvents.SendPriorityBoostEvent(appID, 100, time.Minute)
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	appEvents = NewApplicationEvents(eventSystem)
+	appEvents.SendPriorityBoostEvent(appID, 100, time.Minute)
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_APP, event.Type)
+	assert.Equal(t, si.EventRecord_SET, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "app-0", event.ObjectID)
+	assert.Equal(t, "", event.ReferenceID)
+	assert.Equal(t, "Application priority boosted by 100: oldest pending ask waited 1m0s", event.Message)
+}
+
+func TestSendPriorityRestoreEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	appEvents := NewApplicationEvents(eventSystem)
+	appEvents.SendPriorityRestoreEvent(appID, 100)
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	appEvents = NewApplicationEvents(eventSystem)
+	appEvents.SendPriorityRestoreEvent(appID, 100)
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_APP, event.Type)
+	assert.Equal(t, si.EventRecord_SET, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "app-0", event.ObjectID)
+	assert.Equal(t, "Application priority boost of 100 removed", event.Message)
+}
+
+func TestSendDeadlineMissedEvent(t *testing.T) {
+	deadline := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	eventSystem := mock.NewEventSystemDisabled()
+	appEvents := NewApplicationEvents(eventSystem)
+	appEvents.SendDeadlineMissedEvent(appID, deadline)
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	appEvents = NewApplicationEvents(eventSystem)
+	appEvents.SendDeadlineMissedEvent(appID, deadline)
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_APP, event.Type)
+	assert.Equal(t, si.EventRecord_NONE, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "app-0", event.ObjectID)
+	assert.Equal(t, "Application deadline 2024-01-02T03:04:05Z missed with resources still pending", event.Message)
+}
@@ -73,6 +73,24 @@ func (ae *AskEvents) SendRequestFitsInUserQuota(allocKey, appID string, allocate
 	ae.eventSystem.AddEvent(event)
 }
 
+func (ae *AskEvents) SendRequestExceedsApplicationQuota(allocKey, appID, reason string, allocatedResource *resources.Resource) {
+	if !ae.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("Request '%s' exceeds the application quota (requested %s, %s)", allocKey, allocatedResource, reason)
+	event := events.CreateRequestEventRecord(allocKey, appID, message, allocatedResource)
+	ae.eventSystem.AddEvent(event)
+}
+
+func (ae *AskEvents) SendRequestFitsInApplicationQuota(allocKey, appID string, allocatedResource *resources.Resource) {
+	if !ae.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("Request '%s' fits in the application quota", allocKey)
+	event := events.CreateRequestEventRecord(allocKey, appID, message, allocatedResource)
+	ae.eventSystem.AddEvent(event)
+}
+
 func (ae *AskEvents) SendPredicatesFailed(allocKey, appID string, predicateErrors map[string]int, allocatedResource *resources.Resource) {
 	if !ae.eventSystem.IsEventTrackingEnabled() || !ae.predicateLimiter.Allow() {
 		return
@@ -112,6 +112,44 @@ func TestRequestFitsInUserQuotaEvent(t *testing.T) {
 	assert.Equal(t, "Request 'alloc-0' fits in the available user quota", event.Message)
 }
 
+func TestRequestExceedsApplicationQuotaEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	events := NewAskEvents(eventSystem)
+	events.SendRequestExceedsApplicationQuota(allocKey, appID, "maximum of 2 allocations reached", requestResource)
+	assert.Equal(t, 0, len(eventSystem.Events))
+
+	eventSystem = mock.NewEventSystem()
+	events = NewAskEvents(eventSystem)
+	events.SendRequestExceedsApplicationQuota(allocKey, appID, "maximum of 2 allocations reached", requestResource)
+	assert.Equal(t, 1, len(eventSystem.Events))
+	event := eventSystem.Events[0]
+	assert.Equal(t, "alloc-0", event.ObjectID)
+	assert.Equal(t, appID, event.ReferenceID)
+	assert.Equal(t, si.EventRecord_REQUEST, event.Type)
+	assert.Equal(t, si.EventRecord_NONE, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "Request 'alloc-0' exceeds the application quota (requested map[cpu:100 memory:100], maximum of 2 allocations reached)", event.Message)
+}
+
+func TestRequestFitsInApplicationQuotaEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	events := NewAskEvents(eventSystem)
+	events.SendRequestFitsInApplicationQuota(allocKey, appID, requestResource)
+	assert.Equal(t, 0, len(eventSystem.Events))
+
+	eventSystem = mock.NewEventSystem()
+	events = NewAskEvents(eventSystem)
+	events.SendRequestFitsInApplicationQuota(allocKey, appID, requestResource)
+	assert.Equal(t, 1, len(eventSystem.Events))
+	event := eventSystem.Events[0]
+	assert.Equal(t, "alloc-0", event.ObjectID)
+	assert.Equal(t, appID, event.ReferenceID)
+	assert.Equal(t, si.EventRecord_REQUEST, event.Type)
+	assert.Equal(t, si.EventRecord_NONE, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "Request 'alloc-0' fits in the application quota", event.Message)
+}
+
 func TestPredicateFailedEvents(t *testing.T) {
 	resource := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
 	eventSystem := mock.NewEventSystemDisabled()
@@ -19,6 +19,9 @@
 package events
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/events"
@@ -104,6 +107,42 @@ func (q *QueueEvents) SendTypeChangedEvent(queuePath string, isLeaf bool) {
 	q.eventSystem.AddEvent(event)
 }
 
+func (q *QueueEvents) SendPreemptionBudgetExhaustedEvent(queuePath, appID string, preempted int, window time.Duration) {
+	if !q.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("Preemption budget exhausted: %d allocations preempted in the last %s", preempted, window)
+	event := events.CreateQueueEventRecord(queuePath, message, appID, si.EventRecord_NONE,
+		si.EventRecord_DETAILS_NONE, nil)
+	q.eventSystem.AddEvent(event)
+}
+
+func (q *QueueEvents) SendScheduleChangedEvent(queuePath, schedule string, started bool) {
+	if !q.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("Queue schedule ended: %s", schedule)
+	if started {
+		message = fmt.Sprintf("Queue schedule started: %s", schedule)
+	}
+	event := events.CreateQueueEventRecord(queuePath, message, common.Empty, si.EventRecord_SET,
+		si.EventRecord_DETAILS_NONE, nil)
+	q.eventSystem.AddEvent(event)
+}
+
+func (q *QueueEvents) SendStateChangedEvent(queuePath, state, reason string) {
+	if !q.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("Queue state changed to %s", state)
+	if reason != "" {
+		message = fmt.Sprintf("Queue state changed to %s: %s", state, reason)
+	}
+	event := events.CreateQueueEventRecord(queuePath, message, common.Empty, si.EventRecord_SET,
+		si.EventRecord_DETAILS_NONE, nil)
+	q.eventSystem.AddEvent(event)
+}
+
 func NewQueueEvents(evt events.EventSystem) *QueueEvents {
 	return &QueueEvents{
 		eventSystem: evt,
@@ -20,6 +20,7 @@ package events
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -201,3 +202,64 @@ func TestSendGuaranteedResourceChangedEvent(t *testing.T) {
 	protoRes := resources.NewResourceFromProto(event.Resource)
 	assert.DeepEqual(t, guaranteed, protoRes)
 }
+
+func TestSendPreemptionBudgetExhaustedEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	nq := NewQueueEvents(eventSystem)
+	nq.SendPreemptionBudgetExhaustedEvent(testQueuePath, "app-1", 2, 10*time.Minute)
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	nq = NewQueueEvents(eventSystem)
+	nq.SendPreemptionBudgetExhaustedEvent(testQueuePath, "app-1", 2, 10*time.Minute)
+	assert.Equal(t, 1, len(eventSystem.Events), "event was not generated")
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_QUEUE, event.Type)
+	assert.Equal(t, testQueuePath, event.ObjectID)
+	assert.Equal(t, "app-1", event.ReferenceID)
+	assert.Equal(t, "Preemption budget exhausted: 2 allocations preempted in the last 10m0s", event.Message)
+	assert.Equal(t, si.EventRecord_NONE, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, 0, len(event.Resource.Resources))
+}
+
+func TestSendScheduleChangedEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	nq := NewQueueEvents(eventSystem)
+	nq.SendScheduleChangedEvent(testQueuePath, "night", true)
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	nq = NewQueueEvents(eventSystem)
+	nq.SendScheduleChangedEvent(testQueuePath, "night", true)
+	nq.SendScheduleChangedEvent(testQueuePath, "night", false)
+	assert.Equal(t, 2, len(eventSystem.Events), "events were not generated")
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_QUEUE, event.Type)
+	assert.Equal(t, testQueuePath, event.ObjectID)
+	assert.Equal(t, common.Empty, event.ReferenceID)
+	assert.Equal(t, "Queue schedule started: night", event.Message)
+	assert.Equal(t, si.EventRecord_SET, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "Queue schedule ended: night", eventSystem.Events[1].Message)
+}
+
+func TestSendStateChangedEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	nq := NewQueueEvents(eventSystem)
+	nq.SendStateChangedEvent(testQueuePath, "Stopped", "maintenance")
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	nq = NewQueueEvents(eventSystem)
+	nq.SendStateChangedEvent(testQueuePath, "Stopped", "maintenance")
+	nq.SendStateChangedEvent(testQueuePath, "Active", "")
+	assert.Equal(t, 2, len(eventSystem.Events), "events were not generated")
+	event := eventSystem.Events[0]
+	assert.Equal(t, si.EventRecord_QUEUE, event.Type)
+	assert.Equal(t, testQueuePath, event.ObjectID)
+	assert.Equal(t, "Queue state changed to Stopped: maintenance", event.Message)
+	assert.Equal(t, si.EventRecord_SET, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_DETAILS_NONE, event.EventChangeDetail)
+	assert.Equal(t, "Queue state changed to Active", eventSystem.Events[1].Message)
+}
@@ -60,8 +60,7 @@ type Node struct {
 	listeners    []NodeListener          // a list of node listeners
 	nodeEvents   *schedEvt.NodeEvents
 
-	waitingTime       time.Duration
-	lastAllocatedTime time.Time
+	lastAllocatedTime time.Time // last time an allocation was added to the node
 	locking.RWMutex
 }
 
@@ -80,7 +79,6 @@ func NewNode(proto *si.NodeInfo) *Node {
 		allocations:       make(map[string]*Allocation),
 		schedulable:       true,
 		listeners:         make([]NodeListener, 0),
-		waitingTime:       0,
 		lastAllocatedTime: time.Now(),
 	}
 	sn.nodeEvents = schedEvt.NewNodeEvents(events.GetEventSystem())
@@ -120,27 +118,24 @@ func (sn *Node) initializeAttribute(newAttributes map[string]string) {
 	sn.Partition = sn.attributes[siCommon.NodePartition]
 }
 
-func (n *Node) GetWaitingTime() time.Duration {
-	n.RLock()
-	defer n.RUnlock()
-	return n.waitingTime
-}
-
-func (n *Node) UpdateWaitingTime() {
-	n.Lock()
-	defer n.Unlock()
-	if !n.lastAllocatedTime.IsZero() {
-		n.waitingTime = time.Since(n.lastAllocatedTime)
-	} else {
-		n.waitingTime = 0
+// GetWaitingTime returns the time since the last allocation was added to the node.
+// The time is calculated on each call, it is 0 if the node never tracked an allocation time.
+func (sn *Node) GetWaitingTime() time.Duration {
+	sn.RLock()
+	defer sn.RUnlock()
+	if sn.lastAllocatedTime.IsZero() {
+		return 0
 	}
+	return time.Since(sn.lastAllocatedTime)
 }
 
-// SetWaitingTime sets the waiting time for the node
-func (n *Node) SetWaitingTime(duration time.Duration) {
-	n.Lock()
-	defer n.Unlock()
-	n.waitingTime = duration
+// SetWaitingTime moves the last allocation time back to make the node wait for the duration.
+// Listeners are not notified.
+// Visible for tests
+func (sn *Node) SetWaitingTime(duration time.Duration) {
+	sn.Lock()
+	defer sn.Unlock()
+	sn.lastAllocatedTime = time.Now().Add(-duration)
 }
 
 // Get an attribute by name. The most used attributes can be directly accessed via the
@@ -177,6 +172,17 @@ func (sn *Node) GetReservationKeys() []string {
 	return keys
 }
 
+// GetReservationTimes returns the time each reservation on the node was made, keyed by reservation key.
+func (sn *Node) GetReservationTimes() map[string]time.Time {
+	sn.RLock()
+	defer sn.RUnlock()
+	times := make(map[string]time.Time, len(sn.reservations))
+	for key, reserve := range sn.reservations {
+		times[key] = reserve.GetCreateTime()
+	}
+	return times
+}
+
 func (sn *Node) GetCapacity() *resources.Resource {
 	sn.RLock()
 	defer sn.RUnlock()
@@ -20,15 +20,22 @@ package objects
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/google/btree"
 	"go.uber.org/zap"
 
 	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/metrics"
 	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
 )
 
+var (
+	nodeRescoreInterval = time.Second // minimum time between two rescoring passes for time dependent policies
+	nodeRescoreBatch    = 1000        // maximum number of nodes rescored in one pass
+)
+
 var acceptUnreserved = func(node *Node) bool {
 	return !node.IsReserved()
 }
@@ -49,6 +56,8 @@ type NodeCollection interface {
 	GetFullNodeIterator() NodeIterator
 	SetNodeSortingPolicy(policy NodeSortingPolicy)
 	GetNodeSortingPolicy() NodeSortingPolicy
+	SetNodeTopology(topology *NodeTopology)
+	GetNodeTopology() *NodeTopology
 }
 
 type nodeRef struct {
@@ -75,12 +84,19 @@ type baseNodeCollection struct {
 
 	// Private fields need protection
 	nsp         NodeSortingPolicy   // node sorting policy
+	topology    *NodeTopology       // topology settings for the nodes, nil if not set
 	nodes       map[string]*nodeRef // nodes assigned to this collection
 	sortedNodes *btree.BTree        // nodes sorted by score
 
 	unreservedIterator *treeIterator
 	fullIterator       *treeIterator
 
+	// rescoring of nodes for policies with a time dependent score
+	timeDependent bool      // the node sorting policy score changes over time
+	rescoreOrder  []string  // node IDs in the order they are rescored
+	rescoreNext   int       // index in rescoreOrder of the next node to rescore
+	lastRescore   time.Time // start time of the last rescoring pass
+
 	locking.RWMutex
 }
 
@@ -110,6 +126,7 @@ func (nc *baseNodeCollection) AddNode(node *Node) error {
 	}
 	nc.nodes[node.NodeID] = &nref
 	nc.sortedNodes.ReplaceOrInsert(nref)
+	nc.rescoreOrder = append(nc.rescoreOrder, node.NodeID)
 	return nil
 }
 
@@ -129,6 +146,15 @@ func (nc *baseNodeCollection) RemoveNode(nodeID string) *Node {
 	nc.sortedNodes.Delete(*nref)
 	delete(nc.nodes, nodeID)
 	nref.node.RemoveListener(nc)
+	for i, id := range nc.rescoreOrder {
+		if id == nodeID {
+			nc.rescoreOrder = append(nc.rescoreOrder[:i], nc.rescoreOrder[i+1:]...)
+			if i < nc.rescoreNext {
+				nc.rescoreNext--
+			}
+			break
+		}
+	}
 
 	return nref.node
 }
@@ -178,14 +204,68 @@ func (nc *baseNodeCollection) cloneSortedNodes() *btree.BTree {
 	nc.Lock()
 	defer nc.Unlock()
 
+	nc.rescoreNodes()
 	return nc.sortedNodes.Clone()
 }
 
+// rescoreNodes refreshes the node scores if the node sorting policy is time dependent.
+// Node updates only trigger a rescore of the updated node. The score of all other nodes becomes stale
+// while time passes. Rescoring is done lazily when an iterator is used, at most once per rescore interval.
+// Each pass rescores a bounded number of nodes in a round-robin order to limit the cost on large clusters.
+// NOTE: this is a lock free call. It must only be called holding the collection lock.
+func (nc *baseNodeCollection) rescoreNodes() {
+	if !nc.timeDependent || len(nc.rescoreOrder) == 0 {
+		return
+	}
+	start := time.Now()
+	if start.Sub(nc.lastRescore) < nodeRescoreInterval {
+		return
+	}
+	nc.lastRescore = start
+	count := min(nodeRescoreBatch, len(nc.rescoreOrder))
+	for i := 0; i < count; i++ {
+		if nc.rescoreNext >= len(nc.rescoreOrder) {
+			nc.rescoreNext = 0
+		}
+		if nref := nc.nodes[nc.rescoreOrder[nc.rescoreNext]]; nref != nil {
+			nc.updateScore(nref)
+		}
+		nc.rescoreNext++
+	}
+	metrics.GetSchedulerMetrics().ObserveNodeRescoringLatency(start)
+	metrics.GetSchedulerMetrics().AddNodesRescored(count)
+}
+
+// updateScore recalculates the score of the node and moves it in the sorted tree if the score changed.
+// NOTE: this is a lock free call. It must only be called holding the collection lock.
+func (nc *baseNodeCollection) updateScore(nref *nodeRef) {
+	updatedScore := nc.scoreNode(nref.node)
+	if nref.nodeScore != updatedScore {
+		nc.sortedNodes.Delete(*nref)
+		nref.nodeScore = updatedScore
+		nc.sortedNodes.ReplaceOrInsert(*nref)
+	}
+}
+
+// isTimeDependent returns true if the score calculated by the policy changes over time.
+func isTimeDependent(policy NodeSortingPolicy) bool {
+	switch p := policy.(type) {
+	case AgingNodeSortingPolicy:
+		aging := p.AgingSettings()
+		return aging.Weight > 0 && aging.Cap > 0
+	case timeDependentNodeSortingPolicy:
+		return p.isTimeDependent()
+	}
+	return false
+}
+
 // Sets the node sorting policy.
 func (nc *baseNodeCollection) SetNodeSortingPolicy(policy NodeSortingPolicy) {
 	nc.Lock()
 	defer nc.Unlock()
 	nc.nsp = policy
+	nc.timeDependent = isTimeDependent(policy)
+	nc.lastRescore = time.Now()
 
 	// sortedNodes must be rebuilt since sort ordering is different
 	nc.sortedNodes.Clear(false)
@@ -203,6 +283,20 @@ func (nc *baseNodeCollection) GetNodeSortingPolicy() NodeSortingPolicy {
 	return nc.nsp
 }
 
+// Sets the topology settings for the nodes, nil removes the settings.
+func (nc *baseNodeCollection) SetNodeTopology(topology *NodeTopology) {
+	nc.Lock()
+	defer nc.Unlock()
+	nc.topology = topology
+}
+
+// Gets the topology settings for the nodes, nil if not set.
+func (nc *baseNodeCollection) GetNodeTopology() *NodeTopology {
+	nc.RLock()
+	defer nc.RUnlock()
+	return nc.topology
+}
+
 // Callback method triggered when a node is updated.
 func (nc *baseNodeCollection) NodeUpdated(node *Node) {
 	nc.Lock()
@@ -212,13 +306,7 @@ func (nc *baseNodeCollection) NodeUpdated(node *Node) {
 	if nref == nil {
 		return
 	}
-
-	updatedScore := nc.scoreNode(node)
-	if nref.nodeScore != updatedScore {
-		nc.sortedNodes.Delete(*nref)
-		nref.nodeScore = nc.scoreNode(node)
-		nc.sortedNodes.ReplaceOrInsert(*nref)
-	}
+	nc.updateScore(nref)
 }
 
 // Create a new collection for the given partition.
@@ -228,10 +316,13 @@ func NewNodeCollection(partition string) NodeCollection {
 		nsp:         NewNodeSortingPolicy(policies.FairSortPolicy.String(), nil),
 		nodes:       make(map[string]*nodeRef),
 		sortedNodes: btree.New(7), // Degree=7 here is experimentally the most efficient for up to around 5k nodes
+		lastRescore: time.Now(),
 	}
 
 	unreservedIterator := NewTreeIterator(acceptUnreserved, bsc.cloneSortedNodes)
 	fullIterator := NewTreeIterator(acceptAll, bsc.cloneSortedNodes)
+	unreservedIterator.getTopology = bsc.GetNodeTopology
+	fullIterator.getTopology = bsc.GetNodeTopology
 
 	bsc.fullIterator = fullIterator
 	bsc.unreservedIterator = unreservedIterator
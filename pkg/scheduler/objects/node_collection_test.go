@@ -21,6 +21,7 @@ package objects
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -443,3 +444,56 @@ func TestNodeIteratorReserveUpdate(t *testing.T) {
 	})
 	assert.Equal(t, len(itNodes), count, "wrong length")
 }
+
+func TestNodeIteratorRescore(t *testing.T) {
+	interval := nodeRescoreInterval
+	batch := nodeRescoreBatch
+	defer func() {
+		nodeRescoreInterval = interval
+		nodeRescoreBatch = batch
+	}()
+	nodeRescoreInterval = 0
+	nodeRescoreBatch = 1
+
+	nc := initBaseCollection()
+	assert.Assert(t, !nc.timeDependent, "fair policy should not be time dependent")
+	nc.SetNodeSortingPolicy(NewNodeSortingPolicy(policies.FairWithAgingNodePolicy.String(), nil))
+	assert.Assert(t, nc.timeDependent, "aging policy should be time dependent")
+
+	total := map[string]resources.Quantity{"first": 10}
+	for i, used := range []resources.Quantity{4, 5} {
+		node := newNode(fmt.Sprintf("node-%d", i), total)
+		node.AddAllocation(newAllocation(appID0, node.NodeID, resources.NewResourceFromMap(map[string]resources.Quantity{"first": used})))
+		assert.NilError(t, nc.AddNode(node), "adding node failed")
+	}
+	assert.DeepEqual(t, nc.rescoreOrder, []string{"node-0", "node-1"})
+	getOrder := func() []string {
+		var order []string
+		nc.GetNodeIterator().ForEachNode(func(node *Node) bool {
+			order = append(order, node.NodeID)
+			return true
+		})
+		return order
+	}
+	assert.DeepEqual(t, getOrder(), []string{"node-0", "node-1"})
+
+	// node-1 has been idle for a full period: the aging correction outweighs the usage difference.
+	// waiting time changes do not trigger an update, only the rescoring picks it up
+	nc.GetNode("node-1").SetWaitingTime(configs.DefaultNodeAgingPeriod)
+	// the previous pass rescored node-0 only, the next pass with a batch of 1 rescores node-1
+	assert.Equal(t, nc.rescoreNext, 1, "unexpected rescore position")
+	assert.DeepEqual(t, getOrder(), []string{"node-1", "node-0"})
+
+	// removing a node before the rescore position moves the position back
+	nc.RemoveNode("node-0")
+	assert.DeepEqual(t, nc.rescoreOrder, []string{"node-1"})
+	assert.Equal(t, nc.rescoreNext, 1, "rescore position not moved back")
+
+	// rescoring is rate limited
+	nodeRescoreInterval = time.Hour
+	nc.lastRescore = time.Now()
+	nc.GetNode("node-1").SetWaitingTime(0)
+	before := nc.nodes["node-1"].nodeScore
+	getOrder()
+	assert.Equal(t, nc.nodes["node-1"].nodeScore, before, "node should not have been rescored within the interval")
+}
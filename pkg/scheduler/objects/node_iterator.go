@@ -29,8 +29,9 @@ type NodeIterator interface {
 }
 
 type treeIterator struct {
-	accept  func(*Node) bool
-	getTree func() *btree.BTree
+	accept      func(*Node) bool
+	getTree     func() *btree.BTree
+	getTopology func() *NodeTopology
 }
 
 // ForEachNode Calls the provided "f" function on the sorted Node object until it returns false.
@@ -48,6 +49,14 @@ func (ti *treeIterator) ForEachNode(f func(*Node) bool) {
 	})
 }
 
+// GetNodeTopology returns the topology settings of the nodes, nil if no topology is set.
+func (ti *treeIterator) GetNodeTopology() *NodeTopology {
+	if ti.getTopology == nil {
+		return nil
+	}
+	return ti.getTopology()
+}
+
 func NewTreeIterator(accept func(*Node) bool, getTree func() *btree.BTree) *treeIterator {
 	ti := &treeIterator{
 		getTree: getTree,
@@ -0,0 +1,251 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
+)
+
+// NodeTopology contains the settings to place the allocations of an application over the topology domains.
+// A topology domain is the set of nodes that have the same value for a node attribute key.
+type NodeTopology struct {
+	Keys    []string              // node attribute keys that define the domains, most important first
+	Mode    policies.TopologyMode // default mode for applications that do not set a mode
+	MaxSkew int                   // maximum difference in allocations between two domains when spreading, 0 means no limit
+}
+
+// NewNodeTopology converts the topology configuration into the settings.
+// Returns nil if no keys are configured. The configuration is expected to be validated.
+func NewNodeTopology(conf configs.NodeSortingTopology) *NodeTopology {
+	if len(conf.Keys) == 0 {
+		return nil
+	}
+	mode, err := policies.TopologyModeFromString(conf.Mode)
+	if err != nil {
+		log.Log(log.SchedNode).Debug("node topology mode defaulted to 'spread'",
+			zap.Error(err))
+	}
+	keys := make([]string, len(conf.Keys))
+	copy(keys, conf.Keys)
+	return &NodeTopology{
+		Keys:    keys,
+		Mode:    mode,
+		MaxSkew: int(conf.MaxSkew),
+	}
+}
+
+// TopologyNodeIterator is implemented by node iterators that know the topology settings of the nodes.
+type TopologyNodeIterator interface {
+	NodeIterator
+	GetNodeTopology() *NodeTopology
+}
+
+// topologyIterator changes the order of the nodes returned by the base iterator based on the number of
+// allocations the application already has in the topology domain of each node.
+type topologyIterator struct {
+	base    NodeIterator
+	keys    []string
+	mode    policies.TopologyMode
+	maxSkew int
+	counts  []map[string]int // number of allocations of the application per domain, one map per key
+}
+
+// newTopologyIterator creates an iterator for an application in the spread or colocate mode.
+// The application allocations are counted per domain using the node the allocation is placed on.
+func newTopologyIterator(base NodeIterator, topology *NodeTopology, mode policies.TopologyMode, nodeIDs []string, getNodeFn func(string) *Node) *topologyIterator {
+	ti := &topologyIterator{
+		base:    base,
+		keys:    topology.Keys,
+		mode:    mode,
+		maxSkew: topology.MaxSkew,
+		counts:  make([]map[string]int, len(topology.Keys)),
+	}
+	for i := range ti.counts {
+		ti.counts[i] = make(map[string]int)
+	}
+	for _, nodeID := range nodeIDs {
+		node := getNodeFn(nodeID)
+		if node == nil {
+			continue
+		}
+		for i, key := range ti.keys {
+			if domain := node.GetAttribute(key); domain != "" {
+				ti.counts[i][domain]++
+			}
+		}
+	}
+	return ti
+}
+
+// topologyTier is the number of allocations of the application in the domain of a node for each key,
+// noDomain if the node does not have the attribute.
+type topologyTier []int
+
+const noDomain = -1
+
+// topologyBucket holds the nodes of the same tier in the order of the base iterator.
+type topologyBucket struct {
+	tier  topologyTier
+	nodes []*Node
+}
+
+// ForEachNode calls the provided function on the nodes of the base iterator until it returns false.
+// When spreading the nodes in the domains with the fewest allocations are returned first, and nodes that
+// would exceed the max skew are skipped. When colocating the nodes in the domains with the most allocations
+// are returned first. Nodes without the attribute are returned after the nodes that have the attribute.
+// The order of the base iterator is kept for nodes in domains with the same number of allocations.
+// The base iterator is walked once: nodes in the best possible tier are returned while walking, all other
+// nodes are grouped per tier. Only the tiers are sorted, not the nodes.
+func (ti *topologyIterator) ForEachNode(f func(*Node) bool) {
+	best := ti.bestTier()
+	hasEmpty := make([]bool, len(ti.keys))
+	var buckets []*topologyBucket
+	stopped := false
+	ti.base.ForEachNode(func(node *Node) bool {
+		tier := ti.getTier(node)
+		for i, count := range tier {
+			if count == 0 {
+				hasEmpty[i] = true
+			}
+		}
+		if tier.equals(best) {
+			stopped = !f(node)
+			return !stopped
+		}
+		for _, bucket := range buckets {
+			if bucket.tier.equals(tier) {
+				bucket.nodes = append(bucket.nodes, node)
+				return true
+			}
+		}
+		buckets = append(buckets, &topologyBucket{tier: tier, nodes: []*Node{node}})
+		return true
+	})
+	if stopped {
+		return
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return ti.less(buckets[i].tier, buckets[j].tier)
+	})
+	var minCounts []int
+	if ti.mode == policies.SpreadTopologyMode && ti.maxSkew > 0 {
+		minCounts = ti.minCounts(hasEmpty)
+	}
+	for _, bucket := range buckets {
+		if minCounts != nil && ti.isSkewed(bucket.tier, minCounts) {
+			continue
+		}
+		for _, node := range bucket.nodes {
+			if !f(node) {
+				return
+			}
+		}
+	}
+}
+
+// getTier returns the tier of the node: the number of allocations in the domain of the node for each key.
+func (ti *topologyIterator) getTier(node *Node) topologyTier {
+	tier := make(topologyTier, len(ti.keys))
+	for i, key := range ti.keys {
+		if domain := node.GetAttribute(key); domain != "" {
+			tier[i] = ti.counts[i][domain]
+		} else {
+			tier[i] = noDomain
+		}
+	}
+	return tier
+}
+
+// bestTier returns the tier that is always returned first: domains without allocations when spreading, the
+// domains with the most allocations when colocating.
+func (ti *topologyIterator) bestTier() topologyTier {
+	tier := make(topologyTier, len(ti.keys))
+	if ti.mode == policies.ColocateTopologyMode {
+		for i := range ti.keys {
+			for _, count := range ti.counts[i] {
+				if count > tier[i] {
+					tier[i] = count
+				}
+			}
+		}
+	}
+	return tier
+}
+
+// less returns true if the nodes in tier l must be returned before the nodes in tier r.
+func (ti *topologyIterator) less(l, r topologyTier) bool {
+	for k := range ti.keys {
+		if l[k] == r[k] {
+			continue
+		}
+		if l[k] == noDomain || r[k] == noDomain {
+			return r[k] == noDomain
+		}
+		if ti.mode == policies.ColocateTopologyMode {
+			return l[k] > r[k]
+		}
+		return l[k] < r[k]
+	}
+	return false
+}
+
+// minCounts returns the lowest number of allocations over all domains for each key. A domain without
+// allocations exists if a node was seen in a domain without allocations.
+func (ti *topologyIterator) minCounts(hasEmpty []bool) []int {
+	minCounts := make([]int, len(ti.keys))
+	for i := range ti.keys {
+		if hasEmpty[i] {
+			continue
+		}
+		first := true
+		for _, count := range ti.counts[i] {
+			if first || count < minCounts[i] {
+				minCounts[i] = count
+				first = false
+			}
+		}
+	}
+	return minCounts
+}
+
+// isSkewed returns true if an allocation on a node in the tier would increase the difference between the domain
+// of the node and the domain with the fewest allocations above the max skew.
+func (ti *topologyIterator) isSkewed(tier topologyTier, minCounts []int) bool {
+	for i, count := range tier {
+		if count != noDomain && count+1-minCounts[i] > ti.maxSkew {
+			return true
+		}
+	}
+	return false
+}
+
+func (t topologyTier) equals(other topologyTier) bool {
+	for i := range t {
+		if t[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
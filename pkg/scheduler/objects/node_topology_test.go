@@ -0,0 +1,157 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
+)
+
+// newTopologyNodes creates a collection with nodes in two zones: zone-a has racks r1 and r2, zone-b has rack r3.
+// The last node has no topology attributes.
+func newTopologyNodes(t *testing.T) NodeCollection {
+	nc := NewNodeCollection("test")
+	nc.SetNodeSortingPolicy(NewNodeSortingPolicy(policies.BinPackingPolicy.String(), nil))
+	totalRes := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000, "memory": 1000})
+	for _, n := range []struct {
+		id    string
+		attrs map[string]string
+	}{
+		{"node-1", map[string]string{"zone": "zone-a", "rack": "r1"}},
+		{"node-2", map[string]string{"zone": "zone-a", "rack": "r2"}},
+		{"node-3", map[string]string{"zone": "zone-b", "rack": "r3"}},
+		{"node-4", map[string]string{}},
+	} {
+		assert.NilError(t, nc.AddNode(NewNode(newProto(n.id, totalRes, n.attrs))), "failed to add node %s", n.id)
+	}
+	return nc
+}
+
+func iteratorNodeIDs(iterator NodeIterator) []string {
+	var nodeIDs []string
+	iterator.ForEachNode(func(node *Node) bool {
+		nodeIDs = append(nodeIDs, node.NodeID)
+		return true
+	})
+	return nodeIDs
+}
+
+func TestNewNodeTopology(t *testing.T) {
+	assert.Assert(t, NewNodeTopology(configs.NodeSortingTopology{Mode: "spread"}) == nil, "topology without keys should not be set")
+	topology := NewNodeTopology(configs.NodeSortingTopology{Keys: []string{"zone", "rack"}, MaxSkew: 2})
+	assert.Assert(t, topology != nil, "topology should be set")
+	assert.DeepEqual(t, topology.Keys, []string{"zone", "rack"})
+	assert.Equal(t, topology.Mode, policies.SpreadTopologyMode, "mode should default to spread")
+	assert.Equal(t, topology.MaxSkew, 2, "max skew not set")
+	topology = NewNodeTopology(configs.NodeSortingTopology{Keys: []string{"rack"}, Mode: "colocate"})
+	assert.Equal(t, topology.Mode, policies.ColocateTopologyMode, "mode not set")
+}
+
+func TestTopologyIterator(t *testing.T) {
+	nc := newTopologyNodes(t)
+	getNode := nc.GetNode
+	base := nc.GetNodeIterator()
+	// all nodes are empty: node ID order
+	assert.DeepEqual(t, iteratorNodeIDs(base), []string{"node-1", "node-2", "node-3", "node-4"})
+
+	topology := &NodeTopology{Keys: []string{"zone", "rack"}}
+	// spread: no allocations keeps the base order
+	ti := newTopologyIterator(base, topology, policies.SpreadTopologyMode, nil, getNode)
+	assert.DeepEqual(t, iteratorNodeIDs(ti), []string{"node-1", "node-2", "node-3", "node-4"})
+	// spread: one allocation in zone-a rack r1 prefers zone-b, then the other rack in zone-a
+	ti = newTopologyIterator(base, topology, policies.SpreadTopologyMode, []string{"node-1"}, getNode)
+	assert.DeepEqual(t, iteratorNodeIDs(ti), []string{"node-3", "node-2", "node-1", "node-4"})
+	// colocate: same rack first, then same zone, then the other zone
+	ti = newTopologyIterator(base, topology, policies.ColocateTopologyMode, []string{"node-2"}, getNode)
+	assert.DeepEqual(t, iteratorNodeIDs(ti), []string{"node-2", "node-1", "node-3", "node-4"})
+	// unknown nodes are ignored
+	ti = newTopologyIterator(base, topology, policies.ColocateTopologyMode, []string{"unknown"}, getNode)
+	assert.DeepEqual(t, iteratorNodeIDs(ti), []string{"node-1", "node-2", "node-3", "node-4"})
+
+	// max skew: zone-a has 2 allocations, zone-b none, with a skew of 2 zone-a is skipped
+	topology = &NodeTopology{Keys: []string{"zone"}, MaxSkew: 2}
+	ti = newTopologyIterator(base, topology, policies.SpreadTopologyMode, []string{"node-1", "node-2"}, getNode)
+	assert.DeepEqual(t, iteratorNodeIDs(ti), []string{"node-3", "node-4"})
+	topology.MaxSkew = 3
+	ti = newTopologyIterator(base, topology, policies.SpreadTopologyMode, []string{"node-1", "node-2"}, getNode)
+	assert.DeepEqual(t, iteratorNodeIDs(ti), []string{"node-3", "node-1", "node-2", "node-4"})
+	// skew is not applied when colocating
+	topology.MaxSkew = 1
+	ti = newTopologyIterator(base, topology, policies.ColocateTopologyMode, []string{"node-1", "node-2"}, getNode)
+	assert.DeepEqual(t, iteratorNodeIDs(ti), []string{"node-1", "node-2", "node-3", "node-4"})
+
+	// stop when the function returns false
+	count := 0
+	ti.ForEachNode(func(_ *Node) bool {
+		count++
+		return false
+	})
+	assert.Equal(t, count, 1, "iteration should have stopped")
+
+	// nodes in the best tier are returned while walking the base iterator: node-3 is the third node
+	counting := &countingIterator{base: base}
+	ti = newTopologyIterator(counting, &NodeTopology{Keys: []string{"zone", "rack"}}, policies.SpreadTopologyMode, []string{"node-1"}, getNode)
+	ti.ForEachNode(func(node *Node) bool {
+		assert.Equal(t, node.NodeID, "node-3", "unexpected first node")
+		return false
+	})
+	assert.Equal(t, counting.visited, 3, "base iterator should have stopped at the first node in the best tier")
+}
+
+// countingIterator counts the nodes visited in the base iterator.
+type countingIterator struct {
+	base    NodeIterator
+	visited int
+}
+
+func (ci *countingIterator) ForEachNode(f func(*Node) bool) {
+	ci.base.ForEachNode(func(node *Node) bool {
+		ci.visited++
+		return f(node)
+	})
+}
+
+func TestApplicationTopologyIterator(t *testing.T) {
+	nc := newTopologyNodes(t)
+	app := newApplication(appID1, "default", "root.default")
+	app.allocations["alloc-1"] = newAllocationWithKey("alloc-1", appID1, "node-1", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1}))
+
+	// no topology: iterator is not changed
+	iterator := nc.GetNodeIterator()
+	assert.Equal(t, app.topologyIterator(iterator, nc.GetNode), iterator, "iterator should not be wrapped without topology")
+	assert.Equal(t, app.topologyIterator(testIterator{}, nc.GetNode), NodeIterator(testIterator{}), "iterator without topology support should not be wrapped")
+
+	nc.SetNodeTopology(&NodeTopology{Keys: []string{"zone"}, Mode: policies.SpreadTopologyMode})
+	assert.DeepEqual(t, iteratorNodeIDs(app.topologyIterator(iterator, nc.GetNode)), []string{"node-3", "node-1", "node-2", "node-4"})
+
+	// application tag overrides the mode
+	app = newApplicationWithTags(appID1, "default", "root.default", map[string]string{common.AppTagTopologyMode: "colocate"})
+	app.allocations["alloc-1"] = newAllocationWithKey("alloc-1", appID1, "node-3", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1}))
+	assert.DeepEqual(t, iteratorNodeIDs(app.topologyIterator(iterator, nc.GetNode)), []string{"node-3", "node-1", "node-2", "node-4"})
+	app = newApplicationWithTags(appID1, "default", "root.default", map[string]string{common.AppTagTopologyMode: "none"})
+	assert.Equal(t, app.topologyIterator(iterator, nc.GetNode), iterator, "iterator should not be wrapped when topology is ignored")
+	app = newApplicationWithTags(appID1, "default", "root.default", map[string]string{common.AppTagTopologyMode: "invalid"})
+	assert.Assert(t, app.topologyMode == nil, "invalid mode should be ignored")
+}
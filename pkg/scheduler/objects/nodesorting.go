@@ -20,10 +20,13 @@ package objects
 
 import (
 	"math"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/plugins"
 	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
 )
 
@@ -43,6 +46,85 @@ type fairnessNodeSortingPolicy struct {
 
 type fairWithAgingNodeSortingPolicy struct {
 	resourceWeights map[string]float64
+	aging           NodeAging
+}
+
+// AgingNodeSortingPolicy is implemented by node sorting policies that take the time a node has
+// not received an allocation into account when scoring the node.
+type AgingNodeSortingPolicy interface {
+	NodeSortingPolicy
+	AgingSettings() NodeAging
+}
+
+// ScorerNodeSortingPolicy is implemented by node sorting policies that score nodes using one or more
+// named scorers, like the node scorers registered in the plugins.
+type ScorerNodeSortingPolicy interface {
+	NodeSortingPolicy
+	Scorers() map[string]float64
+}
+
+// timeDependentNodeSortingPolicy is implemented by node sorting policies that do not use the aging settings
+// but might still calculate a score that changes over time.
+type timeDependentNodeSortingPolicy interface {
+	isTimeDependent() bool
+}
+
+// pluginNodeSortingPolicy scores the nodes using a node scorer registered in the plugins.
+type pluginNodeSortingPolicy struct {
+	name            string
+	scorer          plugins.NodeScorer
+	resourceWeights map[string]float64
+}
+
+// weightedScorer is one of the node sorting policies combined by the weighted node sorting policy.
+type weightedScorer struct {
+	name   string
+	weight float64
+	policy NodeSortingPolicy
+}
+
+// weightedNodeSortingPolicy scores the nodes using the weighted average of the scores of several policies.
+type weightedNodeSortingPolicy struct {
+	scorers         []weightedScorer
+	totalWeight     float64
+	resourceWeights map[string]float64
+}
+
+// NodeAging contains the settings to convert the idle time of a node into a score correction.
+type NodeAging struct {
+	Weight   float64             // weight of the aging factor compared to the usage share
+	Cap      float64             // maximum value of the weighted aging factor
+	Curve    policies.AgingCurve // curve used to calculate the aging factor
+	Period   time.Duration       // period for the linear and logarithmic curves
+	HalfLife time.Duration       // half-life for the exponential curve
+}
+
+// score returns the weighted and capped aging factor for the idle time.
+func (a NodeAging) score(idle time.Duration) float64 {
+	return math.Min(a.Weight*a.Curve.Factor(idle, a.Period, a.HalfLife), a.Cap)
+}
+
+// newNodeAging converts the aging configuration into the settings, using defaults for anything not set.
+// The configuration is expected to be validated.
+func newNodeAging(conf configs.NodeSortingAging) NodeAging {
+	aging := NodeAging{
+		Weight:   configs.DefaultNodeAgingWeight,
+		Cap:      configs.DefaultNodeAgingCap,
+		Period:   configs.GetNodeSortingAgingPeriod(conf),
+		HalfLife: configs.GetNodeSortingAgingHalfLife(conf),
+	}
+	if conf.Weight != nil {
+		aging.Weight = *conf.Weight
+	}
+	if conf.Cap != nil {
+		aging.Cap = *conf.Cap
+	}
+	var err error
+	if aging.Curve, err = policies.AgingCurveFromString(conf.Curve); err != nil {
+		log.Log(log.SchedNode).Debug("node aging curve defaulted to 'linear'",
+			zap.Error(err))
+	}
+	return aging
 }
 
 func (binPackingNodeSortingPolicy) PolicyType() policies.SortingPolicy {
@@ -57,6 +139,14 @@ func (p fairWithAgingNodeSortingPolicy) PolicyType() policies.SortingPolicy {
 	return policies.FairWithAgingNodePolicy
 }
 
+func (pluginNodeSortingPolicy) PolicyType() policies.SortingPolicy {
+	return policies.PluginNodePolicy
+}
+
+func (weightedNodeSortingPolicy) PolicyType() policies.SortingPolicy {
+	return policies.WeightedNodePolicy
+}
+
 func absResourceUsage(node *Node, weights *map[string]float64) float64 {
 	totalWeight := float64(0)
 	usage := float64(0)
@@ -95,25 +185,24 @@ func (p fairnessNodeSortingPolicy) ScoreNode(node *Node) float64 {
 }
 
 func (p fairWithAgingNodeSortingPolicy) ScoreNode(node *Node) float64 {
-	// 獲取等待時間，如果為 nil 則使用 0
-	waitingTime := float64(0)
-	if node != nil {
-		waitingTime = node.GetWaitingTime().Seconds()
-	}
-
-	resourceUsage := absResourceUsage(node, &p.resourceWeights)
-
-	// 越少使用（資源佔用低）+ 越久沒用 → 分數高
-	score := (1 - resourceUsage) + 0.3*waitingTime
+	// choose least loaded node first, corrected for the time the node has not been used:
+	// the longer a node is idle the lower the score
+	return absResourceUsage(node, &p.resourceWeights) - p.aging.score(node.GetWaitingTime())
+}
 
-	log.Log(log.SchedNode).Debug("fairWithAgingNode score",
-		zap.String("node", node.NodeID),
-		zap.Float64("resourceUsage", resourceUsage),
-		zap.Float64("waitingTime", waitingTime),
-		zap.Float64("score", score),
-	)
+func (p pluginNodeSortingPolicy) ScoreNode(node *Node) float64 {
+	return p.scorer.ScoreNode(node)
+}
 
-	return score
+func (p weightedNodeSortingPolicy) ScoreNode(node *Node) float64 {
+	if p.totalWeight == 0 {
+		return 0
+	}
+	score := float64(0)
+	for _, ws := range p.scorers {
+		score += ws.weight * ws.policy.ScoreNode(node)
+	}
+	return score / p.totalWeight
 }
 
 func cloneWeights(source map[string]float64) map[string]float64 {
@@ -136,6 +225,43 @@ func (p fairWithAgingNodeSortingPolicy) ResourceWeights() map[string]float64 {
 	return cloneWeights(p.resourceWeights)
 }
 
+func (p fairWithAgingNodeSortingPolicy) AgingSettings() NodeAging {
+	return p.aging
+}
+
+func (p pluginNodeSortingPolicy) ResourceWeights() map[string]float64 {
+	return cloneWeights(p.resourceWeights)
+}
+
+func (p weightedNodeSortingPolicy) ResourceWeights() map[string]float64 {
+	return cloneWeights(p.resourceWeights)
+}
+
+func (p pluginNodeSortingPolicy) Scorers() map[string]float64 {
+	return map[string]float64{p.name: 1}
+}
+
+func (p weightedNodeSortingPolicy) Scorers() map[string]float64 {
+	scorers := make(map[string]float64, len(p.scorers))
+	for _, ws := range p.scorers {
+		scorers[ws.name] = ws.weight
+	}
+	return scorers
+}
+
+func (p pluginNodeSortingPolicy) isTimeDependent() bool {
+	return p.scorer.IsTimeDependent()
+}
+
+func (p weightedNodeSortingPolicy) isTimeDependent() bool {
+	for _, ws := range p.scorers {
+		if isTimeDependent(ws.policy) {
+			return true
+		}
+	}
+	return false
+}
+
 // Return a default set of resource weights if not otherwise specified.
 func defaultResourceWeights() map[string]float64 {
 	weights := make(map[string]float64)
@@ -145,12 +271,20 @@ func defaultResourceWeights() map[string]float64 {
 }
 
 func NewNodeSortingPolicy(policyType string, resourceWeights map[string]float64) NodeSortingPolicy {
-	pType, err := policies.SortingPolicyFromString(policyType)
+	return NewNodeSortingPolicyFromConf(configs.NodeSortingPolicy{
+		Type:            policyType,
+		ResourceWeights: resourceWeights,
+	})
+}
+
+// NewNodeSortingPolicyFromConf creates the node sorting policy using all settings from the partition configuration.
+func NewNodeSortingPolicyFromConf(conf configs.NodeSortingPolicy) NodeSortingPolicy {
+	pType, err := policies.SortingPolicyFromString(conf.Type)
 	if err != nil {
 		log.Log(log.SchedNode).Debug("node sorting policy defaulted to 'undefined'",
 			zap.Error(err))
 	}
-	weights := resourceWeights
+	weights := conf.ResourceWeights
 	if len(weights) == 0 {
 		weights = defaultResourceWeights()
 	}
@@ -168,6 +302,18 @@ func NewNodeSortingPolicy(policyType string, resourceWeights map[string]float64)
 	case policies.FairWithAgingNodePolicy:
 		sp = fairWithAgingNodeSortingPolicy{
 			resourceWeights: weights,
+			aging:           newNodeAging(conf.Aging),
+		}
+	case policies.WeightedNodePolicy:
+		sp = newWeightedNodeSortingPolicy(conf, weights)
+	case policies.PluginNodePolicy:
+		sp = newPluginNodeSortingPolicy(conf.Type, weights)
+	}
+	// a plugin that was removed or a weighted policy without scorers: fall back to the default
+	if sp == nil {
+		pType = policies.FairnessPolicy
+		sp = fairnessNodeSortingPolicy{
+			resourceWeights: weights,
 		}
 	}
 
@@ -175,3 +321,55 @@ func NewNodeSortingPolicy(policyType string, resourceWeights map[string]float64)
 		zap.Stringer("type", pType), zap.Any("resourceWeights", weights))
 	return sp
 }
+
+// newPluginNodeSortingPolicy creates the policy for the node scorer registered under the name.
+// Returns nil if no scorer is registered under the name.
+func newPluginNodeSortingPolicy(name string, weights map[string]float64) NodeSortingPolicy {
+	factory := plugins.GetNodeScorerFactory(name)
+	if factory == nil {
+		return nil
+	}
+	scorer := factory(cloneWeights(weights))
+	if scorer == nil {
+		return nil
+	}
+	return pluginNodeSortingPolicy{
+		name:            name,
+		scorer:          scorer,
+		resourceWeights: weights,
+	}
+}
+
+// newWeightedNodeSortingPolicy creates the policies for all scorers in the configuration.
+// Scorers that cannot be created are skipped. Returns nil if no scorers could be created.
+// The configuration is expected to be validated.
+func newWeightedNodeSortingPolicy(conf configs.NodeSortingPolicy, weights map[string]float64) NodeSortingPolicy {
+	wp := weightedNodeSortingPolicy{
+		resourceWeights: weights,
+	}
+	for _, scorer := range conf.Scorers {
+		pType, err := policies.SortingPolicyFromString(scorer.Name)
+		if err != nil || pType == policies.WeightedNodePolicy || scorer.Weight <= 0 {
+			log.Log(log.SchedNode).Debug("node sorting scorer skipped",
+				zap.String("name", scorer.Name),
+				zap.Float64("weight", scorer.Weight),
+				zap.Error(err))
+			continue
+		}
+		policy := NewNodeSortingPolicyFromConf(configs.NodeSortingPolicy{
+			Type:            scorer.Name,
+			ResourceWeights: weights,
+			Aging:           conf.Aging,
+		})
+		wp.scorers = append(wp.scorers, weightedScorer{
+			name:   scorer.Name,
+			weight: scorer.Weight,
+			policy: policy,
+		})
+		wp.totalWeight += scorer.Weight
+	}
+	if len(wp.scorers) == 0 {
+		return nil
+	}
+	return wp
+}
@@ -19,6 +19,7 @@
 package objects
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 
 	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/plugins"
 	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
 )
 
@@ -199,9 +201,8 @@ func TestSortPolicyWeighting(t *testing.T) {
 	// node1 w/ binpacking: same but 1 - fair => 0.65
 	assert.Equal(t, 0.65, bin.ScoreNode(node1), "Wrong binpacking score for node1")
 
-	// node1 w/ aging: (1 - resourceUsage) + 0.3*waitingTime
-	// waitingTime is 0 initially
-	assert.Equal(t, 0.65, aging.ScoreNode(node1), "Wrong aging score for node1")
+	// node1 w/ aging: same as fair as the node has just been allocated on
+	assert.Assert(t, math.Abs(0.35-aging.ScoreNode(node1)) < 1e-3, "Wrong aging score for node1")
 
 	// node2 w/ fair: 75% vcore, 25% memory => ((.75 * 4) + (.25 * 1)) / 5 = 0.65
 	assert.Equal(t, 0.65, fair.ScoreNode(node2), "Wrong fair score for node2")
@@ -209,15 +210,15 @@ func TestSortPolicyWeighting(t *testing.T) {
 	// node2 w/ binpacking: same but 1 - fair = 0.35
 	assert.Equal(t, 0.35, bin.ScoreNode(node2), "Wrong binpacking score for node2")
 
-	// node2 w/ aging: (1 - resourceUsage) + 0.3*waitingTime
-	// waitingTime is 0 initially
-	assert.Equal(t, 0.35, aging.ScoreNode(node2), "Wrong aging score for node2")
+	// node2 w/ aging: same as fair as the node has just been allocated on
+	assert.Assert(t, math.Abs(0.65-aging.ScoreNode(node2)) < 1e-3, "Wrong aging score for node2")
 
 	// Test aging effect
-	// Simulate waiting time for node1
-	node1.SetWaitingTime(10 * time.Second)
-	// node1 w/ aging: (1 - 0.35) + 0.3*10 = 0.65 + 3 = 3.65
-	assert.Equal(t, 3.65, aging.ScoreNode(node1), "Wrong aging score for node1 with waiting time")
+	// Simulate waiting time for node2: full default period gives the default weight as correction
+	node2.SetWaitingTime(configs.DefaultNodeAgingPeriod)
+	// node2 w/ aging: 0.65 - 0.3 = 0.35
+	assert.Assert(t, math.Abs(0.35-aging.ScoreNode(node2)) < 1e-9, "Wrong aging score for node2 with waiting time")
+	node2.SetWaitingTime(0)
 
 	// node1 should be first as it is the least-loaded
 	nodes := make([]*Node, 0)
@@ -247,7 +248,7 @@ func TestSortPolicyWeighting(t *testing.T) {
 	// switch to aging
 	nc.SetNodeSortingPolicy(aging)
 
-	// node1 should be first as it has higher waiting time
+	// node1 should be first as it is the least-loaded
 	nodes = make([]*Node, 0)
 	nc.GetNodeIterator().ForEachNode(func(node *Node) bool {
 		nodes = append(nodes, node)
@@ -361,3 +362,150 @@ func TestAbsResourceUsage(t *testing.T) {
 	// node1 w/ fair: 400% vcore, 0% memory => ((0 * 4) + (.75 * NaN)) / 0 = 0
 	assert.Equal(t, 0.0, fair.ScoreNode(node1), "Wrong fair score for node1")
 }
+
+func TestFairWithAgingScore(t *testing.T) {
+	weight := 0.5
+	aCap := 0.4
+	policy, ok := NewNodeSortingPolicyFromConf(configs.NodeSortingPolicy{
+		Type: "fairwithaging",
+		Aging: configs.NodeSortingAging{
+			Weight:   &weight,
+			Cap:      &aCap,
+			Curve:    "exponential",
+			HalfLife: "1m",
+		},
+	}).(fairWithAgingNodeSortingPolicy)
+	if !ok {
+		t.Fatal("Didn't get aging policy")
+	}
+	aging := policy.AgingSettings()
+	assert.Equal(t, aging.Weight, weight, "weight not set from config")
+	assert.Equal(t, aging.Cap, aCap, "cap not set from config")
+	assert.Equal(t, aging.Curve, policies.ExponentialAgingCurve, "curve not set from config")
+	assert.Equal(t, aging.HalfLife, time.Minute, "half-life not set from config")
+	assert.Equal(t, aging.Period, configs.DefaultNodeAgingPeriod, "period should be defaulted")
+
+	totalRes := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000, "memory": 1000})
+	node := NewNode(newProto("test1", totalRes, map[string]string{}))
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 500, "memory": 500})
+	node.AddAllocation(newAllocation("test-app-1", "test1", res))
+	assert.Assert(t, math.Abs(0.5-policy.ScoreNode(node)) < 1e-3, "no waiting time: usage only")
+	// one half-life: 0.5 factor * 0.5 weight = 0.25
+	node.SetWaitingTime(time.Minute)
+	assert.Assert(t, math.Abs(0.25-policy.ScoreNode(node)) < 1e-3, "one half-life")
+	// long wait: correction is capped
+	node.SetWaitingTime(time.Hour)
+	assert.Assert(t, math.Abs(0.1-policy.ScoreNode(node)) < 1e-9, "correction should be capped")
+
+	// defaults
+	policy, ok = NewNodeSortingPolicy("fairwithaging", nil).(fairWithAgingNodeSortingPolicy)
+	if !ok {
+		t.Fatal("Didn't get aging policy")
+	}
+	aging = policy.AgingSettings()
+	assert.Equal(t, aging.Weight, configs.DefaultNodeAgingWeight, "default weight not set")
+	assert.Equal(t, aging.Cap, configs.DefaultNodeAgingCap, "default cap not set")
+	assert.Equal(t, aging.Curve, policies.LinearAgingCurve, "default curve not set")
+}
+
+// usedScorer scores nodes on the memory allocated, optionally corrected for the waiting time
+type usedScorer struct {
+	withWaiting bool
+}
+
+func (s usedScorer) ScoreNode(node plugins.NodeScoreInfo) float64 {
+	score := float64(node.GetAllocatedResource().Resources["memory"])
+	if s.withWaiting {
+		score -= node.GetWaitingTime().Minutes()
+	}
+	return score
+}
+
+func (s usedScorer) IsTimeDependent() bool {
+	return s.withWaiting
+}
+
+func TestPluginNodeSortingPolicy(t *testing.T) {
+	err := plugins.RegisterNodeScorer("used", func(_ map[string]float64) plugins.NodeScorer { return usedScorer{} })
+	assert.NilError(t, err, "node scorer registration failed")
+	defer plugins.UnregisterNodeScorer("used")
+
+	policy, ok := NewNodeSortingPolicy("used", nil).(pluginNodeSortingPolicy)
+	if !ok {
+		t.Fatal("Didn't get plugin policy")
+	}
+	assert.Equal(t, policy.PolicyType(), policies.PluginNodePolicy, "wrong policy type")
+	assert.DeepEqual(t, policy.Scorers(), map[string]float64{"used": 1})
+	assert.Assert(t, !isTimeDependent(policy), "plugin policy should not be time dependent")
+
+	totalRes := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000, "memory": 1000})
+	node := NewNode(newProto("test1", totalRes, map[string]string{}))
+	node.AddAllocation(newAllocation("test-app-1", "test1", resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 250})))
+	assert.Equal(t, policy.ScoreNode(node), float64(250), "score not calculated by the plugin")
+
+	// unregistered plugin falls back to the default
+	plugins.UnregisterNodeScorer("used")
+	_, ok = NewNodeSortingPolicy("used", nil).(fairnessNodeSortingPolicy)
+	assert.Assert(t, ok, "unregistered plugin should fall back to fair")
+}
+
+func TestWeightedNodeSortingPolicy(t *testing.T) {
+	err := plugins.RegisterNodeScorer("used", func(_ map[string]float64) plugins.NodeScorer { return usedScorer{withWaiting: true} })
+	assert.NilError(t, err, "node scorer registration failed")
+	defer plugins.UnregisterNodeScorer("used")
+
+	policy, ok := NewNodeSortingPolicyFromConf(configs.NodeSortingPolicy{
+		Type: "weighted",
+		Scorers: []configs.NodeSortingScorer{
+			{Name: "binpacking", Weight: 0.7},
+			{Name: "fair", Weight: 0.3},
+		},
+	}).(weightedNodeSortingPolicy)
+	if !ok {
+		t.Fatal("Didn't get weighted policy")
+	}
+	assert.Equal(t, policy.PolicyType(), policies.WeightedNodePolicy, "wrong policy type")
+	assert.DeepEqual(t, policy.Scorers(), map[string]float64{"binpacking": 0.7, "fair": 0.3})
+	assert.Assert(t, !isTimeDependent(policy), "weighted policy without aging should not be time dependent")
+
+	totalRes := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000, "memory": 1000})
+	node := NewNode(newProto("test1", totalRes, map[string]string{}))
+	node.AddAllocation(newAllocation("test-app-1", "test1", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 250, "memory": 250})))
+	// binpacking: 1 - 0.25 = 0.75, fair: 0.25 => 0.7 * 0.75 + 0.3 * 0.25 = 0.6
+	assert.Assert(t, math.Abs(0.6-policy.ScoreNode(node)) < 1e-9, "wrong weighted score")
+
+	// weights are normalised and time dependence is taken from the scorers
+	policy, ok = NewNodeSortingPolicyFromConf(configs.NodeSortingPolicy{
+		Type: "weighted",
+		Scorers: []configs.NodeSortingScorer{
+			{Name: "fair", Weight: 2},
+			{Name: "used", Weight: 2},
+		},
+	}).(weightedNodeSortingPolicy)
+	if !ok {
+		t.Fatal("Didn't get weighted policy")
+	}
+	assert.Assert(t, isTimeDependent(policy), "weighted policy with time dependent plugin should be time dependent")
+	node.SetWaitingTime(10 * time.Minute)
+	// fair: 0.25, used: 250 - 10 => (0.25 + 240) / 2
+	assert.Assert(t, math.Abs(120.125-policy.ScoreNode(node)) < 1e-3, "wrong weighted score with plugin")
+
+	policy, ok = NewNodeSortingPolicyFromConf(configs.NodeSortingPolicy{
+		Type: "weighted",
+		Scorers: []configs.NodeSortingScorer{
+			{Name: "binpacking", Weight: 0.7},
+			{Name: "fairwithaging", Weight: 0.3},
+		},
+	}).(weightedNodeSortingPolicy)
+	if !ok {
+		t.Fatal("Didn't get weighted policy")
+	}
+	assert.Assert(t, isTimeDependent(policy), "weighted policy with aging should be time dependent")
+
+	// no usable scorers falls back to the default
+	_, ok = NewNodeSortingPolicyFromConf(configs.NodeSortingPolicy{
+		Type:    "weighted",
+		Scorers: []configs.NodeSortingScorer{{Name: "unknown", Weight: 1}},
+	}).(fairnessNodeSortingPolicy)
+	assert.Assert(t, ok, "weighted policy without scorers should fall back to fair")
+}
@@ -36,10 +36,29 @@ type predicateCheckResult struct {
 	success       bool
 	index         int
 	victims       []*Allocation
+	cost          float64 // total cost of the victims
 }
 
+// betterThan returns true if this result is a better preemption solution than the other result.
+// Unfit solutions, and solutions that preempt allocations which did not opt into preemption, are compared on the
+// score. Otherwise the solution with the lowest total victim cost is better, the score breaks ties.
 func (pcr *predicateCheckResult) betterThan(other *predicateCheckResult, allocationsByNode map[string][]*Allocation) bool {
-	return pcr.getSolutionScore(allocationsByNode) < other.getSolutionScore(allocationsByNode)
+	score := pcr.getSolutionScore(allocationsByNode)
+	otherScore := other.getSolutionScore(allocationsByNode)
+	if score&(scoreUnfit|scoreNoPreempt) != otherScore&(scoreUnfit|scoreNoPreempt) {
+		return score < otherScore
+	}
+	if cost, otherCost := pcr.getCost(), other.getCost(); cost != otherCost {
+		return cost < otherCost
+	}
+	return score < otherScore
+}
+
+func (pcr *predicateCheckResult) getCost() float64 {
+	if pcr == nil {
+		return 0
+	}
+	return pcr.cost
 }
 
 func (pcr *predicateCheckResult) getSolutionScore(allocationsByNode map[string][]*Allocation) uint64 {
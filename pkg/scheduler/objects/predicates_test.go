@@ -61,6 +61,19 @@ func TestSolutionScoring(t *testing.T) {
 
 	assert.Check(t, noOp.betterThan(none, singleAlloc), "noop should be better than nil")
 	assert.Check(t, noOp.betterThan(ideal, singleAlloc), "noop should be better than ideal")
+
+	// cost is compared before the score, unless the result is unfit or preempts an allocation not allowed
+	cheap := &predicateCheckResult{nodeID: nodeID1, success: true, index: 0, cost: 10}
+	expensive := &predicateCheckResult{nodeID: nodeID1, success: true, index: -1, cost: 100}
+	assert.Check(t, cheap.betterThan(expensive, singleAlloc), "cheaper result should be better")
+	assert.Check(t, !expensive.betterThan(cheap, singleAlloc), "expensive result should not be better")
+	mixed := scoreMap(nodeID1, []bool{false}, []bool{false})
+	for k, v := range scoreMap(nodeID2, []bool{false}, []bool{true}) {
+		mixed[k] = v
+	}
+	cheapNoPreempt := &predicateCheckResult{nodeID: nodeID1, success: true, index: 0, cost: 10}
+	expensiveAllowed := &predicateCheckResult{nodeID: nodeID2, success: true, index: 0, cost: 100}
+	assert.Check(t, expensiveAllowed.betterThan(cheapNoPreempt, mixed), "no-preempt result should be worse regardless of cost")
 }
 
 func TestPredicateCheckResult_String(t *testing.T) {
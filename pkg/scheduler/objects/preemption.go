@@ -42,6 +42,15 @@ var (
 	scoreUnfit              uint64 = 1 << 35
 )
 
+// reasons for failing the preemption preconditions
+const (
+	preemptNotAllowed      = "ask is not allowed to preempt other allocations"
+	preemptAlreadyDone     = "ask has already triggered preemption"
+	preemptRequiredNode    = "ask requires a specific node, preemption is handled by the required node preemption"
+	preemptDelayNotPassed  = "preemption delay has not passed since the ask was created"
+	preemptCheckedRecently = "preemption was checked for the ask less than the attempt frequency ago"
+)
+
 // Preemptor encapsulates the functionality required for preemption victim selection
 type Preemptor struct {
 	application     *Application        // application containing ask
@@ -52,12 +61,17 @@ type Preemptor struct {
 	ask             *Allocation         // ask to be preempted for
 	iterator        NodeIterator        // iterator to enumerate all nodes
 	nodesTried      bool                // flag indicating that scheduling has already been tried on all nodes
+	now             time.Time           // time the preemption attempt started, used to calculate victim costs
+	costWeights     victimCostWeights   // weights of the victim cost model of the queue
+	costs           map[string]float64  // cost per victim allocationKey, calculated on first use
 
 	// lazily-populated work structures
 	allocationsByQueue map[string]*QueuePreemptionSnapshot // map of queue snapshots by queue path
 	queueByAlloc       map[string]*QueuePreemptionSnapshot // map of queue snapshots by allocationKey
 	allocationsByNode  map[string][]*Allocation            // map of allocation by nodeID
 	nodeAvailableMap   map[string]*resources.Resource      // map of available resources by nodeID
+	nodes              map[string]*Node                    // map of nodes available for preemption by nodeID
+	gangs              map[string][]*Allocation            // members of the task group by allocationKey, only for victims preempted as a gang
 }
 
 // QueuePreemptionSnapshot is used to track a snapshot of a queue for preemption
@@ -71,6 +85,10 @@ type QueuePreemptionSnapshot struct {
 	GuaranteedResource *resources.Resource      // guaranteed resources for this queue
 	PotentialVictims   []*Allocation            // list of allocations which could be preempted
 	AskQueue           *QueuePreemptionSnapshot // snapshot of ask or preemptor queue
+
+	budget     *budgetHeadroom            // preemption budget left for the queue, nil if not limited
+	appBudgets map[string]*budgetHeadroom // preemption budget left per application, only limited applications are tracked
+	gangs      [][]*Allocation            // task groups that are preempted as one unit, all members are potential victims
 }
 
 // NewPreemptor creates a new preemptor. The preemptor itself is not thread safe, and assumes the application lock is held.
@@ -84,43 +102,56 @@ func NewPreemptor(application *Application, headRoom *resources.Resource, preemp
 		ask:             ask,
 		iterator:        iterator,
 		nodesTried:      nodesTried,
+		now:             time.Now(),
+		costWeights:     application.queue.getVictimCostWeights(),
+		costs:           make(map[string]float64),
 	}
 }
 
 // CheckPreconditions performs simple sanity checks designed to determine if preemption should be attempted
 // for an ask. If checks succeed, updates the ask preemption check time.
 func (p *Preemptor) CheckPreconditions() bool {
-	now := time.Now()
+	if len(p.failedPreconditions(time.Now())) != 0 {
+		return false
+	}
+
+	// mark this ask as having been checked recently to avoid doing extra work in the next scheduling cycle
+	p.ask.UpdatePreemptCheckTime()
+
+	return true
+}
+
+// failedPreconditions returns the reasons the preconditions for the ask are not met, empty if all are met.
+// The state of the ask is not changed.
+func (p *Preemptor) failedPreconditions(now time.Time) []string {
+	var failed []string
 
 	// skip if ask is not allowed to preempt other tasks
 	if !p.ask.IsAllowPreemptOther() {
-		return false
+		failed = append(failed, preemptNotAllowed)
 	}
 
 	// skip if ask has previously triggered preemption
 	if p.ask.HasTriggeredPreemption() {
-		return false
+		failed = append(failed, preemptAlreadyDone)
 	}
 
 	// skip if ask requires a specific node (this should be handled by required node preemption algorithm)
 	if p.ask.GetRequiredNode() != "" {
-		return false
+		failed = append(failed, preemptRequiredNode)
 	}
 
 	// skip if preemption delay has not yet passed
 	if now.Before(p.ask.GetCreateTime().Add(p.preemptionDelay)) {
-		return false
+		failed = append(failed, preemptDelayNotPassed)
 	}
 
 	// skip if attempt frequency hasn't been reached again
 	if now.Before(p.ask.GetPreemptCheckTime().Add(preemptAttemptFrequency)) {
-		return false
+		failed = append(failed, preemptCheckedRecently)
 	}
 
-	// mark this ask as having been checked recently to avoid doing extra work in the next scheduling cycle
-	p.ask.UpdatePreemptCheckTime()
-
-	return true
+	return failed
 }
 
 // initQueueSnapshots ensures that snapshots have been taken of the queue
@@ -130,6 +161,7 @@ func (p *Preemptor) initQueueSnapshots() {
 	}
 
 	p.allocationsByQueue = p.queue.FindEligiblePreemptionVictims(p.queuePath, p.ask)
+	p.initGangs()
 }
 
 // initWorkingState builds helper data structures required to compute a solution
@@ -145,17 +177,27 @@ func (p *Preemptor) initWorkingState() {
 	allocationsByNode := make(map[string][]*Allocation)
 	queueByAlloc := make(map[string]*QueuePreemptionSnapshot)
 	nodeAvailableMap := make(map[string]*resources.Resource)
+	nodes := make(map[string]*Node)
 
 	// build a map from NodeID to allocation and from allocationKey to queue capacities
+	// a gang is listed once per node, the first member found on the node represents all members on that node
+	listed := make(map[string]bool)
 	for _, victims := range p.allocationsByQueue {
 		for _, allocation := range victims.PotentialVictims {
 			nodeID := allocation.GetNodeID()
+			queueByAlloc[allocation.GetAllocationKey()] = victims
+			if members, ok := p.gangs[allocation.GetAllocationKey()]; ok {
+				unitKey := nodeID + "/" + members[0].GetAllocationKey()
+				if listed[unitKey] {
+					continue
+				}
+				listed[unitKey] = true
+			}
 			allocations, ok := allocationsByNode[nodeID]
 			if !ok {
 				allocations = make([]*Allocation, 0)
 			}
 			allocationsByNode[nodeID] = append(allocations, allocation)
-			queueByAlloc[allocation.GetAllocationKey()] = victims
 		}
 	}
 
@@ -167,16 +209,18 @@ func (p *Preemptor) initWorkingState() {
 		} else {
 			// track allocated and available resources
 			nodeAvailableMap[node.NodeID] = node.GetAvailableResource()
+			nodes[node.NodeID] = node
 		}
 		return true
 	})
 
 	// sort the allocations on each node in the order we'd like to try them
-	sortVictimsForPreemption(allocationsByNode)
+	p.queueByAlloc = queueByAlloc
+	p.sortVictimsForPreemption(allocationsByNode)
 
 	p.allocationsByNode = allocationsByNode
-	p.queueByAlloc = queueByAlloc
 	p.nodeAvailableMap = nodeAvailableMap
+	p.nodes = nodes
 }
 
 // checkPreemptionQueueGuarantees verifies that it's possible to free enough resources to fit the given ask
@@ -237,11 +281,13 @@ func (p *Preemptor) calculateVictimsByNode(nodeAvailable *resources.Resource, po
 	head := make([]*Allocation, 0)
 	tail := make([]*Allocation, 0)
 	for _, victim := range potentialVictims {
+		victimResource := p.victimResource(victim)
+		nodeResource := p.victimNodeResource(victim)
 		// check to see if removing this task will keep queue above guaranteed amount; if not, skip to the next one
 		if qv, ok := p.queueByAlloc[victim.GetAllocationKey()]; ok {
 			if queueSnapshot, ok2 := allocationsByQueueSnap[qv.QueuePath]; ok2 {
 				oldRemaining := queueSnapshot.GetRemainingGuaranteedResource()
-				queueSnapshot.RemoveAllocation(victim.GetAllocatedResource())
+				queueSnapshot.RemoveAllocation(victimResource)
 				preemptableResource := queueSnapshot.GetPreemptableResource()
 
 				// Did removing this allocation still keep the queue over-allocated?
@@ -254,33 +300,33 @@ func (p *Preemptor) calculateVictimsByNode(nodeAvailable *resources.Resource, po
 				if resources.StrictlyGreaterThanOrEquals(preemptableResource, resources.Zero) &&
 					(oldRemaining == nil || resources.StrictlyGreaterThan(resources.Zero, oldRemaining)) {
 					// add the current victim into the ask queue
-					askQueue.AddAllocation(victim.GetAllocatedResource())
+					askQueue.AddAllocation(nodeResource)
 					askQueueNewRemaining := askQueue.GetRemainingGuaranteedResource()
 
 					// Did adding this allocation make the ask queue over - utilized?
 					if askQueueNewRemaining != nil && resources.StrictlyGreaterThan(resources.Zero, askQueueNewRemaining) {
-						askQueue.RemoveAllocation(victim.GetAllocatedResource())
-						queueSnapshot.AddAllocation(victim.GetAllocatedResource())
+						askQueue.RemoveAllocation(nodeResource)
+						queueSnapshot.AddAllocation(victimResource)
 						break
 					}
 
 					// check to see if the shortfall on the node has changed
 					shortfall := resources.SubEliminateNegative(p.ask.GetAllocatedResource(), nodeCurrentAvailable)
-					newAvailable := resources.Add(nodeCurrentAvailable, victim.GetAllocatedResource())
+					newAvailable := resources.Add(nodeCurrentAvailable, nodeResource)
 					newShortfall := resources.SubEliminateNegative(p.ask.GetAllocatedResource(), newAvailable)
 					if resources.EqualsOrEmpty(shortfall, newShortfall) {
 						// shortfall did not change, so task should only be considered as a last resort
-						askQueue.RemoveAllocation(victim.GetAllocatedResource())
-						queueSnapshot.AddAllocation(victim.GetAllocatedResource())
+						askQueue.RemoveAllocation(nodeResource)
+						queueSnapshot.AddAllocation(victimResource)
 						tail = append(tail, victim)
 					} else {
 						// shortfall was decreased, so we should keep this task on the main list and adjust usage
-						nodeCurrentAvailable.AddTo(victim.GetAllocatedResource())
+						nodeCurrentAvailable.AddTo(nodeResource)
 						head = append(head, victim)
 					}
 				} else {
 					// removing this allocation would have reduced queue below guaranteed limits, put it back
-					queueSnapshot.AddAllocation(victim.GetAllocatedResource())
+					queueSnapshot.AddAllocation(victimResource)
 				}
 			}
 		}
@@ -309,11 +355,12 @@ func (p *Preemptor) calculateVictimsByNode(nodeAvailable *resources.Resource, po
 	results := make([]*Allocation, 0)
 	index := -1
 	for _, victim := range head {
+		victimResource := p.victimResource(victim)
 		// check to see if removing this task will keep queue above guaranteed amount; if not, skip to the next one
 		if qv, ok := p.queueByAlloc[victim.GetAllocationKey()]; ok {
 			if queueSnapshot, ok2 := allocationsByQueueSnap[qv.QueuePath]; ok2 {
 				oldRemaining := queueSnapshot.GetRemainingGuaranteedResource()
-				queueSnapshot.RemoveAllocation(victim.GetAllocatedResource())
+				queueSnapshot.RemoveAllocation(victimResource)
 				preemptableResource := queueSnapshot.GetPreemptableResource()
 
 				// Did removing this allocation still keep the queue over-allocated?
@@ -324,7 +371,7 @@ func (p *Preemptor) calculateVictimsByNode(nodeAvailable *resources.Resource, po
 				if resources.StrictlyGreaterThanOrEquals(preemptableResource, resources.Zero) &&
 					(oldRemaining == nil || resources.StrictlyGreaterThan(resources.Zero, oldRemaining)) {
 					// removing task does not violate queue constraints, adjust queue and node
-					nodeCurrentAvailable.AddTo(victim.GetAllocatedResource())
+					nodeCurrentAvailable.AddTo(p.victimNodeResource(victim))
 					// check if ask now fits and we haven't had this happen before
 					if nodeCurrentAvailable.FitIn(p.ask.GetAllocatedResource()) && index < 0 {
 						index = len(results)
@@ -333,7 +380,7 @@ func (p *Preemptor) calculateVictimsByNode(nodeAvailable *resources.Resource, po
 					results = append(results, victim)
 				} else {
 					// add back resources
-					queueSnapshot.AddAllocation(victim.GetAllocatedResource())
+					queueSnapshot.AddAllocation(victimResource)
 				}
 			}
 		}
@@ -362,8 +409,15 @@ func (p *Preemptor) checkPreemptionPredicates(predicateChecks []*si.PreemptionPr
 		return nil
 	}
 
-	// sort predicate checks by number of expected preempted tasks
+	// sort predicate checks by the cost of the expected preempted tasks, then by the number of expected preempted tasks
+	checkCost := func(check *si.PreemptionPredicatesArgs) float64 {
+		index := p.victimIndex(victimsByNode[check.NodeID], int(check.StartIndex))
+		return p.solutionCost(&predicateCheckResult{nodeID: check.NodeID, index: index}, victimsByNode)
+	}
 	sort.SliceStable(predicateChecks, func(i int, j int) bool {
+		if costLeft, costRight := checkCost(predicateChecks[i]), checkCost(predicateChecks[j]); costLeft != costRight {
+			return costLeft < costRight
+		}
 		// sort by NodeID if StartIndex are same
 		if predicateChecks[i].StartIndex == predicateChecks[j].StartIndex {
 			return predicateChecks[i].NodeID < predicateChecks[j].NodeID
@@ -384,7 +438,7 @@ func (p *Preemptor) checkPreemptionPredicates(predicateChecks []*si.PreemptionPr
 			allocationKey: check.AllocationKey,
 			nodeID:        check.NodeID,
 			success:       true,
-			index:         int(check.StartIndex),
+			index:         p.victimIndex(victimsByNode[check.NodeID], int(check.StartIndex)),
 		}
 		result.populateVictims(victimsByNode)
 		return result
@@ -411,6 +465,8 @@ func (p *Preemptor) checkPreemptionPredicates(predicateChecks []*si.PreemptionPr
 		for result := range ch {
 			// if resultType is successful, keep track of it
 			if result.success {
+				result.index = p.victimIndex(victimsByNode[result.nodeID], result.index)
+				result.cost = p.solutionCost(result, victimsByNode)
 				if bestResult == nil {
 					bestResult = result
 				} else if result.betterThan(bestResult, p.allocationsByNode) {
@@ -444,13 +500,15 @@ func (p *Preemptor) calculateAdditionalVictims(nodeVictims []*Allocation) ([]*Al
 	for _, victim := range nodeVictims {
 		if qv, ok := p.queueByAlloc[victim.GetAllocationKey()]; ok {
 			if queueSnapshot, ok2 := allocationsByQueueSnap[qv.QueuePath]; ok2 {
-				queueSnapshot.RemoveAllocation(victim.GetAllocatedResource())
-				seen[victim.GetAllocationKey()] = victim
+				queueSnapshot.RemoveAllocation(p.victimResource(victim))
+				for _, member := range p.victimUnit(victim) {
+					seen[member.GetAllocationKey()] = member
+				}
 			}
 		}
 	}
 
-	// build and sort list of potential victims
+	// build and sort list of potential victims, a gang is only listed once
 	potentialVictims := make([]*Allocation, 0)
 	for _, alloc := range p.allocationsByQueue {
 		for _, victim := range alloc.PotentialVictims {
@@ -458,21 +516,37 @@ func (p *Preemptor) calculateAdditionalVictims(nodeVictims []*Allocation) ([]*Al
 				// skip already processed victim
 				continue
 			}
+			for _, member := range p.victimUnit(victim) {
+				seen[member.GetAllocationKey()] = member
+			}
 			potentialVictims = append(potentialVictims, victim)
 		}
 	}
 	sort.SliceStable(potentialVictims, func(i, j int) bool {
-		return compareAllocationLess(potentialVictims[i], potentialVictims[j])
+		return p.compareVictimsLess(potentialVictims[i], potentialVictims[j])
 	})
 
+	// the node victims are already within the preemption budgets, they count against the budgets
+	tracker := newBudgetTracker()
+	for _, victim := range nodeVictims {
+		tracker.add(p.victimUnit(victim), p.victimBudgets(victim)...)
+	}
+
 	// evaluate each potential victim in turn, stopping once sufficient resources have been freed
 	victims := make([]*Allocation, 0)
 	for _, victim := range potentialVictims {
+		// skip the victim if it would exceed the preemption budget of its queue or application
+		unit := p.victimUnit(victim)
+		budgets := p.victimBudgets(victim)
+		if !tracker.fits(unit, budgets...) {
+			continue
+		}
+		victimResource := p.victimResource(victim)
 		// check to see if removing this task will keep queue above guaranteed amount; if not, skip to the next one
 		if qv, ok := p.queueByAlloc[victim.GetAllocationKey()]; ok {
 			if queueSnapshot, ok2 := allocationsByQueueSnap[qv.QueuePath]; ok2 {
 				oldRemaining := queueSnapshot.GetRemainingGuaranteedResource()
-				queueSnapshot.RemoveAllocation(victim.GetAllocatedResource())
+				queueSnapshot.RemoveAllocation(victimResource)
 
 				// Did removing this allocation still keep the queue over-allocated?
 				// At times, over-allocation happens because of resource types in usage but not defined as guaranteed.
@@ -484,26 +558,27 @@ func (p *Preemptor) calculateAdditionalVictims(nodeVictims []*Allocation) ([]*Al
 					askQueueRemainingAfterVictimRemoval := askQueue.GetRemainingGuaranteedResource()
 
 					// add the current victim into the ask queue
-					askQueue.AddAllocation(victim.GetAllocatedResource())
+					askQueue.AddAllocation(victimResource)
 					askQueueNewRemaining := askQueue.GetRemainingGuaranteedResource()
 					// Did adding this allocation make the ask queue over - utilized?
 					if askQueueNewRemaining != nil && resources.StrictlyGreaterThan(resources.Zero, askQueueNewRemaining) {
-						askQueue.RemoveAllocation(victim.GetAllocatedResource())
-						queueSnapshot.AddAllocation(victim.GetAllocatedResource())
+						askQueue.RemoveAllocation(victimResource)
+						queueSnapshot.AddAllocation(victimResource)
 						break
 					}
 					// check to see if the shortfall on the queue has changed
 					if !resources.EqualsOrEmpty(askQueueRemainingAfterVictimRemoval, askQueueNewRemaining) {
 						// remaining capacity changed, so we should keep this task
 						victims = append(victims, victim)
+						tracker.add(unit, budgets...)
 					} else {
 						// remaining guaranteed amount in ask queue did not change, so preempting task won't help
-						askQueue.RemoveAllocation(victim.GetAllocatedResource())
-						queueSnapshot.AddAllocation(victim.GetAllocatedResource())
+						askQueue.RemoveAllocation(victimResource)
+						queueSnapshot.AddAllocation(victimResource)
 					}
 				} else {
 					// removing this allocation would have reduced queue below guaranteed limits, put it back
-					queueSnapshot.AddAllocation(victim.GetAllocatedResource())
+					queueSnapshot.AddAllocation(victimResource)
 				}
 			}
 		}
@@ -519,6 +594,18 @@ func (p *Preemptor) calculateAdditionalVictims(nodeVictims []*Allocation) ([]*Al
 // tryNodes attempts to find potential nodes for scheduling. For each node, potential victims are passed to
 // the shim for evaluation, and the best solution found will be returned.
 func (p *Preemptor) tryNodes() (string, []*Allocation, bool) {
+	predicateChecks, victimsByNode := p.calculatePredicateChecks()
+	// call predicates to evaluate each node
+	result := p.checkPreemptionPredicates(predicateChecks, victimsByNode)
+	if result != nil && result.success {
+		return result.nodeID, result.victims, true
+	}
+	return "", nil, false
+}
+
+// calculatePredicateChecks calculates the victims for each node and builds the predicate checks for the nodes
+// that should be evaluated by the shim.
+func (p *Preemptor) calculatePredicateChecks() ([]*si.PreemptionPredicatesArgs, map[string][]*Allocation) {
 	// calculate victim list for each node
 	predicateChecks := make([]*si.PreemptionPredicatesArgs, 0)
 	victimsByNode := make(map[string][]*Allocation)
@@ -531,27 +618,19 @@ func (p *Preemptor) tryNodes() (string, []*Allocation, bool) {
 		// identify which victims and in which order should be tried
 		if idx, victims := p.calculateVictimsByNode(nodeAvailable, allocations); victims != nil {
 			victimsByNode[nodeID] = victims
-			keys := make([]string, 0)
-			for _, victim := range victims {
-				keys = append(keys, victim.GetAllocationKey())
-			}
+			keys, keyIdx := p.predicateKeys(victims, idx)
 			// only check this node if there are victims or we have not already tried scheduling
 			if len(victims) > 0 || !p.nodesTried {
 				predicateChecks = append(predicateChecks, &si.PreemptionPredicatesArgs{
 					AllocationKey:         p.ask.GetAllocationKey(),
 					NodeID:                nodeID,
 					PreemptAllocationKeys: keys,
-					StartIndex:            int32(idx), //nolint: gosec
+					StartIndex:            int32(keyIdx), //nolint: gosec
 				})
 			}
 		}
 	}
-	// call predicates to evaluate each node
-	result := p.checkPreemptionPredicates(predicateChecks, victimsByNode)
-	if result != nil && result.success {
-		return result.nodeID, result.victims, true
-	}
-	return "", nil, false
+	return predicateChecks, victimsByNode
 }
 
 func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
@@ -567,8 +646,8 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 	// try to find a node to schedule on and victims to preempt
 	nodeID, victims, ok := p.tryNodes()
 	if !ok {
-		// no preemption possible
-		return nil, false
+		// no single node can fit the ask, try to make room by preempting across nodes
+		return p.tryCrossNodePreemption()
 	}
 
 	// look for additional victims in case we have not yet made enough capacity in the queue
@@ -582,6 +661,64 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 		return nil, false
 	}
 
+	finalVictims, ok := p.selectFinalVictims(nodeID, victims)
+	if !ok {
+		// there is shortfall, so preemption doesn't help
+		p.ask.LogAllocationFailure(common.PreemptionShortfall, true)
+		return nil, false
+	}
+
+	// preempt the victims
+	record := newPreemptionRecord(p.ask, p.queuePath, nodeID, false)
+	p.preemptVictims(finalVictims, record)
+	p.queue.recordPreemptionDecision(record)
+
+	// mark ask as having triggered preemption so that we don't preempt again
+	p.ask.MarkTriggeredPreemption()
+
+	// notify RM that victims should be released
+	p.application.notifyRMAllocationReleased(finalVictims, si.TerminationType_PREEMPTED_BY_SCHEDULER,
+		"preempting allocations to free up resources to run ask: "+p.ask.GetAllocationKey())
+
+	// reserve the selected node for the new allocation if it will fit
+	log.Log(log.SchedPreemption).Info("Reserving node for ask after preemption",
+		zap.String("allocationKey", p.ask.GetAllocationKey()),
+		zap.String("nodeID", nodeID),
+		zap.Int("victimCount", len(victims)))
+	return newReservedAllocationResult(nodeID, p.ask), true
+}
+
+// preemptVictims marks the victims as preempted and adds them to the preemption record.
+func (p *Preemptor) preemptVictims(victims []*Allocation, record *PreemptionRecord) {
+	for _, victim := range victims {
+		if victimQueue := p.queue.FindQueueByAppID(victim.GetApplicationID()); victimQueue != nil {
+			victimQueue.IncPreemptingResource(victim.GetAllocatedResource())
+			victim.MarkPreempted()
+			victimQueue.recordPreemption(victim)
+			record.addVictim(victim, victimQueue.QueuePath)
+			log.Log(log.SchedPreemption).Info("Preempting task",
+				zap.String("askApplicationID", p.ask.applicationID),
+				zap.String("askAllocationKey", p.ask.allocationKey),
+				zap.String("askQueue", p.queue.Name),
+				zap.String("victimApplicationID", victim.GetApplicationID()),
+				zap.String("victimAllocationKey", victim.GetAllocationKey()),
+				zap.Stringer("victimAllocatedResource", victim.GetAllocatedResource()),
+				zap.String("victimNodeID", victim.GetNodeID()),
+				zap.String("victimQueue", victimQueue.Name),
+			)
+			victim.SendPreemptedBySchedulerEvent(p.ask.allocationKey, p.ask.applicationID, p.application.queuePath)
+		} else {
+			log.Log(log.SchedPreemption).Warn("BUG: Queue not found for preemption victim",
+				zap.String("queue", p.queue.Name),
+				zap.String("victimApplicationID", victim.GetApplicationID()),
+				zap.String("victimAllocationKey", victim.GetAllocationKey()))
+		}
+	}
+}
+
+// selectFinalVictims filters the victims down to the victims required to fit the ask on the node.
+// Returns false if the victims do not free up enough resources for the ask.
+func (p *Preemptor) selectFinalVictims(nodeID string, victims []*Allocation) ([]*Allocation, bool) {
 	// Did victims collected so far fulfill the ask need? In case of any shortfall between the ask resource requirement
 	// and total victims resources, preemption won't help even though victims has been collected.
 
@@ -599,63 +736,35 @@ func (p *Preemptor) TryPreemption() (*AllocationResult, bool) {
 	// on different criteria. for example, victims could be picked up either from specific node (bin packing) or
 	// from multiple nodes (fair) given the choices.
 	var finalVictims []*Allocation
+	tracker := newBudgetTracker()
 	for _, victim := range victims {
 		// Victims from any node is acceptable as long as chosen node has enough space to accommodate the ask
 		// Otherwise, preempting victims from 'n' different nodes doesn't help to achieve the goal.
 		if !fitIn && victim.GetNodeID() != nodeID {
 			continue
 		}
+		// only the members of a gang on the chosen node free up space for the ask
+		freed := p.victimResource(victim)
+		if !fitIn {
+			freed = p.victimNodeResource(victim)
+		}
 		// stop collecting the victims once ask resource requirement met
 		if p.ask.GetAllocatedResource().StrictlyGreaterThanOnlyExisting(victimsTotalResource) {
+			// never select more victims than the preemption budgets allow
+			if !tracker.take(p.victimUnit(victim), p.victimBudgets(victim)...) {
+				continue
+			}
 			finalVictims = append(finalVictims, victim)
 		}
 		// add the victim resources to the total
-		victimsTotalResource.AddTo(victim.GetAllocatedResource())
+		victimsTotalResource.AddTo(freed)
 	}
 
 	if p.ask.GetAllocatedResource().StrictlyGreaterThanOnlyExisting(victimsTotalResource) {
-		// there is shortfall, so preemption doesn't help
-		p.ask.LogAllocationFailure(common.PreemptionShortfall, true)
 		return nil, false
 	}
-
-	// preempt the victims
-	for _, victim := range finalVictims {
-		if victimQueue := p.queue.FindQueueByAppID(victim.GetApplicationID()); victimQueue != nil {
-			victimQueue.IncPreemptingResource(victim.GetAllocatedResource())
-			victim.MarkPreempted()
-			log.Log(log.SchedPreemption).Info("Preempting task",
-				zap.String("askApplicationID", p.ask.applicationID),
-				zap.String("askAllocationKey", p.ask.allocationKey),
-				zap.String("askQueue", p.queue.Name),
-				zap.String("victimApplicationID", victim.GetApplicationID()),
-				zap.String("victimAllocationKey", victim.GetAllocationKey()),
-				zap.Stringer("victimAllocatedResource", victim.GetAllocatedResource()),
-				zap.String("victimNodeID", victim.GetNodeID()),
-				zap.String("victimQueue", victimQueue.Name),
-			)
-			victim.SendPreemptedBySchedulerEvent(p.ask.allocationKey, p.ask.applicationID, p.application.queuePath)
-		} else {
-			log.Log(log.SchedPreemption).Warn("BUG: Queue not found for preemption victim",
-				zap.String("queue", p.queue.Name),
-				zap.String("victimApplicationID", victim.GetApplicationID()),
-				zap.String("victimAllocationKey", victim.GetAllocationKey()))
-		}
-	}
-
-	// mark ask as having triggered preemption so that we don't preempt again
-	p.ask.MarkTriggeredPreemption()
-
-	// notify RM that victims should be released
-	p.application.notifyRMAllocationReleased(finalVictims, si.TerminationType_PREEMPTED_BY_SCHEDULER,
-		"preempting allocations to free up resources to run ask: "+p.ask.GetAllocationKey())
-
-	// reserve the selected node for the new allocation if it will fit
-	log.Log(log.SchedPreemption).Info("Reserving node for ask after preemption",
-		zap.String("allocationKey", p.ask.GetAllocationKey()),
-		zap.String("nodeID", nodeID),
-		zap.Int("victimCount", len(victims)))
-	return newReservedAllocationResult(nodeID, p.ask), true
+	// a gang is always preempted as a whole
+	return p.expandVictims(finalVictims), true
 }
 
 // Duplicate creates a copy of this snapshot into the given map by queue path
@@ -682,6 +791,9 @@ func (qps *QueuePreemptionSnapshot) Duplicate(copy map[string]*QueuePreemptionSn
 		GuaranteedResource: qps.GuaranteedResource.Clone(),
 		PotentialVictims:   qps.PotentialVictims,
 		AskQueue:           qps.AskQueue,
+		budget:             qps.budget,
+		appBudgets:         qps.appBudgets,
+		gangs:              qps.gangs,
 	}
 	copy[qps.QueuePath] = snapshot
 	return snapshot
@@ -797,37 +909,93 @@ func (qps *QueuePreemptionSnapshot) RemoveAllocation(alloc *resources.Resource)
 	qps.AllocatedResource.SubFrom(alloc)
 }
 
-// compareAllocationLess compares two allocations for preemption. Allocations which have opted into preemption are
-// considered first, then allocations which are not the originator of their associated application. Ties are broken
-// by creation time, with
-// then
-func compareAllocationLess(left *Allocation, right *Allocation) bool {
-	scoreLeft := scoreAllocation(left)
-	scoreRight := scoreAllocation(right)
-	if scoreLeft != scoreRight {
-		return scoreLeft < scoreRight
+// compareVictimsLess compares two allocations for preemption. Allocations which have opted into preemption are
+// considered first, then allocations that are not an originator, then allocations with the lowest cost. Ties are
+// broken by creation time, newest first.
+func (p *Preemptor) compareVictimsLess(left *Allocation, right *Allocation) bool {
+	if left.IsAllowPreemptSelf() != right.IsAllowPreemptSelf() {
+		return left.IsAllowPreemptSelf()
 	}
-	return left.createTime.After(right.createTime)
+	if leftOriginator, rightOriginator := p.victimOriginator(left), p.victimOriginator(right); leftOriginator != rightOriginator {
+		return rightOriginator
+	}
+	costLeft := p.victimCost(left)
+	costRight := p.victimCost(right)
+	if costLeft != costRight {
+		return costLeft < costRight
+	}
+	return left.GetCreateTime().After(right.GetCreateTime())
 }
 
-// scoreAllocation generates a relative score for an allocation. Lower-scored allocations are considered more likely
-// preemption candidates. Tasks which have opted into preemption are considered first, then tasks which are not
-// application originators.
-func scoreAllocation(allocation *Allocation) uint64 {
-	var score uint64 = 0
-	if allocation.IsOriginator() {
-		score |= scoreOriginator
+// victimCost returns the cost of preempting the victim. A registered victim cost model takes precedence over the
+// weighted cost model of the queue. The cost of a gang member is the cost of all members of its task group.
+func (p *Preemptor) victimCost(victim *Allocation) float64 {
+	if cost, ok := p.costs[victim.GetAllocationKey()]; ok {
+		return cost
 	}
-	if !allocation.IsAllowPreemptSelf() {
-		score |= scoreNoPreempt
+	model := plugins.GetVictimCostModel()
+	var cost float64
+	for _, member := range p.victimUnit(victim) {
+		if model != nil {
+			cost += max(0, model.VictimCost(member, p.ask, p.now))
+		} else {
+			cost += p.costWeights.cost(member, p.ask, p.now)
+		}
 	}
-	return score
+	p.costs[victim.GetAllocationKey()] = cost
+	return cost
+}
+
+// victimOriginator returns true if the victim, or any member of its task group, is the originator of its application.
+// Originators are always selected after all other victims, independent of the cost.
+func (p *Preemptor) victimOriginator(victim *Allocation) bool {
+	for _, member := range p.victimUnit(victim) {
+		if member.IsOriginator() {
+			return true
+		}
+	}
+	return false
+}
+
+// solutionCost returns the total cost of the victims that must be preempted for the predicate check result.
+func (p *Preemptor) solutionCost(result *predicateCheckResult, victimsByNode map[string][]*Allocation) float64 {
+	victims := victimsByNode[result.nodeID]
+	var cost float64
+	for i := 0; i <= result.index && i < len(victims); i++ {
+		cost += p.victimCost(victims[i])
+	}
+	return cost
+}
+
+// victimBudgets returns the preemption budget left for the queue and the application of the victim.
+// Entries are nil if the queue or application is not limited.
+func (p *Preemptor) victimBudgets(victim *Allocation) []*budgetHeadroom {
+	return p.queueByAlloc[victim.GetAllocationKey()].getBudgets(victim.GetApplicationID())
+}
+
+// getBudgets returns the preemption budget left for the queue and the application.
+func (qps *QueuePreemptionSnapshot) getBudgets(appID string) []*budgetHeadroom {
+	if qps == nil {
+		return nil
+	}
+	return []*budgetHeadroom{qps.budget, qps.appBudgets[appID]}
 }
 
 // sortVictimsForPreemption sorts allocations on each node, preferring those that have opted-in to preemption,
-// those that are not originating tasks for an application, and newest first
-func sortVictimsForPreemption(allocationsByNode map[string][]*Allocation) {
-	for _, allocations := range allocationsByNode {
+// those that are not an originator, those not limited by a preemption budget, those with the lowest cost, and
+// newest first.
+// Victims that would exceed the preemption budget of their queue or application, on top of the victims sorted
+// before them, are removed from the list.
+func (p *Preemptor) sortVictimsForPreemption(allocationsByNode map[string][]*Allocation) {
+	limited := func(alloc *Allocation) bool {
+		for _, budget := range p.victimBudgets(alloc) {
+			if budget != nil {
+				return true
+			}
+		}
+		return false
+	}
+	for nodeID, allocations := range allocationsByNode {
 		sort.SliceStable(allocations, func(i, j int) bool {
 			leftAsk := allocations[i]
 			rightAsk := allocations[j]
@@ -840,17 +1008,36 @@ func sortVictimsForPreemption(allocationsByNode map[string][]*Allocation) {
 				return false
 			}
 
-			// next those that are not app originators
-			if leftAsk.IsOriginator() && !rightAsk.IsOriginator() {
-				return false
+			// next those that are not an originator
+			leftOriginator := p.victimOriginator(leftAsk)
+			if rightOriginator := p.victimOriginator(rightAsk); leftOriginator != rightOriginator {
+				return rightOriginator
 			}
-			if rightAsk.IsOriginator() && !leftAsk.IsOriginator() {
-				return true
+
+			// next those that are not limited by a preemption budget
+			leftLimited := limited(leftAsk)
+			if rightLimited := limited(rightAsk); leftLimited != rightLimited {
+				return rightLimited
+			}
+
+			// next those that cost the least to preempt
+			if leftCost, rightCost := p.victimCost(leftAsk), p.victimCost(rightAsk); leftCost != rightCost {
+				return leftCost < rightCost
 			}
 
 			// finally sort by creation time descending
 			return leftAsk.GetCreateTime().After(rightAsk.GetCreateTime())
 		})
+
+		// drop the victims that do not fit in the budgets
+		tracker := newBudgetTracker()
+		withinBudget := allocations[:0]
+		for _, alloc := range allocations {
+			if tracker.take(p.victimUnit(alloc), p.victimBudgets(alloc)...) {
+				withinBudget = append(withinBudget, alloc)
+			}
+		}
+		allocationsByNode[nodeID] = withinBudget
 	}
 }
 
@@ -0,0 +1,315 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
+)
+
+// preemptionBudget limits the number of allocations and the share of the allocated resources that can be
+// preempted from a queue or an application within a rolling window.
+type preemptionBudget struct {
+	maxAllocations int           // allocations that can be preempted in the window, negative if not limited
+	maxPercentage  float64       // percentage of the allocated resources that can be preempted in the window, 0 if not limited
+	window         time.Duration // length of the rolling window
+	preempted      []budgetEntry // preemptions within the window, oldest first
+	reported       bool          // exhaustion of the budget has been reported
+
+	locking.Mutex
+}
+
+type budgetEntry struct {
+	time     time.Time
+	resource *resources.Resource
+}
+
+// budgetHeadroom is the part of a preemption budget that is still available when the victims are selected.
+type budgetHeadroom struct {
+	allocations int                 // allocations that can still be preempted, negative if not limited
+	resource    *resources.Resource // resources that can still be preempted, nil if not limited
+}
+
+// budgetTracker tracks the victims selected against the budget headroom during a single preemption attempt.
+type budgetTracker struct {
+	used map[*budgetHeadroom]*budgetUsage
+}
+
+type budgetUsage struct {
+	allocations int
+	resource    *resources.Resource
+}
+
+// newPreemptionBudget creates a budget from the string values of the configuration. Empty values are not set.
+// Returns nil if neither the number of allocations nor the percentage is limited.
+func newPreemptionBudget(allocations, percentage, window string) (*preemptionBudget, error) {
+	budget := &preemptionBudget{
+		maxAllocations: -1,
+		window:         configs.DefaultPreemptionBudgetWindow,
+	}
+	if allocations != "" {
+		value, err := strconv.Atoi(allocations)
+		if err != nil {
+			return nil, err
+		}
+		if value < 0 {
+			return nil, fmt.Errorf("%s must not be negative: %s", configs.PreemptionBudgetAllocations, allocations)
+		}
+		budget.maxAllocations = value
+	}
+	if percentage != "" {
+		value, err := strconv.ParseFloat(percentage, 64)
+		if err != nil {
+			return nil, err
+		}
+		if value <= 0 || value > 100 {
+			return nil, fmt.Errorf("%s must be larger than 0 and at most 100: %s", configs.PreemptionBudgetPercentage, percentage)
+		}
+		budget.maxPercentage = value
+	}
+	if window != "" {
+		value, err := time.ParseDuration(window)
+		if err != nil {
+			return nil, err
+		}
+		if value <= 0 {
+			return nil, fmt.Errorf("%s must be positive: %s", configs.PreemptionBudgetWindow, window)
+		}
+		budget.window = value
+	}
+	if budget.maxAllocations < 0 && budget.maxPercentage == 0 {
+		return nil, nil
+	}
+	return budget, nil
+}
+
+// keepHistory copies the preemptions of the old budget into this budget to survive a configuration change.
+func (pb *preemptionBudget) keepHistory(old *preemptionBudget) {
+	if pb == nil || old == nil {
+		return
+	}
+	old.Lock()
+	defer old.Unlock()
+	pb.preempted = append(pb.preempted, old.preempted...)
+}
+
+// prune removes the preemptions that are outside the window. Must be called with the lock held.
+func (pb *preemptionBudget) prune(now time.Time) {
+	start := now.Add(-pb.window)
+	i := 0
+	for i < len(pb.preempted) && !pb.preempted[i].time.After(start) {
+		i++
+	}
+	pb.preempted = pb.preempted[i:]
+}
+
+// getHeadroom returns what is left of the budget based on the allocated resources of the queue or application.
+// Must be called with the lock held.
+func (pb *preemptionBudget) getHeadroom(allocated *resources.Resource) *budgetHeadroom {
+	headroom := &budgetHeadroom{allocations: -1}
+	if pb.maxAllocations >= 0 {
+		headroom.allocations = max(0, pb.maxAllocations-len(pb.preempted))
+	}
+	if pb.maxPercentage > 0 {
+		preempted := resources.NewResource()
+		for _, entry := range pb.preempted {
+			preempted.AddTo(entry.resource)
+		}
+		headroom.resource = resources.SubEliminateNegative(budgetShare(allocated, pb.maxPercentage), preempted)
+	}
+	return headroom
+}
+
+// budgetShare returns the percentage of the allocated resources, rounded up. Rounding down would leave types with
+// small quantities, like the number of pods, without any budget at all.
+func budgetShare(allocated *resources.Resource, percentage float64) *resources.Resource {
+	share := resources.NewResource()
+	if allocated == nil {
+		return share
+	}
+	for name, value := range allocated.Resources {
+		if value <= 0 {
+			share.Resources[name] = 0
+			continue
+		}
+		share.Resources[name] = resources.Quantity(math.Ceil(float64(value) * percentage / 100))
+	}
+	return share
+}
+
+// headroom returns what is left of the budget at the given time, nil if there is no budget.
+func (pb *preemptionBudget) headroom(now time.Time, allocated *resources.Resource) *budgetHeadroom {
+	if pb == nil {
+		return nil
+	}
+	pb.Lock()
+	defer pb.Unlock()
+	pb.prune(now)
+	if len(pb.preempted) == 0 {
+		pb.reported = false
+	}
+	return pb.getHeadroom(allocated)
+}
+
+// record adds a preemption to the budget. Returns the number of preemptions in the window and true if this
+// exhausted the budget and the exhaustion was not reported yet.
+func (pb *preemptionBudget) record(now time.Time, preempted, allocated *resources.Resource) (int, bool) {
+	if pb == nil {
+		return 0, false
+	}
+	pb.Lock()
+	defer pb.Unlock()
+	pb.prune(now)
+	pb.preempted = append(pb.preempted, budgetEntry{time: now, resource: preempted.Clone()})
+	// the victim is still allocated until it is released by the shim
+	if pb.reported || !pb.getHeadroom(resources.Sub(allocated, preempted)).exhausted() {
+		return len(pb.preempted), false
+	}
+	pb.reported = true
+	return len(pb.preempted), true
+}
+
+// getDAOInfo returns the configuration and state of the budget, nil if there is no budget.
+func (pb *preemptionBudget) getDAOInfo(now time.Time, allocated *resources.Resource) *dao.PreemptionBudgetDAOInfo {
+	if pb == nil {
+		return nil
+	}
+	pb.Lock()
+	defer pb.Unlock()
+	pb.prune(now)
+	preempted := resources.NewResource()
+	for _, entry := range pb.preempted {
+		preempted.AddTo(entry.resource)
+	}
+	info := &dao.PreemptionBudgetDAOInfo{
+		MaxPercentage:        pb.maxPercentage,
+		Window:               pb.window.String(),
+		PreemptedAllocations: len(pb.preempted),
+		PreemptedResource:    preempted.DAOMap(),
+		Exhausted:            pb.getHeadroom(allocated).exhausted(),
+	}
+	if pb.maxAllocations >= 0 {
+		maxAllocations := pb.maxAllocations
+		info.MaxAllocations = &maxAllocations
+	}
+	return info
+}
+
+// exhausted returns true if no more allocations can be preempted within the budget: either the number of
+// allocations is used up or nothing is left of any of the resource types. A budget that is only used up for some
+// types is not exhausted, victims are checked against the types they use when they are selected.
+func (bh *budgetHeadroom) exhausted() bool {
+	if bh == nil {
+		return false
+	}
+	if bh.allocations == 0 {
+		return true
+	}
+	if bh.resource == nil || len(bh.resource.Resources) == 0 {
+		return false
+	}
+	for _, value := range bh.resource.Resources {
+		if value > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func newBudgetTracker() *budgetTracker {
+	return &budgetTracker{
+		used: make(map[*budgetHeadroom]*budgetUsage),
+	}
+}
+
+// fits returns true if the victims fit in all the headroom on top of what is already used.
+func (bt *budgetTracker) fits(victims []*Allocation, headroom ...*budgetHeadroom) bool {
+	res := victimsResource(victims)
+	for _, bh := range headroom {
+		if bh == nil {
+			continue
+		}
+		count := len(victims)
+		total := res
+		if used, ok := bt.used[bh]; ok {
+			count += used.allocations
+			total = resources.Add(used.resource, res)
+		}
+		if bh.allocations >= 0 && count > bh.allocations {
+			return false
+		}
+		if bh.resource != nil && !bh.resource.FitIn(total) {
+			return false
+		}
+	}
+	return true
+}
+
+// add registers the victims as used in all the headroom.
+func (bt *budgetTracker) add(victims []*Allocation, headroom ...*budgetHeadroom) {
+	res := victimsResource(victims)
+	for _, bh := range headroom {
+		if bh == nil {
+			continue
+		}
+		used, ok := bt.used[bh]
+		if !ok {
+			used = &budgetUsage{resource: resources.NewResource()}
+			bt.used[bh] = used
+		}
+		used.allocations += len(victims)
+		used.resource.AddTo(res)
+	}
+}
+
+// remove unregisters victims previously added to all the headroom.
+func (bt *budgetTracker) remove(victims []*Allocation, headroom ...*budgetHeadroom) {
+	res := victimsResource(victims)
+	for _, bh := range headroom {
+		if used, ok := bt.used[bh]; ok {
+			used.allocations -= len(victims)
+			used.resource.SubFrom(res)
+		}
+	}
+}
+
+// take registers the victims as used if they fit in all the headroom. Returns false if they do not fit.
+func (bt *budgetTracker) take(victims []*Allocation, headroom ...*budgetHeadroom) bool {
+	if !bt.fits(victims, headroom...) {
+		return false
+	}
+	bt.add(victims, headroom...)
+	return true
+}
+
+// victimsResource returns the total resources allocated to the victims.
+func victimsResource(victims []*Allocation) *resources.Resource {
+	total := resources.NewResource()
+	for _, victim := range victims {
+		total.AddTo(victim.GetAllocatedResource())
+	}
+	return total
+}
@@ -0,0 +1,200 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestNewPreemptionBudget(t *testing.T) {
+	var tests = []struct {
+		name        string
+		allocations string
+		percentage  string
+		window      string
+		nilBudget   bool
+		errorSet    bool
+		maxAllocs   int
+		maxPct      float64
+		window2     time.Duration
+	}{
+		{"not set", "", "", "", true, false, 0, 0, 0},
+		{"window only", "", "", "1m", true, false, 0, 0, 0},
+		{"allocations", "2", "", "", false, false, 2, 0, configs.DefaultPreemptionBudgetWindow},
+		{"zero allocations", "0", "", "", false, false, 0, 0, configs.DefaultPreemptionBudgetWindow},
+		{"percentage", "", "25", "1h", false, false, -1, 25, time.Hour},
+		{"both", "3", "12.5", "30s", false, false, 3, 12.5, 30 * time.Second},
+		{"negative allocations", "-1", "", "", true, true, 0, 0, 0},
+		{"invalid allocations", "x", "", "", true, true, 0, 0, 0},
+		{"zero percentage", "", "0", "", true, true, 0, 0, 0},
+		{"large percentage", "", "101", "", true, true, 0, 0, 0},
+		{"invalid percentage", "", "x", "", true, true, 0, 0, 0},
+		{"negative window", "1", "", "-1m", true, true, 0, 0, 0},
+		{"invalid window", "1", "", "x", true, true, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget, err := newPreemptionBudget(tt.allocations, tt.percentage, tt.window)
+			if tt.errorSet {
+				assert.Assert(t, err != nil, "expected error")
+			} else {
+				assert.NilError(t, err)
+			}
+			if tt.nilBudget {
+				assert.Assert(t, budget == nil, "budget should not be set")
+				return
+			}
+			assert.Equal(t, budget.maxAllocations, tt.maxAllocs)
+			assert.Equal(t, budget.maxPercentage, tt.maxPct)
+			assert.Equal(t, budget.window, tt.window2)
+		})
+	}
+}
+
+func TestPreemptionBudgetNil(t *testing.T) {
+	var budget *preemptionBudget
+	now := time.Now()
+	assert.Assert(t, budget.headroom(now, nil) == nil, "nil budget should have no headroom")
+	count, exhausted := budget.record(now, resources.NewResource(), nil)
+	assert.Equal(t, count, 0)
+	assert.Assert(t, !exhausted, "nil budget cannot be exhausted")
+	assert.Assert(t, budget.getDAOInfo(now, nil) == nil, "nil budget should have no DAO")
+	var headroom *budgetHeadroom
+	assert.Assert(t, !headroom.exhausted(), "nil headroom cannot be exhausted")
+}
+
+func TestPreemptionBudgetAllocations(t *testing.T) {
+	budget, err := newPreemptionBudget("2", "", "1m")
+	assert.NilError(t, err)
+	allocated := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	now := time.Now()
+
+	headroom := budget.headroom(now, allocated)
+	assert.Equal(t, headroom.allocations, 2)
+	assert.Assert(t, headroom.resource == nil, "resources should not be limited")
+	count, exhausted := budget.record(now, res, allocated)
+	assert.Equal(t, count, 1)
+	assert.Assert(t, !exhausted, "budget should not be exhausted after first preemption")
+	count, exhausted = budget.record(now.Add(time.Second), res, allocated)
+	assert.Equal(t, count, 2)
+	assert.Assert(t, exhausted, "budget should be exhausted after second preemption")
+	// exhaustion is only reported once
+	count, exhausted = budget.record(now.Add(2*time.Second), res, allocated)
+	assert.Equal(t, count, 3)
+	assert.Assert(t, !exhausted, "exhaustion should only be reported once")
+	assert.Assert(t, budget.headroom(now.Add(2*time.Second), allocated).exhausted(), "budget should be exhausted")
+
+	info := budget.getDAOInfo(now.Add(2*time.Second), allocated)
+	assert.Equal(t, *info.MaxAllocations, 2)
+	assert.Equal(t, info.PreemptedAllocations, 3)
+	assert.Equal(t, info.Window, "1m0s")
+	assert.DeepEqual(t, info.PreemptedResource, map[string]int64{"first": 3})
+	assert.Assert(t, info.Exhausted, "DAO should show exhausted budget")
+
+	// the first preemption leaves the window
+	headroom = budget.headroom(now.Add(time.Minute), allocated)
+	assert.Equal(t, headroom.allocations, 0)
+	// all preemptions leave the window, exhaustion can be reported again
+	headroom = budget.headroom(now.Add(time.Minute+2*time.Second), allocated)
+	assert.Equal(t, headroom.allocations, 2)
+	assert.Assert(t, !budget.reported, "reported flag should be reset")
+}
+
+func TestPreemptionBudgetPercentage(t *testing.T) {
+	budget, err := newPreemptionBudget("", "50", "")
+	assert.NilError(t, err)
+	allocated := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10, "second": 10})
+	now := time.Now()
+
+	headroom := budget.headroom(now, allocated)
+	assert.Equal(t, headroom.allocations, -1)
+	assert.Assert(t, resources.Equals(headroom.resource, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5, "second": 5})), "unexpected headroom %s", headroom.resource)
+	_, exhausted := budget.record(now, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2, "second": 2}), allocated)
+	assert.Assert(t, !exhausted, "budget should not be exhausted")
+	// victim still counts as allocated: the allocation after release is used to check exhaustion
+	_, exhausted = budget.record(now, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2, "second": 2}), allocated)
+	assert.Assert(t, exhausted, "budget should be exhausted")
+
+	// small quantities are rounded up and do not block preemption
+	budget, err = newPreemptionBudget("", "10", "")
+	assert.NilError(t, err)
+	allocated = resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1000, "pods": 9})
+	headroom = budget.headroom(now, allocated)
+	assert.Assert(t, resources.Equals(headroom.resource, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 100, "pods": 1})), "unexpected headroom %s", headroom.resource)
+	assert.Assert(t, !headroom.exhausted(), "budget should not be exhausted")
+
+	// a budget used up for one type only is not exhausted, the victims are checked per type
+	headroom = &budgetHeadroom{allocations: -1, resource: resources.NewResourceFromMap(map[string]resources.Quantity{"first": 0, "second": 5})}
+	assert.Assert(t, !headroom.exhausted(), "budget with one type left should not be exhausted")
+	tracker := newBudgetTracker()
+	second := []*Allocation{newAllocationWithKey("second", appID1, nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"second": 2}))}
+	assert.Assert(t, tracker.fits(second, headroom), "victim using a type with budget left should fit")
+	first := []*Allocation{newAllocationWithKey("first", appID1, nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2}))}
+	assert.Assert(t, !tracker.fits(first, headroom), "victim using a used up type should not fit")
+}
+
+func TestPreemptionBudgetKeepHistory(t *testing.T) {
+	old, err := newPreemptionBudget("5", "", "")
+	assert.NilError(t, err)
+	now := time.Now()
+	old.record(now, resources.NewResource(), nil)
+	budget, err := newPreemptionBudget("2", "", "")
+	assert.NilError(t, err)
+	budget.keepHistory(old)
+	assert.Equal(t, budget.headroom(now, nil).allocations, 1)
+	// nil budgets are ignored
+	budget.keepHistory(nil)
+	var none *preemptionBudget
+	none.keepHistory(old)
+}
+
+func TestBudgetTracker(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2})
+	victim := []*Allocation{newAllocationWithKey("victim", appID1, nodeID1, res)}
+	countLimit := &budgetHeadroom{allocations: 2}
+	resLimit := &budgetHeadroom{allocations: -1, resource: resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})}
+
+	tracker := newBudgetTracker()
+	assert.Assert(t, tracker.fits(victim), "no budgets should always fit")
+	assert.Assert(t, tracker.fits(victim, nil, nil), "nil budgets should always fit")
+	assert.Assert(t, tracker.take(victim, countLimit, resLimit), "first take should fit")
+	assert.Assert(t, tracker.take(victim, countLimit, resLimit), "second take should fit")
+	assert.Assert(t, !tracker.fits(victim, countLimit), "third take should not fit the count")
+	assert.Assert(t, !tracker.fits(victim, resLimit), "third take should not fit the resources")
+	small := []*Allocation{newAllocationWithKey("small", appID1, nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1}))}
+	assert.Assert(t, tracker.fits(small, resLimit), "smaller take should fit the resources")
+	assert.Assert(t, !tracker.take(victim, countLimit, resLimit), "failed take should not fit")
+	assert.Equal(t, tracker.used[countLimit].allocations, 2, "failed take should not change usage")
+
+	// all victims of a unit count against the budget
+	tracker = newBudgetTracker()
+	unit := append(small, small[0])
+	assert.Assert(t, tracker.fits(unit, countLimit, resLimit), "unit should fit")
+	assert.Assert(t, !tracker.fits(append(unit, small[0]), countLimit), "unit larger than the count should not fit")
+	assert.Assert(t, tracker.take(unit, countLimit, resLimit), "unit take should fit")
+	assert.Equal(t, tracker.used[countLimit].allocations, 2, "all victims of the unit should be counted")
+	assert.Assert(t, resources.Equals(tracker.used[resLimit].resource, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2})))
+}
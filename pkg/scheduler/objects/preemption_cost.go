@@ -0,0 +1,94 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+)
+
+// victimCostWeights are the weights of the default victim cost model. The cost of a victim is the weighted sum of
+// the time the victim has been running, its priority relative to the ask, and its originator and placeholder status.
+// The weights are set using the preemption policy properties of the queue the ask is in.
+// Originators are always selected after all other victims whatever their cost, the originator weight still adds to
+// the cost of a preemption solution.
+type victimCostWeights struct {
+	runtime     float64 // cost per full minute the victim has been running since it was bound
+	priority    float64 // cost of a victim with the same or a higher priority than the ask, lower priorities cost less
+	originator  float64 // cost of a victim that is the originator of its application
+	placeholder float64 // cost of a victim that is a placeholder
+}
+
+func defaultVictimCostWeights() victimCostWeights {
+	return victimCostWeights{
+		runtime:     configs.DefaultPreemptionCostRuntime,
+		priority:    configs.DefaultPreemptionCostPriority,
+		originator:  configs.DefaultPreemptionCostOriginator,
+		placeholder: configs.DefaultPreemptionCostPlaceholder,
+	}
+}
+
+// set updates the weight for the property key. The weight must be a non negative number, the weight is not
+// changed if the value is invalid.
+func (w *victimCostWeights) set(key, value string) error {
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	if weight < 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return fmt.Errorf("%s must be a non negative number: %s", key, value)
+	}
+	switch key {
+	case configs.PreemptionCostRuntime:
+		w.runtime = weight
+	case configs.PreemptionCostPriority:
+		w.priority = weight
+	case configs.PreemptionCostOriginator:
+		w.originator = weight
+	case configs.PreemptionCostPlaceholder:
+		w.placeholder = weight
+	}
+	return nil
+}
+
+// cost returns the cost of preempting the victim to make room for the ask, the cost is never negative.
+func (w victimCostWeights) cost(victim, ask *Allocation, now time.Time) float64 {
+	var cost float64
+	start := victim.GetBindTime()
+	if start.IsZero() {
+		start = victim.GetCreateTime()
+	}
+	if running := now.Sub(start); running > 0 {
+		cost += w.runtime * math.Floor(running.Minutes())
+	}
+	// the priority cost halves, and keeps dropping, as the gap between the victim and the ask grows
+	gap := max(0, float64(ask.GetPriority())-float64(victim.GetPriority()))
+	cost += w.priority / (1 + gap)
+	if victim.IsOriginator() {
+		cost += w.originator
+	}
+	if victim.IsPlaceholder() {
+		cost += w.placeholder
+	}
+	return cost
+}
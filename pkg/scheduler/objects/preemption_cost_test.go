@@ -0,0 +1,82 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestVictimCostWeightsSet(t *testing.T) {
+	weights := defaultVictimCostWeights()
+	assert.Equal(t, weights.runtime, configs.DefaultPreemptionCostRuntime)
+	assert.Equal(t, weights.priority, configs.DefaultPreemptionCostPriority)
+	assert.Equal(t, weights.originator, configs.DefaultPreemptionCostOriginator)
+	assert.Equal(t, weights.placeholder, configs.DefaultPreemptionCostPlaceholder)
+
+	assert.NilError(t, weights.set(configs.PreemptionCostRuntime, "2"))
+	assert.NilError(t, weights.set(configs.PreemptionCostPriority, "0"))
+	assert.NilError(t, weights.set(configs.PreemptionCostOriginator, "5.5"))
+	assert.NilError(t, weights.set(configs.PreemptionCostPlaceholder, "10"))
+	assert.Equal(t, weights, victimCostWeights{runtime: 2, priority: 0, originator: 5.5, placeholder: 10})
+
+	for _, value := range []string{"-1", "weight", "NaN", "+Inf"} {
+		err := weights.set(configs.PreemptionCostRuntime, value)
+		assert.Assert(t, err != nil, "expected error for value %s", value)
+		assert.Equal(t, weights.runtime, float64(2), "invalid value %s changed the weight", value)
+	}
+}
+
+func TestVictimCost(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	now := time.Now()
+	weights := victimCostWeights{runtime: 1, priority: 60, originator: 1000, placeholder: 500}
+	ask := newAllocationAskPriority("ask", appID1, res, 10)
+
+	victim := newAllocationAll("victim", appID2, nodeID1, "", res, false, 10)
+	victim.SetBindTime(now.Add(-90 * time.Second))
+	// one full minute running, same priority as the ask
+	assert.Equal(t, weights.cost(victim, ask, now), float64(61))
+
+	// the gap in priority lowers the cost
+	lower := newAllocationAll("lower", appID2, nodeID1, "", res, false, 7)
+	lower.SetBindTime(now)
+	assert.Equal(t, weights.cost(lower, ask, now), float64(15))
+	// a higher priority than the ask costs the full weight
+	higher := newAllocationAll("higher", appID2, nodeID1, "", res, false, 20)
+	higher.SetBindTime(now)
+	assert.Equal(t, weights.cost(higher, ask, now), float64(60))
+
+	// originator and placeholder costs are added
+	victim.originator = true
+	assert.Equal(t, weights.cost(victim, ask, now), float64(1061))
+	placeholder := newAllocationAll("placeholder", appID2, nodeID1, "tg", res, true, 10)
+	placeholder.SetBindTime(now)
+	assert.Equal(t, weights.cost(placeholder, ask, now), float64(560))
+
+	// create time is used if the victim is not bound
+	unbound := newAllocationAskPriority("unbound", appID2, res, 10)
+	unbound.createTime = now.Add(-10 * time.Minute)
+	assert.Equal(t, weights.cost(unbound, ask, now), float64(70))
+}
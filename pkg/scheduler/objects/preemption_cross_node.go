@@ -0,0 +1,444 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/plugins"
+	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// crossNodePlan is a preemption that spans several nodes. Victims are preempted on the target node and, if that is
+// not enough, allocations are moved off the target node until the ask fits. An allocation is moved by preempting it
+// after room has been made for it on another node by preempting victims on that node.
+// A moved allocation must pass the same checks as a victim: it must allow preemption, its queue must stay above the
+// guaranteed resources and the move must fit in the preemption budgets. Only the priority is not checked as the
+// allocation keeps running on the other node.
+type crossNodePlan struct {
+	nodeID  string           // node the ask is placed on
+	victims []*Allocation    // victims preempted on the target node
+	moves   []*crossNodeMove // allocations moved off the target node
+}
+
+// crossNodeMove is an allocation moved off the target node of a cross node plan.
+type crossNodeMove struct {
+	alloc   *Allocation   // allocation moved off the target node
+	nodeID  string        // node that has room for the allocation after preemption
+	victims []*Allocation // victims preempted to make room for the allocation
+}
+
+// crossNodeState tracks the queue snapshots, preemption budgets and node resources while building a plan.
+type crossNodeState struct {
+	snapshots map[string]*QueuePreemptionSnapshot
+	tracker   *budgetTracker
+	available map[string]*resources.Resource // available resources on the nodes that received a moved allocation
+	used      map[string]bool                // allocations that are already part of the plan
+}
+
+// getVictims returns the victims of the plan on all nodes, without the moved allocations.
+func (cnp *crossNodePlan) getVictims() []*Allocation {
+	victims := make([]*Allocation, 0, len(cnp.victims))
+	victims = append(victims, cnp.victims...)
+	for _, move := range cnp.moves {
+		victims = append(victims, move.victims...)
+	}
+	return victims
+}
+
+// getMoved returns the allocations moved off the target node.
+func (cnp *crossNodePlan) getMoved() []*Allocation {
+	moved := make([]*Allocation, 0, len(cnp.moves))
+	for _, move := range cnp.moves {
+		moved = append(moved, move.alloc)
+	}
+	return moved
+}
+
+// crossNodeTargets returns the nodes that the ask could be placed on, sorted by node ID.
+func (p *Preemptor) crossNodeTargets() []string {
+	targets := make([]string, 0, len(p.nodeAvailableMap))
+	for nodeID := range p.nodeAvailableMap {
+		targets = append(targets, nodeID)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// tryCrossNode looks for a plan that places the ask on one of the target nodes by preempting across nodes.
+// The number of allocations moved off the target node is limited by the cross node depth of the queue, nothing is
+// moved if the depth is 0. Plans that move the fewest allocations, and then preempt the fewest allocations, are
+// checked first. The first plan that passes the predicate checks of the shim is returned.
+func (p *Preemptor) tryCrossNode(targets []string) (*crossNodePlan, bool) {
+	depth := p.queue.getCrossNodeDepth()
+	if depth <= 0 {
+		return nil, false
+	}
+	plans := make([]*crossNodePlan, 0)
+	for _, nodeID := range targets {
+		if plan := p.planCrossNode(nodeID, depth); plan != nil {
+			plans = append(plans, plan)
+		}
+	}
+	sort.SliceStable(plans, func(i, j int) bool {
+		if len(plans[i].moves) != len(plans[j].moves) {
+			return len(plans[i].moves) < len(plans[j].moves)
+		}
+		return len(plans[i].getVictims()) < len(plans[j].getVictims())
+	})
+	for _, plan := range plans {
+		if p.checkCrossNodePredicates(plan) {
+			log.Log(log.SchedPreemption).Debug("Found cross node preemption plan",
+				zap.String("allocationKey", p.ask.GetAllocationKey()),
+				zap.String("nodeID", plan.nodeID),
+				zap.Int("moveCount", len(plan.moves)),
+				zap.Int("victimCount", len(plan.getVictims())))
+			return plan, true
+		}
+	}
+	return nil, false
+}
+
+// planCrossNode builds a plan to place the ask on the node. Victims on the node are preempted first, then up to depth
+// allocations are moved off the node. Returns nil if the ask does not fit or no allocation needs to be moved: the
+// single node preemption already covers that case.
+func (p *Preemptor) planCrossNode(nodeID string, depth int) *crossNodePlan {
+	node, ok := p.nodes[nodeID]
+	if !ok {
+		return nil
+	}
+	// the snapshots of the queues of the movable allocations must exist before they are copied
+	movable, snapshots := p.movableAllocations(node)
+	state := &crossNodeState{
+		snapshots: p.duplicateQueueSnapshots(),
+		tracker:   newBudgetTracker(),
+		available: make(map[string]*resources.Resource),
+		used:      make(map[string]bool),
+	}
+	ask := p.ask.GetAllocatedResource()
+	free := p.nodeAvailableMap[nodeID].Clone()
+	plan := &crossNodePlan{nodeID: nodeID}
+	for _, victim := range p.allocationsByNode[nodeID] {
+		if free.FitIn(ask) {
+			break
+		}
+		if p.takeCrossNodeVictim(state, victim) {
+			free.AddTo(p.victimNodeResource(victim))
+			plan.victims = append(plan.victims, victim)
+		}
+	}
+	for _, alloc := range movable {
+		if free.FitIn(ask) || len(plan.moves) >= depth {
+			break
+		}
+		// skip allocations that do not reduce the shortfall
+		shortfall := resources.SubEliminateNegative(ask, free)
+		if resources.EqualsOrEmpty(shortfall, resources.SubEliminateNegative(ask, resources.Add(free, alloc.GetAllocatedResource()))) {
+			continue
+		}
+		// the allocation is preempted to move it: it must be allowed as a victim
+		snapshot := snapshots[alloc.GetAllocationKey()]
+		if !p.takeCrossNodeAllocation(state, alloc, snapshot) {
+			continue
+		}
+		if move := p.planMove(state, nodeID, alloc); move != nil {
+			free.AddTo(alloc.GetAllocatedResource())
+			plan.moves = append(plan.moves, move)
+		} else {
+			p.releaseCrossNodeAllocation(state, alloc, snapshot)
+		}
+	}
+	if len(plan.moves) == 0 || !free.FitIn(ask) {
+		return nil
+	}
+	return plan
+}
+
+// movableAllocations returns the allocations on the node that could be moved to another node, lowest priority and
+// newest first, with the queue snapshot of each allocation. Victims are preempted not moved. Allocations that
+// require the node, originators, allocations that have not opted into preemption and members of a task group that
+// is preempted as a unit are never moved. The queue of the allocation must allow preemption, allocations in the
+// queue of the ask are not moved.
+func (p *Preemptor) movableAllocations(node *Node) ([]*Allocation, map[string]*QueuePreemptionSnapshot) {
+	movable := make([]*Allocation, 0)
+	snapshots := make(map[string]*QueuePreemptionSnapshot)
+	for _, alloc := range node.GetYunikornAllocations() {
+		if _, ok := p.queueByAlloc[alloc.GetAllocationKey()]; ok {
+			continue
+		}
+		if alloc.GetRequiredNode() != "" || alloc.IsOriginator() || !alloc.IsAllowPreemptSelf() || alloc.IsPreempted() || alloc.IsReleased() {
+			continue
+		}
+		if !p.ask.GetAllocatedResource().MatchAny(alloc.GetAllocatedResource()) {
+			continue
+		}
+		queue := p.queue.FindQueueByAppID(alloc.GetApplicationID())
+		if queue == nil || queue.QueuePath == p.queuePath || queue.GetPreemptionPolicy() == policies.DisabledPreemptionPolicy {
+			continue
+		}
+		if alloc.GetTaskGroup() != "" && queue.isGangPreemption() {
+			continue
+		}
+		snapshots[alloc.GetAllocationKey()] = p.moveSnapshot(queue)
+		movable = append(movable, alloc)
+	}
+	sort.SliceStable(movable, func(i, j int) bool {
+		if movable[i].GetPriority() != movable[j].GetPriority() {
+			return movable[i].GetPriority() < movable[j].GetPriority()
+		}
+		return movable[i].GetCreateTime().After(movable[j].GetCreateTime())
+	})
+	return movable, snapshots
+}
+
+// moveSnapshot returns the snapshot of the queue of a moved allocation. A queue that has no victims for the ask
+// might not have a snapshot yet: the snapshot is added with the preemption budgets of the queue and its applications.
+func (p *Preemptor) moveSnapshot(queue *Queue) *QueuePreemptionSnapshot {
+	if snapshot, ok := p.allocationsByQueue[queue.QueuePath]; ok {
+		return snapshot
+	}
+	snapshot := queue.createPreemptionSnapshot(p.allocationsByQueue, p.queuePath)
+	snapshot.budget = queue.getPreemptionBudget().headroom(p.now, queue.GetAllocatedResource())
+	for _, app := range queue.GetCopyOfApps() {
+		if appBudget := app.preemptionBudget.headroom(p.now, app.GetAllocatedResource()); appBudget != nil {
+			if snapshot.appBudgets == nil {
+				snapshot.appBudgets = make(map[string]*budgetHeadroom)
+			}
+			snapshot.appBudgets[app.ApplicationID] = appBudget
+		}
+	}
+	return snapshot
+}
+
+// planMove finds another node for the allocation moved off the target node. The first node, by node ID, that has
+// room for the allocation after preempting victims is used. Returns nil if no node has room.
+func (p *Preemptor) planMove(state *crossNodeState, targetID string, alloc *Allocation) *crossNodeMove {
+	res := alloc.GetAllocatedResource()
+	for _, nodeID := range p.crossNodeTargets() {
+		if nodeID == targetID {
+			continue
+		}
+		free, ok := state.available[nodeID]
+		if !ok {
+			free = p.nodeAvailableMap[nodeID]
+		}
+		free = free.Clone()
+		victims := make([]*Allocation, 0)
+		for _, victim := range p.allocationsByNode[nodeID] {
+			if free.FitIn(res) {
+				break
+			}
+			if p.takeCrossNodeVictim(state, victim) {
+				free.AddTo(p.victimNodeResource(victim))
+				victims = append(victims, victim)
+			}
+		}
+		if free.FitIn(res) {
+			state.available[nodeID] = resources.Sub(free, res)
+			return &crossNodeMove{alloc: alloc, nodeID: nodeID, victims: victims}
+		}
+		// the node cannot take the allocation, undo the victims taken
+		for _, victim := range victims {
+			p.releaseCrossNodeVictim(state, victim)
+		}
+	}
+	return nil
+}
+
+// takeCrossNodeVictim adds the victim to the plan if it is not part of the plan yet, fits in the preemption budgets
+// and does not take its queue below the guaranteed resources. Returns false if the victim cannot be added.
+func (p *Preemptor) takeCrossNodeVictim(state *crossNodeState, victim *Allocation) bool {
+	qv, ok := p.queueByAlloc[victim.GetAllocationKey()]
+	if !ok {
+		return false
+	}
+	return p.takeCrossNodeAllocation(state, victim, qv)
+}
+
+// takeCrossNodeAllocation adds the allocation, or its unit, to the plan using the queue snapshot of the allocation.
+// The same checks as for a victim are applied. Returns false if the allocation cannot be added.
+func (p *Preemptor) takeCrossNodeAllocation(state *crossNodeState, alloc *Allocation, qv *QueuePreemptionSnapshot) bool {
+	if qv == nil {
+		return false
+	}
+	unit := p.victimUnit(alloc)
+	for _, member := range unit {
+		if state.used[member.GetAllocationKey()] {
+			return false
+		}
+	}
+	budgets := qv.getBudgets(alloc.GetApplicationID())
+	if !state.tracker.fits(unit, budgets...) {
+		return false
+	}
+	snapshot, ok := state.snapshots[qv.QueuePath]
+	if !ok {
+		return false
+	}
+	allocResource := p.victimResource(alloc)
+	oldRemaining := snapshot.GetRemainingGuaranteedResource()
+	snapshot.RemoveAllocation(allocResource)
+	if !resources.StrictlyGreaterThanOrEquals(snapshot.GetPreemptableResource(), resources.Zero) ||
+		(oldRemaining != nil && !resources.StrictlyGreaterThan(resources.Zero, oldRemaining)) {
+		snapshot.AddAllocation(allocResource)
+		return false
+	}
+	state.tracker.add(unit, budgets...)
+	for _, member := range unit {
+		state.used[member.GetAllocationKey()] = true
+	}
+	return true
+}
+
+// releaseCrossNodeVictim removes a victim previously added by takeCrossNodeVictim from the plan.
+func (p *Preemptor) releaseCrossNodeVictim(state *crossNodeState, victim *Allocation) {
+	p.releaseCrossNodeAllocation(state, victim, p.queueByAlloc[victim.GetAllocationKey()])
+}
+
+// releaseCrossNodeAllocation removes an allocation previously added by takeCrossNodeAllocation from the plan.
+func (p *Preemptor) releaseCrossNodeAllocation(state *crossNodeState, alloc *Allocation, qv *QueuePreemptionSnapshot) {
+	if qv == nil {
+		return
+	}
+	unit := p.victimUnit(alloc)
+	state.tracker.remove(unit, qv.getBudgets(alloc.GetApplicationID())...)
+	for _, member := range unit {
+		delete(state.used, member.GetAllocationKey())
+	}
+	if snapshot, ok := state.snapshots[qv.QueuePath]; ok {
+		snapshot.AddAllocation(p.victimResource(alloc))
+	}
+}
+
+// crossNodeChecks returns the predicate checks for the plan: each moved allocation on its new node with the victims
+// on that node preempted, and the ask on the target node with the victims preempted and the allocations moved.
+func (p *Preemptor) crossNodeChecks(plan *crossNodePlan) []*si.PreemptionPredicatesArgs {
+	checks := make([]*si.PreemptionPredicatesArgs, 0, len(plan.moves)+1)
+	newCheck := func(allocationKey, nodeID string, keys []string) *si.PreemptionPredicatesArgs {
+		return &si.PreemptionPredicatesArgs{
+			AllocationKey:         allocationKey,
+			NodeID:                nodeID,
+			PreemptAllocationKeys: keys,
+			StartIndex:            int32(len(keys) - 1), //nolint: gosec
+		}
+	}
+	for _, move := range plan.moves {
+		keys, _ := p.predicateKeys(move.victims, -1)
+		checks = append(checks, newCheck(move.alloc.GetAllocationKey(), move.nodeID, keys))
+	}
+	keys, _ := p.predicateKeys(plan.victims, -1)
+	for _, move := range plan.moves {
+		keys = append(keys, move.alloc.GetAllocationKey())
+	}
+	return append(checks, newCheck(p.ask.GetAllocationKey(), plan.nodeID, keys))
+}
+
+// checkCrossNodePredicates calls the shim via the SI to verify all steps of the plan. The plan is only accepted if
+// all checks succeed with all listed allocations preempted.
+func (p *Preemptor) checkCrossNodePredicates(plan *crossNodePlan) bool {
+	plugin := plugins.GetResourceManagerCallbackPlugin()
+	if plugin == nil {
+		// if a plugin isn't registered, assume checks will succeed
+		return true
+	}
+	checks := p.crossNodeChecks(plan)
+	var wg sync.WaitGroup
+	ch := make(chan *predicateCheckResult, len(checks))
+	for _, args := range checks {
+		wg.Add(1)
+		go preemptPredicateCheck(plugin, ch, &wg, args)
+	}
+	wg.Wait()
+	close(ch)
+	success := true
+	for result := range ch {
+		if !result.success {
+			log.Log(log.SchedPreemption).Debug("Cross node preemption predicate check failed",
+				zap.String("allocationKey", result.allocationKey),
+				zap.String("nodeID", result.nodeID))
+			success = false
+		}
+	}
+	return success
+}
+
+// tryCrossNodePreemption tries to place the ask by preempting across nodes, used when no single node can fit the
+// ask after preemption. The selected node is reserved for the ask.
+func (p *Preemptor) tryCrossNodePreemption() (*AllocationResult, bool) {
+	plan, ok := p.tryCrossNode(p.crossNodeTargets())
+	if !ok {
+		return nil, false
+	}
+
+	// look for additional victims in case we have not yet made enough capacity in the queue
+	victims := plan.getVictims()
+	extraVictims, ok := p.calculateAdditionalVictims(victims)
+	if !ok {
+		return nil, false
+	}
+	victims = append(victims, extraVictims...)
+	p.preemptCrossNode(plan, victims, false)
+
+	log.Log(log.SchedPreemption).Info("Reserving node for ask after cross node preemption",
+		zap.String("allocationKey", p.ask.GetAllocationKey()),
+		zap.String("nodeID", plan.nodeID),
+		zap.Int("victimCount", len(victims)),
+		zap.Int("moveCount", len(plan.moves)))
+	return newReservedAllocationResult(plan.nodeID, p.ask), true
+}
+
+// tryRequiredNodeCrossNode tries to make room for an ask that requires the node by preempting across nodes.
+// The node is already reserved for the ask. Returns true if preemption was triggered.
+func (p *Preemptor) tryRequiredNodeCrossNode(nodeID string) bool {
+	p.initWorkingState()
+	plan, ok := p.tryCrossNode([]string{nodeID})
+	if !ok {
+		return false
+	}
+	p.preemptCrossNode(plan, plan.getVictims(), true)
+	return true
+}
+
+// preemptCrossNode preempts the victims and the allocations moved off the target node of the plan.
+func (p *Preemptor) preemptCrossNode(plan *crossNodePlan, victims []*Allocation, requiredNode bool) {
+	for _, move := range plan.moves {
+		log.Log(log.SchedPreemption).Info("Moving task to other node",
+			zap.String("askAllocationKey", p.ask.GetAllocationKey()),
+			zap.String("allocationKey", move.alloc.GetAllocationKey()),
+			zap.String("fromNodeID", plan.nodeID),
+			zap.String("toNodeID", move.nodeID))
+	}
+	released := append(p.expandVictims(victims), plan.getMoved()...)
+	record := newPreemptionRecord(p.ask, p.queuePath, plan.nodeID, requiredNode)
+	p.preemptVictims(released, record)
+	p.queue.recordPreemptionDecision(record)
+
+	// mark ask as having triggered preemption so that we don't preempt again
+	p.ask.MarkTriggeredPreemption()
+
+	// notify RM that victims should be released
+	p.application.notifyRMAllocationReleased(released, si.TerminationType_PREEMPTED_BY_SCHEDULER,
+		"preempting allocations on multiple nodes to free up resources to run ask: "+p.ask.GetAllocationKey())
+}
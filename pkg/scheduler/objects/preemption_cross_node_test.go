@@ -0,0 +1,228 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/mock"
+	"github.com/apache/yunikorn-core/pkg/plugins"
+)
+
+// createCrossNodeTest creates two nodes that are full. Each node runs a victim for the ask and allocations with a
+// higher priority than the ask that can be moved. No single node can fit the ask after preemption:
+// node-1: victim1 (3), high1a (1), high1b (1)
+// node-2: victim2 (3), high2 (2)
+func createCrossNodeTest(t *testing.T, depth string) (*Application, *Allocation, func() NodeIterator, map[string]*Allocation) {
+	return createCrossNodeTestWithProps(t, depth, nil)
+}
+
+// createCrossNodeTestWithProps creates the cross node test with the properties set on the queue of the allocations
+// that can be moved.
+func createCrossNodeTestWithProps(t *testing.T, depth string, props map[string]string) (*Application, *Allocation, func() NodeIterator, map[string]*Allocation) {
+	node1 := newNode(nodeID1, map[string]resources.Quantity{"first": 5})
+	node2 := newNode(nodeID2, map[string]resources.Quantity{"first": 5})
+	rootQ, err := createRootQueue(map[string]string{"first": "10"})
+	assert.NilError(t, err)
+	parentQ, err := createManagedQueueGuaranteed(rootQ, "parent", true, nil, nil)
+	assert.NilError(t, err)
+	childQ1, err := createManagedQueueGuaranteed(parentQ, "child1", false, nil, nil)
+	assert.NilError(t, err)
+	childQ2, err := createManagedQueueWithProps(parentQ, "child2", false, nil, map[string]string{configs.PreemptionCrossNodeDepth: depth})
+	assert.NilError(t, err)
+	childQ2.guaranteedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+	childQ3, err := createManagedQueueWithProps(parentQ, "child3", false, nil, props)
+	assert.NilError(t, err)
+
+	app1 := newApplication(appID1, "default", "root.parent.child1")
+	app1.SetQueue(childQ1)
+	childQ1.applications[appID1] = app1
+	app3 := newApplication(appID3, "default", "root.parent.child3")
+	app3.SetQueue(childQ3)
+	childQ3.applications[appID3] = app3
+
+	allocs := make(map[string]*Allocation)
+	addAlloc := func(app *Application, queue *Queue, node *Node, key string, size resources.Quantity, priority int32) {
+		res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": size})
+		alloc := newAllocationAll(key, app.ApplicationID, node.NodeID, "", res, false, priority)
+		alloc.allowPreemptSelf = true
+		app.AddAllocation(alloc)
+		assert.Check(t, node.TryAddAllocation(alloc), "node alloc %s failed", key)
+		assert.NilError(t, queue.TryIncAllocatedResource(res))
+		allocs[key] = alloc
+	}
+	addAlloc(app1, childQ1, node1, "victim1", 3, 0)
+	addAlloc(app1, childQ1, node2, "victim2", 3, 0)
+	addAlloc(app3, childQ3, node1, "high1a", 1, 10)
+	addAlloc(app3, childQ3, node1, "high1b", 1, 11)
+	addAlloc(app3, childQ3, node2, "high2", 2, 10)
+
+	app2, ask, err := creatApp2(childQ2, map[string]resources.Quantity{"first": 5}, "alloc3")
+	assert.NilError(t, err)
+	return app2, ask, getNodeIteratorFn(node1, node2), allocs
+}
+
+func TestTryPreemption_CrossNode(t *testing.T) {
+	node2Plan := []mock.Preemption{
+		mock.NewPreemption(true, "high2", nodeID1, []string{"victim1"}, 0, 0),
+		mock.NewPreemption(true, "alloc3", nodeID2, []string{"victim2", "high2"}, 1, 1),
+	}
+	node1Plan := []mock.Preemption{
+		mock.NewPreemption(true, "high1a", nodeID2, []string{"victim2"}, 0, 0),
+		mock.NewPreemption(true, "alloc3", nodeID1, []string{"victim1", "high1a", "high1b"}, 2, 2),
+	}
+	testCases := []struct {
+		name        string
+		depth       string
+		preemptions []mock.Preemption
+		nodeID      string
+		preempted   []string
+	}{
+		{"disabled", "0", nil, "", nil},
+		{"depth limits moves", "1", node2Plan, nodeID2, []string{"victim1", "victim2", "high2"}},
+		{"fewest moves first", "2", node2Plan, nodeID2, []string{"victim1", "victim2", "high2"}},
+		{"predicate check fails", "2", append([]mock.Preemption{
+			mock.NewPreemption(true, "high2", nodeID1, []string{"victim1"}, 0, 0),
+			mock.NewPreemption(false, "alloc3", nodeID2, []string{"victim2", "high2"}, 1, -1),
+		}, node1Plan...), nodeID1, []string{"victim1", "victim2", "high1a", "high1b"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app, ask, iterator, allocs := createCrossNodeTest(t, tc.depth)
+			headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})
+			preemptor := NewPreemptor(app, headRoom, 30*time.Second, ask, iterator(), false)
+			// high1b moves to node-2 without preemption after high1a made room
+			plugin := mock.NewPreemptionPredicatePlugin(nil, map[string]string{"high1b": nodeID2}, tc.preemptions)
+			plugins.RegisterSchedulerPlugin(plugin)
+			defer plugins.UnregisterSchedulerPlugins()
+
+			result, ok := preemptor.TryPreemption()
+			assert.NilError(t, plugin.GetPredicateError())
+			assert.Equal(t, ok, tc.nodeID != "", "unexpected preemption result")
+			if !ok {
+				assert.Assert(t, !ask.HasTriggeredPreemption(), "ask should not have triggered preemption")
+				for key, alloc := range allocs {
+					assert.Assert(t, !alloc.IsPreempted(), "unexpected preemption of %s", key)
+				}
+				return
+			}
+			assert.Equal(t, result.NodeID, tc.nodeID, "wrong node")
+			assert.Equal(t, result.ResultType, Reserved, "ask should reserve the node")
+			assert.Assert(t, ask.HasTriggeredPreemption(), "ask should have triggered preemption")
+			preempted := make(map[string]bool)
+			for _, key := range tc.preempted {
+				preempted[key] = true
+			}
+			for key, alloc := range allocs {
+				assert.Equal(t, alloc.IsPreempted(), preempted[key], "unexpected preemption state of %s", key)
+			}
+		})
+	}
+}
+
+func TestTryRequiredNodeCrossNode(t *testing.T) {
+	app, ask, iterator, allocs := createCrossNodeTest(t, "2")
+	ask.requiredNode = nodeID1
+	preemptor := NewPreemptor(app, nil, 0, ask, iterator(), false)
+	assert.Assert(t, preemptor.tryRequiredNodeCrossNode(nodeID1), "required node cross node preemption failed")
+	assert.Assert(t, ask.HasTriggeredPreemption(), "ask should have triggered preemption")
+	for key, alloc := range allocs {
+		assert.Equal(t, alloc.IsPreempted(), key != "high2", "unexpected preemption state of %s", key)
+	}
+
+	// cross node preemption disabled for the queue
+	app, ask, iterator, _ = createCrossNodeTest(t, "0")
+	ask.requiredNode = nodeID1
+	preemptor = NewPreemptor(app, nil, 0, ask, iterator(), false)
+	assert.Assert(t, !preemptor.tryRequiredNodeCrossNode(nodeID1), "cross node preemption should be disabled")
+}
+
+func TestPreemptionDryRun_CrossNode(t *testing.T) {
+	app, ask, iterator, allocs := createCrossNodeTest(t, "1")
+	ask.allowPreemptOther = true
+	preemptor := NewPreemptor(app, nil, 0, ask, iterator(), false)
+	result := preemptor.DryRun()
+	assert.Equal(t, result.Reason, "", "ask should be able to preempt")
+	assert.Equal(t, result.NodeID, nodeID2, "wrong node")
+	assert.DeepEqual(t, allocationKeys(result.Victims), []string{"victim2", "victim1"})
+	assert.DeepEqual(t, allocationKeys(result.Moved), []string{"high2"})
+	for key, alloc := range allocs {
+		assert.Assert(t, !alloc.IsPreempted(), "dry run preempted %s", key)
+	}
+}
+
+func TestTryPreemption_CrossNodeIneligible(t *testing.T) {
+	preemptions := []mock.Preemption{
+		mock.NewPreemption(true, "high2", nodeID1, []string{"victim1"}, 0, 0),
+		mock.NewPreemption(true, "alloc3", nodeID2, []string{"victim2", "high2"}, 1, 1),
+		mock.NewPreemption(true, "high1a", nodeID2, []string{"victim2"}, 0, 0),
+		mock.NewPreemption(true, "alloc3", nodeID1, []string{"victim1", "high1a", "high1b"}, 2, 2),
+	}
+	testCases := []struct {
+		name   string
+		props  map[string]string
+		update func(allocs map[string]*Allocation, queue *Queue)
+	}{
+		{"not preemptable", nil, func(allocs map[string]*Allocation, _ *Queue) {
+			for _, key := range []string{"high1a", "high1b", "high2"} {
+				allocs[key].allowPreemptSelf = false
+			}
+		}},
+		{"within guarantee", nil, func(_ map[string]*Allocation, queue *Queue) {
+			queue.guaranteedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"first": 4})
+		}},
+		{"budget exhausted", map[string]string{configs.PreemptionBudgetAllocations: "0"}, nil},
+		{"preemption disabled", map[string]string{configs.PreemptionPolicy: "disabled"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app, ask, iterator, allocs := createCrossNodeTestWithProps(t, "2", tc.props)
+			if tc.update != nil {
+				tc.update(allocs, app.queue.parent.children["child3"])
+			}
+			headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})
+			plugin := mock.NewPreemptionPredicatePlugin(nil, map[string]string{"high1b": nodeID2}, preemptions)
+			plugins.RegisterSchedulerPlugin(plugin)
+			defer plugins.UnregisterSchedulerPlugins()
+
+			preemptor := NewPreemptor(app, headRoom, 0, ask, iterator(), false)
+			dryRun := preemptor.DryRun()
+			assert.Equal(t, dryRun.NodeID, "", "dry run should not select a node")
+			assert.Equal(t, len(dryRun.Moved), 0, "dry run should not move allocations")
+
+			preemptor = NewPreemptor(app, headRoom, 30*time.Second, ask, iterator(), false)
+			_, ok := preemptor.TryPreemption()
+			assert.NilError(t, plugin.GetPredicateError())
+			assert.Assert(t, !ok, "ineligible allocations should not be moved")
+			assert.Assert(t, !ask.HasTriggeredPreemption(), "ask should not have triggered preemption")
+			for key, alloc := range allocs {
+				assert.Assert(t, !alloc.IsPreempted(), "unexpected preemption of %s", key)
+			}
+
+			ask.requiredNode = nodeID1
+			preemptor = NewPreemptor(app, nil, 0, ask, iterator(), false)
+			assert.Assert(t, !preemptor.tryRequiredNodeCrossNode(nodeID1), "ineligible allocations should not be moved for a required node")
+		})
+	}
+}
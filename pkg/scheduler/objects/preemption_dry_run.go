@@ -0,0 +1,173 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+const preemptQueueShortfall = "Preemption does not free enough resources within the queue guarantees"
+
+// PreemptionDryRun is the result of running the preemption checks for an ask without changing any state.
+type PreemptionDryRun struct {
+	ApplicationID       string
+	AllocationKey       string
+	QueuePath           string
+	FailedPreconditions []string                 // preconditions that are not met, empty if all are met
+	GuaranteesMet       bool                     // enough resources can be freed within the queue guarantees
+	Queues              []*PreemptionDryRunQueue // queue snapshots used by the checks, sorted by queue path
+	Nodes               []*PreemptionDryRunNode  // nodes considered for preemption, sorted by node ID
+	NodeID              string                   // node selected for the ask, empty if none
+	Victims             []*Allocation            // allocations that would be preempted
+	Moved               []*Allocation            // allocations that would be moved off the selected node
+	Reason              string                   // reason the ask cannot preempt, empty if it can
+}
+
+// PreemptionDryRunQueue is the snapshot of a queue used by the preemption checks.
+type PreemptionDryRunQueue struct {
+	QueuePath          string
+	AllocatedResource  *resources.Resource
+	PreemptingResource *resources.Resource
+	GuaranteedResource *resources.Resource
+	MaxResource        *resources.Resource
+	RemainingGuarantee *resources.Resource // guaranteed resources not used by the queue
+	Preemptable        *resources.Resource // resources that can be preempted without going below the guarantee
+	PotentialVictims   int
+}
+
+// PreemptionDryRunNode is a node that was considered for the preemption.
+type PreemptionDryRunNode struct {
+	NodeID            string
+	AvailableResource *resources.Resource
+	Victims           []*Allocation // victims for the node in order, nil if the node cannot be used
+}
+
+// DryRun runs the same checks as TryPreemption but does not preempt, reserve or update the ask.
+// The preconditions are checked and reported but do not stop the checks.
+func (p *Preemptor) DryRun() *PreemptionDryRun {
+	result := &PreemptionDryRun{
+		ApplicationID:       p.application.ApplicationID,
+		AllocationKey:       p.ask.GetAllocationKey(),
+		QueuePath:           p.queuePath,
+		FailedPreconditions: p.failedPreconditions(time.Now()),
+	}
+	if len(result.FailedPreconditions) != 0 {
+		result.Reason = common.PreemptionPreconditionsFailed
+	}
+	setReason := func(reason string) {
+		if result.Reason == "" {
+			result.Reason = reason
+		}
+	}
+
+	result.GuaranteesMet = p.checkPreemptionQueueGuarantees()
+	result.Queues = p.dryRunQueues()
+	if !result.GuaranteesMet {
+		setReason(common.PreemptionDoesNotGuarantee)
+		return result
+	}
+
+	p.initWorkingState()
+	predicateChecks, victimsByNode := p.calculatePredicateChecks()
+	result.Nodes = p.dryRunNodes(victimsByNode)
+	predicateResult := p.checkPreemptionPredicates(predicateChecks, victimsByNode)
+	if predicateResult == nil || !predicateResult.success {
+		p.dryRunCrossNode(result, setReason)
+		return result
+	}
+
+	extraVictims, ok := p.calculateAdditionalVictims(predicateResult.victims)
+	if !ok {
+		setReason(preemptQueueShortfall)
+		return result
+	}
+	victims := predicateResult.victims
+	victims = append(victims, extraVictims...)
+	if len(victims) == 0 {
+		setReason(common.PreemptionDoesNotHelp)
+		return result
+	}
+	finalVictims, ok := p.selectFinalVictims(predicateResult.nodeID, victims)
+	if !ok {
+		setReason(common.PreemptionShortfall)
+		return result
+	}
+	result.NodeID = predicateResult.nodeID
+	result.Victims = finalVictims
+	return result
+}
+
+// dryRunCrossNode adds the cross node preemption plan to the result, used when no single node can fit the ask.
+func (p *Preemptor) dryRunCrossNode(result *PreemptionDryRun, setReason func(string)) {
+	plan, ok := p.tryCrossNode(p.crossNodeTargets())
+	if !ok {
+		setReason(common.PreemptionDoesNotHelp)
+		return
+	}
+	victims := plan.getVictims()
+	extraVictims, ok := p.calculateAdditionalVictims(victims)
+	if !ok {
+		setReason(preemptQueueShortfall)
+		return
+	}
+	result.NodeID = plan.nodeID
+	result.Victims = p.expandVictims(append(victims, extraVictims...))
+	result.Moved = plan.getMoved()
+}
+
+// dryRunQueues returns the queue snapshots sorted by queue path.
+func (p *Preemptor) dryRunQueues() []*PreemptionDryRunQueue {
+	queues := make([]*PreemptionDryRunQueue, 0, len(p.allocationsByQueue))
+	for _, snapshot := range p.allocationsByQueue {
+		queues = append(queues, &PreemptionDryRunQueue{
+			QueuePath:          snapshot.QueuePath,
+			AllocatedResource:  snapshot.AllocatedResource.Clone(),
+			PreemptingResource: snapshot.PreemptingResource.Clone(),
+			GuaranteedResource: snapshot.GetGuaranteedResource(),
+			MaxResource:        snapshot.GetMaxResource(),
+			RemainingGuarantee: snapshot.GetRemainingGuaranteedResource(),
+			Preemptable:        snapshot.GetPreemptableResource(),
+			PotentialVictims:   len(snapshot.PotentialVictims),
+		})
+	}
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].QueuePath < queues[j].QueuePath
+	})
+	return queues
+}
+
+// dryRunNodes returns the nodes that were considered with the victims calculated per node, sorted by node ID.
+func (p *Preemptor) dryRunNodes(victimsByNode map[string][]*Allocation) []*PreemptionDryRunNode {
+	nodes := make([]*PreemptionDryRunNode, 0, len(p.nodeAvailableMap))
+	for nodeID, available := range p.nodeAvailableMap {
+		nodes = append(nodes, &PreemptionDryRunNode{
+			NodeID:            nodeID,
+			AvailableResource: available.Clone(),
+			Victims:           victimsByNode[nodeID],
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeID < nodes[j].NodeID
+	})
+	return nodes
+}
@@ -0,0 +1,130 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/mock"
+	"github.com/apache/yunikorn-core/pkg/plugins"
+)
+
+func TestPreemptionDryRun(t *testing.T) {
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 10, "pods": 5})
+	iterator := getNodeIteratorFn(node)
+	rootQ, err := createRootQueue(map[string]string{"first": "20", "pods": "5"})
+	assert.NilError(t, err)
+	parentQ, err := createManagedQueueGuaranteed(rootQ, "parent", true, map[string]string{"first": "20"}, map[string]string{"first": "10"})
+	assert.NilError(t, err)
+	childQ1, err := createManagedQueueGuaranteed(parentQ, "child1", false, map[string]string{"first": "10"}, map[string]string{"first": "5"})
+	assert.NilError(t, err)
+	childQ2, err := createManagedQueueGuaranteed(parentQ, "child2", false, map[string]string{"first": "10"}, map[string]string{"first": "5"})
+	assert.NilError(t, err)
+
+	alloc1, alloc2, err := creatApp1(childQ1, node, nil, map[string]resources.Quantity{"first": 5, "pods": 1})
+	assert.NilError(t, err)
+	app2, ask3, err := creatApp2(childQ2, map[string]resources.Quantity{"first": 5, "pods": 1}, "alloc3")
+	assert.NilError(t, err)
+	ask3.allowPreemptOther = true
+	ask3.createTime = time.Now().Add(-1 * time.Minute)
+	childQ2.incPendingResource(ask3.GetAllocatedResource())
+	checkTime := ask3.GetPreemptCheckTime()
+	pending := app2.GetPendingResource()
+	state := app2.CurrentState()
+
+	preemptions := []mock.Preemption{
+		mock.NewPreemption(true, "alloc3", nodeID1, []string{"alloc1"}, 0, 0),
+	}
+	plugin := mock.NewPreemptionPredicatePlugin(nil, nil, preemptions)
+	plugins.RegisterSchedulerPlugin(plugin)
+	defer plugins.UnregisterSchedulerPlugins()
+
+	// unknown asks fail
+	_, err = app2.PreemptionDryRun("unknown", iterator())
+	assert.ErrorContains(t, err, "not found")
+
+	dryRun, err := app2.PreemptionDryRun("alloc3", iterator())
+	assert.NilError(t, err)
+	assert.NilError(t, plugin.GetPredicateError())
+	assert.Equal(t, dryRun.ApplicationID, appID2)
+	assert.Equal(t, dryRun.AllocationKey, "alloc3")
+	assert.Equal(t, dryRun.QueuePath, "root.parent.child2")
+	assert.Equal(t, dryRun.Reason, "", "dry run should have found victims")
+	assert.Equal(t, len(dryRun.FailedPreconditions), 0)
+	assert.Assert(t, dryRun.GuaranteesMet, "guarantees should be met")
+	assert.Equal(t, dryRun.NodeID, nodeID1)
+	assert.Equal(t, len(dryRun.Victims), 1)
+	assert.Equal(t, dryRun.Victims[0].GetAllocationKey(), "alloc1")
+	assert.Equal(t, len(dryRun.Nodes), 1)
+	assert.Equal(t, dryRun.Nodes[0].NodeID, nodeID1)
+	assert.Assert(t, len(dryRun.Nodes[0].Victims) > 0, "node should have victims")
+	assert.Assert(t, len(dryRun.Queues) > 0, "queues should be reported")
+	for i := 1; i < len(dryRun.Queues); i++ {
+		assert.Assert(t, dryRun.Queues[i-1].QueuePath < dryRun.Queues[i].QueuePath, "queues not sorted")
+	}
+
+	// nothing may have changed
+	assert.Check(t, !alloc1.IsPreempted(), "alloc1 preempted")
+	assert.Check(t, !alloc2.IsPreempted(), "alloc2 preempted")
+	assert.Check(t, !ask3.HasTriggeredPreemption(), "ask marked as triggered preemption")
+	assert.Equal(t, ask3.GetPreemptCheckTime(), checkTime, "preempt check time changed")
+	assert.Equal(t, len(ask3.GetAllocationLog()), 0)
+	assert.Assert(t, resources.IsZero(childQ1.GetPreemptingResource()), "preempting resource set on victim queue")
+	assert.Assert(t, resources.Equals(app2.GetPendingResource(), pending), "pending resource of the application changed")
+	assert.Assert(t, resources.IsZero(app2.GetAllocatedResource()), "allocated resource of the application changed")
+	assert.Equal(t, app2.CurrentState(), state, "application state changed")
+}
+
+func TestPreemptionDryRunPreconditions(t *testing.T) {
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 5})
+	iterator := getNodeIteratorFn(node)
+	rootQ, err := createRootQueue(map[string]string{"first": "5"})
+	assert.NilError(t, err)
+	childQ, err := createManagedQueue(rootQ, "child", false, map[string]string{"first": "5"})
+	assert.NilError(t, err)
+	app := newApplication(appID1, "default", "root.child")
+	app.SetQueue(childQ)
+	childQ.applications[appID1] = app
+	ask := newAllocationAsk("alloc1", appID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5}))
+	ask.allowPreemptOther = false
+	ask.createTime = time.Now()
+	ask.SetRequiredNode(nodeID1)
+	assert.NilError(t, app.AddAllocationAsk(ask))
+
+	dryRun, err := app.PreemptionDryRun("alloc1", iterator())
+	assert.NilError(t, err)
+	assert.Equal(t, dryRun.Reason, common.PreemptionPreconditionsFailed)
+	assert.DeepEqual(t, dryRun.FailedPreconditions, []string{preemptNotAllowed, preemptRequiredNode, preemptDelayNotPassed})
+	assert.Assert(t, dryRun.Victims == nil, "victims should not be set")
+
+	// the queue is not guaranteed: the guarantees check still runs and is reported
+	assert.Assert(t, !dryRun.GuaranteesMet, "guarantees should not be met")
+	assert.Assert(t, !ask.HasTriggeredPreemption(), "ask marked as triggered preemption")
+}
+
+func TestPreemptionDryRunNoQueue(t *testing.T) {
+	app := newApplication(appID1, "default", "root.child")
+	_, err := app.PreemptionDryRun("alloc1", nil)
+	assert.ErrorContains(t, err, "not assigned to a queue")
+}
@@ -0,0 +1,127 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+// initGangs maps each gang member in the queue snapshots to all members of its task group.
+func (p *Preemptor) initGangs() {
+	p.gangs = make(map[string][]*Allocation)
+	for _, snapshot := range p.allocationsByQueue {
+		for _, members := range snapshot.gangs {
+			for _, member := range members {
+				p.gangs[member.GetAllocationKey()] = members
+			}
+		}
+	}
+}
+
+// victimUnit returns the allocations that are preempted together with the victim: all members of the task group
+// if the victim is preempted as part of a gang, otherwise just the victim.
+func (p *Preemptor) victimUnit(victim *Allocation) []*Allocation {
+	if members, ok := p.gangs[victim.GetAllocationKey()]; ok {
+		return members
+	}
+	return []*Allocation{victim}
+}
+
+// victimResource returns the resources freed in the queue when the victim unit is preempted.
+func (p *Preemptor) victimResource(victim *Allocation) *resources.Resource {
+	if _, ok := p.gangs[victim.GetAllocationKey()]; !ok {
+		return victim.GetAllocatedResource()
+	}
+	return victimsResource(p.victimUnit(victim))
+}
+
+// victimNodeMembers returns the members of the victim unit that run on the same node as the victim.
+func (p *Preemptor) victimNodeMembers(victim *Allocation) []*Allocation {
+	members, ok := p.gangs[victim.GetAllocationKey()]
+	if !ok {
+		return []*Allocation{victim}
+	}
+	nodeMembers := make([]*Allocation, 0, len(members))
+	for _, member := range members {
+		if member.GetNodeID() == victim.GetNodeID() {
+			nodeMembers = append(nodeMembers, member)
+		}
+	}
+	return nodeMembers
+}
+
+// victimNodeResource returns the resources freed on the node of the victim when the victim unit is preempted.
+func (p *Preemptor) victimNodeResource(victim *Allocation) *resources.Resource {
+	if _, ok := p.gangs[victim.GetAllocationKey()]; !ok {
+		return victim.GetAllocatedResource()
+	}
+	return victimsResource(p.victimNodeMembers(victim))
+}
+
+// expandVictims replaces each victim with all allocations of its unit. Each allocation is listed only once.
+func (p *Preemptor) expandVictims(victims []*Allocation) []*Allocation {
+	if len(p.gangs) == 0 {
+		return victims
+	}
+	seen := make(map[string]bool)
+	expanded := make([]*Allocation, 0, len(victims))
+	for _, victim := range victims {
+		for _, member := range p.victimUnit(victim) {
+			if !seen[member.GetAllocationKey()] {
+				seen[member.GetAllocationKey()] = true
+				expanded = append(expanded, member)
+			}
+		}
+	}
+	return expanded
+}
+
+// predicateKeys returns the allocation keys the shim must check to preempt the victims on a node, and the index of
+// the key that matches the victim at the start index. A gang member on the node is replaced by all members of its
+// task group on that node, the members are always checked together.
+func (p *Preemptor) predicateKeys(victims []*Allocation, index int) ([]string, int) {
+	keys := make([]string, 0, len(victims))
+	keyIndex := -1
+	for i, victim := range victims {
+		for _, member := range p.victimNodeMembers(victim) {
+			keys = append(keys, member.GetAllocationKey())
+		}
+		if i == index {
+			keyIndex = len(keys) - 1
+		}
+	}
+	return keys, keyIndex
+}
+
+// victimIndex converts an index into the allocation keys returned by predicateKeys back into an index into the
+// victims. An index that points to part of the members of a gang selects the whole gang.
+func (p *Preemptor) victimIndex(victims []*Allocation, keyIndex int) int {
+	if keyIndex < 0 || len(p.gangs) == 0 {
+		return keyIndex
+	}
+	keys := 0
+	for i, victim := range victims {
+		keys += len(p.victimNodeMembers(victim))
+		if keyIndex < keys {
+			return i
+		}
+	}
+	// out of range, leave it to the caller to reject
+	return len(victims)
+}
@@ -0,0 +1,88 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestPreemptorGangs(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	gang1 := newAllocationAll("gang1", appID1, nodeID1, "tg", res, false, 0)
+	gang2 := newAllocationAll("gang2", appID1, nodeID1, "tg", res, false, 0)
+	gang3 := newAllocationAll("gang3", appID1, nodeID2, "tg", res, false, 0)
+	single := newAllocationWithKey("single", appID2, nodeID1, res)
+	members := []*Allocation{gang1, gang2, gang3}
+	p := &Preemptor{
+		allocationsByQueue: map[string]*QueuePreemptionSnapshot{
+			"root.gang":   {QueuePath: "root.gang", PotentialVictims: members, gangs: [][]*Allocation{members}},
+			"root.single": {QueuePath: "root.single", PotentialVictims: []*Allocation{single}},
+		},
+	}
+	p.initGangs()
+	assert.Equal(t, len(p.gangs), 3, "all members should be tracked")
+
+	assert.DeepEqual(t, allocationKeys(p.victimUnit(gang3)), []string{"gang1", "gang2", "gang3"})
+	assert.DeepEqual(t, allocationKeys(p.victimUnit(single)), []string{"single"})
+	assert.Assert(t, resources.Equals(p.victimResource(gang1), resources.Multiply(res, 3)), "unit resource should include all members")
+	assert.Assert(t, resources.Equals(p.victimResource(single), res), "single resource should be the allocation")
+	assert.DeepEqual(t, allocationKeys(p.victimNodeMembers(gang1)), []string{"gang1", "gang2"})
+	assert.DeepEqual(t, allocationKeys(p.victimNodeMembers(gang3)), []string{"gang3"})
+	assert.Assert(t, resources.Equals(p.victimNodeResource(gang2), resources.Multiply(res, 2)), "node resource should include members on the node")
+	assert.Assert(t, resources.Equals(p.victimNodeResource(single), res), "node resource should be the allocation")
+
+	expanded := p.expandVictims([]*Allocation{single, gang3, gang1})
+	assert.DeepEqual(t, allocationKeys(expanded), []string{"single", "gang1", "gang2", "gang3"})
+
+	// a gang member stands for all members of the task group on the node
+	victims := []*Allocation{single, gang1}
+	keys, index := p.predicateKeys(victims, 0)
+	assert.DeepEqual(t, keys, []string{"single", "gang1", "gang2"})
+	assert.Equal(t, index, 0)
+	_, index = p.predicateKeys(victims, 1)
+	assert.Equal(t, index, 2, "start index should include all members")
+	_, index = p.predicateKeys(victims, -1)
+	assert.Equal(t, index, -1)
+	assert.Equal(t, p.victimIndex(victims, -1), -1)
+	assert.Equal(t, p.victimIndex(victims, 0), 0)
+	assert.Equal(t, p.victimIndex(victims, 1), 1, "part of the gang should select the gang")
+	assert.Equal(t, p.victimIndex(victims, 2), 1)
+	assert.Equal(t, p.victimIndex(victims, 3), 2, "out of range index should stay out of range")
+
+	// without gangs everything is passed through
+	p = &Preemptor{}
+	assert.DeepEqual(t, allocationKeys(p.victimUnit(gang1)), []string{"gang1"})
+	keys, index = p.predicateKeys(victims, 1)
+	assert.DeepEqual(t, keys, []string{"single", "gang1"})
+	assert.Equal(t, index, 1)
+	assert.Equal(t, p.victimIndex(victims, 5), 5)
+}
+
+// allocationKeys returns the keys of the allocations in order
+func allocationKeys(allocs []*Allocation) []string {
+	keys := make([]string, 0, len(allocs))
+	for _, alloc := range allocs {
+		keys = append(keys, alloc.GetAllocationKey())
+	}
+	return keys
+}
@@ -0,0 +1,153 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"strings"
+	"time"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/locking"
+)
+
+// DefaultPreemptionHistorySize is the number of preemption decisions kept per partition.
+const DefaultPreemptionHistorySize = 1000
+
+// PreemptionRecord is a preemption decision: the ask that triggered the preemption and the victims preempted for it.
+type PreemptionRecord struct {
+	Timestamp     time.Time
+	ApplicationID string                    // application of the ask
+	AllocationKey string                    // ask that triggered the preemption
+	QueuePath     string                    // queue of the ask
+	NodeID        string                    // node the ask will be placed on
+	RequiredNode  bool                      // ask requires the node
+	Victims       []*PreemptionVictimRecord // allocations preempted for the ask
+	Reclaimed     *resources.Resource       // total resources of the victims
+}
+
+// PreemptionVictimRecord is an allocation preempted as part of a preemption decision.
+type PreemptionVictimRecord struct {
+	ApplicationID string
+	AllocationKey string
+	QueuePath     string
+	NodeID        string
+	Priority      int32
+	Resource      *resources.Resource
+}
+
+// PreemptionHistory keeps a bounded history of the preemption decisions. When the history is full the oldest
+// decision is replaced.
+type PreemptionHistory struct {
+	records []*PreemptionRecord
+	limit   int
+	pointer int // position of the next record to store
+
+	locking.RWMutex
+}
+
+func NewPreemptionHistory(limit int) *PreemptionHistory {
+	return &PreemptionHistory{
+		records: make([]*PreemptionRecord, limit),
+		limit:   limit,
+	}
+}
+
+// newPreemptionRecord creates a record for the preemption triggered by the ask without victims.
+func newPreemptionRecord(ask *Allocation, queuePath, nodeID string, requiredNode bool) *PreemptionRecord {
+	return &PreemptionRecord{
+		Timestamp:     time.Now(),
+		ApplicationID: ask.GetApplicationID(),
+		AllocationKey: ask.GetAllocationKey(),
+		QueuePath:     queuePath,
+		NodeID:        nodeID,
+		RequiredNode:  requiredNode,
+		Reclaimed:     resources.NewResource(),
+	}
+}
+
+// addVictim adds the preempted allocation from the queue to the record.
+func (pr *PreemptionRecord) addVictim(victim *Allocation, queuePath string) {
+	pr.Victims = append(pr.Victims, &PreemptionVictimRecord{
+		ApplicationID: victim.GetApplicationID(),
+		AllocationKey: victim.GetAllocationKey(),
+		QueuePath:     queuePath,
+		NodeID:        victim.GetNodeID(),
+		Priority:      victim.GetPriority(),
+		Resource:      victim.GetAllocatedResource().Clone(),
+	})
+	pr.Reclaimed.AddTo(victim.GetAllocatedResource())
+}
+
+// involvesQueue returns true if the ask or one of the victims ran in the queue or one of its descendants.
+func (pr *PreemptionRecord) involvesQueue(queuePath string) bool {
+	inQueue := func(path string) bool {
+		return path == queuePath || strings.HasPrefix(path, queuePath+".")
+	}
+	if inQueue(pr.QueuePath) {
+		return true
+	}
+	for _, victim := range pr.Victims {
+		if inQueue(victim.QueuePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add stores the record, replacing the oldest record if the history is full.
+func (h *PreemptionHistory) Add(record *PreemptionRecord) {
+	if h == nil || h.limit <= 0 {
+		return
+	}
+	h.Lock()
+	defer h.Unlock()
+	h.records[h.pointer] = record
+	h.pointer++
+	if h.pointer == h.limit {
+		h.pointer = 0
+	}
+}
+
+// GetRecords returns the preemption decisions, oldest first. If the queue path is set only decisions that involve
+// the queue or one of its descendants are returned. A zero start or end time does not limit the time range.
+func (h *PreemptionHistory) GetRecords(queuePath string, start, end time.Time) []*PreemptionRecord {
+	records := make([]*PreemptionRecord, 0)
+	if h == nil {
+		return records
+	}
+	h.RLock()
+	defer h.RUnlock()
+	for i := 0; i < h.limit; i++ {
+		record := h.records[(h.pointer+i)%h.limit]
+		if record == nil {
+			continue
+		}
+		if !start.IsZero() && record.Timestamp.Before(start) {
+			continue
+		}
+		if !end.IsZero() && record.Timestamp.After(end) {
+			continue
+		}
+		if queuePath != "" && !record.involvesQueue(queuePath) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,87 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestPreemptionRecord(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2})
+	ask := newAllocationAsk("ask", appID1, res)
+	record := newPreemptionRecord(ask, "root.parent.ask", nodeID1, false)
+	assert.Equal(t, record.ApplicationID, appID1)
+	assert.Equal(t, record.AllocationKey, "ask")
+	assert.Assert(t, resources.IsZero(record.Reclaimed), "new record should not reclaim resources")
+
+	record.addVictim(newAllocationAll("victim1", appID2, nodeID1, "", res, false, 5), "root.other.victim")
+	record.addVictim(newAllocationWithKey("victim2", appID2, nodeID2, res), "root.other.victim")
+	assert.Equal(t, len(record.Victims), 2)
+	assert.Equal(t, record.Victims[0].Priority, int32(5))
+	assert.Equal(t, record.Victims[1].NodeID, nodeID2)
+	assert.Assert(t, resources.Equals(record.Reclaimed, resources.Multiply(res, 2)), "reclaimed resources not summed")
+
+	assert.Assert(t, record.involvesQueue("root"), "root should always be involved")
+	assert.Assert(t, record.involvesQueue("root.parent"), "ask parent queue should be involved")
+	assert.Assert(t, record.involvesQueue("root.other.victim"), "victim queue should be involved")
+	assert.Assert(t, !record.involvesQueue("root.par"), "queue name prefix should not match")
+	assert.Assert(t, !record.involvesQueue("root.unrelated"), "unrelated queue should not be involved")
+}
+
+func TestPreemptionHistory(t *testing.T) {
+	var none *PreemptionHistory
+	none.Add(&PreemptionRecord{})
+	assert.Equal(t, len(none.GetRecords("", time.Time{}, time.Time{})), 0)
+
+	now := time.Now()
+	newRecord := func(key, queuePath string, offset time.Duration) *PreemptionRecord {
+		return &PreemptionRecord{Timestamp: now.Add(offset), AllocationKey: key, QueuePath: queuePath}
+	}
+	history := NewPreemptionHistory(3)
+	assert.Equal(t, len(history.GetRecords("", time.Time{}, time.Time{})), 0)
+	history.Add(newRecord("ask1", "root.a", -3*time.Minute))
+	history.Add(newRecord("ask2", "root.b", -2*time.Minute))
+	assert.DeepEqual(t, recordKeys(history.GetRecords("", time.Time{}, time.Time{})), []string{"ask1", "ask2"})
+
+	// the oldest record is replaced once the history is full
+	history.Add(newRecord("ask3", "root.a.child", -time.Minute))
+	history.Add(newRecord("ask4", "root.b", 0))
+	assert.DeepEqual(t, recordKeys(history.GetRecords("", time.Time{}, time.Time{})), []string{"ask2", "ask3", "ask4"})
+
+	// filters
+	assert.DeepEqual(t, recordKeys(history.GetRecords("root.a", time.Time{}, time.Time{})), []string{"ask3"})
+	assert.DeepEqual(t, recordKeys(history.GetRecords("root.b", time.Time{}, time.Time{})), []string{"ask2", "ask4"})
+	assert.DeepEqual(t, recordKeys(history.GetRecords("", now.Add(-90*time.Second), time.Time{})), []string{"ask3", "ask4"})
+	assert.DeepEqual(t, recordKeys(history.GetRecords("", time.Time{}, now.Add(-time.Minute))), []string{"ask2", "ask3"})
+	assert.DeepEqual(t, recordKeys(history.GetRecords("root.b", now.Add(-time.Minute), now)), []string{"ask4"})
+}
+
+// recordKeys returns the allocation keys of the asks of the records in order
+func recordKeys(records []*PreemptionRecord) []string {
+	keys := make([]string, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, record.AllocationKey)
+	}
+	return keys
+}
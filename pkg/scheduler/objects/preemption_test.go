@@ -27,6 +27,7 @@ import (
 	"gotest.tools/v3/assert"
 
 	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	evtMock "github.com/apache/yunikorn-core/pkg/events/mock"
 	"github.com/apache/yunikorn-core/pkg/mock"
@@ -260,6 +261,8 @@ func TestTryPreemption(t *testing.T) {
 	iterator := getNodeIteratorFn(node)
 	rootQ, err := createRootQueue(map[string]string{"first": "20", "pods": "5"})
 	assert.NilError(t, err)
+	history := NewPreemptionHistory(10)
+	rootQ.SetPreemptionHistory(history)
 	parentQ, err := createManagedQueueGuaranteed(rootQ, "parent", true, map[string]string{"first": "20"}, map[string]string{"first": "10"})
 	assert.NilError(t, err)
 	childQ1, err := createManagedQueueGuaranteed(parentQ, "child1", false, map[string]string{"first": "10"}, map[string]string{"first": "5"})
@@ -293,6 +296,15 @@ func TestTryPreemption(t *testing.T) {
 	assert.Check(t, alloc1.IsPreempted(), "alloc1 not preempted")
 	assert.Check(t, !alloc2.IsPreempted(), "alloc2 preempted")
 	assert.Equal(t, len(ask3.GetAllocationLog()), 0)
+	records := history.GetRecords("", time.Time{}, time.Time{})
+	assert.Equal(t, len(records), 1, "preemption decision not recorded")
+	assert.Equal(t, records[0].AllocationKey, "alloc3")
+	assert.Equal(t, records[0].QueuePath, "root.parent.child2")
+	assert.Equal(t, records[0].NodeID, nodeID1)
+	assert.Assert(t, !records[0].RequiredNode, "not a required node preemption")
+	assert.Equal(t, len(records[0].Victims), 1)
+	assert.Equal(t, records[0].Victims[0].AllocationKey, "alloc1")
+	assert.Equal(t, records[0].Victims[0].QueuePath, "root.parent.child1")
 }
 
 func TestTryPreemption_SendEvent(t *testing.T) {
@@ -347,6 +359,291 @@ func TestTryPreemption_SendEvent(t *testing.T) {
 	assert.Equal(t, len(ask3.GetAllocationLog()), 0)
 }
 
+func TestTryPreemption_PreemptionBudget(t *testing.T) {
+	var tests = []struct {
+		name        string
+		queueBudget []string
+		appBudget   []string
+		preempted   bool
+		eventRef    string
+	}{
+		{"no budget", nil, nil, true, ""},
+		{"queue budget exhausted", []string{"0", "", ""}, nil, false, ""},
+		{"queue budget single", []string{"1", "", ""}, nil, true, ""},
+		{"queue percentage too small", []string{"", "10", ""}, nil, false, ""},
+		{"app budget exhausted", nil, []string{"0", "", ""}, false, ""},
+		{"app budget single", nil, []string{"1", "", ""}, true, appID1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := newNode(nodeID1, map[string]resources.Quantity{"first": 10, "pods": 5})
+			iterator := getNodeIteratorFn(node)
+			rootQ, err := createRootQueue(map[string]string{"first": "20", "pods": "5"})
+			assert.NilError(t, err)
+			parentQ, err := createManagedQueueGuaranteed(rootQ, "parent", true, map[string]string{"first": "20"}, map[string]string{"first": "10"})
+			assert.NilError(t, err)
+			childQ1, err := createManagedQueueGuaranteed(parentQ, "child1", false, map[string]string{"first": "10"}, map[string]string{"first": "5"})
+			assert.NilError(t, err)
+			childQ2, err := createManagedQueueGuaranteed(parentQ, "child2", false, map[string]string{"first": "10"}, map[string]string{"first": "5"})
+			assert.NilError(t, err)
+			eventSystem := evtMock.NewEventSystem()
+			childQ1.queueEvents = schedEvt.NewQueueEvents(eventSystem)
+
+			alloc1, alloc2, err := creatApp1(childQ1, node, nil, map[string]resources.Quantity{"first": 5, "pods": 1})
+			assert.NilError(t, err)
+			if tt.queueBudget != nil {
+				childQ1.preemptionBudget, err = newPreemptionBudget(tt.queueBudget[0], tt.queueBudget[1], tt.queueBudget[2])
+				assert.NilError(t, err)
+			}
+			if tt.appBudget != nil {
+				childQ1.GetApplication(appID1).preemptionBudget, err = newPreemptionBudget(tt.appBudget[0], tt.appBudget[1], tt.appBudget[2])
+				assert.NilError(t, err)
+			}
+
+			app2, ask3, err := creatApp2(childQ2, map[string]resources.Quantity{"first": 5, "pods": 1}, "alloc3")
+			assert.NilError(t, err)
+			childQ2.incPendingResource(ask3.GetAllocatedResource())
+
+			headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10, "pods": 3})
+			preemptor := NewPreemptor(app2, headRoom, 30*time.Second, ask3, iterator(), false)
+
+			preemptions := []mock.Preemption{
+				mock.NewPreemption(true, "alloc3", nodeID1, []string{"alloc1"}, 0, 0),
+			}
+			plugin := mock.NewPreemptionPredicatePlugin(nil, nil, preemptions)
+			plugins.RegisterSchedulerPlugin(plugin)
+			defer plugins.UnregisterSchedulerPlugins()
+
+			result, ok := preemptor.TryPreemption()
+			assert.Equal(t, ok, tt.preempted, "unexpected preemption result")
+			assert.Equal(t, result != nil, tt.preempted, "unexpected preemption result")
+			assert.Check(t, !alloc2.IsPreempted(), "alloc2 preempted")
+			if !tt.preempted {
+				assert.Check(t, !alloc1.IsPreempted(), "alloc1 preempted")
+				assert.Equal(t, len(eventSystem.Events), 0)
+				return
+			}
+			assert.Check(t, alloc1.IsPreempted(), "alloc1 not preempted")
+			if tt.queueBudget == nil && tt.appBudget == nil {
+				assert.Equal(t, len(eventSystem.Events), 0)
+				return
+			}
+			// the single preemption exhausts the budget
+			assert.Equal(t, len(eventSystem.Events), 1)
+			event := eventSystem.Events[0]
+			assert.Equal(t, si.EventRecord_QUEUE, event.Type)
+			assert.Equal(t, "root.parent.child1", event.ObjectID)
+			assert.Equal(t, tt.eventRef, event.ReferenceID)
+			assert.Equal(t, "Preemption budget exhausted: 1 allocations preempted in the last 10m0s", event.Message)
+		})
+	}
+}
+
+func TestSortVictimsForPreemption_PreemptionBudget(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	now := time.Now()
+	limited1 := newAllocationWithKey("limited1", appID1, nodeID1, res)
+	limited1.createTime = now
+	limited2 := newAllocationWithKey("limited2", appID1, nodeID1, res)
+	limited2.createTime = now.Add(-time.Second)
+	limited3 := newAllocationWithKey("limited3", appID1, nodeID1, res)
+	limited3.createTime = now.Add(-2 * time.Second)
+	free := newAllocationWithKey("free", appID2, nodeID1, res)
+	free.createTime = now.Add(-time.Minute)
+
+	limitedQueue := &QueuePreemptionSnapshot{QueuePath: "root.limited", budget: &budgetHeadroom{allocations: 2}}
+	freeQueue := &QueuePreemptionSnapshot{QueuePath: "root.free"}
+	queueByAlloc := map[string]*QueuePreemptionSnapshot{
+		"limited1": limitedQueue,
+		"limited2": limitedQueue,
+		"limited3": limitedQueue,
+		"free":     freeQueue,
+	}
+	allocationsByNode := map[string][]*Allocation{
+		nodeID1: {limited3, limited1, free, limited2},
+	}
+	p := &Preemptor{ask: newAllocationAsk("ask", appID3, res), queueByAlloc: queueByAlloc, costs: make(map[string]float64)}
+	p.sortVictimsForPreemption(allocationsByNode)
+	victims := allocationsByNode[nodeID1]
+	assert.Equal(t, len(victims), 3, "victim over budget not removed")
+	assert.Equal(t, victims[0].GetAllocationKey(), "free")
+	assert.Equal(t, victims[1].GetAllocationKey(), "limited1")
+	assert.Equal(t, victims[2].GetAllocationKey(), "limited2")
+}
+
+// TestTryPreemption_Gang preempts a task group member on the only node the ask can use. In gang-aware mode the
+// member on the other node is preempted with it, unless one of the members cannot be preempted.
+func TestTryPreemption_Gang(t *testing.T) {
+	testCases := []struct {
+		name         string
+		gang         string
+		requiredNode bool
+		preempted    []bool
+	}{
+		{"gang disabled", "false", false, []bool{true, false}},
+		{"gang enabled", "true", false, []bool{true, true}},
+		{"gang member not eligible", "true", true, []bool{false, false}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node1 := newNode(nodeID1, map[string]resources.Quantity{"first": 5})
+			node2 := newNode(nodeID2, map[string]resources.Quantity{"first": 5})
+			iterator := getNodeIteratorFn(node1)
+			rootQ, err := createRootQueue(map[string]string{"first": "10"})
+			assert.NilError(t, err)
+			parentQ, err := createManagedQueueGuaranteed(rootQ, "parent", true, nil, nil)
+			assert.NilError(t, err)
+			childQ1, err := createManagedQueueWithProps(parentQ, "child1", false, nil, map[string]string{configs.PreemptionGang: tc.gang})
+			assert.NilError(t, err)
+			childQ2, err := createManagedQueueGuaranteed(parentQ, "child2", false, nil, map[string]string{"first": "5"})
+			assert.NilError(t, err)
+
+			app1 := newApplication(appID1, "default", "root.parent.child1")
+			app1.SetQueue(childQ1)
+			childQ1.applications[appID1] = app1
+			res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 4})
+			members := []*Allocation{
+				newAllocationAll("gang1", appID1, nodeID1, "tg", res, false, 0),
+				newAllocationAll("gang2", appID1, nodeID2, "tg", res, false, 0),
+			}
+			if tc.requiredNode {
+				members[1].requiredNode = nodeID2
+			}
+			app1.AddAllocation(members[0])
+			app1.AddAllocation(members[1])
+			assert.Check(t, node1.TryAddAllocation(members[0]), "node gang1 failed")
+			assert.Check(t, node2.TryAddAllocation(members[1]), "node gang2 failed")
+			assert.NilError(t, childQ1.TryIncAllocatedResource(resources.Multiply(res, 2)))
+
+			app2, ask3, err := creatApp2(childQ2, map[string]resources.Quantity{"first": 4}, "alloc3")
+			assert.NilError(t, err)
+			headRoom := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})
+			preemptor := NewPreemptor(app2, headRoom, 30*time.Second, ask3, iterator(), false)
+
+			preemptions := []mock.Preemption{
+				mock.NewPreemption(true, "alloc3", nodeID1, []string{"gang1"}, 0, 0),
+			}
+			plugin := mock.NewPreemptionPredicatePlugin(nil, nil, preemptions)
+			plugins.RegisterSchedulerPlugin(plugin)
+			defer plugins.UnregisterSchedulerPlugins()
+
+			result, ok := preemptor.TryPreemption()
+			assert.NilError(t, plugin.GetPredicateError())
+			assert.Equal(t, ok, tc.preempted[0], "unexpected preemption result")
+			if ok {
+				assert.Equal(t, result.NodeID, nodeID1, "wrong node")
+			}
+			for i, member := range members {
+				assert.Equal(t, member.IsPreempted(), tc.preempted[i], "unexpected preemption of %s", member.GetAllocationKey())
+			}
+		})
+	}
+}
+
+// victimCostModel is a victim cost model that charges a fixed cost per application
+type victimCostModel struct {
+	costs map[string]float64
+}
+
+func (m *victimCostModel) VictimCost(victim plugins.VictimCostInfo, _ plugins.VictimCostInfo, _ time.Time) float64 {
+	return m.costs[victim.GetApplicationID()]
+}
+
+func TestCompareVictimsLess(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	now := time.Now()
+	ask := newAllocationAskPriority("ask", appID1, res, 0)
+	p := &Preemptor{ask: ask, now: now, costWeights: defaultVictimCostWeights(), costs: make(map[string]float64)}
+
+	longRunning := newAllocationWithKey("long", appID2, nodeID1, res)
+	longRunning.SetBindTime(now.Add(-2 * time.Hour))
+	longRunning.createTime = now
+	started := newAllocationWithKey("started", appID2, nodeID1, res)
+	started.SetBindTime(now)
+	started.createTime = now.Add(-time.Hour)
+	assert.Assert(t, p.compareVictimsLess(started, longRunning), "new victim should be cheaper than long running victim")
+	assert.Assert(t, !p.compareVictimsLess(longRunning, started), "long running victim should be more expensive")
+
+	// same cost: newest first
+	newer := newAllocationWithKey("newer", appID2, nodeID1, res)
+	newer.SetBindTime(now)
+	newer.createTime = now
+	assert.Assert(t, p.compareVictimsLess(newer, started), "newest victim should be first on equal cost")
+
+	// opted in to preemption always first
+	self := newAllocationWithKey("self", appID2, nodeID1, res)
+	self.allowPreemptSelf = true
+	self.SetBindTime(now.Add(-24 * time.Hour))
+	assert.Assert(t, p.compareVictimsLess(self, newer), "victim allowing preemption should be first")
+	assert.Equal(t, p.costs["long"], float64(120+configs.DefaultPreemptionCostPriority), "cost not cached")
+
+	// originators are always last, even if another victim has run long enough to cost more
+	originator := newAllocationWithKey("originator", appID2, nodeID1, res)
+	originator.originator = true
+	originator.SetBindTime(now)
+	veteran := newAllocationWithKey("veteran", appID2, nodeID1, res)
+	veteran.SetBindTime(now.Add(-30 * 24 * time.Hour))
+	assert.Assert(t, p.victimCost(veteran) > p.victimCost(originator), "veteran should cost more than the originator")
+	assert.Assert(t, p.compareVictimsLess(veteran, originator), "non originator should be first")
+	assert.Assert(t, !p.compareVictimsLess(originator, veteran), "originator should be last")
+
+	// a registered cost model overrides the queue weights, negative costs are clamped
+	plugins.RegisterSchedulerPlugin(&victimCostModel{costs: map[string]float64{appID2: -10, appID3: 5}})
+	defer plugins.UnregisterSchedulerPlugins()
+	p.costs = make(map[string]float64)
+	other := newAllocationWithKey("other", appID3, nodeID1, res)
+	other.SetBindTime(now)
+	assert.Assert(t, p.compareVictimsLess(longRunning, other), "cost model not used")
+	assert.Equal(t, p.victimCost(longRunning), float64(0), "negative cost not clamped")
+	assert.Equal(t, p.victimCost(other), float64(5))
+}
+
+func TestCheckPreemptionPredicates_Cost(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	now := time.Now()
+	ask := newAllocationAskPriority("ask", appID1, res, 0)
+	weights := defaultVictimCostWeights()
+	weights.priority = 0
+	p := &Preemptor{ask: ask, now: now, costWeights: weights, costs: make(map[string]float64)}
+
+	// node1: a single victim running for two hours, node2: two victims just started
+	long := newAllocationWithKey("long", appID2, nodeID1, res)
+	long.SetBindTime(now.Add(-2 * time.Hour))
+	new1 := newAllocationWithKey("new1", appID2, nodeID2, res)
+	new1.SetBindTime(now.Add(-time.Minute))
+	new2 := newAllocationWithKey("new2", appID2, nodeID2, res)
+	new2.SetBindTime(now.Add(-time.Minute))
+	victimsByNode := map[string][]*Allocation{
+		nodeID1: {long},
+		nodeID2: {new1, new2},
+	}
+	p.allocationsByNode = victimsByNode
+	checks := []*si.PreemptionPredicatesArgs{
+		{AllocationKey: "ask", NodeID: nodeID1, PreemptAllocationKeys: []string{"long"}, StartIndex: 0},
+		{AllocationKey: "ask", NodeID: nodeID2, PreemptAllocationKeys: []string{"new1", "new2"}, StartIndex: 1},
+	}
+
+	// without a RM callback the cheapest check is used
+	result := p.checkPreemptionPredicates(checks, victimsByNode)
+	assert.Assert(t, result != nil, "no result")
+	assert.Equal(t, result.nodeID, nodeID2, "cheapest node not selected")
+	assert.Equal(t, len(result.victims), 2)
+
+	// all checks succeed: the result with the lowest cost wins
+	preemptions := []mock.Preemption{
+		mock.NewPreemption(true, "ask", nodeID1, []string{"long"}, 0, 0),
+		mock.NewPreemption(true, "ask", nodeID2, []string{"new1", "new2"}, 1, 1),
+	}
+	plugin := mock.NewPreemptionPredicatePlugin(nil, nil, preemptions)
+	plugins.RegisterSchedulerPlugin(plugin)
+	defer plugins.UnregisterSchedulerPlugins()
+	result = p.checkPreemptionPredicates(checks, victimsByNode)
+	assert.NilError(t, plugin.GetPredicateError())
+	assert.Assert(t, result != nil, "no result")
+	assert.Equal(t, result.nodeID, nodeID2, "cheapest node not selected")
+	assert.Equal(t, result.cost, float64(2))
+}
+
 // TestTryPreemptionOnNode Test try preemption on node with simple queue hierarchy. Since Node doesn't have enough resources to accomodate, preemption happens because of node resource constraint.
 // Guaranteed and Max resource set on both victim queue path and preemptor queue path in 2 levels. victim and preemptor queue are siblings.
 // Request (Preemptor) resource type matches with all resource types of the victim. But Guaranteed set only on specific resource type. 2 Victims are available, but 1 should be preempted because further preemption would make usage go below the guaranteed quota
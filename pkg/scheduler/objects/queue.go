@@ -22,6 +22,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -70,6 +71,24 @@ type Queue struct {
 	preemptionPolicy    policies.PreemptionPolicy // preemption policy
 	preemptionDelay     time.Duration             // time before preemption is considered
 	currentPriority     int32                     // the current scheduling priority of this queue
+	agingPeriod         time.Duration             // time a child queue must wait to get the full aging credit, 0 disables aging (parent queue only)
+	appAgingPeriod      time.Duration             // time an application must wait to get the full aging credit (leaf queue only)
+	drfWeight           float64                   // weight of the queue when the parent sorts its children using drf, defaults to the fair share weight
+	fairShareWeight     float64                   // weight of the queue when sharing spare capacity with its siblings, defaults to the drf weight
+	pendingSince        time.Time                 // start of the current wait for pending resources, zero if nothing is waiting
+	preemptionBudget    *preemptionBudget         // limits preemption of the allocations in the queue, nil if not limited (leaf queue only)
+	appQuota            *applicationQuota         // default quota of the applications in the queue, nil if not limited (leaf queue only)
+	victimCostWeights   victimCostWeights         // weights of the cost model used to select victims for asks in the queue
+	gangPreemption      bool                      // preempt the members of a task group of an application as one unit
+	crossNodeDepth      int                       // allocations that may be moved off a node to make room for an ask, 0 disables cross node preemption
+	preemptionHistory   *PreemptionHistory        // history of the preemption decisions in the hierarchy (root queue only)
+	advanceReservations *AdvanceReservations      // capacity booked for queues in the hierarchy (root queue only)
+	conf                configs.QueueConfig       // configuration of the queue without the changes of a schedule
+	schedules           []*queueSchedule          // schedules that change the configuration during a time window
+	activeSchedule      string                    // name of the schedule that is applied, empty if none
+	reclaimTimeout      time.Duration             // time the queue gets to give back borrowed guarantee before the lenders preempt without delay, 0 disables it
+	borrowLedger        *borrowLedger             // guarantee borrowed from and lent to the siblings of the queue
+	reclaimDue          bool                      // lent guarantee was not given back before the reclaim deadline
 
 	// The queue properties should be treated as immutable the value is a merge of the
 	// parent properties with the config for this queue only manipulated during creation
@@ -83,6 +102,8 @@ type Queue struct {
 	isManaged              bool                // queue is part of the config, not auto created
 	stateMachine           *fsm.FSM            // the state of the queue for scheduling
 	stateTime              time.Time           // last time the state was updated (needed for cleanup)
+	adminState             bool                // the state is set by an operator and not by the configuration
+	stateReason            string              // reason given by the operator for the last state change
 	maxRunningApps         uint64
 	runningApps            uint64
 	allocatingAcceptedApps map[string]bool
@@ -99,6 +120,9 @@ func newBlankQueue() *Queue {
 		childPriorities:        make(map[string]int32),
 		applications:           make(map[string]*Application),
 		appPriorities:          make(map[string]int32),
+		drfWeight:              1,
+		fairShareWeight:        1,
+		victimCostWeights:      defaultVictimCostWeights(),
 		reservedApps:           make(map[string]int),
 		allocatingAcceptedApps: make(map[string]bool),
 		properties:             make(map[string]string),
@@ -109,6 +133,7 @@ func newBlankQueue() *Queue {
 		currentPriority:        configs.MinPriority,
 		prioritySortEnabled:    true,
 		preemptionDelay:        configs.DefaultPreemptionDelay,
+		appAgingPeriod:         configs.DefaultApplicationAgingPeriod,
 		preemptionPolicy:       policies.DefaultPreemptionPolicy,
 	}
 }
@@ -137,7 +162,7 @@ func NewConfiguredQueue(conf configs.QueueConfig, parent *Queue, silence bool) (
 	// still need to make sure we lock the parent so we do not interfere with scheduling
 	if parent != nil {
 		// pull the properties from the parent that should be set on the child
-		sq.mergeProperties(parent.getProperties(), conf.Properties)
+		sq.mergeProperties(parent.getProperties(), sq.properties)
 		sq.UpdateQueueProperties()
 		err := parent.addChildQueue(sq)
 		if err != nil {
@@ -251,6 +276,10 @@ func (sq *Queue) mergeProperties(parent, config map[string]string) {
 	// Set the parent properties
 	if len(parent) != 0 {
 		for key, value := range parent {
+			// weights are relative to the siblings, a parent weight has no meaning for the children
+			if key == configs.DRFWeight || key == configs.FairShareWeight {
+				continue
+			}
 			sq.properties[key] = filterParentProperty(key, value)
 		}
 	}
@@ -273,6 +302,72 @@ func preemptionDelay(value string) (time.Duration, error) {
 	return result, nil
 }
 
+func applicationAgingPeriod(value string) (time.Duration, error) {
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		return configs.DefaultApplicationAgingPeriod, err
+	}
+	if int64(result) <= int64(0) {
+		return configs.DefaultApplicationAgingPeriod, fmt.Errorf("%s must be positive: %s", configs.ApplicationAgingPeriod, value)
+	}
+	return result, nil
+}
+
+// reclaimTimeout parses the reclaim deadline, the deadline must not be negative. Returns 0 on error.
+func reclaimTimeout(value string) (time.Duration, error) {
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if result < 0 {
+		return 0, fmt.Errorf("%s must not be negative: %s", configs.PreemptionReclaimDeadline, value)
+	}
+	return result, nil
+}
+
+// crossNodeDepth parses the cross node preemption depth, the depth must not be negative. Returns 0 on error.
+func crossNodeDepth(value string) (int, error) {
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if result < 0 {
+		return 0, fmt.Errorf("%s must not be negative: %s", configs.PreemptionCrossNodeDepth, value)
+	}
+	return result, nil
+}
+
+func drfWeight(value string) (float64, error) {
+	return queueWeight(configs.DRFWeight, value)
+}
+
+func fairShareWeight(value string) (float64, error) {
+	return queueWeight(configs.FairShareWeight, value)
+}
+
+// queueWeight parses a weight property, the weight must be a positive number. Returns 1 on error.
+func queueWeight(key, value string) (float64, error) {
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 1, err
+	}
+	if result <= 0 || math.IsInf(result, 0) || math.IsNaN(result) {
+		return 1, fmt.Errorf("%s must be a positive number: %s", key, value)
+	}
+	return result, nil
+}
+
+func queueAgingPeriod(value string) (time.Duration, error) {
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if int64(result) <= int64(0) {
+		return 0, fmt.Errorf("%s must be positive: %s", configs.QueueAgingPeriod, value)
+	}
+	return result, nil
+}
+
 func priorityOffset(value string) (int32, error) {
 	intValue, err := strconv.ParseInt(value, 10, 32)
 	if err != nil {
@@ -317,10 +412,25 @@ func (sq *Queue) ApplyConf(conf configs.QueueConfig) error {
 	return sq.applyConf(conf, false)
 }
 
-// applyConf applies all the properties to the queue from the config.
+// applyConf applies all the properties to the queue from the config. The changes of the schedule that is active
+// are applied on top of the config.
 // lock free call, must be called holding the queue lock or during create only.
 // If the silence flag is set to true, the function will not log when setting users and groups.
 func (sq *Queue) applyConf(conf configs.QueueConfig, silence bool) error {
+	schedules, err := newQueueSchedules(conf.Schedules)
+	if err != nil {
+		return err
+	}
+	sq.conf = conf
+	sq.schedules = schedules
+	active := sq.findSchedule(time.Now())
+	sq.setActiveSchedule(active)
+	return sq.applyEffectiveConf(scheduledConf(conf, active), silence)
+}
+
+// applyEffectiveConf applies the config with the changes of the active schedule already merged in.
+// lock free call, must be called holding the queue lock or during create only.
+func (sq *Queue) applyEffectiveConf(conf configs.QueueConfig, silence bool) error {
 	// Set the ACLs
 	var err error
 	sq.submitACL, err = security.NewACL(conf.SubmitACL, silence)
@@ -342,8 +452,8 @@ func (sq *Queue) applyConf(conf configs.QueueConfig, silence bool) error {
 		sq.isManaged = true
 	}
 
-	// if the queue is marked for removal reverse that state
-	if !sq.IsRunning() {
+	// if the queue is marked for removal reverse that state, a state set by an operator is kept
+	if !sq.IsRunning() && !sq.adminState {
 		err = sq.handleQueueEvent(Start)
 		if err != nil {
 			log.Log(log.SchedQueue).Info("managed queue state change failed",
@@ -496,7 +606,17 @@ func (sq *Queue) UpdateQueueProperties() {
 	if !sq.isLeaf {
 		// set the sorting type for parent queues
 		sq.sortType = policies.FairSortPolicy
-	}
+		// aging is off unless set
+		sq.agingPeriod = 0
+	}
+	sq.appAgingPeriod = configs.DefaultApplicationAgingPeriod
+	sq.victimCostWeights = defaultVictimCostWeights()
+	sq.gangPreemption = false
+	sq.crossNodeDepth = 0
+	sq.reclaimTimeout = 0
+	var budgetAllocations, budgetPercentage, budgetWindow string
+	var appMaxResource, appMaxAllocations, appMaxLifetime string
+	var drfValue, fairShareValue string
 	// walk over all properties and process
 	var err error
 	for key, value := range sq.properties {
@@ -512,6 +632,9 @@ func (sq *Queue) UpdateQueueProperties() {
 				if sq.sortType == policies.Undefined {
 					sq.sortType = policies.FifoSortPolicy
 				}
+			} else if value == policies.DrfSortPolicy.String() {
+				// parent queues only support drf as an alternative to fair for sorting the child queues
+				sq.sortType = policies.DrfSortPolicy
 			}
 		case configs.ApplicationSortPriority:
 			sq.prioritySortEnabled, err = applicationSortPriorityEnabled(value)
@@ -545,6 +668,63 @@ func (sq *Queue) UpdateQueueProperties() {
 						zap.Error(err))
 				}
 			}
+		case configs.DRFWeight:
+			drfValue = value
+		case configs.FairShareWeight:
+			fairShareValue = value
+		case configs.QueueAgingPeriod:
+			if !sq.isLeaf {
+				sq.agingPeriod, err = queueAgingPeriod(value)
+				if err != nil {
+					log.Log(log.SchedQueue).Debug("queue aging period property configuration error",
+						zap.Error(err))
+				}
+			}
+		case configs.ApplicationAgingPeriod:
+			if sq.isLeaf {
+				sq.appAgingPeriod, err = applicationAgingPeriod(value)
+				if err != nil {
+					log.Log(log.SchedQueue).Debug("application aging period property configuration error",
+						zap.Error(err))
+				}
+			}
+		case configs.PreemptionCostRuntime, configs.PreemptionCostPriority, configs.PreemptionCostOriginator, configs.PreemptionCostPlaceholder:
+			err = sq.victimCostWeights.set(key, value)
+			if err != nil {
+				log.Log(log.SchedQueue).Debug("preemption cost property configuration error",
+					zap.String("key", key),
+					zap.Error(err))
+			}
+		case configs.PreemptionGang:
+			sq.gangPreemption, err = strconv.ParseBool(value)
+			if err != nil {
+				log.Log(log.SchedQueue).Debug("gang preemption property configuration error",
+					zap.Error(err))
+			}
+		case configs.PreemptionCrossNodeDepth:
+			sq.crossNodeDepth, err = crossNodeDepth(value)
+			if err != nil {
+				log.Log(log.SchedQueue).Debug("cross node preemption property configuration error",
+					zap.Error(err))
+			}
+		case configs.PreemptionReclaimDeadline:
+			sq.reclaimTimeout, err = reclaimTimeout(value)
+			if err != nil {
+				log.Log(log.SchedQueue).Debug("reclaim deadline property configuration error",
+					zap.Error(err))
+			}
+		case configs.PreemptionBudgetAllocations:
+			budgetAllocations = value
+		case configs.PreemptionBudgetPercentage:
+			budgetPercentage = value
+		case configs.PreemptionBudgetWindow:
+			budgetWindow = value
+		case configs.ApplicationMaxResource:
+			appMaxResource = value
+		case configs.ApplicationMaxAllocations:
+			appMaxAllocations = value
+		case configs.ApplicationMaxLifetime:
+			appMaxLifetime = value
 		default:
 			// skip unknown properties just log them
 			log.Log(log.SchedQueue).Debug("queue property skipped",
@@ -552,6 +732,69 @@ func (sq *Queue) UpdateQueueProperties() {
 				zap.String("value", value))
 		}
 	}
+	sq.updateWeights(drfValue, fairShareValue)
+	sq.updatePreemptionBudget(budgetAllocations, budgetPercentage, budgetWindow)
+	sq.updateApplicationQuota(appMaxResource, appMaxAllocations, appMaxLifetime)
+}
+
+// updateWeights sets the drf and fair share weight of the queue. Both describe the share of the queue compared
+// to its siblings: a weight that is not set uses the other weight, a weight that is not set or not valid is 1.
+// Lock free call, must be called holding the queue lock.
+func (sq *Queue) updateWeights(drfValue, fairShareValue string) {
+	if drfValue == "" {
+		drfValue = fairShareValue
+	}
+	if fairShareValue == "" {
+		fairShareValue = drfValue
+	}
+	var err error
+	sq.drfWeight = 1
+	if drfValue != "" {
+		sq.drfWeight, err = drfWeight(drfValue)
+		if err != nil {
+			log.Log(log.SchedQueue).Debug("drf weight property configuration error",
+				zap.Error(err))
+		}
+	}
+	sq.fairShareWeight = 1
+	if fairShareValue != "" {
+		sq.fairShareWeight, err = fairShareWeight(fairShareValue)
+		if err != nil {
+			log.Log(log.SchedQueue).Debug("fair share weight property configuration error",
+				zap.Error(err))
+		}
+	}
+}
+
+// updatePreemptionBudget replaces the preemption budget of a leaf queue keeping the preemptions already tracked.
+// Lock free call, must be called holding the queue lock.
+func (sq *Queue) updatePreemptionBudget(allocations, percentage, window string) {
+	if !sq.isLeaf {
+		sq.preemptionBudget = nil
+		return
+	}
+	budget, err := newPreemptionBudget(allocations, percentage, window)
+	if err != nil {
+		log.Log(log.SchedQueue).Debug("preemption budget property configuration error",
+			zap.Error(err))
+	}
+	budget.keepHistory(sq.preemptionBudget)
+	sq.preemptionBudget = budget
+}
+
+// updateApplicationQuota replaces the default quota of the applications in a leaf queue.
+// Lock free call, must be called holding the queue lock.
+func (sq *Queue) updateApplicationQuota(maxResource, maxAllocations, maxLifetime string) {
+	if !sq.isLeaf {
+		sq.appQuota = nil
+		return
+	}
+	quota, err := newApplicationQuota(maxResource, maxAllocations, maxLifetime)
+	if err != nil {
+		log.Log(log.SchedQueue).Debug("application quota property configuration error",
+			zap.Error(err))
+	}
+	sq.appQuota = quota
 }
 
 // GetQueuePath returns the fully qualified path of this queue.
@@ -645,6 +888,97 @@ func (sq *Queue) GetPreemptionDelay() time.Duration {
 	return sq.preemptionDelay
 }
 
+// GetAgingPeriod returns the time a child queue must wait to get the full aging credit when the
+// children of this queue are sorted. Returns 0 if aging is not enabled.
+func (sq *Queue) GetAgingPeriod() time.Duration {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.agingPeriod
+}
+
+// GetApplicationAgingPeriod returns the time an application must wait to get the full aging credit when the
+// applications in this queue are sorted using the fair with aging policy.
+func (sq *Queue) GetApplicationAgingPeriod() time.Duration {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.appAgingPeriod
+}
+
+// GetDRFWeight returns the weight of the queue used when the parent queue sorts its children using drf.
+func (sq *Queue) GetDRFWeight() float64 {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.drfWeight
+}
+
+// GetFairShareWeight returns the weight of the queue used to share the spare capacity of the parent with its siblings.
+func (sq *Queue) GetFairShareWeight() float64 {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.fairShareWeight
+}
+
+// GetFairShare returns the weighted fair share of the queue. The fair share of the root queue is the partition
+// capacity. Each child gets its guaranteed resources, the remaining capacity of the parent's fair share is divided
+// between the siblings in proportion to their fair share weight. The fair share is limited by the max resource.
+// In case there are no nodes in a newly started cluster and no queues have a limit configured this call
+// will return nil.
+// Lock free call all locks are taken when needed in called functions
+func (sq *Queue) GetFairShare() *resources.Resource {
+	if sq.parent == nil {
+		return sq.GetMaxResource().Clone()
+	}
+	return sq.parent.getChildFairShares([]*Queue{sq})[sq.QueuePath]
+}
+
+// getChildFairShares returns the weighted fair share for each of the passed in children of this queue,
+// keyed on the queue path.
+// Lock free call all locks are taken when needed in called functions
+func (sq *Queue) getChildFairShares(children []*Queue) map[string]*resources.Resource {
+	parentShare := sq.GetFairShare()
+	totalWeight := float64(0)
+	var guaranteed *resources.Resource
+	for _, child := range sq.GetCopyOfChildren() {
+		totalWeight += child.GetFairShareWeight()
+		guaranteed = resources.Add(guaranteed, child.GetGuaranteedResource())
+	}
+	spare := resources.SubEliminateNegative(parentShare, guaranteed)
+	shares := make(map[string]*resources.Resource, len(children))
+	for _, child := range children {
+		share := resources.Add(child.GetGuaranteedResource(), resources.MultiplyBy(spare, child.GetFairShareWeight()/totalWeight))
+		shares[child.QueuePath] = resources.ComponentWiseMinOnlyExisting(share, child.GetMaxResource())
+	}
+	return shares
+}
+
+// GetWaitingTime returns how long the queue has been waiting for its pending resources to be
+// allocated, since it last received an allocation. Returns 0 if nothing is pending.
+func (sq *Queue) GetWaitingTime() time.Duration {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.getWaitingTime()
+}
+
+func (sq *Queue) getWaitingTime() time.Duration {
+	if sq.pendingSince.IsZero() {
+		return 0
+	}
+	return time.Since(sq.pendingSince)
+}
+
+// updateWaitingTime tracks the start of the wait for pending resources. A wait starts when resources become
+// pending, it ends when nothing is pending anymore. An allocation restarts the wait if resources are still pending.
+// NOTE: this is a lock free call. It must only be called holding the queue lock.
+func (sq *Queue) updateWaitingTime(allocated bool) {
+	if !resources.StrictlyGreaterThanZero(sq.pending) {
+		sq.pendingSince = time.Time{}
+		return
+	}
+	if allocated || sq.pendingSince.IsZero() {
+		sq.pendingSince = time.Now()
+	}
+}
+
 // CheckSubmitAccess checks if the user has access to the queue to submit an application.
 // The check is performed recursively: i.e. access to the parent allows access to this queue.
 // This will check both submitACL and adminACL.
@@ -687,6 +1021,7 @@ func (sq *Queue) GetPartitionQueueDAOInfo(include bool) dao.PartitionQueueDAOInf
 	}
 	// we have held the read lock so following method should not take lock again.
 	queueInfo.HeadRoom = sq.getHeadRoom().DAOMap()
+	queueInfo.FairShare = sq.GetFairShare().DAOMap()
 	sq.RLock()
 	defer sq.RUnlock()
 
@@ -695,6 +1030,7 @@ func (sq *Queue) GetPartitionQueueDAOInfo(include bool) dao.PartitionQueueDAOInf
 	}
 	queueInfo.QueueName = sq.QueuePath
 	queueInfo.Status = sq.stateMachine.Current()
+	queueInfo.StateReason = sq.stateReason
 	queueInfo.PendingResource = sq.pending.DAOMap()
 	queueInfo.MaxResource = sq.maxResource.DAOMap()
 	queueInfo.GuaranteedResource = sq.guaranteedResource.DAOMap()
@@ -712,6 +1048,15 @@ func (sq *Queue) GetPartitionQueueDAOInfo(include bool) dao.PartitionQueueDAOInf
 	queueInfo.PreemptionDelay = sq.preemptionDelay.String()
 	queueInfo.IsPriorityFence = sq.priorityPolicy == policies.FencePriorityPolicy
 	queueInfo.PriorityOffset = sq.priorityOffset
+	if sq.agingPeriod > 0 {
+		queueInfo.AgingPeriod = sq.agingPeriod.String()
+	}
+	if waiting := sq.getWaitingTime(); waiting > 0 {
+		queueInfo.WaitingTime = waiting.String()
+	}
+	queueInfo.PreemptionBudget = sq.preemptionBudget.getDAOInfo(time.Now(), sq.allocatedResource)
+	queueInfo.ActiveSchedule = sq.activeSchedule
+	queueInfo.BorrowLedger = sq.borrowLedger.getDAOInfo()
 	queueInfo.Properties = make(map[string]string)
 	for k, v := range sq.properties {
 		queueInfo.Properties[k] = v
@@ -750,6 +1095,7 @@ func (sq *Queue) incPendingResource(delta *resources.Resource) {
 	defer sq.Unlock()
 	sq.pending = resources.Add(sq.pending, delta)
 	sq.updatePendingResourceMetrics()
+	sq.updateWaitingTime(false)
 }
 
 // decPendingResource decrements pending resource of this queue and its parents.
@@ -777,6 +1123,7 @@ func (sq *Queue) decPendingResource(delta *resources.Resource) {
 		// If we prune the resource first and the resource become nil after pruning,
 		// the metrics will not be updated with nil resource, this is not expected.
 		sq.pending.Prune()
+		sq.updateWaitingTime(false)
 	}
 }
 
@@ -972,6 +1319,15 @@ func (sq *Queue) MarkQueueForRemoval() {
 func (sq *Queue) doRemoveQueue() {
 	sq.Lock()
 	defer sq.Unlock()
+	// the removal from the config overrides the state set by an operator: a stopped queue cannot be removed directly
+	sq.adminState = false
+	if sq.IsStopped() {
+		if err := sq.handleQueueEvent(Start); err != nil {
+			log.Log(log.SchedQueue).Warn("failed to start stopped queue for deletion",
+				zap.String("queue", sq.QueuePath),
+				zap.Error(err))
+		}
+	}
 	if err := sq.handleQueueEvent(Remove); err != nil {
 		log.Log(log.SchedQueue).Warn("failed to mark managed queue for deletion",
 			zap.String("queue", sq.QueuePath),
@@ -979,6 +1335,89 @@ func (sq *Queue) doRemoveQueue() {
 	}
 }
 
+// SetAdminState changes the state of the queue and of all queues below it on request of an operator:
+// - Stop: the queue is skipped for scheduling
+// - Remove: the queue drains, no new applications are placed in the queue
+// - Start: the queue is active again
+// The state set by an operator is kept when the configuration is updated, a drained queue is not removed when it is
+// empty. A queue that drains because it is removed from the configuration cannot be changed.
+// All queues in the subtree are checked before the state of any queue is changed.
+func (sq *Queue) SetAdminState(event ObjectEvent, reason string) error {
+	queues := sq.getSubtree()
+	for _, queue := range queues {
+		if err := queue.checkAdminState(event); err != nil {
+			return err
+		}
+	}
+	for _, queue := range queues {
+		if err := queue.setAdminState(event, reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSubtree returns the queue and all queues below it, parents before their children.
+func (sq *Queue) getSubtree() []*Queue {
+	queues := []*Queue{sq}
+	for _, child := range sq.GetCopyOfChildren() {
+		queues = append(queues, child.getSubtree()...)
+	}
+	return queues
+}
+
+// checkAdminState returns an error if the state of the queue cannot be changed by an operator.
+func (sq *Queue) checkAdminState(event ObjectEvent) error {
+	sq.RLock()
+	defer sq.RUnlock()
+	if sq.IsDraining() && !sq.adminState {
+		return fmt.Errorf("queue is removed from the configuration: %s", sq.QueuePath)
+	}
+	// a draining queue is started before it is stopped, a stopped queue before it drains
+	if (event == Stop && sq.IsDraining()) || (event == Remove && sq.IsStopped()) {
+		return nil
+	}
+	if !sq.stateMachine.Can(event.String()) {
+		return fmt.Errorf("queue state cannot change from %s using %s: %s", sq.CurrentState(), event.String(), sq.QueuePath)
+	}
+	return nil
+}
+
+func (sq *Queue) setAdminState(event ObjectEvent, reason string) error {
+	sq.Lock()
+	defer sq.Unlock()
+	if sq.IsDraining() && !sq.adminState {
+		return fmt.Errorf("queue is removed from the configuration: %s", sq.QueuePath)
+	}
+	// a draining queue cannot be stopped directly, a stopped queue cannot drain directly
+	if (event == Stop && sq.IsDraining()) || (event == Remove && sq.IsStopped()) {
+		if err := sq.handleQueueEvent(Start); err != nil {
+			return err
+		}
+	}
+	if err := sq.handleQueueEvent(event); err != nil {
+		return err
+	}
+	sq.adminState = event != Start
+	sq.stateReason = reason
+	log.Log(log.SchedQueue).Info("queue state changed by operator",
+		zap.String("queue", sq.QueuePath),
+		zap.String("event", event.String()),
+		zap.String("state", sq.CurrentState()),
+		zap.String("reason", reason))
+	if sq.queueEvents != nil {
+		sq.queueEvents.SendStateChangedEvent(sq.QueuePath, sq.CurrentState(), reason)
+	}
+	return nil
+}
+
+// IsAdminState returns true if the state of the queue is set by an operator.
+func (sq *Queue) IsAdminState() bool {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.adminState
+}
+
 // GetChildQueue returns a queue if the name exists in the child map as a key.
 func (sq *Queue) GetChildQueue(name string) *Queue {
 	sq.RLock()
@@ -1058,6 +1497,7 @@ func (sq *Queue) TryIncAllocatedResource(alloc *resources.Resource) error {
 	// all OK update this queue
 	sq.allocatedResource = resources.Add(sq.allocatedResource, alloc)
 	sq.updateAllocatedResourceMetrics()
+	sq.updateWaitingTime(true)
 	return nil
 }
 
@@ -1076,6 +1516,7 @@ func (sq *Queue) IncAllocatedResource(alloc *resources.Resource) {
 	defer sq.Unlock()
 	sq.allocatedResource = resources.Add(sq.allocatedResource, alloc)
 	sq.updateAllocatedResourceMetrics()
+	sq.updateWaitingTime(true)
 }
 
 // allocatedResFits adds the passed in resource to the allocatedResource of the queue and checks if it still fits in the
@@ -1084,6 +1525,12 @@ func (sq *Queue) IncAllocatedResource(alloc *resources.Resource) {
 func (sq *Queue) allocatedResFits(alloc *resources.Resource) bool {
 	sq.RLock()
 	defer sq.RUnlock()
+	return sq.allocatedResFitsInternal(alloc)
+}
+
+// allocatedResFitsInternal is the lock free version of allocatedResFits.
+// Lock free call, must be called holding the queue lock.
+func (sq *Queue) allocatedResFitsInternal(alloc *resources.Resource) bool {
 	// on the root we want to reject a new allocation if it asks for resources not registered
 	// so do not use the "undefined" flag, also handles pruned max for root
 	if sq.isRoot() {
@@ -1187,19 +1634,23 @@ func (sq *Queue) sortApplications(withPlaceholdersOnly bool) []*Application {
 	}
 
 	apps := sq.GetCopyOfApps()
-	if withPlaceholdersOnly {
-		for key, app := range apps {
-			if !app.HasPlaceholderAllocation() {
-				delete(apps, key)
-			}
+	for key, app := range apps {
+		// suspended applications and applications waiting on their dependencies are not scheduled
+		if app.IsSuspended() || app.IsWaitingOnDependencies() || (withPlaceholdersOnly && !app.HasPlaceholderAllocation()) {
+			delete(apps, key)
 		}
 	}
 	if len(apps) == 0 {
 		return nil
 	}
 
-	// sort applications based on the sorting policy
-	return sortApplications(apps, sq.getSortType(), sq.IsPrioritySortEnabled(), sq.GetGuaranteedResource())
+	// sort applications based on the sorting policy: drf uses the partition capacity
+	sortType := sq.getSortType()
+	globalResource := sq.GetGuaranteedResource()
+	if sortType == policies.DrfSortPolicy {
+		globalResource = sq.getPartitionCapacity()
+	}
+	return sortApplications(apps, sortType, sq.IsPrioritySortEnabled(), globalResource, sq.GetApplicationAgingPeriod())
 }
 
 // sortQueues returns a sorted shallow copy of the queues for this parent queue.
@@ -1213,7 +1664,8 @@ func (sq *Queue) sortQueues() []*Queue {
 	// Create a list of the queues with pending resources
 	sortedQueues := make([]*Queue, 0)
 	sortedMaxFairResources := make([]*resources.Resource, 0)
-	for _, child := range sq.GetCopyOfChildren() {
+	children := sq.GetCopyOfChildren()
+	for _, child := range children {
 		// a stopped queue cannot be scheduled
 		if child.IsStopped() {
 			continue
@@ -1224,8 +1676,23 @@ func (sq *Queue) sortQueues() []*Queue {
 			sortedMaxFairResources = append(sortedMaxFairResources, child.GetFairMaxResource())
 		}
 	}
-	// Sort the queues
-	sortQueue(sortedQueues, sortedMaxFairResources, sq.getSortType(), sq.IsPrioritySortEnabled())
+	sortType := sq.getSortType()
+	// weighted siblings use the weighted fair share instead of the fair max resource. The weighted fair share
+	// includes the guarantee of the queue and replaces it: the weight must also apply to guaranteed resource types
+	var sortedGuaranteed []*resources.Resource
+	if sortType == policies.FairSortPolicy && isWeighted(children) {
+		shares := sq.getChildFairShares(sortedQueues)
+		sortedGuaranteed = make([]*resources.Resource, len(sortedQueues))
+		for i, child := range sortedQueues {
+			sortedMaxFairResources[i] = shares[child.QueuePath]
+		}
+	}
+	// Sort the queues: drf uses the partition capacity
+	var capacity *resources.Resource
+	if sortType == policies.DrfSortPolicy {
+		capacity = sq.getPartitionCapacity()
+	}
+	sortQueue(sortedQueues, sortedMaxFairResources, sortedGuaranteed, sortType, sq.IsPrioritySortEnabled(), sq.GetAgingPeriod(), capacity)
 
 	return sortedQueues
 }
@@ -1295,6 +1762,25 @@ func (sq *Queue) GetMaxResource() *resources.Resource {
 	return sq.internalGetMax(limit)
 }
 
+// isWeighted returns true if any of the queues has a fair share weight set.
+func isWeighted(queues map[string]*Queue) bool {
+	for _, queue := range queues {
+		if queue.GetFairShareWeight() != 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// getPartitionCapacity returns the total resources of the partition: the maximum resource of the root queue.
+// In case there are no nodes in a newly started cluster this call will return nil.
+func (sq *Queue) getPartitionCapacity() *resources.Resource {
+	if sq.parent != nil {
+		return sq.parent.getPartitionCapacity()
+	}
+	return sq.GetMaxResource()
+}
+
 // GetFairMaxResource computes the fair max resources for a given queue.
 // Starting with the root, descend down to the target queue allowing children to override Resource values .
 // If the root includes an explicit 0 value for a Resource, do not include it in the accumulator and treat it as missing.
@@ -1431,7 +1917,7 @@ func (sq *Queue) TryAllocate(iterator func() NodeIterator, fullIterator func() N
 	if sq.IsLeafQueue() {
 		// get the headroom
 		headRoom := sq.getHeadRoom()
-		preemptionDelay := sq.GetPreemptionDelay()
+		preemptionDelay := sq.getAskPreemptionDelay()
 		preemptAttemptsRemaining := maxPreemptionsPerQueue
 
 		// process the apps (filters out app without pending requests)
@@ -1546,7 +2032,7 @@ func (sq *Queue) TryReservedAllocate(iterator func() NodeIterator) *AllocationRe
 						zap.String("appID", appID))
 					return nil
 				}
-				if app.IsAccepted() && (!sq.canRunApp(appID) || !ugm.GetUserManager().CanRunApp(sq.QueuePath, appID, app.user)) {
+				if app.IsSuspended() || app.IsAccepted() && (!sq.canRunApp(appID) || !ugm.GetUserManager().CanRunApp(sq.QueuePath, appID, app.user)) {
 					continue
 				}
 				result := app.tryReservedAllocate(headRoom, iterator)
@@ -1787,6 +2273,130 @@ func (sq *Queue) setAllocatingAccepted(appID string) {
 	sq.allocatingAcceptedApps[appID] = true
 }
 
+// getVictimCostWeights returns the weights of the cost model used to select preemption victims.
+func (sq *Queue) getVictimCostWeights() victimCostWeights {
+	if sq == nil {
+		return defaultVictimCostWeights()
+	}
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.victimCostWeights
+}
+
+// SetPreemptionHistory sets the history that keeps the preemption decisions for the queue hierarchy.
+// Only the root queue keeps the history.
+func (sq *Queue) SetPreemptionHistory(history *PreemptionHistory) {
+	if sq.parent != nil {
+		return
+	}
+	sq.Lock()
+	defer sq.Unlock()
+	sq.preemptionHistory = history
+}
+
+// SetAdvanceReservations sets the advance reservations that book capacity for queues in the hierarchy.
+// Only the root queue keeps the reservations.
+func (sq *Queue) SetAdvanceReservations(reservations *AdvanceReservations) {
+	if sq.parent != nil {
+		return
+	}
+	sq.Lock()
+	defer sq.Unlock()
+	sq.advanceReservations = reservations
+}
+
+// fitsAdvanceReservations returns true if the resource can be placed on the node for an allocation in this queue
+// without using capacity booked for other queues. A zero expected end means the runtime is not known.
+func (sq *Queue) fitsAdvanceReservations(node *Node, res *resources.Resource, expectedEnd time.Time) bool {
+	if sq == nil {
+		return true
+	}
+	root := sq
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.RLock()
+	reservations := root.advanceReservations
+	root.RUnlock()
+	return reservations.fits(root, sq.QueuePath, node, res, expectedEnd, time.Now())
+}
+
+// recordPreemptionDecision adds the preemption decision to the history kept by the root queue.
+func (sq *Queue) recordPreemptionDecision(record *PreemptionRecord) {
+	if sq == nil {
+		return
+	}
+	if sq.parent != nil {
+		sq.parent.recordPreemptionDecision(record)
+		return
+	}
+	sq.RLock()
+	history := sq.preemptionHistory
+	sq.RUnlock()
+	history.Add(record)
+}
+
+// isGangPreemption returns true if the members of a task group in the queue must be preempted as one unit.
+func (sq *Queue) isGangPreemption() bool {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.gangPreemption
+}
+
+// getCrossNodeDepth returns the maximum number of allocations that may be moved off a node to make room for an ask
+// in the queue. Cross node preemption is disabled if 0 is returned.
+func (sq *Queue) getCrossNodeDepth() int {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.crossNodeDepth
+}
+
+// getPreemptionBudget returns the preemption budget of the queue, nil if preemption is not limited.
+func (sq *Queue) getPreemptionBudget() *preemptionBudget {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.preemptionBudget
+}
+
+// recordPreemption registers the preempted allocation against the preemption budget of the queue and of the
+// application the allocation belongs to. A queue event is sent when a budget is exhausted.
+func (sq *Queue) recordPreemption(victim *Allocation) {
+	app := sq.GetApplication(victim.GetApplicationID())
+	if app == nil {
+		sq.recordAppPreemption(victim, nil, nil)
+		return
+	}
+	sq.recordAppPreemption(victim, app, app.GetAllocatedResource())
+}
+
+// recordAppPreemption registers the preempted allocation against the preemption budget of the queue and of the
+// given application using the allocated resources passed in. The application budget is skipped if app is nil.
+// Lock free call: does not lock the application, the caller may hold the application lock.
+func (sq *Queue) recordAppPreemption(victim *Allocation, app *Application, appAllocated *resources.Resource) {
+	now := time.Now()
+	preempted := victim.GetAllocatedResource()
+	budget := sq.getPreemptionBudget()
+	if count, exhausted := budget.record(now, preempted, sq.GetAllocatedResource()); exhausted {
+		log.Log(log.SchedPreemption).Info("queue preemption budget exhausted",
+			zap.String("queuePath", sq.QueuePath),
+			zap.Int("preempted", count),
+			zap.Duration("window", budget.window))
+		sq.queueEvents.SendPreemptionBudgetExhaustedEvent(sq.QueuePath, common.Empty, count, budget.window)
+	}
+	if app == nil {
+		return
+	}
+	budget = app.preemptionBudget
+	if count, exhausted := budget.record(now, preempted, appAllocated); exhausted {
+		log.Log(log.SchedPreemption).Info("application preemption budget exhausted",
+			zap.String("queuePath", sq.QueuePath),
+			zap.String("appID", app.ApplicationID),
+			zap.Int("preempted", count),
+			zap.Duration("window", budget.window))
+		sq.queueEvents.SendPreemptionBudgetExhaustedEvent(sq.QueuePath, app.ApplicationID, count, budget.window)
+	}
+}
+
 func (sq *Queue) GetPreemptionPolicy() policies.PreemptionPolicy {
 	sq.RLock()
 	defer sq.RUnlock()
@@ -1880,19 +2490,44 @@ func (sq *Queue) findEligiblePreemptionVictims(results map[string]*QueuePreempti
 			return
 		}
 
+		// skip this queue if the preemption budget is exhausted
+		now := time.Now()
+		victims.budget = sq.getPreemptionBudget().headroom(now, sq.GetAllocatedResource())
+		if victims.budget.exhausted() {
+			return
+		}
+
+		// an allocation is eligible if it can be preempted for the ask
+		eligible := func(alloc *Allocation) bool {
+			// at least any one of the ask resource type should match with potential victim
+			if !ask.GetAllocatedResource().MatchAny(alloc.GetAllocatedResource()) {
+				return false
+			}
+			// skip tasks which require a specific node
+			if alloc.GetRequiredNode() != "" {
+				return false
+			}
+			// if we have encountered a fence then all tasks are eligible for preemption
+			// otherwise the task is a candidate if its priority is less than or equal to the ask priority
+			return fenced || int64(alloc.GetPriority()) <= askPriority
+		}
+
 		// walk allocations and select those that are equal or lower than current priority
+		gang := sq.isGangPreemption()
 		for _, app := range sq.GetCopyOfApps() {
-			for _, alloc := range app.GetAllAllocations() {
-				// at least any one of the ask resource type should match with potential victim
-				if !ask.GetAllocatedResource().MatchAny(alloc.GetAllocatedResource()) {
-					continue
-				}
-
-				// skip tasks which require a specific node
-				if alloc.GetRequiredNode() != "" {
-					continue
+			// skip this application if the preemption budget is exhausted
+			appBudget := app.preemptionBudget.headroom(now, app.GetAllocatedResource())
+			if appBudget.exhausted() {
+				continue
+			}
+			if appBudget != nil {
+				if victims.appBudgets == nil {
+					victims.appBudgets = make(map[string]*budgetHeadroom)
 				}
-
+				victims.appBudgets[app.ApplicationID] = appBudget
+			}
+			taskGroups := make(map[string][]*Allocation)
+			for _, alloc := range app.GetAllAllocations() {
 				// skip placeholder tasks which are marked released
 				if alloc.IsReleased() {
 					continue
@@ -1903,12 +2538,35 @@ func (sq *Queue) findEligiblePreemptionVictims(results map[string]*QueuePreempti
 					continue
 				}
 
-				// if we have encountered a fence then all tasks are eligible for preemption
-				// otherwise the task is a candidate if its priority is less than or equal to the ask priority
-				if fenced || int64(alloc.GetPriority()) <= askPriority {
+				// collect the task group members, they are evaluated as one unit
+				if gang && alloc.GetTaskGroup() != "" {
+					taskGroups[alloc.GetTaskGroup()] = append(taskGroups[alloc.GetTaskGroup()], alloc)
+					continue
+				}
+
+				// skip allocations that do not fit in the preemption budget left
+				if eligible(alloc) && newBudgetTracker().fits([]*Allocation{alloc}, victims.budget, appBudget) {
 					victims.PotentialVictims = append(victims.PotentialVictims, alloc)
 				}
 			}
+
+			// a task group is only eligible if all its members are eligible and fit in the budget together
+			for _, members := range taskGroups {
+				if !newBudgetTracker().fits(members, victims.budget, appBudget) {
+					continue
+				}
+				allEligible := true
+				for _, alloc := range members {
+					if !eligible(alloc) {
+						allEligible = false
+						break
+					}
+				}
+				if allEligible {
+					victims.PotentialVictims = append(victims.PotentialVictims, members...)
+					victims.gangs = append(victims.gangs, members)
+				}
+			}
 		}
 
 		// remove from potential victim list if there are no potential victims
@@ -0,0 +1,224 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/log"
+	"github.com/apache/yunikorn-core/pkg/webservice/dao"
+)
+
+// borrowLedger records the guarantee a queue has borrowed from and lent to its siblings. A queue borrows when it uses
+// more than its guarantee, the usage above the guarantee is taken from the unused guarantee of its siblings.
+type borrowLedger struct {
+	borrowed        map[string]*resources.Resource // guarantee borrowed from each lending sibling by queue path
+	lent            map[string]*resources.Resource // guarantee lent to each borrowing sibling by queue path
+	reclaim         *resources.Resource            // borrowed guarantee the lenders need back, nil if none
+	reclaimDeadline time.Time                      // time after which the lenders reclaim the borrowed guarantee, zero if not set
+}
+
+func newBorrowLedger() *borrowLedger {
+	return &borrowLedger{
+		borrowed: make(map[string]*resources.Resource),
+		lent:     make(map[string]*resources.Resource),
+	}
+}
+
+// isEmpty returns true if the queue has not borrowed or lent any guarantee.
+func (bl *borrowLedger) isEmpty() bool {
+	return bl == nil || (len(bl.borrowed) == 0 && len(bl.lent) == 0)
+}
+
+func (bl *borrowLedger) getDAOInfo() *dao.BorrowLedgerDAOInfo {
+	if bl.isEmpty() {
+		return nil
+	}
+	info := &dao.BorrowLedgerDAOInfo{}
+	if len(bl.borrowed) != 0 {
+		info.Borrowed = make(map[string]map[string]int64, len(bl.borrowed))
+		for queuePath, res := range bl.borrowed {
+			info.Borrowed[queuePath] = res.DAOMap()
+		}
+	}
+	if len(bl.lent) != 0 {
+		info.Lent = make(map[string]map[string]int64, len(bl.lent))
+		for queuePath, res := range bl.lent {
+			info.Lent[queuePath] = res.DAOMap()
+		}
+	}
+	if bl.reclaim != nil {
+		info.Reclaim = bl.reclaim.DAOMap()
+	}
+	if !bl.reclaimDeadline.IsZero() {
+		info.ReclaimDeadline = bl.reclaimDeadline.UnixNano()
+	}
+	return info
+}
+
+// sharedResource returns the smallest quantity of each resource type that is set in both resources.
+// Returns nil if none of the quantities is positive.
+func sharedResource(left, right *resources.Resource) *resources.Resource {
+	if left == nil || right == nil {
+		return nil
+	}
+	shared := resources.NewResource()
+	for name, quantity := range left.Resources {
+		if other, ok := right.Resources[name]; ok && min(quantity, other) > 0 {
+			shared.Resources[name] = min(quantity, other)
+		}
+	}
+	if len(shared.Resources) == 0 {
+		return nil
+	}
+	return shared
+}
+
+// UpdateBorrowing rebuilds the borrow ledgers of the children of the queue and of all queues below them.
+// Borrowers get a reclaim deadline when a lender has pending resources that need the lent guarantee. When the deadline
+// of a borrower has passed the lender is marked as reclaim due: the pending asks of the lender, and of the queues below
+// it, preempt without waiting for the preemption delay.
+func (sq *Queue) UpdateBorrowing(now time.Time) {
+	children := make([]*Queue, 0)
+	for _, child := range sq.GetCopyOfChildren() {
+		children = append(children, child)
+	}
+	if len(children) == 0 {
+		return
+	}
+	// a stable order keeps the ledger the same between runs if nothing changes
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].QueuePath < children[j].QueuePath
+	})
+	borrowing := make([]*resources.Resource, len(children))
+	unused := make([]*resources.Resource, len(children))
+	ledgers := make([]*borrowLedger, len(children))
+	for i, child := range children {
+		guaranteed := child.GetGuaranteedResource()
+		// allocations that are being preempted are already given back
+		used := resources.SubEliminateNegative(child.GetAllocatedResource(), child.GetPreemptingResource())
+		borrowing[i] = resources.SubEliminateNegative(used, guaranteed)
+		unused[i] = resources.SubEliminateNegative(guaranteed, used)
+		ledgers[i] = newBorrowLedger()
+	}
+	for i := range children {
+		for j := range children {
+			if i == j {
+				continue
+			}
+			amount := sharedResource(borrowing[i], unused[j])
+			if amount == nil {
+				continue
+			}
+			borrowing[i].SubFrom(amount)
+			unused[j].SubFrom(amount)
+			ledgers[i].borrowed[children[j].QueuePath] = amount
+			ledgers[j].lent[children[i].QueuePath] = amount
+		}
+	}
+	// a lender with pending resources needs the lent guarantee back
+	reclaimFrom := make([][]int, len(children))
+	for j, lender := range children {
+		need := lender.GetPendingResource()
+		for i, borrower := range children {
+			amount := sharedResource(ledgers[j].lent[borrower.QueuePath], need)
+			if amount == nil {
+				continue
+			}
+			need = resources.SubEliminateNegative(need, amount)
+			ledgers[i].reclaim = resources.Add(ledgers[i].reclaim, amount)
+			reclaimFrom[j] = append(reclaimFrom[j], i)
+		}
+	}
+	expired := make([]bool, len(children))
+	for i, child := range children {
+		expired[i] = child.setBorrowLedger(ledgers[i], now)
+	}
+	for j, lender := range children {
+		due := false
+		for _, i := range reclaimFrom[j] {
+			due = due || expired[i]
+		}
+		lender.setReclaimDue(due)
+		if !lender.IsLeafQueue() {
+			lender.UpdateBorrowing(now)
+		}
+	}
+}
+
+// setBorrowLedger replaces the borrow ledger of the queue. A reclaim deadline that is set stays unchanged while the
+// lenders need the guarantee back. Returns true if the reclaim deadline has passed.
+func (sq *Queue) setBorrowLedger(ledger *borrowLedger, now time.Time) bool {
+	sq.Lock()
+	defer sq.Unlock()
+	if ledger.reclaim != nil && sq.reclaimTimeout > 0 {
+		if sq.borrowLedger != nil && !sq.borrowLedger.reclaimDeadline.IsZero() {
+			ledger.reclaimDeadline = sq.borrowLedger.reclaimDeadline
+		} else {
+			ledger.reclaimDeadline = now.Add(sq.reclaimTimeout)
+			log.Log(log.SchedPreemption).Info("borrowed guarantee needed by lender, reclaim deadline set",
+				zap.String("queuePath", sq.QueuePath),
+				zap.Stringer("reclaim", ledger.reclaim),
+				zap.Time("deadline", ledger.reclaimDeadline))
+		}
+	}
+	if ledger.isEmpty() {
+		ledger = nil
+	}
+	sq.borrowLedger = ledger
+	return ledger != nil && !ledger.reclaimDeadline.IsZero() && !now.Before(ledger.reclaimDeadline)
+}
+
+// setReclaimDue marks the queue as needing lent guarantee back that was not given back before the reclaim deadline.
+func (sq *Queue) setReclaimDue(due bool) {
+	sq.Lock()
+	defer sq.Unlock()
+	if due && !sq.reclaimDue {
+		log.Log(log.SchedPreemption).Info("lent guarantee not given back before the reclaim deadline, preempting without delay",
+			zap.String("queuePath", sq.QueuePath))
+	}
+	sq.reclaimDue = due
+}
+
+// isReclaimDue returns true if the queue, or a queue above it, needs lent guarantee back that was not given back
+// before the reclaim deadline.
+func (sq *Queue) isReclaimDue() bool {
+	for queue := sq; queue != nil; queue = queue.parent {
+		queue.RLock()
+		due := queue.reclaimDue
+		queue.RUnlock()
+		if due {
+			return true
+		}
+	}
+	return false
+}
+
+// getAskPreemptionDelay returns the time an ask in the queue must wait before it triggers preemption. Asks do not
+// wait when the reclaim of lent guarantee is due.
+func (sq *Queue) getAskPreemptionDelay() time.Duration {
+	if sq.isReclaimDue() {
+		return 0
+	}
+	return sq.GetPreemptionDelay()
+}
@@ -0,0 +1,144 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestSharedResource(t *testing.T) {
+	left := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5, "second": 2})
+	right := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 3, "third": 1})
+	assert.Assert(t, sharedResource(nil, right) == nil, "nil resource should not share")
+	assert.Assert(t, sharedResource(left, nil) == nil, "nil resource should not share")
+	assert.Assert(t, resources.DeepEquals(sharedResource(left, right), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 3})), "unexpected shared resource")
+	zero := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 0, "second": 2})
+	assert.Assert(t, sharedResource(zero, right) == nil, "zero quantity should not be shared")
+}
+
+// createBorrowingTest creates two sibling queues with a guarantee of 5 each. The borrower runs 8 allocations of
+// size 1 with increasing priority, the lender runs 1 allocation.
+func createBorrowingTest(t *testing.T, reclaimDeadline string) (*Queue, *Queue, *Queue, []*Allocation) {
+	root, err := createRootQueue(map[string]string{"first": "20"})
+	assert.NilError(t, err, "failed to create root queue")
+	guaranteed := map[string]string{"first": "5"}
+	var props map[string]string
+	if reclaimDeadline != "" {
+		props = map[string]string{configs.PreemptionReclaimDeadline: reclaimDeadline}
+	}
+	borrower, err := createManagedQueuePropsMaxApps(root, "borrower", false, nil, guaranteed, props, 0)
+	assert.NilError(t, err, "failed to create queue")
+	lender, err := createManagedQueueGuaranteed(root, "lender", false, nil, guaranteed)
+	assert.NilError(t, err, "failed to create queue")
+
+	app := newApplication(appID1, "default", borrower.QueuePath)
+	app.SetQueue(borrower)
+	borrower.applications[appID1] = app
+	allocs := make([]*Allocation, 0)
+	one := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	for i := 0; i < 8; i++ {
+		alloc := newAllocationAll(fmt.Sprintf("alloc-%d", i), appID1, nodeID1, "", one, false, int32(i))
+		app.AddAllocation(alloc)
+		assert.NilError(t, borrower.TryIncAllocatedResource(one))
+		allocs = append(allocs, alloc)
+	}
+	assert.NilError(t, lender.TryIncAllocatedResource(one))
+	return root, borrower, lender, allocs
+}
+
+func TestUpdateBorrowing(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	root, borrower, lender, _ := createBorrowingTest(t, "")
+	root.UpdateBorrowing(time.Now())
+	three := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 3})
+	assert.Assert(t, borrower.borrowLedger != nil, "borrower should have a ledger")
+	assert.Equal(t, len(borrower.borrowLedger.borrowed), 1)
+	assert.Assert(t, resources.Equals(borrower.borrowLedger.borrowed["root.lender"], three), "unexpected borrowed guarantee")
+	assert.Equal(t, len(borrower.borrowLedger.lent), 0)
+	assert.Assert(t, lender.borrowLedger != nil, "lender should have a ledger")
+	assert.Assert(t, resources.Equals(lender.borrowLedger.lent["root.borrower"], three), "unexpected lent guarantee")
+	assert.Assert(t, borrower.borrowLedger.reclaim == nil, "lender does not need guarantee back")
+
+	info := borrower.GetPartitionQueueDAOInfo(false).BorrowLedger
+	assert.Assert(t, info != nil, "ledger should be shown")
+	assert.DeepEqual(t, info.Borrowed, map[string]map[string]int64{"root.lender": {"first": 3}})
+	assert.Assert(t, info.Lent == nil, "borrower has not lent guarantee")
+	assert.Equal(t, info.ReclaimDeadline, int64(0))
+	info = lender.GetPartitionQueueDAOInfo(false).BorrowLedger
+	assert.DeepEqual(t, info.Lent, map[string]map[string]int64{"root.borrower": {"first": 3}})
+
+	// the lender needs guarantee back: without a reclaim deadline nothing is preempted
+	lender.incPendingResource(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2}))
+	root.UpdateBorrowing(time.Now())
+	assert.Assert(t, resources.Equals(borrower.borrowLedger.reclaim, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2})), "unexpected reclaim")
+	assert.Assert(t, borrower.borrowLedger.reclaimDeadline.IsZero(), "reclaim deadline should not be set")
+
+	// guarantee that is given back clears the ledger
+	assert.NilError(t, borrower.DecAllocatedResource(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 3})))
+	root.UpdateBorrowing(time.Now())
+	assert.Assert(t, borrower.borrowLedger == nil, "borrower ledger should be cleared")
+	assert.Assert(t, lender.borrowLedger == nil, "lender ledger should be cleared")
+	assert.Assert(t, borrower.GetPartitionQueueDAOInfo(false).BorrowLedger == nil, "empty ledger should not be shown")
+}
+
+func TestReclaimBorrowed(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	root, borrower, lender, allocs := createBorrowingTest(t, "1m")
+	lender.incPendingResource(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 2}))
+	now := time.Now()
+	root.UpdateBorrowing(now)
+	deadline := now.Add(time.Minute)
+	assert.Equal(t, borrower.borrowLedger.reclaimDeadline, deadline)
+	assert.Equal(t, borrower.GetPartitionQueueDAOInfo(false).BorrowLedger.ReclaimDeadline, deadline.UnixNano())
+
+	// the deadline does not move while the guarantee is needed
+	root.UpdateBorrowing(now.Add(30 * time.Second))
+	assert.Equal(t, borrower.borrowLedger.reclaimDeadline, deadline)
+	for _, alloc := range allocs {
+		assert.Assert(t, !alloc.IsPreempted(), "allocation preempted before the deadline")
+	}
+	assert.Assert(t, !lender.isReclaimDue(), "reclaim not due before the deadline")
+
+	// at the deadline the lender preempts without delay, the borrower is not preempted directly
+	lender.preemptionDelay = time.Minute
+	root.UpdateBorrowing(deadline)
+	for _, alloc := range allocs {
+		assert.Assert(t, !alloc.IsPreempted(), "allocation preempted outside of the preemptor")
+	}
+	assert.Assert(t, lender.isReclaimDue(), "reclaim should be due after the deadline")
+	assert.Equal(t, lender.getAskPreemptionDelay(), time.Duration(0))
+	assert.Assert(t, !borrower.isReclaimDue(), "borrower does not reclaim guarantee")
+	assert.Equal(t, borrower.borrowLedger.reclaimDeadline, deadline)
+
+	// guarantee that is given back clears the reclaim
+	assert.NilError(t, borrower.DecAllocatedResource(resources.NewResourceFromMap(map[string]resources.Quantity{"first": 3})))
+	root.UpdateBorrowing(deadline.Add(time.Second))
+	assert.Assert(t, borrower.borrowLedger == nil, "borrower ledger should be cleared")
+	assert.Assert(t, !lender.isReclaimDue(), "reclaim should not be due")
+	assert.Equal(t, lender.getAskPreemptionDelay(), time.Minute)
+}
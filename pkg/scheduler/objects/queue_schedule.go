@@ -0,0 +1,171 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"maps"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+// queueSchedule changes the resources and properties of a queue during a time window that starts each time the
+// cron expression matches.
+type queueSchedule struct {
+	name     string
+	start    *configs.CronSchedule
+	duration time.Duration
+	conf     configs.QueueSchedule
+}
+
+// newQueueSchedules creates the schedules from the configuration, keeping the configured order.
+func newQueueSchedules(confs []configs.QueueSchedule) ([]*queueSchedule, error) {
+	if len(confs) == 0 {
+		return nil, nil
+	}
+	schedules := make([]*queueSchedule, 0, len(confs))
+	for _, conf := range confs {
+		start, duration, err := configs.GetQueueScheduleWindow(conf)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, &queueSchedule{
+			name:     conf.Name,
+			start:    start,
+			duration: duration,
+			conf:     conf,
+		})
+	}
+	return schedules, nil
+}
+
+// isActive returns true if a window of the schedule is open at the given time.
+func (qs *queueSchedule) isActive(now time.Time) bool {
+	return !qs.start.LastStart(now, qs.duration).IsZero()
+}
+
+// scheduledConf returns the queue config with the changes of the schedule applied. The resources set in the schedule
+// replace the configured resources, the properties are merged with the configured properties.
+// The passed in config is not changed.
+func scheduledConf(conf configs.QueueConfig, schedule *queueSchedule) configs.QueueConfig {
+	if schedule == nil {
+		return conf
+	}
+	if len(schedule.conf.Resources.Max) != 0 {
+		conf.Resources.Max = schedule.conf.Resources.Max
+	}
+	if len(schedule.conf.Resources.Guaranteed) != 0 {
+		conf.Resources.Guaranteed = schedule.conf.Resources.Guaranteed
+	}
+	if len(schedule.conf.Properties) != 0 {
+		properties := maps.Clone(conf.Properties)
+		if properties == nil {
+			properties = make(map[string]string)
+		}
+		maps.Copy(properties, schedule.conf.Properties)
+		conf.Properties = properties
+	}
+	return conf
+}
+
+// findSchedule returns the first schedule with an open window at the given time, nil if no window is open.
+// lock free call, must be called holding the queue lock or during create only.
+func (sq *Queue) findSchedule(now time.Time) *queueSchedule {
+	for _, schedule := range sq.schedules {
+		if schedule.isActive(now) {
+			return schedule
+		}
+	}
+	return nil
+}
+
+// setActiveSchedule records the schedule that is applied and sends an event for each schedule that ends or starts.
+// lock free call, must be called holding the queue lock or during create only.
+func (sq *Queue) setActiveSchedule(schedule *queueSchedule) {
+	name := ""
+	if schedule != nil {
+		name = schedule.name
+	}
+	if name == sq.activeSchedule {
+		return
+	}
+	log.Log(log.SchedQueue).Info("queue schedule changed",
+		zap.String("queue", sq.QueuePath),
+		zap.String("previous", sq.activeSchedule),
+		zap.String("current", name))
+	if sq.queueEvents != nil {
+		if sq.activeSchedule != "" {
+			sq.queueEvents.SendScheduleChangedEvent(sq.QueuePath, sq.activeSchedule, false)
+		}
+		if name != "" {
+			sq.queueEvents.SendScheduleChangedEvent(sq.QueuePath, name, true)
+		}
+	}
+	sq.activeSchedule = name
+}
+
+// GetActiveSchedule returns the name of the schedule that is applied to the queue, empty if none.
+func (sq *Queue) GetActiveSchedule() string {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.activeSchedule
+}
+
+// UpdateSchedules applies the schedule that is active at the given time to the queue and all queues below it.
+// The queue is only changed when a window starts or ends.
+func (sq *Queue) UpdateSchedules(now time.Time) {
+	if sq.updateSchedule(now) {
+		// special call to convert to a real policy from the property
+		sq.UpdateQueueProperties()
+	}
+	for _, child := range sq.GetCopyOfChildren() {
+		child.UpdateSchedules(now)
+	}
+}
+
+// updateSchedule applies the config with the changes of the active schedule when the active schedule changed.
+// Returns true if the config was applied.
+func (sq *Queue) updateSchedule(now time.Time) bool {
+	sq.Lock()
+	defer sq.Unlock()
+	// a queue that is removed from the config keeps the settings it has
+	if !sq.isManaged || (!sq.IsRunning() && !sq.adminState) {
+		return false
+	}
+	active := sq.findSchedule(now)
+	name := ""
+	if active != nil {
+		name = active.name
+	}
+	if name == sq.activeSchedule {
+		return false
+	}
+	sq.setActiveSchedule(active)
+	if err := sq.applyEffectiveConf(scheduledConf(sq.conf, active), true); err != nil {
+		log.Log(log.SchedQueue).Error("applying queue schedule failed",
+			zap.String("queue", sq.QueuePath),
+			zap.String("schedule", name),
+			zap.Error(err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+	evtMock "github.com/apache/yunikorn-core/pkg/events/mock"
+	"github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
+	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// scheduleMessages returns the messages of the schedule change events, the resource change events are skipped.
+func scheduleMessages(eventSystem *evtMock.EventSystem) []string {
+	messages := make([]string, 0)
+	for _, event := range eventSystem.Events {
+		if event.EventChangeDetail == si.EventRecord_DETAILS_NONE {
+			messages = append(messages, event.Message)
+		}
+	}
+	return messages
+}
+
+func TestScheduledConf(t *testing.T) {
+	conf := configs.QueueConfig{
+		Name: "leaf",
+		Resources: configs.Resources{
+			Max:        map[string]string{"first": "10"},
+			Guaranteed: map[string]string{"first": "5"},
+		},
+		Properties: map[string]string{configs.ApplicationSortPolicy: "fifo", configs.PriorityOffset: "1"},
+	}
+	assert.DeepEqual(t, scheduledConf(conf, nil), conf)
+
+	schedule := &queueSchedule{name: "night", conf: configs.QueueSchedule{
+		Resources:  configs.Resources{Max: map[string]string{"first": "20"}},
+		Properties: map[string]string{configs.ApplicationSortPolicy: "fair"},
+	}}
+	result := scheduledConf(conf, schedule)
+	assert.DeepEqual(t, result.Resources.Max, map[string]string{"first": "20"})
+	assert.DeepEqual(t, result.Resources.Guaranteed, map[string]string{"first": "5"})
+	assert.DeepEqual(t, result.Properties, map[string]string{configs.ApplicationSortPolicy: "fair", configs.PriorityOffset: "1"})
+	// the configured values are not changed
+	assert.DeepEqual(t, conf.Resources.Max, map[string]string{"first": "10"})
+	assert.Equal(t, conf.Properties[configs.ApplicationSortPolicy], "fifo")
+
+	// properties can be set by the schedule only
+	conf.Properties = nil
+	result = scheduledConf(conf, schedule)
+	assert.DeepEqual(t, result.Properties, map[string]string{configs.ApplicationSortPolicy: "fair"})
+}
+
+func TestNewConfiguredQueueSchedule(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create root queue")
+	conf := configs.QueueConfig{
+		Name:       "parent",
+		Parent:     true,
+		Properties: map[string]string{configs.PreemptionDelay: "10s"},
+		Schedules: []configs.QueueSchedule{{
+			Name:       "always",
+			Start:      "* * * * *",
+			Duration:   "1m",
+			Resources:  configs.Resources{Max: map[string]string{"first": "20"}},
+			Properties: map[string]string{configs.PreemptionDelay: "20s"},
+		}},
+	}
+	parent, err := NewConfiguredQueue(conf, root, true)
+	assert.NilError(t, err, "failed to create queue")
+	assert.Equal(t, parent.GetActiveSchedule(), "always")
+	assert.Assert(t, resources.Equals(parent.GetMaxResource(), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 20})), "schedule max not applied")
+	assert.Equal(t, parent.getProperties()[configs.PreemptionDelay], "20s")
+	assert.Equal(t, parent.GetPartitionQueueDAOInfo(false).ActiveSchedule, "always")
+
+	// the properties of the active schedule are inherited
+	leaf, err := createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue")
+	assert.Equal(t, leaf.GetActiveSchedule(), "")
+	assert.Equal(t, leaf.getProperties()[configs.PreemptionDelay], "20s")
+
+	conf.Name = "broken"
+	conf.Schedules[0].Start = "* * *"
+	_, err = NewConfiguredQueue(conf, root, true)
+	assert.ErrorContains(t, err, "cron expression must have 5 fields")
+}
+
+func TestUpdateSchedules(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create root queue")
+	parent, err := createManagedQueue(root, "parent", true, nil)
+	assert.NilError(t, err, "failed to create queue")
+	conf := configs.QueueConfig{
+		Name: "leaf",
+		Resources: configs.Resources{
+			Max:        map[string]string{"first": "10"},
+			Guaranteed: map[string]string{"first": "5"},
+		},
+		Properties: map[string]string{configs.ApplicationSortPolicy: "fifo"},
+		Schedules: []configs.QueueSchedule{{
+			Name:     "night",
+			Start:    "0 20 * * *",
+			Duration: "10h",
+			Resources: configs.Resources{
+				Max:        map[string]string{"first": "20"},
+				Guaranteed: map[string]string{"first": "15"},
+			},
+			Properties: map[string]string{configs.ApplicationSortPolicy: "fair"},
+		}},
+	}
+	leaf, err := NewConfiguredQueue(conf, parent, true)
+	assert.NilError(t, err, "failed to create queue")
+
+	day := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.Local)
+	night := time.Date(2024, time.March, 4, 21, 0, 0, 0, time.Local)
+	morning := time.Date(2024, time.March, 5, 7, 0, 0, 0, time.Local)
+	baseMax := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})
+	baseGuaranteed := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 5})
+
+	// the window that is open when the test runs is not known: start outside the window
+	root.UpdateSchedules(day)
+	assert.Equal(t, leaf.GetActiveSchedule(), "")
+	assert.Assert(t, resources.Equals(leaf.GetMaxResource(), baseMax), "configured max not applied")
+	assert.Assert(t, resources.Equals(leaf.GetGuaranteedResource(), baseGuaranteed), "configured guaranteed not applied")
+	assert.Equal(t, leaf.getSortType(), policies.FifoSortPolicy)
+
+	eventSystem := evtMock.NewEventSystem()
+	leaf.queueEvents = events.NewQueueEvents(eventSystem)
+	root.UpdateSchedules(night)
+	assert.Equal(t, leaf.GetActiveSchedule(), "night")
+	assert.Assert(t, resources.Equals(leaf.GetMaxResource(), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 20})), "schedule max not applied")
+	assert.Assert(t, resources.Equals(leaf.GetGuaranteedResource(), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 15})), "schedule guaranteed not applied")
+	assert.Equal(t, leaf.getSortType(), policies.FairSortPolicy)
+	assert.DeepEqual(t, scheduleMessages(eventSystem), []string{"Queue schedule started: night"})
+
+	// nothing changes while the window stays open
+	root.UpdateSchedules(night.Add(time.Hour))
+	assert.Equal(t, len(scheduleMessages(eventSystem)), 1, "unexpected event")
+
+	root.UpdateSchedules(morning)
+	assert.Equal(t, leaf.GetActiveSchedule(), "")
+	assert.Assert(t, resources.Equals(leaf.GetMaxResource(), baseMax), "configured max not restored")
+	assert.Assert(t, resources.Equals(leaf.GetGuaranteedResource(), baseGuaranteed), "configured guaranteed not restored")
+	assert.Equal(t, leaf.getSortType(), policies.FifoSortPolicy)
+	assert.DeepEqual(t, scheduleMessages(eventSystem), []string{"Queue schedule started: night", "Queue schedule ended: night"})
+
+	// a queue that is removed from the config is not changed
+	leaf.MarkQueueForRemoval()
+	root.UpdateSchedules(night)
+	assert.Equal(t, leaf.GetActiveSchedule(), "")
+	assert.Assert(t, resources.Equals(leaf.GetMaxResource(), baseMax), "draining queue should not change")
+}
@@ -36,7 +36,9 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/events"
+	evtMock "github.com/apache/yunikorn-core/pkg/events/mock"
 	"github.com/apache/yunikorn-core/pkg/metrics"
+	schedEvt "github.com/apache/yunikorn-core/pkg/scheduler/objects/events"
 	"github.com/apache/yunikorn-core/pkg/scheduler/objects/template"
 	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
 	siCommon "github.com/apache/yunikorn-scheduler-interface/lib/go/common"
@@ -513,6 +515,66 @@ func TestQueueStates(t *testing.T) {
 	}
 }
 
+func TestSetAdminState(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	parent, err := createManagedQueue(root, "parent", true, nil)
+	assert.NilError(t, err, "failed to create parent queue")
+	leaf, err := createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	eventSystem := evtMock.NewEventSystem()
+	leaf.queueEvents = schedEvt.NewQueueEvents(eventSystem)
+
+	// the state is set for the whole subtree
+	assert.NilError(t, parent.SetAdminState(Remove, "maintenance"))
+	assert.Assert(t, parent.IsDraining() && leaf.IsDraining(), "queues should be draining")
+	assert.Assert(t, leaf.IsAdminState(), "state should be set by the operator")
+	assert.Equal(t, leaf.GetPartitionQueueDAOInfo(false).StateReason, "maintenance")
+	assert.Equal(t, len(eventSystem.Events), 1, "state change event not sent")
+	assert.Equal(t, eventSystem.Events[0].Message, "Queue state changed to Draining: maintenance")
+
+	// a draining queue can be stopped, the state is kept on a config update
+	assert.NilError(t, leaf.SetAdminState(Stop, "broken nodes"))
+	assert.Assert(t, leaf.IsStopped(), "queue should be stopped")
+	assert.NilError(t, leaf.ApplyConf(configs.QueueConfig{Name: "leaf"}))
+	assert.Assert(t, leaf.IsStopped(), "config update should not start the queue")
+
+	assert.NilError(t, parent.SetAdminState(Start, ""))
+	assert.Assert(t, parent.IsRunning() && leaf.IsRunning(), "queues should be running")
+	assert.Assert(t, !leaf.IsAdminState(), "state should not be set by the operator")
+
+	// removal from the config overrides the state set by an operator
+	assert.NilError(t, leaf.SetAdminState(Stop, "broken nodes"))
+	parent.MarkQueueForRemoval()
+	assert.Assert(t, leaf.IsDraining(), "removed queue should be draining")
+	assert.Assert(t, !leaf.IsAdminState(), "state should not be set by the operator")
+	assert.ErrorContains(t, parent.SetAdminState(Start, ""), "queue is removed from the configuration: root.parent")
+	assert.Assert(t, parent.IsDraining(), "removed queue should be draining")
+}
+
+func TestSetAdminStateSubtree(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	parent, err := createManagedQueue(root, "parent", true, nil)
+	assert.NilError(t, err, "failed to create parent queue")
+	leaf1, err := createManagedQueue(parent, "leaf1", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	leaf2, err := createManagedQueue(parent, "leaf2", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+
+	// a stopped queue in the subtree drains with the rest
+	assert.NilError(t, leaf1.SetAdminState(Stop, "broken nodes"))
+	assert.NilError(t, parent.SetAdminState(Remove, "maintenance"))
+	assert.Assert(t, parent.IsDraining() && leaf1.IsDraining() && leaf2.IsDraining(), "queues should be draining")
+
+	// nothing changes if one queue in the subtree cannot be changed
+	assert.NilError(t, parent.SetAdminState(Start, ""))
+	leaf2.MarkQueueForRemoval()
+	assert.ErrorContains(t, parent.SetAdminState(Stop, "broken nodes"), "queue is removed from the configuration: root.parent.leaf2")
+	assert.Assert(t, parent.IsRunning() && leaf1.IsRunning(), "queues should not be changed")
+	assert.Assert(t, !parent.IsAdminState() && !leaf1.IsAdminState(), "state should not be set by the operator")
+}
+
 // This test must not test the sorter that is underlying.
 // It tests the queue specific parts of the code only.
 func TestSortApplications(t *testing.T) {
@@ -1643,6 +1705,248 @@ func TestSupportTaskGroup(t *testing.T) {
 	leaf, err = createManagedQueueWithProps(parent, "leaf3", false, nil, properties)
 	assert.NilError(t, err, "failed to create queue: %v", err)
 	assert.Assert(t, !leaf.SupportTaskGroup(), "leaf queue (FAIR policy) should not support task group")
+
+	properties = map[string]string{configs.ApplicationSortPolicy: "fairwithaging"}
+	leaf, err = createManagedQueueWithProps(parent, "leaf4", false, nil, properties)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, leaf.getSortType(), policies.FairWithAgingSortPolicy, "leaf queue should have fair with aging policy")
+	assert.Assert(t, !leaf.SupportTaskGroup(), "leaf queue (FAIRWITHAGING policy) should not support task group")
+}
+
+func TestQueueDRF(t *testing.T) {
+	root, err := createRootQueue(map[string]string{"memory": "100", "vcores": "10"})
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	assert.Equal(t, root.getSortType(), policies.FairSortPolicy, "root queue should default to fair")
+
+	// drf on a parent sorts the child queues and is inherited by the leaf
+	properties := map[string]string{configs.ApplicationSortPolicy: "drf", configs.DRFWeight: "2"}
+	parent, err := createManagedQueueWithProps(root, "parent", true, nil, properties)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, parent.getSortType(), policies.DrfSortPolicy, "parent queue should have drf policy")
+	assert.Equal(t, parent.GetDRFWeight(), float64(2), "parent weight not set from property")
+	leaf, err := createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, leaf.getSortType(), policies.DrfSortPolicy, "leaf queue should inherit drf policy")
+	assert.Equal(t, leaf.GetDRFWeight(), float64(1), "weight should not be inherited")
+	assert.Assert(t, !leaf.SupportTaskGroup(), "leaf queue (DRF policy) should not support task group")
+	assert.Assert(t, resources.Equals(leaf.getPartitionCapacity(), root.GetMaxResource()), "partition capacity should be the root max")
+
+	// other policies on a parent fall back to fair
+	parent2, err := createManagedQueueWithProps(root, "parent2", true, nil, map[string]string{configs.ApplicationSortPolicy: "fifo"})
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, parent2.getSortType(), policies.FairSortPolicy, "parent queue should have fair policy")
+
+	// invalid weights fall back to 1
+	for _, value := range []string{"0", "-1", "x", "NaN", "+Inf"} {
+		parent.properties = map[string]string{configs.DRFWeight: value}
+		parent.UpdateQueueProperties()
+		assert.Equal(t, parent.GetDRFWeight(), float64(1), "weight should be reset for value %s", value)
+	}
+}
+
+func TestFairShareWeight(t *testing.T) {
+	root, err := createRootQueue(map[string]string{"memory": "900"})
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	assert.DeepEqual(t, root.GetFairShare(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 900}))
+
+	parent, err := createManagedQueueWithProps(root, "parent", true, nil, map[string]string{configs.FairShareWeight: "2"})
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, parent.GetFairShareWeight(), float64(2), "weight not set from property")
+	other, err := createManagedQueue(root, "other", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, other.GetFairShareWeight(), float64(1), "default weight should be 1")
+
+	// spare capacity is shared 2:1
+	assert.DeepEqual(t, parent.GetFairShare(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 600}))
+	assert.DeepEqual(t, other.GetFairShare(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300}))
+	assert.DeepEqual(t, parent.GetPartitionQueueDAOInfo(false).FairShare, map[string]int64{"memory": 600})
+
+	// guarantees are given first, the rest is shared: 900 - 300 = 600 spare, other: 200, parent: 300 + 400
+	parent.guaranteedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300})
+	assert.DeepEqual(t, parent.GetFairShare(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 700}))
+	assert.DeepEqual(t, other.GetFairShare(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 200}))
+
+	// the max resource limits the fair share
+	other.maxResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100})
+	assert.DeepEqual(t, other.GetFairShare(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100}))
+
+	// weights are not inherited, dynamic children get the weight from the template
+	parent.template, err = template.FromConf(&configs.ChildTemplate{
+		Properties: map[string]string{configs.FairShareWeight: "3"},
+	})
+	assert.NilError(t, err, "failed to create template")
+	dynamic, err := createDynamicQueue(parent, "dynamic", false)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, dynamic.GetFairShareWeight(), float64(3), "weight not set from template")
+	managed, err := createManagedQueue(parent, "managed", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, managed.GetFairShareWeight(), float64(1), "weight should not be inherited")
+	// parent share 700 is divided 3:1
+	assert.DeepEqual(t, dynamic.GetFairShare(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 525}))
+	assert.DeepEqual(t, managed.GetFairShare(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 175}))
+
+	// invalid weights fall back to 1
+	for _, value := range []string{"0", "-2", "two"} {
+		parent.properties = map[string]string{configs.FairShareWeight: value}
+		parent.UpdateQueueProperties()
+		assert.Equal(t, parent.GetFairShareWeight(), float64(1), "weight should be reset for value %s", value)
+	}
+
+	// one weight sets both, a weight that is set is not overwritten by the other
+	parent.properties = map[string]string{configs.DRFWeight: "4"}
+	parent.UpdateQueueProperties()
+	assert.Equal(t, parent.GetFairShareWeight(), float64(4), "fair share weight should use the drf weight")
+	parent.properties = map[string]string{configs.FairShareWeight: "5"}
+	parent.UpdateQueueProperties()
+	assert.Equal(t, parent.GetDRFWeight(), float64(5), "drf weight should use the fair share weight")
+	parent.properties = map[string]string{configs.DRFWeight: "2", configs.FairShareWeight: "3"}
+	parent.UpdateQueueProperties()
+	assert.Equal(t, parent.GetDRFWeight(), float64(2), "drf weight should not be replaced")
+	assert.Equal(t, parent.GetFairShareWeight(), float64(3), "fair share weight should not be replaced")
+	// weights of the parent are not inherited: the weight of the child sets both
+	managed.properties = map[string]string{configs.DRFWeight: "6"}
+	managed.mergeProperties(parent.getProperties(), managed.properties)
+	managed.UpdateQueueProperties()
+	assert.Equal(t, managed.GetFairShareWeight(), float64(6), "fair share weight should use the drf weight of the child")
+}
+
+func TestQueuePreemptionBudget(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	props := map[string]string{
+		configs.PreemptionBudgetAllocations: "1",
+		configs.PreemptionBudgetWindow:      "1h",
+	}
+	parent, err := createManagedQueueWithProps(root, "parent", true, nil, props)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Assert(t, parent.getPreemptionBudget() == nil, "parent queue should not have a budget")
+	assert.Assert(t, parent.GetPartitionQueueDAOInfo(false).PreemptionBudget == nil, "parent queue should not show a budget")
+	// budget properties are inherited by the leaf queues
+	leaf, err := createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	budget := leaf.getPreemptionBudget()
+	assert.Assert(t, budget != nil, "leaf queue should have a budget")
+	assert.Equal(t, budget.maxAllocations, 1)
+	assert.Equal(t, budget.window, time.Hour)
+
+	eventSystem := evtMock.NewEventSystem()
+	leaf.queueEvents = schedEvt.NewQueueEvents(eventSystem)
+	victim := newAllocationWithKey("alloc-1", appID1, nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1}))
+	leaf.recordPreemption(victim)
+	assert.Equal(t, len(eventSystem.Events), 1, "exhausted event not sent")
+	assert.Equal(t, eventSystem.Events[0].ObjectID, "root.parent.leaf")
+	info := leaf.GetPartitionQueueDAOInfo(false).PreemptionBudget
+	assert.Assert(t, info != nil, "budget should be shown")
+	assert.Equal(t, *info.MaxAllocations, 1)
+	assert.Equal(t, info.PreemptedAllocations, 1)
+	assert.Equal(t, info.Window, "1h0m0s")
+	assert.Assert(t, info.Exhausted, "budget should be exhausted")
+
+	// a config update keeps the preemptions
+	leaf.properties[configs.PreemptionBudgetAllocations] = "2"
+	leaf.UpdateQueueProperties()
+	assert.Equal(t, leaf.getPreemptionBudget().headroom(time.Now(), nil).allocations, 1)
+
+	// invalid or removed properties remove the budget
+	leaf.properties[configs.PreemptionBudgetAllocations] = "-1"
+	leaf.UpdateQueueProperties()
+	assert.Assert(t, leaf.getPreemptionBudget() == nil, "invalid budget should be removed")
+	delete(leaf.properties, configs.PreemptionBudgetAllocations)
+	leaf.UpdateQueueProperties()
+	assert.Assert(t, leaf.getPreemptionBudget() == nil, "budget should be removed")
+}
+
+func TestQueueVictimCostWeights(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	assert.Equal(t, root.getVictimCostWeights(), defaultVictimCostWeights())
+	props := map[string]string{
+		configs.PreemptionCostRuntime:    "2",
+		configs.PreemptionCostPriority:   "0",
+		configs.PreemptionCostOriginator: "-1",
+	}
+	leaf, err := createManagedQueueWithProps(root, "leaf", false, nil, props)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	weights := leaf.getVictimCostWeights()
+	assert.Equal(t, weights.runtime, float64(2))
+	assert.Equal(t, weights.priority, float64(0))
+	assert.Equal(t, weights.originator, configs.DefaultPreemptionCostOriginator, "invalid weight should be ignored")
+	assert.Equal(t, weights.placeholder, configs.DefaultPreemptionCostPlaceholder)
+
+	// removed properties reset the weights
+	delete(leaf.properties, configs.PreemptionCostRuntime)
+	leaf.UpdateQueueProperties()
+	assert.Equal(t, leaf.getVictimCostWeights().runtime, configs.DefaultPreemptionCostRuntime)
+
+	var nilQueue *Queue
+	assert.Equal(t, nilQueue.getVictimCostWeights(), defaultVictimCostWeights())
+}
+
+func TestQueueGangPreemption(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	assert.Assert(t, !root.isGangPreemption(), "gang preemption should be off by default")
+	parent, err := createManagedQueueWithProps(root, "parent", true, nil, map[string]string{configs.PreemptionGang: "true"})
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	leaf, err := createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Assert(t, leaf.isGangPreemption(), "gang preemption should be inherited")
+
+	leaf.properties[configs.PreemptionGang] = "gang"
+	leaf.UpdateQueueProperties()
+	assert.Assert(t, !leaf.isGangPreemption(), "invalid value should turn gang preemption off")
+}
+
+func TestQueueCrossNodeDepth(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	assert.Equal(t, root.getCrossNodeDepth(), 0, "cross node preemption should be off by default")
+	parent, err := createManagedQueueWithProps(root, "parent", true, nil, map[string]string{configs.PreemptionCrossNodeDepth: "2"})
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	leaf, err := createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, leaf.getCrossNodeDepth(), 2, "cross node depth should be inherited")
+
+	leaf.properties[configs.PreemptionCrossNodeDepth] = "-1"
+	leaf.UpdateQueueProperties()
+	assert.Equal(t, leaf.getCrossNodeDepth(), 0, "negative value should turn cross node preemption off")
+	leaf.properties[configs.PreemptionCrossNodeDepth] = "deep"
+	leaf.UpdateQueueProperties()
+	assert.Equal(t, leaf.getCrossNodeDepth(), 0, "invalid value should turn cross node preemption off")
+}
+
+func TestSortQueuesFairShareWeight(t *testing.T) {
+	root, err := createRootQueue(map[string]string{"memory": "900"})
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	heavy, err := createManagedQueueWithProps(root, "heavy", false, nil, map[string]string{configs.FairShareWeight: "2"})
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	light, err := createManagedQueue(root, "light", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	pending := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 10})
+	heavy.pending = pending
+	light.pending = pending
+
+	// the weighted queue has more allocated but uses less of its fair share
+	heavy.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 250})
+	light.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 200})
+	// heavy: 250/600 = 0.42, light: 200/300 = 0.67
+	sorted := root.sortQueues()
+	assert.Equal(t, queueNames(sorted), queueNames([]*Queue{heavy, light}), "weighted queue should be first")
+
+	// heavy: 500/600 = 0.83, light: 200/300 = 0.67
+	heavy.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 500})
+	sorted = root.sortQueues()
+	assert.Equal(t, queueNames(sorted), queueNames([]*Queue{light, heavy}), "weighted queue over its share should be last")
+
+	// the weight also applies to guaranteed resource types: 900 - 300 = 600 spare, heavy: 150 + 400, light: 150 + 200
+	guaranteed := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 150})
+	heavy.guaranteedResource = guaranteed
+	light.guaranteedResource = guaranteed
+	// heavy: 400/550 = 0.73, light: 300/350 = 0.86
+	heavy.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 400})
+	light.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300})
+	sorted = root.sortQueues()
+	assert.Equal(t, queueNames(sorted), queueNames([]*Queue{heavy, light}), "weight should apply to guaranteed types")
 }
 
 func TestGetPartitionQueueDAOInfo(t *testing.T) {
@@ -1837,6 +2141,79 @@ func TestPreemptionDelay(t *testing.T) {
 	assert.Equal(t, twice, queue.GetPreemptionDelay(), "preemption delay not updated correctly")
 }
 
+func TestApplicationAgingPeriod(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	leaf, err := createManagedQueueWithProps(root, "leaf", false, nil, map[string]string{configs.ApplicationAgingPeriod: "10m"})
+	assert.NilError(t, err, "failed to create leaf queue")
+	assert.Equal(t, leaf.GetApplicationAgingPeriod(), 10*time.Minute, "application aging period not set from property")
+
+	// invalid values fall back to the default
+	for _, value := range []string{"-1m", "0s", "ten minutes"} {
+		leaf.properties = map[string]string{configs.ApplicationAgingPeriod: value}
+		leaf.UpdateQueueProperties()
+		assert.Equal(t, leaf.GetApplicationAgingPeriod(), configs.DefaultApplicationAgingPeriod, "default expected for value %s", value)
+	}
+	leaf.properties = map[string]string{configs.ApplicationAgingPeriod: "1m"}
+	leaf.UpdateQueueProperties()
+	assert.Equal(t, leaf.GetApplicationAgingPeriod(), time.Minute, "application aging period not updated")
+	leaf.properties = map[string]string{}
+	leaf.UpdateQueueProperties()
+	assert.Equal(t, leaf.GetApplicationAgingPeriod(), configs.DefaultApplicationAgingPeriod, "application aging period not reset")
+}
+
+func TestQueueAging(t *testing.T) {
+	root, err := createRootQueue(map[string]string{"memory": "100"})
+	assert.NilError(t, err, "queue create failed")
+	parent, err := createManagedQueueWithProps(root, "parent", true, nil, map[string]string{configs.QueueAgingPeriod: "10m"})
+	assert.NilError(t, err, "failed to create parent queue")
+	assert.Equal(t, parent.GetAgingPeriod(), 10*time.Minute, "aging period not set from property")
+	assert.Equal(t, root.GetAgingPeriod(), time.Duration(0), "aging should be off if not set")
+	leaf, err := createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	assert.Equal(t, leaf.GetAgingPeriod(), time.Duration(0), "aging period should not be set on a leaf")
+
+	// invalid values disable aging
+	for _, value := range []string{"-1m", "0s", "ten minutes"} {
+		parent.properties = map[string]string{configs.QueueAgingPeriod: value}
+		parent.UpdateQueueProperties()
+		assert.Equal(t, parent.GetAgingPeriod(), time.Duration(0), "aging should be off for value %s", value)
+	}
+	parent.properties = map[string]string{configs.QueueAgingPeriod: "1m"}
+	parent.UpdateQueueProperties()
+	assert.Equal(t, parent.GetAgingPeriod(), time.Minute, "aging period not updated")
+	daoInfo := parent.GetPartitionQueueDAOInfo(false)
+	assert.Equal(t, daoInfo.AgingPeriod, "1m0s", "aging period not exposed")
+
+	// wait starts when resources become pending and is tracked up the hierarchy
+	assert.Equal(t, leaf.GetWaitingTime(), time.Duration(0), "new queue should not be waiting")
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 10})
+	leaf.incPendingResource(res)
+	assert.Assert(t, !leaf.pendingSince.IsZero(), "leaf wait should have started")
+	assert.Assert(t, !parent.pendingSince.IsZero(), "parent wait should have started")
+	start := time.Now().Add(-time.Minute)
+	leaf.pendingSince = start
+	assert.Assert(t, leaf.GetWaitingTime() >= time.Minute, "leaf should have been waiting for at least a minute")
+	assert.Assert(t, leaf.GetPartitionQueueDAOInfo(false).WaitingTime != "", "waiting time not exposed")
+
+	// more pending resources do not reset the wait
+	leaf.incPendingResource(res)
+	assert.Equal(t, leaf.pendingSince, start, "wait should not restart for new pending resources")
+
+	// an allocation with resources still pending restarts the wait
+	leaf.IncAllocatedResource(res)
+	leaf.decPendingResource(res)
+	assert.Assert(t, leaf.pendingSince.After(start), "wait should restart after an allocation")
+
+	// nothing pending ends the wait
+	leaf.pendingSince = start
+	assert.NilError(t, leaf.TryIncAllocatedResource(res), "allocation should have fit")
+	leaf.decPendingResource(res)
+	assert.Equal(t, leaf.GetWaitingTime(), time.Duration(0), "queue without pending resources should not be waiting")
+	assert.Equal(t, parent.GetWaitingTime(), time.Duration(0), "parent without pending resources should not be waiting")
+	assert.Equal(t, leaf.GetPartitionQueueDAOInfo(false).WaitingTime, "", "waiting time should not be exposed")
+}
+
 func TestFindQueueByAppID(t *testing.T) {
 	root, err := createRootQueue(nil)
 	assert.NilError(t, err, "failed to create queue")
@@ -28,6 +28,7 @@ type PreemptionContext struct {
 	node        *Node
 	requiredAsk *Allocation
 	allocations []*Allocation
+	budgets     func(*Allocation) []*budgetHeadroom // preemption budgets left for an allocation, nil if not limited
 }
 
 func NewRequiredNodePreemptor(node *Node, requiredAsk *Allocation) *PreemptionContext {
@@ -121,11 +122,21 @@ func (p *PreemptionContext) sortAllocations() {
 	})
 }
 
+// setBudgets sets the function that returns the preemption budgets left for an allocation. Allocations that do not
+// fit in their budgets, on top of the victims selected before them, are never returned as victims.
+func (p *PreemptionContext) setBudgets(budgets func(*Allocation) []*budgetHeadroom) {
+	p.budgets = budgets
+}
+
 func (p *PreemptionContext) GetVictims() []*Allocation {
 	var victims []*Allocation
 	var currentResource = resources.NewResource()
+	tracker := newBudgetTracker()
 	for _, allocation := range p.allocations {
 		if !resources.StrictlyGreaterThanOrEquals(currentResource, p.requiredAsk.GetAllocatedResource()) {
+			if p.budgets != nil && !tracker.take([]*Allocation{allocation}, p.budgets(allocation)...) {
+				continue
+			}
 			currentResource.AddTo(allocation.GetAllocatedResource())
 			victims = append(victims, allocation)
 		} else {
@@ -304,4 +304,24 @@ func TestGetVictims(t *testing.T) {
 	victims3 := p3.GetVictims()
 	assert.Equal(t, len(victims3), 0)
 	removeAllocationAsks(node, asks)
+
+	// case 4: the preemption budget allows one victim only, the victims do not free up enough resources
+	requiredAsk4 := createAllocationAsk("ask14", "app1", true, true, 20,
+		resources.NewResourceFromMap(map[string]resources.Quantity{"first": 30}))
+	p4 := NewRequiredNodePreemptor(node, requiredAsk4)
+	asks = prepareAllocationAsks(t, node)
+	p4.filterAllocations()
+	p4.sortAllocations()
+	budget := &budgetHeadroom{allocations: 1}
+	p4.setBudgets(func(*Allocation) []*budgetHeadroom {
+		return []*budgetHeadroom{budget}
+	})
+	victims4 := p4.GetVictims()
+	assert.Equal(t, len(victims4), 0)
+
+	// case 5: the budget allows one victim large enough for the ask
+	p4.requiredAsk = requiredAsk2
+	victims5 := p4.GetVictims()
+	assert.Equal(t, len(victims5), 1)
+	removeAllocationAsks(node, asks)
 }
@@ -19,6 +19,8 @@
 package objects
 
 import (
+	"time"
+
 	"go.uber.org/zap"
 
 	"github.com/apache/yunikorn-core/pkg/log"
@@ -28,6 +30,7 @@ type reservation struct {
 	appID    string
 	nodeID   string
 	allocKey string
+	created  time.Time
 	// these references must ONLY be used for alloc, node and application removal otherwise
 	// the reservations cannot be removed and scheduling might be impacted.
 	app   *Application
@@ -48,6 +51,7 @@ func newReservation(node *Node, app *Application, alloc *Allocation, appBased bo
 	}
 	res := &reservation{
 		allocKey: alloc.GetAllocationKey(),
+		created:  time.Now(),
 		alloc:    alloc,
 		app:      app,
 		node:     node,
@@ -70,6 +74,14 @@ func (r *reservation) String() string {
 	return r.app.ApplicationID + " -> " + r.nodeID + "|" + r.allocKey
 }
 
+// GetCreateTime returns the time the reservation was made.
+func (r *reservation) GetCreateTime() time.Time {
+	if r != nil {
+		return r.created
+	}
+	return time.Time{}
+}
+
 // GetObjects returns the objects that created the reservation.
 // None of the returned values will be nil unless the reservation itself is nil
 func (r *reservation) GetObjects() (*Node, *Application, *Allocation) {
@@ -20,6 +20,7 @@ package objects
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -45,8 +46,8 @@ func TestNewReservation(t *testing.T) {
 		{"nil alloc", node, app, nil, true, nil},
 		{"nil app", node, nil, ask, true, nil},
 		{"nil node", nil, app, ask, true, nil},
-		{"node based", node, app, ask, false, &reservation{"app-1", "", "alloc-1", app, node, ask}},
-		{"app based", node, app, ask, true, &reservation{"", "node-1", "alloc-1", app, node, ask}},
+		{"node based", node, app, ask, false, &reservation{"app-1", "", "alloc-1", time.Time{}, app, node, ask}},
+		{"app based", node, app, ask, true, &reservation{"", "node-1", "alloc-1", time.Time{}, app, node, ask}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -67,6 +68,20 @@ func TestNewReservation(t *testing.T) {
 	}
 }
 
+func TestReservationCreateTime(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	ask := newAllocationAsk("alloc-1", "app-1", res)
+	app := newApplication("app-1", "default", "root.unknown")
+	node := newNodeRes("node-1", res)
+	before := time.Now()
+	reserve := newReservation(node, app, ask, true)
+	assert.Assert(t, !reserve.GetCreateTime().Before(before), "create time not set on reservation")
+	assert.Assert(t, !time.Now().Before(reserve.GetCreateTime()), "create time in the future")
+
+	var nilReserve *reservation
+	assert.Assert(t, nilReserve.GetCreateTime().IsZero(), "nil reservation should return zero time")
+}
+
 func TestReservationString(t *testing.T) {
 	var nilReserve *reservation
 	defer func() {
@@ -19,6 +19,7 @@
 package objects
 
 import (
+	"math"
 	"sort"
 	"time"
 
@@ -27,15 +28,30 @@ import (
 	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
 )
 
-func sortQueue(queues []*Queue, fairMaxResources []*resources.Resource, sortType policies.SortPolicy, considerPriority bool) {
+// sortQueue sorts the queues using the sort type. The fair max resources and, if not nil, the guaranteed resources
+// are listed in the same order as the queues. If the guaranteed resources are nil the guarantee of each queue is used.
+func sortQueue(queues []*Queue, fairMaxResources, guaranteedResources []*resources.Resource, sortType policies.SortPolicy, considerPriority bool, agingPeriod time.Duration, capacity *resources.Resource) {
 	sortingStart := time.Now()
-	if sortType == policies.FairSortPolicy {
+	switch sortType {
+	case policies.FairSortPolicy:
+		if agingPeriod > 0 {
+			if considerPriority {
+				sortQueuesByPriorityAndFairnessWithAging(queues, fairMaxResources, guaranteedResources, agingPeriod)
+			} else {
+				sortQueuesByFairnessAndPriorityWithAging(queues, fairMaxResources, guaranteedResources, agingPeriod)
+			}
+		} else if considerPriority {
+			sortQueuesByPriorityAndFairness(queues, fairMaxResources, guaranteedResources)
+		} else {
+			sortQueuesByFairnessAndPriority(queues, fairMaxResources, guaranteedResources)
+		}
+	case policies.DrfSortPolicy:
 		if considerPriority {
-			sortQueuesByPriorityAndFairness(queues, fairMaxResources)
+			sortQueuesByPriorityAndDominantShare(queues, capacity, agingPeriod)
 		} else {
-			sortQueuesByFairnessAndPriority(queues, fairMaxResources)
+			sortQueuesByDominantShareAndPriority(queues, capacity, agingPeriod)
 		}
-	} else {
+	default:
 		if considerPriority {
 			sortQueuesByPriority(queues)
 		}
@@ -53,7 +69,7 @@ func sortQueuesByPriority(queues []*Queue) {
 	})
 }
 
-func sortQueuesByPriorityAndFairness(queues []*Queue, fairMaxResources []*resources.Resource) {
+func sortQueuesByPriorityAndFairness(queues []*Queue, fairMaxResources, guaranteedResources []*resources.Resource) {
 	sort.SliceStable(queues, func(i, j int) bool {
 		l := queues[i]
 		r := queues[j]
@@ -66,8 +82,8 @@ func sortQueuesByPriorityAndFairness(queues []*Queue, fairMaxResources []*resour
 			return false
 		}
 
-		comp := resources.CompUsageRatioSeparately(l.GetAllocatedResource(), l.GetGuaranteedResource(), fairMaxResources[i],
-			r.GetAllocatedResource(), r.GetGuaranteedResource(), fairMaxResources[j])
+		comp := resources.CompUsageRatioSeparately(l.GetAllocatedResource(), queueGuarantee(guaranteedResources, i, l), fairMaxResources[i],
+			r.GetAllocatedResource(), queueGuarantee(guaranteedResources, j, r), fairMaxResources[j])
 
 		if comp == 0 {
 			return resources.StrictlyGreaterThan(resources.Sub(l.GetPendingResource(), r.GetPendingResource()), resources.Zero)
@@ -76,13 +92,13 @@ func sortQueuesByPriorityAndFairness(queues []*Queue, fairMaxResources []*resour
 	})
 }
 
-func sortQueuesByFairnessAndPriority(queues []*Queue, fairMaxResources []*resources.Resource) {
+func sortQueuesByFairnessAndPriority(queues []*Queue, fairMaxResources, guaranteedResources []*resources.Resource) {
 	sort.SliceStable(queues, func(i, j int) bool {
 		l := queues[i]
 		r := queues[j]
 
-		comp := resources.CompUsageRatioSeparately(l.GetAllocatedResource(), l.GetGuaranteedResource(), fairMaxResources[i],
-			r.GetAllocatedResource(), r.GetGuaranteedResource(), fairMaxResources[j])
+		comp := resources.CompUsageRatioSeparately(l.GetAllocatedResource(), queueGuarantee(guaranteedResources, i, l), fairMaxResources[i],
+			r.GetAllocatedResource(), queueGuarantee(guaranteedResources, j, r), fairMaxResources[j])
 		if comp == 0 {
 			lPriority := l.GetCurrentPriority()
 			rPriority := r.GetCurrentPriority()
@@ -98,7 +114,149 @@ func sortQueuesByFairnessAndPriority(queues []*Queue, fairMaxResources []*resour
 	})
 }
 
-func sortApplications(apps map[string]*Application, sortType policies.SortPolicy, considerPriority bool, globalResource *resources.Resource) []*Application {
+// queueGuarantee returns the guaranteed resource at the index of the list, or the guarantee of the queue if the
+// list is nil.
+func queueGuarantee(guaranteedResources []*resources.Resource, i int, queue *Queue) *resources.Resource {
+	if guaranteedResources == nil {
+		return queue.GetGuaranteedResource()
+	}
+	return guaranteedResources[i]
+}
+
+// queueAgingScores returns the fair share of each queue reduced by an aging credit. The credit grows linearly
+// with the time the queue has been waiting since its last allocation until the aging period is reached.
+// A full credit equals the largest share in the list: a queue that has waited for the whole period is treated
+// as if it has no usage.
+// Scores are calculated once before sorting to keep the order stable while the queues are sorted.
+func queueAgingScores(queues []*Queue, fairMaxResources, guaranteedResources []*resources.Resource, agingPeriod time.Duration) map[string]float64 {
+	scores := make(map[string]float64, len(queues))
+	maxShare := float64(1)
+	for i, queue := range queues {
+		share := resources.FairShare(queue.GetAllocatedResource(), queueGuarantee(guaranteedResources, i, queue), fairMaxResources[i])
+		scores[queue.QueuePath] = share
+		if share > maxShare {
+			maxShare = share
+		}
+	}
+	for _, queue := range queues {
+		credit := math.Min(float64(queue.GetWaitingTime())/float64(agingPeriod), 1)
+		scores[queue.QueuePath] -= credit * maxShare
+	}
+	return scores
+}
+
+func sortQueuesByFairnessAndPriorityWithAging(queues []*Queue, fairMaxResources, guaranteedResources []*resources.Resource, agingPeriod time.Duration) {
+	scores := queueAgingScores(queues, fairMaxResources, guaranteedResources, agingPeriod)
+	sort.SliceStable(queues, func(i, j int) bool {
+		l := queues[i]
+		r := queues[j]
+		lScore := scores[l.QueuePath]
+		rScore := scores[r.QueuePath]
+		if lScore != rScore {
+			return lScore < rScore
+		}
+		lPriority := l.GetCurrentPriority()
+		rPriority := r.GetCurrentPriority()
+		if lPriority > rPriority {
+			return true
+		}
+		if lPriority < rPriority {
+			return false
+		}
+		return resources.StrictlyGreaterThan(resources.Sub(l.GetPendingResource(), r.GetPendingResource()), resources.Zero)
+	})
+}
+
+func sortQueuesByPriorityAndFairnessWithAging(queues []*Queue, fairMaxResources, guaranteedResources []*resources.Resource, agingPeriod time.Duration) {
+	scores := queueAgingScores(queues, fairMaxResources, guaranteedResources, agingPeriod)
+	sort.SliceStable(queues, func(i, j int) bool {
+		l := queues[i]
+		r := queues[j]
+		lPriority := l.GetCurrentPriority()
+		rPriority := r.GetCurrentPriority()
+		if lPriority > rPriority {
+			return true
+		}
+		if lPriority < rPriority {
+			return false
+		}
+		lScore := scores[l.QueuePath]
+		rScore := scores[r.QueuePath]
+		if lScore != rScore {
+			return lScore < rScore
+		}
+		return resources.StrictlyGreaterThan(resources.Sub(l.GetPendingResource(), r.GetPendingResource()), resources.Zero)
+	})
+}
+
+// queueDominantShares returns the weighted dominant share of each queue: the largest share of any resource
+// type allocated to the queue compared to the partition capacity, divided by the drf weight of the queue.
+// If an aging period is set the share is reduced by an aging credit in the same way as for the fair policy.
+// Shares are calculated once before sorting to keep the order stable while the queues are sorted.
+func queueDominantShares(queues []*Queue, capacity *resources.Resource, agingPeriod time.Duration) map[string]float64 {
+	shares := make(map[string]float64, len(queues))
+	maxShare := float64(1)
+	for _, queue := range queues {
+		share := resources.DominantShare(queue.GetAllocatedResource(), capacity) / queue.GetDRFWeight()
+		shares[queue.QueuePath] = share
+		if share > maxShare {
+			maxShare = share
+		}
+	}
+	if agingPeriod > 0 {
+		for _, queue := range queues {
+			credit := math.Min(float64(queue.GetWaitingTime())/float64(agingPeriod), 1)
+			shares[queue.QueuePath] -= credit * maxShare
+		}
+	}
+	return shares
+}
+
+func sortQueuesByDominantShareAndPriority(queues []*Queue, capacity *resources.Resource, agingPeriod time.Duration) {
+	shares := queueDominantShares(queues, capacity, agingPeriod)
+	sort.SliceStable(queues, func(i, j int) bool {
+		l := queues[i]
+		r := queues[j]
+		lShare := shares[l.QueuePath]
+		rShare := shares[r.QueuePath]
+		if lShare != rShare {
+			return lShare < rShare
+		}
+		lPriority := l.GetCurrentPriority()
+		rPriority := r.GetCurrentPriority()
+		if lPriority > rPriority {
+			return true
+		}
+		if lPriority < rPriority {
+			return false
+		}
+		return resources.StrictlyGreaterThan(resources.Sub(l.GetPendingResource(), r.GetPendingResource()), resources.Zero)
+	})
+}
+
+func sortQueuesByPriorityAndDominantShare(queues []*Queue, capacity *resources.Resource, agingPeriod time.Duration) {
+	shares := queueDominantShares(queues, capacity, agingPeriod)
+	sort.SliceStable(queues, func(i, j int) bool {
+		l := queues[i]
+		r := queues[j]
+		lPriority := l.GetCurrentPriority()
+		rPriority := r.GetCurrentPriority()
+		if lPriority > rPriority {
+			return true
+		}
+		if lPriority < rPriority {
+			return false
+		}
+		lShare := shares[l.QueuePath]
+		rShare := shares[r.QueuePath]
+		if lShare != rShare {
+			return lShare < rShare
+		}
+		return resources.StrictlyGreaterThan(resources.Sub(l.GetPendingResource(), r.GetPendingResource()), resources.Zero)
+	})
+}
+
+func sortApplications(apps map[string]*Application, sortType policies.SortPolicy, considerPriority bool, globalResource *resources.Resource, agingPeriod time.Duration) []*Application {
 	sortingStart := time.Now()
 	sortedApps := filterOnPendingResources(apps)
 	switch sortType {
@@ -114,12 +272,22 @@ func sortApplications(apps map[string]*Application, sortType policies.SortPolicy
 		} else {
 			sortApplicationsBySubmissionTimeAndPriority(sortedApps)
 		}
-	// case policies.FairWithAgingSortPolicy:
-	// 	if considerPriority {
-	// 		sortApplicationsByPriorityAndFairnessWithAging(sortedApps, globalResource)
-	// 	} else {
-	// 		sortApplicationsByFairnessAndPriorityWithAging(sortedApps, globalResource)
-	// 	}
+	case policies.DrfSortPolicy:
+		if considerPriority {
+			sortApplicationsByPriorityAndDominantShare(sortedApps, globalResource)
+		} else {
+			sortApplicationsByDominantShareAndPriority(sortedApps, globalResource)
+		}
+	case policies.FairWithAgingSortPolicy:
+		if considerPriority {
+			sortApplicationsByPriorityAndFairnessWithAging(sortedApps, globalResource, agingPeriod)
+		} else {
+			sortApplicationsByFairnessAndPriorityWithAging(sortedApps, globalResource, agingPeriod)
+		}
+	case policies.SjfSortPolicy:
+		sortApplicationsByRuntimeEstimate(sortedApps, considerPriority)
+	case policies.EdfSortPolicy:
+		sortApplicationsByDeadline(sortedApps, considerPriority)
 	}
 	metrics.GetSchedulerMetrics().ObserveAppSortingLatency(sortingStart)
 	return sortedApps
@@ -132,7 +300,7 @@ func sortApplicationsByFairnessAndPriority(sortedApps []*Application, globalReso
 		if comp := resources.CompUsageRatio(l.GetAllocatedResource(), r.GetAllocatedResource(), globalResource); comp != 0 {
 			return comp < 0
 		}
-		return l.GetAskMaxPriority() > r.GetAskMaxPriority()
+		return l.GetEffectivePriority() > r.GetEffectivePriority()
 	})
 }
 
@@ -140,8 +308,8 @@ func sortApplicationsByPriorityAndFairness(sortedApps []*Application, globalReso
 	sort.SliceStable(sortedApps, func(i, j int) bool {
 		l := sortedApps[i]
 		r := sortedApps[j]
-		leftPriority := l.GetAskMaxPriority()
-		rightPriority := r.GetAskMaxPriority()
+		leftPriority := l.GetEffectivePriority()
+		rightPriority := r.GetEffectivePriority()
 		if leftPriority > rightPriority {
 			return true
 		}
@@ -162,7 +330,7 @@ func sortApplicationsBySubmissionTimeAndPriority(sortedApps []*Application) {
 		if r.SubmissionTime.Before(l.SubmissionTime) {
 			return false
 		}
-		return l.GetAskMaxPriority() > r.GetAskMaxPriority()
+		return l.GetEffectivePriority() > r.GetEffectivePriority()
 	})
 }
 
@@ -170,8 +338,8 @@ func sortApplicationsByPriorityAndSubmissionTime(sortedApps []*Application) {
 	sort.SliceStable(sortedApps, func(i, j int) bool {
 		l := sortedApps[i]
 		r := sortedApps[j]
-		leftPriority := l.GetAskMaxPriority()
-		rightPriority := r.GetAskMaxPriority()
+		leftPriority := l.GetEffectivePriority()
+		rightPriority := r.GetEffectivePriority()
 		if leftPriority > rightPriority {
 			return true
 		}
@@ -182,60 +350,118 @@ func sortApplicationsByPriorityAndSubmissionTime(sortedApps []*Application) {
 	})
 }
 
-// func sortApplicationsByFairnessAndPriorityWithAging(sortedApps []*Application, globalResource *resources.Resource) {
-// 	sort.SliceStable(sortedApps, func(i, j int) bool {
-// 		l := sortedApps[i]
-// 		r := sortedApps[j]
-		
-// 		// 計算公平性分數
-// 		fairnessScore := float64(resources.CompUsageRatio(l.GetAllocatedResource(), r.GetAllocatedResource(), globalResource))
-		
-// 		// 計算老化分數 (等待時間越長分數越高)
-// 		lWaitingTime := l.GetMaxWaitingTime().Seconds()
-// 		rWaitingTime := r.GetMaxWaitingTime().Seconds()
-// 		agingScore := lWaitingTime - rWaitingTime
-		
-// 		// 綜合分數 = 公平性分數 + 老化分數
-// 		// 權重可以根據需求調整
-// 		const agingWeight = 0.3
-// 		totalScore := fairnessScore + (agingScore * agingWeight)
-		
-// 		if totalScore != 0 {
-// 			return totalScore < 0
-// 		}
-		
-// 		// 如果分數相同，則考慮優先級
-// 		return l.GetAskMaxPriority() > r.GetAskMaxPriority()
-// 	})
-// }
-
-// func sortApplicationsByPriorityAndFairnessWithAging(sortedApps []*Application, globalResource *resources.Resource) {
-// 	sort.SliceStable(sortedApps, func(i, j int) bool {
-// 		l := sortedApps[i]
-// 		r := sortedApps[j]
-		
-// 		// 首先考慮優先級
-// 		lPriority := l.GetAskMaxPriority()
-// 		rPriority := r.GetAskMaxPriority()
-// 		if lPriority != rPriority {
-// 			return lPriority > rPriority
-// 		}
-		
-// 		// 如果優先級相同，則考慮公平性和老化
-// 		fairnessScore := float64(resources.CompUsageRatio(l.GetAllocatedResource(), r.GetAllocatedResource(), globalResource))
-		
-// 		// 計算老化分數
-// 		lWaitingTime := l.GetMaxWaitingTime().Seconds()
-// 		rWaitingTime := r.GetMaxWaitingTime().Seconds()
-// 		agingScore := lWaitingTime - rWaitingTime
-		
-// 		// 綜合分數 = 公平性分數 + 老化分數
-// 		const agingWeight = 0.3
-// 		totalScore := fairnessScore + (agingScore * agingWeight)
-		
-// 		return totalScore < 0
-// 	})
-// }
+// appAgingScores returns the usage share of each application corrected for the time the application
+// has been waiting for its pending asks. The waiting time is converted into a credit which grows linearly
+// until the aging period is reached. A full credit equals the largest share in the list: an application
+// that has waited for the whole period is treated as if it has no usage.
+// The credit needs a single number to subtract from, the dominant share is used as that is the share
+// compared first by the usage ratio of the fair policy. Applications with the same score fall back to
+// the full usage ratio comparison, without any credit the order is the same as the fair policy.
+// Scores are calculated once before sorting to keep the order stable while the applications are sorted.
+func appAgingScores(sortedApps []*Application, globalResource *resources.Resource, agingPeriod time.Duration) map[string]float64 {
+	shares := make(map[string]float64, len(sortedApps))
+	maxShare := float64(1)
+	for _, app := range sortedApps {
+		share := resources.DominantShare(app.GetAllocatedResource(), globalResource)
+		shares[app.ApplicationID] = share
+		if share > maxShare {
+			maxShare = share
+		}
+	}
+	if agingPeriod <= 0 {
+		return shares
+	}
+	for _, app := range sortedApps {
+		credit := math.Min(float64(app.GetWaitingTime())/float64(agingPeriod), 1)
+		shares[app.ApplicationID] -= credit * maxShare
+	}
+	return shares
+}
+
+// compareAgingScores compares the aging scores of two applications, equal scores are compared using the usage ratio.
+func compareAgingScores(l, r *Application, scores map[string]float64, globalResource *resources.Resource) int {
+	lScore := scores[l.ApplicationID]
+	rScore := scores[r.ApplicationID]
+	switch {
+	case lScore < rScore:
+		return -1
+	case lScore > rScore:
+		return 1
+	default:
+		return resources.CompUsageRatio(l.GetAllocatedResource(), r.GetAllocatedResource(), globalResource)
+	}
+}
+
+func sortApplicationsByFairnessAndPriorityWithAging(sortedApps []*Application, globalResource *resources.Resource, agingPeriod time.Duration) {
+	scores := appAgingScores(sortedApps, globalResource, agingPeriod)
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		if comp := compareAgingScores(l, r, scores, globalResource); comp != 0 {
+			return comp < 0
+		}
+		return l.GetEffectivePriority() > r.GetEffectivePriority()
+	})
+}
+
+func sortApplicationsByPriorityAndFairnessWithAging(sortedApps []*Application, globalResource *resources.Resource, agingPeriod time.Duration) {
+	scores := appAgingScores(sortedApps, globalResource, agingPeriod)
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		leftPriority := l.GetEffectivePriority()
+		rightPriority := r.GetEffectivePriority()
+		if leftPriority > rightPriority {
+			return true
+		}
+		if leftPriority < rightPriority {
+			return false
+		}
+		return compareAgingScores(l, r, scores, globalResource) < 0
+	})
+}
+
+// appDominantShares returns the dominant share of each application: the largest share of any resource
+// type allocated to the application compared to the partition capacity.
+// Shares are calculated once before sorting to keep the order stable while the applications are sorted.
+func appDominantShares(sortedApps []*Application, capacity *resources.Resource) map[string]float64 {
+	shares := make(map[string]float64, len(sortedApps))
+	for _, app := range sortedApps {
+		shares[app.ApplicationID] = resources.DominantShare(app.GetAllocatedResource(), capacity)
+	}
+	return shares
+}
+
+func sortApplicationsByDominantShareAndPriority(sortedApps []*Application, capacity *resources.Resource) {
+	shares := appDominantShares(sortedApps, capacity)
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		lShare := shares[l.ApplicationID]
+		rShare := shares[r.ApplicationID]
+		if lShare != rShare {
+			return lShare < rShare
+		}
+		return l.GetEffectivePriority() > r.GetEffectivePriority()
+	})
+}
+
+func sortApplicationsByPriorityAndDominantShare(sortedApps []*Application, capacity *resources.Resource) {
+	shares := appDominantShares(sortedApps, capacity)
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		leftPriority := l.GetEffectivePriority()
+		rightPriority := r.GetEffectivePriority()
+		if leftPriority > rightPriority {
+			return true
+		}
+		if leftPriority < rightPriority {
+			return false
+		}
+		return shares[l.ApplicationID] < shares[r.ApplicationID]
+	})
+}
 
 func filterOnPendingResources(apps map[string]*Application) []*Application {
 	filteredApps := make([]*Application, 0)
@@ -247,3 +473,57 @@ func filterOnPendingResources(apps map[string]*Application) []*Application {
 	}
 	return filteredApps
 }
+
+// sortApplicationsByRuntimeEstimate sorts the applications on the runtime estimate declared in the
+// application tags, shortest first. Applications without an estimate are sorted after all applications
+// with an estimate. Submission time is used if the estimates are equal.
+// If priority is considered the priority of the applications is checked before the estimate.
+func sortApplicationsByRuntimeEstimate(sortedApps []*Application, considerPriority bool) {
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		if considerPriority {
+			leftPriority := l.GetEffectivePriority()
+			rightPriority := r.GetEffectivePriority()
+			if leftPriority != rightPriority {
+				return leftPriority > rightPriority
+			}
+		}
+		lEstimate := l.GetRuntimeEstimate()
+		rEstimate := r.GetRuntimeEstimate()
+		if lEstimate != rEstimate {
+			if lEstimate == 0 || rEstimate == 0 {
+				return rEstimate == 0
+			}
+			return lEstimate < rEstimate
+		}
+		return l.SubmissionTime.Before(r.SubmissionTime)
+	})
+}
+
+// sortApplicationsByDeadline sorts the applications on the deadline declared in the application tags,
+// earliest first. Applications without a deadline are sorted after all applications with a deadline.
+// Submission time is used if the deadlines are equal.
+// If priority is considered the priority of the applications is checked before the deadline.
+func sortApplicationsByDeadline(sortedApps []*Application, considerPriority bool) {
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		if considerPriority {
+			leftPriority := l.GetEffectivePriority()
+			rightPriority := r.GetEffectivePriority()
+			if leftPriority != rightPriority {
+				return leftPriority > rightPriority
+			}
+		}
+		lDeadline := l.GetDeadline()
+		rDeadline := r.GetDeadline()
+		if !lDeadline.Equal(rDeadline) {
+			if lDeadline.IsZero() || rDeadline.IsZero() {
+				return rDeadline.IsZero()
+			}
+			return lDeadline.Before(rDeadline)
+		}
+		return l.SubmissionTime.Before(r.SubmissionTime)
+	})
+}
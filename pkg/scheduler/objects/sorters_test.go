@@ -26,6 +26,8 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/apache/yunikorn-core/pkg/common"
+	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
 	"github.com/apache/yunikorn-core/pkg/scheduler/policies"
 )
@@ -70,49 +72,49 @@ func TestSortQueuesWithGuarantees(t *testing.T) {
 	// fifo
 	queues = []*Queue{q0, q1, q2, q3}
 
-	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, false)
+	sortQueue(queues, fairMaxResources, nil, policies.FifoSortPolicy, false, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q1, q2, q3}), "fifo first")
 
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, true)
+	sortQueue(queues, fairMaxResources, nil, policies.FifoSortPolicy, true, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q0, q1, q2}), "fifo first - priority")
 
 	// fifo - different starting order
 	queues = []*Queue{q1, q3, q0, q2}
-	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, false)
+	sortQueue(queues, fairMaxResources, nil, policies.FifoSortPolicy, false, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q1, q3, q0, q2}), "fifo second")
 
 	queues = []*Queue{q1, q3, q0, q2}
-	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, true)
+	sortQueue(queues, fairMaxResources, nil, policies.FifoSortPolicy, true, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q1, q0, q2}), "fifo second - priority")
 
 	// fairness ratios: q0:300/500=0.6, q1:200/300=0.67, q2:100/200=0.5, q3:100/200=0.5
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair first")
 
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, true, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair first - priority")
 
 	// fairness ratios: q0:200/500=0.4, q1:300/300=1, q2:100/200=0.5, q3:100/200=0.5
 	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 200, "vcore": 200})
 	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300, "vcore": 300})
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q3, q2, q1}), "fair second")
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, true, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q0, q2, q1}), "fair second - priority")
 
 	// fairness ratios: q0:150/500=0.3, q1:120/300=0.4, q2:100/200=0.5, q3:100/200=0.5
 	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 150, "vcore": 150})
 	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 120, "vcore": 120})
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q1, q3, q2}), "fair third")
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, true, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q0, q1, q2}), "fair third - priority")
 
 	// fairness ratios: q0:400/800=0.5, q1:200/400= 0.5, q2:100/200=0.5, q3:100/200=0.5
@@ -121,7 +123,7 @@ func TestSortQueuesWithGuarantees(t *testing.T) {
 	q1.guaranteedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 400, "vcore": 300})
 	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 200, "vcore": 150})
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q0, q1, q2}), "fair - pending resource")
 }
 
@@ -157,22 +159,194 @@ func TestSortQueuesNoGuarantees(t *testing.T) {
 		resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "vcore": 1000}),
 		resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "vcore": 1000}),
 	}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q1, q0}), "fair no gaurantees first")
 
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, true, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair no gaurantees first - priority")
 
 	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 200, "vcore": 200})
 	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300, "vcore": 300})
 
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair no gaurantees second")
 
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, true, 0, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair no limit second - priority")
 }
 
+func TestSortQueuesWithAging(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+
+	var q0, q1, q2 *Queue
+	q0, err = createManagedQueue(root, "q0", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q0.guaranteedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 500})
+	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100})
+	q0.currentPriority = 1
+
+	q1, err = createManagedQueue(root, "q1", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q1.guaranteedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 500})
+	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 250})
+	q1.currentPriority = 1
+
+	q2, err = createManagedQueue(root, "q2", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q2.guaranteedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 500})
+	q2.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 400})
+	q2.currentPriority = 2
+
+	fairMaxResources := []*resources.Resource{nil, nil, nil}
+	// fairness ratios: q0:100/500=0.2, q1:250/500=0.5, q2:400/500=0.8: nothing waiting so same order as fair
+	queues := []*Queue{q2, q1, q0}
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, time.Minute, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q1, q2}), "aging nothing waiting")
+
+	// q2 waited a full period: full credit moves it to the front
+	// q1 waited half a period: 0.5 - 0.5 credit moves it in front of q0
+	now := time.Now()
+	q2.pendingSince = now.Add(-time.Minute)
+	q1.pendingSince = now.Add(-30 * time.Second)
+	queues = []*Queue{q0, q1, q2}
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, time.Minute, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q2, q1, q0}), "aging with waiting queues")
+
+	// aging disabled: waiting time is ignored
+	queues = []*Queue{q2, q1, q0}
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, false, 0, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q1, q2}), "aging disabled")
+
+	// priority first: q2 has the highest priority, aging only orders q0 and q1
+	q2.pendingSince = time.Time{}
+	queues = []*Queue{q0, q1, q2}
+	sortQueue(queues, fairMaxResources, nil, policies.FairSortPolicy, true, time.Minute, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q2, q1, q0}), "aging with priority")
+}
+
+func TestSortQueuesDRF(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+
+	var q0, q1, q2 *Queue
+	q0, err = createManagedQueue(root, "q0", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 400, "gpu": 1})
+	q0.currentPriority = 1
+
+	q1, err = createManagedQueue(root, "q1", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100, "gpu": 2})
+	q1.currentPriority = 2
+
+	q2, err = createManagedQueue(root, "q2", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q2.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300})
+	q2.currentPriority = 1
+
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "gpu": 4})
+	fairMaxResources := []*resources.Resource{nil, nil, nil}
+	// dominant shares: q0: max(0.4, 0.25)=0.4, q1: max(0.1, 0.5)=0.5, q2: 0.3
+	queues := []*Queue{q0, q1, q2}
+	sortQueue(queues, fairMaxResources, nil, policies.DrfSortPolicy, false, 0, capacity)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q2, q0, q1}), "drf")
+
+	queues = []*Queue{q0, q1, q2}
+	sortQueue(queues, fairMaxResources, nil, policies.DrfSortPolicy, true, 0, capacity)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q1, q2, q0}), "drf - priority")
+
+	// weights: q1 with weight 2 gets a share of 0.25
+	q1.drfWeight = 2
+	queues = []*Queue{q0, q1, q2}
+	sortQueue(queues, fairMaxResources, nil, policies.DrfSortPolicy, false, 0, capacity)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q1, q2, q0}), "drf - weighted")
+
+	// aging: q0 waited a full period and moves to the front, waiting time is ignored without an aging period
+	q0.pendingSince = time.Now().Add(-time.Minute)
+	queues = []*Queue{q0, q1, q2}
+	sortQueue(queues, fairMaxResources, nil, policies.DrfSortPolicy, false, 0, capacity)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q1, q2, q0}), "drf - aging disabled")
+	queues = []*Queue{q0, q1, q2}
+	sortQueue(queues, fairMaxResources, nil, policies.DrfSortPolicy, false, time.Minute, capacity)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q1, q2}), "drf - aging")
+}
+
+func TestSortAppsDRF(t *testing.T) {
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "gpu": 4})
+	pending := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1})
+	input := make(map[string]*Application, 3)
+	for i, used := range []map[string]resources.Quantity{
+		{"memory": 400, "gpu": 1},
+		{"memory": 100, "gpu": 2},
+		{"memory": 300},
+	} {
+		num := strconv.Itoa(i)
+		appID := "app-" + num
+		app := newApplication(appID, "partition", "queue")
+		app.pending = pending
+		app.allocatedResource = resources.NewResourceFromMap(used)
+		input[appID] = app
+	}
+	// dominant shares: app-0: 0.4, app-1: 0.5, app-2: 0.3
+	list := sortApplications(input, policies.DrfSortPolicy, false, capacity, 0)
+	assertAppListLength(t, list, []string{"app-2", "app-0", "app-1"}, "drf")
+
+	input["app-1"].askMaxPriority = 5
+	list = sortApplications(input, policies.DrfSortPolicy, true, capacity, 0)
+	assertAppListLength(t, list, []string{"app-1", "app-2", "app-0"}, "drf - priority")
+}
+
+func TestSortAppsSJF(t *testing.T) {
+	pending := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1})
+	input := make(map[string]*Application, 4)
+	now := time.Now()
+	for i, estimate := range []string{"", "1h", "10m", "1h"} {
+		num := strconv.Itoa(i)
+		appID := "app-" + num
+		tags := map[string]string{}
+		if estimate != "" {
+			tags[common.AppTagRuntimeEstimate] = estimate
+		}
+		app := newApplicationWithTags(appID, "partition", "queue", tags)
+		app.pending = pending
+		app.SubmissionTime = now.Add(time.Duration(i) * time.Second)
+		input[appID] = app
+	}
+	// shortest first, no estimate last, equal estimates in submission order
+	list := sortApplications(input, policies.SjfSortPolicy, false, nil, 0)
+	assertAppListLength(t, list, []string{"app-2", "app-1", "app-3", "app-0"}, "sjf")
+
+	input["app-0"].askMaxPriority = 5
+	list = sortApplications(input, policies.SjfSortPolicy, true, nil, 0)
+	assertAppListLength(t, list, []string{"app-0", "app-2", "app-1", "app-3"}, "sjf - priority")
+}
+
+func TestSortAppsEDF(t *testing.T) {
+	pending := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1})
+	input := make(map[string]*Application, 4)
+	now := time.Now()
+	for i, deadline := range []time.Duration{0, 2 * time.Hour, time.Hour, 2 * time.Hour} {
+		num := strconv.Itoa(i)
+		appID := "app-" + num
+		tags := map[string]string{}
+		if deadline != 0 {
+			tags[common.AppTagDeadline] = now.Add(deadline).Format(time.RFC3339)
+		}
+		app := newApplicationWithTags(appID, "partition", "queue", tags)
+		app.pending = pending
+		app.SubmissionTime = now.Add(time.Duration(i) * time.Second)
+		input[appID] = app
+	}
+	// earliest first, no deadline last, equal deadlines in submission order
+	list := sortApplications(input, policies.EdfSortPolicy, false, nil, 0)
+	assertAppListLength(t, list, []string{"app-2", "app-1", "app-3", "app-0"}, "edf")
+
+	input["app-0"].askMaxPriority = 5
+	list = sortApplications(input, policies.EdfSortPolicy, true, nil, 0)
+	assertAppListLength(t, list, []string{"app-0", "app-2", "app-1", "app-3"}, "edf - priority")
+}
+
 func TestSortAppsNoPending(t *testing.T) {
 	var list []*Application
 
@@ -188,27 +362,27 @@ func TestSortAppsNoPending(t *testing.T) {
 	}
 
 	// no apps with pending resources should come back empty
-	list = sortApplications(input, policies.FairSortPolicy, false, nil)
+	list = sortApplications(input, policies.FairSortPolicy, false, nil, 0)
 	assertAppListLength(t, list, []string{}, "fair no pending")
-	list = sortApplications(input, policies.FairSortPolicy, true, nil)
+	list = sortApplications(input, policies.FairSortPolicy, true, nil, 0)
 	assertAppListLength(t, list, []string{}, "fair no pending - priority")
 
-	list = sortApplications(input, policies.FifoSortPolicy, false, nil)
+	list = sortApplications(input, policies.FifoSortPolicy, false, nil, 0)
 	assertAppListLength(t, list, []string{}, "fifo no pending")
-	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
+	list = sortApplications(input, policies.FifoSortPolicy, true, nil, 0)
 	assertAppListLength(t, list, []string{}, "fifo no pending - priority")
 
 	// set one app with pending
 	appID := "app-1"
 	input[appID].pending = res
-	list = sortApplications(input, policies.FairSortPolicy, false, nil)
+	list = sortApplications(input, policies.FairSortPolicy, false, nil, 0)
 	assertAppListLength(t, list, []string{appID}, "fair one pending")
-	list = sortApplications(input, policies.FairSortPolicy, true, nil)
+	list = sortApplications(input, policies.FairSortPolicy, true, nil, 0)
 	assertAppListLength(t, list, []string{appID}, "fair one pending - priority")
 
-	list = sortApplications(input, policies.FifoSortPolicy, false, nil)
+	list = sortApplications(input, policies.FifoSortPolicy, false, nil, 0)
 	assertAppListLength(t, list, []string{appID}, "fifo one pending")
-	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
+	list = sortApplications(input, policies.FifoSortPolicy, true, nil, 0)
 	assertAppListLength(t, list, []string{appID}, "fifo one pending - priority")
 }
 
@@ -231,14 +405,14 @@ func TestSortAppsFifo(t *testing.T) {
 	}
 
 	// fifo - apps should come back in order created 0, 1, 2, 3
-	list = sortApplications(input, policies.FifoSortPolicy, false, nil)
+	list = sortApplications(input, policies.FifoSortPolicy, false, nil, 0)
 	assertAppList(t, list, []int{0, 1, 2, 3}, "fifo simple")
 
 	input["app-1"].askMaxPriority = 3
 	input["app-3"].askMaxPriority = 5
 	input["app-2"].SubmissionTime = input["app-3"].SubmissionTime
 	input["app-1"].SubmissionTime = input["app-3"].SubmissionTime
-	list = sortApplications(input, policies.FifoSortPolicy, false, nil)
+	list = sortApplications(input, policies.FifoSortPolicy, false, nil, 0)
 	/*
 	* apps order: 0, 3, 1, 2
 	* the resultType of app index is [0, 2, 3, 1]
@@ -270,7 +444,7 @@ func TestSortAppsPriorityFifo(t *testing.T) {
 	input["app-3"].askMaxPriority = 4
 
 	// priority - apps should come back in order 1, 3, 0, 2
-	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
+	list = sortApplications(input, policies.FifoSortPolicy, true, nil, 0)
 	assertAppList(t, list, []int{2, 0, 3, 1}, "fifo simple")
 }
 
@@ -290,27 +464,27 @@ func TestSortAppsFair(t *testing.T) {
 	}
 	// nil resource: usage based sorting
 	// apps should come back in order: 0, 1, 2, 3
-	list := sortApplications(input, policies.FairSortPolicy, false, nil)
+	list := sortApplications(input, policies.FairSortPolicy, false, nil, 0)
 	assertAppList(t, list, []int{0, 1, 2, 3}, "nil total")
 
 	// apps should come back in order: 0, 1, 2, 3
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 0))
+	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 0), 0)
 	assertAppList(t, list, []int{0, 1, 2, 3}, "zero total")
 
 	// apps should come back in order: 0, 1, 2, 3
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
+	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5), 0)
 	assertAppList(t, list, []int{0, 1, 2, 3}, "no alloc, set total")
 
 	// update allocated resource for app-1
 	input["app-1"].allocatedResource = resources.Multiply(res, 10)
 	// apps should come back in order: 0, 2, 3, 1
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
+	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5), 0)
 	assertAppList(t, list, []int{0, 3, 1, 2}, "app-1 allocated")
 
 	// update allocated resource for app-3 to negative (move to head of the list)
 	input["app-3"].allocatedResource = resources.Multiply(res, -10)
 	// apps should come back in order: 3, 0, 2, 1
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
+	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5), 0)
 	assertAppList(t, list, []int{1, 3, 2, 0}, "app-1 & app-3 allocated")
 
 	// update allocated resource for app-3 & app-1 where priority of app-3 is higher
@@ -318,7 +492,7 @@ func TestSortAppsFair(t *testing.T) {
 	input["app-1"].askMaxPriority = 2
 	input["app-3"].allocatedResource = resources.Multiply(res, 10)
 	input["app-3"].askMaxPriority = 3
-	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5))
+	list = sortApplications(input, policies.FairSortPolicy, false, resources.Multiply(res, 5), 0)
 	/*
 	*  expected apps order: 0, 2, 3, 1 means
 	*  So resultType of apps indexs is [0, 3, 1, 2]
@@ -347,30 +521,108 @@ func TestSortAppsPriorityFair(t *testing.T) {
 
 	// nil resource: priority then usage based sorting
 	// apps should come back in order: 1, 0, 2, 3
-	list := sortApplications(input, policies.FairSortPolicy, true, nil)
+	list := sortApplications(input, policies.FairSortPolicy, true, nil, 0)
 	assertAppList(t, list, []int{1, 0, 2, 3}, "nil total")
 
 	// apps should come back in order: 1, 0, 2, 3
-	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 0))
+	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 0), 0)
 	assertAppList(t, list, []int{1, 0, 2, 3}, "zero total")
 
 	// apps should come back in order: 1, 0, 2, 3
-	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
+	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5), 0)
 	assertAppList(t, list, []int{1, 0, 2, 3}, "no alloc, set total")
 
 	// update allocated resource for app-2
 	input["app-2"].allocatedResource = resources.Multiply(res, 10)
 	// apps should come back in order: 1, 0, 3, 2
-	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
+	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5), 0)
 	assertAppList(t, list, []int{1, 0, 3, 2}, "app-1 allocated")
 
 	// update allocated resource for app-3 to negative (move to head of the list within priority 0)
 	input["app-3"].allocatedResource = resources.Multiply(res, -10)
 	// apps should come back in order: 1, 3, 0, 2
-	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5))
+	list = sortApplications(input, policies.FairSortPolicy, true, resources.Multiply(res, 5), 0)
 	assertAppList(t, list, []int{2, 0, 3, 1}, "app-1 & app-3 allocated")
 }
 
+func TestSortAppsFairWithAging(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{
+		"vcore": resources.Quantity(100)})
+	total := resources.Multiply(res, 10)
+	// setup to sort ascending on usage: all apps have pending resources
+	input := make(map[string]*Application, 4)
+	for i := 0; i < 4; i++ {
+		num := strconv.Itoa(i)
+		appID := "app-" + num
+		app := newApplication(appID, "partition", "queue")
+		app.allocatedResource = resources.Multiply(res, int64(i+1))
+		app.pending = res
+		input[appID] = app
+	}
+	// no waiting: same as fair
+	list := sortApplications(input, policies.FairWithAgingSortPolicy, false, total, configs.DefaultApplicationAgingPeriod)
+	assertAppList(t, list, []int{0, 1, 2, 3}, "no waiting")
+
+	// app-3 waited for the whole aging period: full credit moves it to the front
+	input["app-3"].pendingSince = time.Now().Add(-2 * configs.DefaultApplicationAgingPeriod)
+	list = sortApplications(input, policies.FairWithAgingSortPolicy, false, total, configs.DefaultApplicationAgingPeriod)
+	assertAppList(t, list, []int{1, 2, 3, 0}, "app-3 full aging credit")
+
+	// app-2 waited for half the period: share 0.3 - 0.5 credit is lower than app-0 with 0.1
+	input["app-2"].pendingSince = time.Now().Add(-configs.DefaultApplicationAgingPeriod / 2)
+	list = sortApplications(input, policies.FairWithAgingSortPolicy, false, total, configs.DefaultApplicationAgingPeriod)
+	assertAppList(t, list, []int{2, 3, 1, 0}, "app-2 partial aging credit")
+
+	// equal scores fall back to priority
+	input["app-2"].pendingSince = time.Time{}
+	input["app-3"].pendingSince = time.Time{}
+	input["app-1"].allocatedResource = res
+	input["app-1"].askMaxPriority = 1
+	list = sortApplications(input, policies.FairWithAgingSortPolicy, false, total, configs.DefaultApplicationAgingPeriod)
+	assertAppList(t, list, []int{1, 0, 2, 3}, "equal score, app-1 high priority")
+
+	// the credit depends on the aging period of the queue: app-3 waited for a minute
+	input["app-3"].pendingSince = time.Now().Add(-time.Minute)
+	list = sortApplications(input, policies.FairWithAgingSortPolicy, false, total, configs.DefaultApplicationAgingPeriod)
+	assertAppList(t, list, []int{1, 0, 3, 2}, "app-3 partial aging credit default period")
+	list = sortApplications(input, policies.FairWithAgingSortPolicy, false, total, 2*time.Minute)
+	assertAppList(t, list, []int{2, 1, 3, 0}, "app-3 partial aging credit short period")
+
+	// equal scores are compared on the usage ratio before the priority
+	input["app-3"].pendingSince = time.Time{}
+	input["app-1"].askMaxPriority = 0
+	input["app-0"].allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{
+		"vcore": resources.Quantity(100), "memory": resources.Quantity(100)})
+	total = resources.NewResourceFromMap(map[string]resources.Quantity{
+		"vcore": resources.Quantity(1000), "memory": resources.Quantity(1000)})
+	list = sortApplications(input, policies.FairWithAgingSortPolicy, false, total, configs.DefaultApplicationAgingPeriod)
+	assertAppList(t, list, []int{1, 0, 2, 3}, "equal score, app-0 higher usage ratio")
+}
+
+func TestSortAppsPriorityFairWithAging(t *testing.T) {
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{
+		"vcore": resources.Quantity(100)})
+	total := resources.Multiply(res, 10)
+	input := make(map[string]*Application, 4)
+	for i := 0; i < 4; i++ {
+		num := strconv.Itoa(i)
+		appID := "app-" + num
+		app := newApplication(appID, "partition", "queue")
+		app.allocatedResource = resources.Multiply(res, int64(i+1))
+		app.pending = res
+		input[appID] = app
+	}
+	input["app-1"].askMaxPriority = 5
+	// priority first then usage
+	list := sortApplications(input, policies.FairWithAgingSortPolicy, true, total, configs.DefaultApplicationAgingPeriod)
+	assertAppList(t, list, []int{1, 0, 2, 3}, "no waiting")
+
+	// aging does not overrule priority
+	input["app-3"].pendingSince = time.Now().Add(-2 * configs.DefaultApplicationAgingPeriod)
+	list = sortApplications(input, policies.FairWithAgingSortPolicy, true, total, configs.DefaultApplicationAgingPeriod)
+	assertAppList(t, list, []int{2, 0, 3, 1}, "app-3 full aging credit")
+}
+
 func queueNames(list []*Queue) string {
 	result := make([]string, 0)
 	for _, v := range list {
@@ -414,6 +666,6 @@ func TestSortBySubmissionTime(t *testing.T) {
 		input[appID] = app
 	}
 
-	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
+	list = sortApplications(input, policies.FifoSortPolicy, true, nil, 0)
 	assertAppList(t, list, []int{3, 2, 1, 0}, "sort by submission time")
 }
@@ -54,6 +54,7 @@ type PartitionContext struct {
 	applications           map[string]*objects.Application // applications assigned to this partition
 	completedApplications  map[string]*objects.Application // completed applications from this partition
 	rejectedApplications   map[string]*objects.Application // rejected applications from this partition
+	expiredCompletedApps   map[string]bool                 // IDs of the completed applications removed after expiry, satisfy dependencies
 	nodes                  objects.NodeCollection          // nodes assigned to this partition
 	placementManager       *placement.AppPlacementManager  // placement manager for this partition
 	partitionManager       *partitionManager               // manager for this partition
@@ -65,7 +66,15 @@ type PartitionContext struct {
 	reservations           int                             // number of reservations
 	placeholderAllocations int                             // number of placeholder allocations
 	preemptionEnabled      bool                            // whether preemption is enabled or not
+	starvationEnabled      bool                            // whether starvation detection is enabled or not
+	starvationThreshold    time.Duration                   // wait time of the oldest pending ask before an application is starved
+	starvationBoost        int32                           // priority boost for starved applications
+	reservationTimeout     time.Duration                   // time after which a node reservation is removed, 0 means no timeout
+	reservationCooldown    time.Duration                   // wait time before an ask can reserve again after a timeout
+	maxReservations        int                             // maximum number of node reservations, 0 means no limit
 	foreignAllocs          map[string]*objects.Allocation  // foreign (non-Yunikorn) allocations
+	preemptionHistory      *objects.PreemptionHistory      // history of the preemption decisions
+	advanceReservations    *objects.AdvanceReservations    // capacity booked for queues in a time window
 
 	// The partition write lock must not be held while manipulating an application.
 	// Scheduling is running continuously as a lock free background task. Scheduling an application
@@ -96,6 +105,8 @@ func newPartitionContext(conf configs.PartitionConfig, rmID string, cc *ClusterC
 		completedApplications: make(map[string]*objects.Application),
 		nodes:                 objects.NewNodeCollection(conf.Name),
 		foreignAllocs:         make(map[string]*objects.Allocation),
+		preemptionHistory:     objects.NewPreemptionHistory(objects.DefaultPreemptionHistorySize),
+		advanceReservations:   objects.NewAdvanceReservations(),
 	}
 	pc.partitionManager = newPartitionManager(pc, cc)
 	if err := pc.initialPartitionFromConfig(conf, silence); err != nil {
@@ -119,6 +130,8 @@ func (pc *PartitionContext) initialPartitionFromConfig(conf configs.PartitionCon
 	if pc.root, err = objects.NewConfiguredQueue(queueConf, nil, silence); err != nil {
 		return err
 	}
+	pc.root.SetPreemptionHistory(pc.preemptionHistory)
+	pc.root.SetAdvanceReservations(pc.advanceReservations)
 	// recursively add the queues to the root
 	if err = pc.addQueue(queueConf.Queues, pc.root, silence); err != nil {
 		return err
@@ -137,6 +150,9 @@ func (pc *PartitionContext) initialPartitionFromConfig(conf configs.PartitionCon
 	pc.userGroupCache = security.GetUserGroupCache("")
 	pc.updateNodeSortingPolicy(conf, silence)
 	pc.updatePreemption(conf)
+	pc.updateStarvation(conf)
+	pc.updateAdvanceReservations(conf)
+	pc.updateNodeReservation(conf)
 
 	// update limit settings: start at the root
 	if !silence {
@@ -158,7 +174,8 @@ func (pc *PartitionContext) updateNodeSortingPolicy(conf configs.PartitionConfig
 		log.Log(log.SchedPartition).Info("NodeSorting policy set from config",
 			zap.Stringer("policyName", configuredPolicy))
 	}
-	pc.nodes.SetNodeSortingPolicy(objects.NewNodeSortingPolicy(conf.NodeSortPolicy.Type, conf.NodeSortPolicy.ResourceWeights))
+	pc.nodes.SetNodeSortingPolicy(objects.NewNodeSortingPolicyFromConf(conf.NodeSortPolicy))
+	pc.nodes.SetNodeTopology(objects.NewNodeTopology(conf.NodeSortPolicy.Topology))
 }
 
 // NOTE: this is a lock free call. It should only be called holding the PartitionContext lock.
@@ -166,6 +183,36 @@ func (pc *PartitionContext) updatePreemption(conf configs.PartitionConfig) {
 	pc.preemptionEnabled = conf.Preemption.Enabled == nil || *conf.Preemption.Enabled
 }
 
+// NOTE: this is a lock free call. It should only be called holding the PartitionContext lock.
+func (pc *PartitionContext) updateStarvation(conf configs.PartitionConfig) {
+	pc.starvationEnabled = conf.Starvation.Enabled != nil && *conf.Starvation.Enabled
+	pc.starvationThreshold = configs.GetStarvationThreshold(conf.Starvation)
+	pc.starvationBoost = configs.GetStarvationPriorityBoost(conf.Starvation)
+}
+
+// NOTE: this is a lock free call. It should only be called holding the PartitionContext lock.
+func (pc *PartitionContext) updateNodeReservation(conf configs.PartitionConfig) {
+	pc.reservationTimeout = configs.GetNodeReservationTimeout(conf.NodeReservation)
+	pc.reservationCooldown = configs.GetNodeReservationCooldown(conf.NodeReservation)
+	pc.maxReservations = configs.GetNodeReservationMax(conf.NodeReservation)
+}
+
+// NOTE: this is a lock free call. It should only be called holding the PartitionContext lock.
+func (pc *PartitionContext) updateAdvanceReservations(conf configs.PartitionConfig) {
+	reservations := make([]*objects.AdvanceReservation, 0, len(conf.Reservations))
+	for _, reservationConf := range conf.Reservations {
+		reservation, err := objects.NewAdvanceReservation(reservationConf, true)
+		if err != nil {
+			log.Log(log.SchedPartition).Warn("advance reservation skipped",
+				zap.String("name", reservationConf.Name),
+				zap.Error(err))
+			continue
+		}
+		reservations = append(reservations, reservation)
+	}
+	pc.advanceReservations.SetConfigured(reservations)
+}
+
 func (pc *PartitionContext) updatePartitionDetails(conf configs.PartitionConfig) error {
 	// the following piece of code (before pc.Lock()) must be performed without locking
 	// to avoid lock order differences between PartitionContext and AppPlacementManager
@@ -183,6 +230,9 @@ func (pc *PartitionContext) updatePartitionDetails(conf configs.PartitionConfig)
 	pc.Lock()
 	defer pc.Unlock()
 	pc.updatePreemption(conf)
+	pc.updateStarvation(conf)
+	pc.updateAdvanceReservations(conf)
+	pc.updateNodeReservation(conf)
 	// start at the root: there is only one queue
 	queueConf := conf.Queues[0]
 	root := pc.root
@@ -314,6 +364,9 @@ func (pc *PartitionContext) AddApplication(app *objects.Application) error {
 	if pc.getApplication(appID) != nil {
 		return fmt.Errorf("adding application %s to partition %s, but application already existed", appID, pc.Name)
 	}
+	if err := pc.checkDependencies(app); err != nil {
+		return err
+	}
 
 	// Resolve the queue for this app using the placement rules
 	// We either have an error or a queue name is set on the application.
@@ -427,6 +480,16 @@ func (pc *PartitionContext) removeApplication(appID string) []*objects.Allocatio
 			}
 		}
 	}
+	// the application does not progress after the removal: dependents only continue if it finished its work
+	state := app.CurrentState()
+	switch state {
+	case objects.Completing.String():
+		state = objects.Completed.String()
+	case objects.Completed.String(), objects.Rejected.String():
+	default:
+		state = objects.Failed.String()
+	}
+	pc.notifyDependents(appID, state)
 	return allocations
 }
 
@@ -456,6 +519,150 @@ func (pc *PartitionContext) getApplication(appID string) *objects.Application {
 	return pc.applications[appID]
 }
 
+// MoveApplication moves the application with all its asks and allocations to another leaf queue. The target queue
+// must exist, queues are not created for the move. If dryRun is set the move is only checked.
+func (pc *PartitionContext) MoveApplication(appID, queuePath string, dryRun bool) error {
+	app := pc.getApplication(appID)
+	if app == nil {
+		return fmt.Errorf("application %s not found in partition %s", appID, pc.Name)
+	}
+	queue := pc.GetQueue(queuePath)
+	if queue == nil {
+		return fmt.Errorf("queue %s not found in partition %s", queuePath, pc.Name)
+	}
+	return app.MoveQueue(queue, dryRun)
+}
+
+// SuspendApplication stops the scheduling of the application. If release is set the allocations of the application
+// are released back to the RM.
+func (pc *PartitionContext) SuspendApplication(appID string, release bool, reason string) error {
+	app := pc.getApplication(appID)
+	if app == nil {
+		return fmt.Errorf("application %s not found in partition %s", appID, pc.Name)
+	}
+	num, err := app.Suspend(release, reason)
+	if err != nil {
+		return err
+	}
+	pc.decReservationCount(num)
+	return nil
+}
+
+// ResumeApplication puts a suspended application back in its queue for scheduling.
+func (pc *PartitionContext) ResumeApplication(appID string) error {
+	app := pc.getApplication(appID)
+	if app == nil {
+		return fmt.Errorf("application %s not found in partition %s", appID, pc.Name)
+	}
+	return app.Resume()
+}
+
+// checkDependencies records the state of the upstream applications that already completed, failed or were rejected.
+// An upstream application that is not known in the partition keeps the application waiting, it might still be
+// submitted. Returns an error if an upstream application failed or was rejected, or if the application is an upstream
+// application of one of its waiting dependencies: the application will never be able to run.
+func (pc *PartitionContext) checkDependencies(app *objects.Application) error {
+	ids := app.GetDependencyIDs()
+	states := make([]string, len(ids))
+	var waiting []string
+	for i, id := range ids {
+		states[i] = pc.getApplicationState(id)
+		switch states[i] {
+		case objects.Completed.String():
+		case objects.Failed.String(), objects.Rejected.String():
+			return fmt.Errorf("dependency %s of application %s is %s", id, app.ApplicationID, strings.ToLower(states[i]))
+		default:
+			waiting = append(waiting, id)
+		}
+	}
+	if pc.isUpstreamApplication(app.ApplicationID, waiting) {
+		return fmt.Errorf("dependencies of application %s form a cycle", app.ApplicationID)
+	}
+	for i, id := range ids {
+		app.UpdateDependency(id, states[i])
+	}
+	return nil
+}
+
+// isUpstreamApplication returns true if the application with the ID is a direct or indirect dependency of one of the
+// applications with the IDs. Only the dependencies that are still waiting are followed.
+func (pc *PartitionContext) isUpstreamApplication(appID string, ids []string) bool {
+	visited := make(map[string]bool)
+	for len(ids) > 0 {
+		id := ids[0]
+		ids = ids[1:]
+		if id == appID {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if upstream := pc.getApplication(id); upstream != nil {
+			ids = append(ids, upstream.GetWaitingDependencyIDs()...)
+		}
+	}
+	return false
+}
+
+// getApplicationState returns the state of the application with the ID from the active, rejected or completed
+// applications. An expired application returns the state it had before it expired, a completed application that was
+// removed after expiry returns the Completed state. Returns an empty string if the application is not known in the
+// partition.
+func (pc *PartitionContext) getApplicationState(appID string) string {
+	app, expiredCompleted := pc.findApplication(appID)
+	if app != nil {
+		return getTerminatedState(app)
+	}
+	if expiredCompleted {
+		return objects.Completed.String()
+	}
+	return ""
+}
+
+// findApplication returns the application with the ID from the active, rejected or completed applications. If the
+// application is not found the second return value is true if it completed and was removed after expiry.
+func (pc *PartitionContext) findApplication(appID string) (*objects.Application, bool) {
+	pc.RLock()
+	defer pc.RUnlock()
+	if app, ok := pc.applications[appID]; ok {
+		return app, false
+	}
+	if app, ok := pc.rejectedApplications[appID]; ok {
+		return app, false
+	}
+	for _, app := range pc.completedApplications {
+		if app.ApplicationID == appID {
+			return app, false
+		}
+	}
+	return nil, pc.expiredCompletedApps[appID]
+}
+
+// getTerminatedState returns the current state of the application. For an expired application the state before
+// the expiry is returned.
+func getTerminatedState(app *objects.Application) string {
+	state := app.CurrentState()
+	if state != objects.Expired.String() {
+		return state
+	}
+	stateLog := app.GetStateLog()
+	for i := len(stateLog) - 1; i >= 0; i-- {
+		if stateLog[i].ApplicationState != objects.Expired.String() {
+			return stateLog[i].ApplicationState
+		}
+	}
+	return state
+}
+
+// notifyDependents updates the dependency state on all applications that depend on the application with the ID.
+// NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
+func (pc *PartitionContext) notifyDependents(appID, state string) {
+	for _, app := range pc.GetApplications() {
+		app.UpdateDependency(appID, state)
+	}
+}
+
 func (pc *PartitionContext) getRejectedApplication(appID string) *objects.Application {
 	pc.RLock()
 	defer pc.RUnlock()
@@ -808,15 +1015,6 @@ func (pc *PartitionContext) calculateOutstandingRequests() []*objects.Allocation
 // Try regular allocation for the partition
 // Lock free call this all locks are taken when needed in called functions
 func (pc *PartitionContext) tryAllocate() *objects.AllocationResult {
-	// 更新所有應用程式的等待時間
-	// for _, app := range pc.applications {
-	// 	app.UpdateWaitingTime()
-	// }
-	pc.GetNodeIterator().ForEachNode(func(node *objects.Node) bool {
-		node.UpdateWaitingTime()
-		return true
-	})
-
 	if !resources.StrictlyGreaterThanZero(pc.root.GetPendingResource()) {
 		// nothing to do just return
 		return nil
@@ -985,6 +1183,14 @@ func (pc *PartitionContext) reserve(app *objects.Application, node *objects.Node
 			zap.String("new nodeID", node.NodeID))
 		pc.unReserve(app, pc.nodes.GetNode(nodeID), ask)
 	}
+	// asks that require a node are not limited: they cannot use another node
+	if maxReservations := pc.getMaxReservations(); maxReservations > 0 && ask.GetRequiredNode() == "" && pc.getReservationCount() >= maxReservations {
+		log.Log(log.SchedPartition).Debug("reservation skipped, partition reservation limit reached",
+			zap.String("appID", appID),
+			zap.String("allocationKey", ask.GetAllocationKey()),
+			zap.Int("maxReservations", maxReservations))
+		return
+	}
 	// all ok, add the reservation to the app, this will also reserve the node
 	if err := app.Reserve(node, ask); err != nil {
 		log.Log(log.SchedPartition).Debug("Failed to handle reservation, error during update of app",
@@ -1021,6 +1227,62 @@ func (pc *PartitionContext) unReserve(app *objects.Application, node *objects.No
 		zap.Int("reservationsRemoved", num))
 }
 
+// updateQueueSchedules applies the queue schedules that start or end at the given time to the queues.
+// NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
+func (pc *PartitionContext) updateQueueSchedules(now time.Time) {
+	pc.root.UpdateSchedules(now)
+}
+
+// updateBorrowing updates the guarantee borrowed between sibling queues and marks the lenders for which the reclaim
+// deadline of a borrower has passed.
+// NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
+func (pc *PartitionContext) updateBorrowing(now time.Time) {
+	pc.root.UpdateBorrowing(now)
+}
+
+// failExpiredApplications fails the applications that passed the maximum lifetime from their quota, or waited on
+// their dependencies longer than the dependency timeout.
+// NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
+func (pc *PartitionContext) failExpiredApplications(now time.Time) {
+	for _, app := range pc.GetApplications() {
+		if num := app.CheckLifetime(now); num > 0 {
+			pc.decReservationCount(num)
+		}
+		app.CheckDependencyTimeout(now)
+	}
+}
+
+// removeExpiredReservations removes the node reservations that are older than the reservation timeout. The ask of
+// a removed reservation cannot reserve a node again until the cooldown has passed. Reservations for asks that
+// require a node do not time out: the ask cannot use another node.
+// NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
+func (pc *PartitionContext) removeExpiredReservations() {
+	timeout, cooldown := pc.getReservationTimeout()
+	if timeout <= 0 || pc.getReservationCount() == 0 {
+		return
+	}
+	now := time.Now()
+	for _, node := range pc.GetNodes() {
+		for _, reserve := range node.GetReservations() {
+			age := now.Sub(reserve.GetCreateTime())
+			if age < timeout {
+				continue
+			}
+			reservedNode, app, ask := reserve.GetObjects()
+			if ask.GetRequiredNode() != "" {
+				continue
+			}
+			log.Log(log.SchedPartition).Info("node reservation timed out",
+				zap.String("appID", app.ApplicationID),
+				zap.String("allocationKey", ask.GetAllocationKey()),
+				zap.String("node", reservedNode.NodeID),
+				zap.Stringer("age", age))
+			pc.unReserve(app, reservedNode, ask)
+			ask.BlockReservation(now.Add(cooldown))
+		}
+	}
+}
+
 // Create an ordered node iterator based on the node sort policy set for this partition.
 // The iterator is nil if there are no unreserved nodes available.
 func (pc *PartitionContext) GetNodeIterator() objects.NodeIterator {
@@ -1138,7 +1400,18 @@ func (pc *PartitionContext) cleanupExpiredApps() {
 		pc.Unlock()
 	}
 	for _, appID := range pc.getCompletedAppsByState(objects.Expired.String()) {
+		pc.RLock()
+		app := pc.completedApplications[appID]
+		pc.RUnlock()
+		// remember the completed applications: they still satisfy the dependencies of new applications
+		completed := app != nil && getTerminatedState(app) == objects.Completed.String()
 		pc.Lock()
+		if completed {
+			if pc.expiredCompletedApps == nil {
+				pc.expiredCompletedApps = make(map[string]bool)
+			}
+			pc.expiredCompletedApps[app.ApplicationID] = true
+		}
 		delete(pc.completedApplications, appID)
 		pc.Unlock()
 	}
@@ -1638,12 +1911,92 @@ func (pc *PartitionContext) GetNodeSortingResourceWeights() map[string]float64 {
 	return policy.ResourceWeights()
 }
 
+// GetNodeSortingAgingDAOInfo returns the aging settings of the node sorting policy.
+// Returns nil if the policy does not use aging.
+func (pc *PartitionContext) GetNodeSortingAgingDAOInfo() *dao.NodeSortingAging {
+	policy, ok := pc.nodes.GetNodeSortingPolicy().(objects.AgingNodeSortingPolicy)
+	if !ok {
+		return nil
+	}
+	aging := policy.AgingSettings()
+	info := &dao.NodeSortingAging{
+		Weight: aging.Weight,
+		Cap:    aging.Cap,
+		Curve:  aging.Curve.String(),
+	}
+	if aging.Curve == policies.ExponentialAgingCurve {
+		info.HalfLife = aging.HalfLife.String()
+	} else {
+		info.Period = aging.Period.String()
+	}
+	return info
+}
+
+// GetNodeTopologyDAOInfo returns the topology settings of the nodes.
+// Returns nil if no topology is set.
+func (pc *PartitionContext) GetNodeTopologyDAOInfo() *dao.NodeSortingTopology {
+	topology := pc.nodes.GetNodeTopology()
+	if topology == nil {
+		return nil
+	}
+	return &dao.NodeSortingTopology{
+		Keys:    topology.Keys,
+		Mode:    topology.Mode.String(),
+		MaxSkew: topology.MaxSkew,
+	}
+}
+
+// GetNodeSortingScorers returns the names and weights of the scorers used by the node sorting policy.
+// Returns nil if the policy is one of the built-in policies.
+func (pc *PartitionContext) GetNodeSortingScorers() map[string]float64 {
+	policy, ok := pc.nodes.GetNodeSortingPolicy().(objects.ScorerNodeSortingPolicy)
+	if !ok {
+		return nil
+	}
+	return policy.Scorers()
+}
+
+// GetPreemptionHistory returns the history of the preemption decisions made in the partition.
+func (pc *PartitionContext) GetPreemptionHistory() *objects.PreemptionHistory {
+	pc.RLock()
+	defer pc.RUnlock()
+	return pc.preemptionHistory
+}
+
+// GetAdvanceReservations returns the advance reservations that book capacity in the partition.
+func (pc *PartitionContext) GetAdvanceReservations() *objects.AdvanceReservations {
+	pc.RLock()
+	defer pc.RUnlock()
+	return pc.advanceReservations
+}
+
 func (pc *PartitionContext) IsPreemptionEnabled() bool {
 	pc.RLock()
 	defer pc.RUnlock()
 	return pc.preemptionEnabled
 }
 
+// getReservationTimeout returns the node reservation timeout and the cooldown after a reservation timed out.
+func (pc *PartitionContext) getReservationTimeout() (time.Duration, time.Duration) {
+	pc.RLock()
+	defer pc.RUnlock()
+	return pc.reservationTimeout, pc.reservationCooldown
+}
+
+// getMaxReservations returns the maximum number of node reservations in the partition, 0 means no limit.
+func (pc *PartitionContext) getMaxReservations() int {
+	pc.RLock()
+	defer pc.RUnlock()
+	return pc.maxReservations
+}
+
+// getStarvationSettings returns the starvation detection settings: enabled flag, threshold and priority boost.
+func (pc *PartitionContext) getStarvationSettings() (bool, time.Duration, int32) {
+	pc.RLock()
+	defer pc.RUnlock()
+	return pc.starvationEnabled, pc.starvationThreshold, pc.starvationBoost
+}
+
 func (pc *PartitionContext) moveTerminatedApp(appID string) {
 	app := pc.getApplication(appID)
 	// nothing to do if the app is not found on the partition
@@ -1660,9 +2013,10 @@ func (pc *PartitionContext) moveTerminatedApp(appID string) {
 		zap.String("app status", app.CurrentState()))
 	app.LogAppSummary(pc.RmID)
 	pc.Lock()
-	defer pc.Unlock()
 	delete(pc.applications, appID)
 	pc.completedApplications[newID] = app
+	pc.Unlock()
+	pc.notifyDependents(appID, app.CurrentState())
 }
 
 func (pc *PartitionContext) AddRejectedApplication(rejectedApplication *objects.Application, rejectedMessage string) {
@@ -1675,6 +2029,7 @@ func (pc *PartitionContext) AddRejectedApplication(rejectedApplication *objects.
 		pc.rejectedApplications = make(map[string]*objects.Application)
 	}
 	pc.rejectedApplications[rejectedApplication.ApplicationID] = rejectedApplication
+	pc.notifyDependents(rejectedApplication.ApplicationID, objects.Rejected.String())
 }
 
 func (pc *PartitionContext) incPhAllocationCount() {
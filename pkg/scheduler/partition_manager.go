@@ -28,36 +28,63 @@ import (
 )
 
 const (
-	DefaultCleanRootInterval        = 10000 * time.Millisecond // sleep between queue removal checks
-	DefaultCleanExpiredAppsInterval = 24 * time.Hour           // sleep between apps removal checks
+	DefaultCleanRootInterval         = 10000 * time.Millisecond // sleep between queue removal checks
+	DefaultCleanExpiredAppsInterval  = 24 * time.Hour           // sleep between apps removal checks
+	DefaultCleanReservationsInterval = time.Second              // sleep between reservation timeout checks
+	DefaultQueueSchedulesInterval    = 10 * time.Second         // sleep between queue schedule checks
+	DefaultBorrowingInterval         = 5 * time.Second          // sleep between borrow ledger updates
+	DefaultLifetimeInterval          = time.Second              // sleep between application timeout checks
 )
 
 type partitionManager struct {
-	pc                       *PartitionContext
-	cc                       *ClusterContext
-	stopCleanRoot            chan struct{}
-	stopCleanExpiredApps     chan struct{}
-	cleanRootInterval        time.Duration
-	cleanExpiredAppsInterval time.Duration
+	pc                        *PartitionContext
+	cc                        *ClusterContext
+	stopCleanRoot             chan struct{}
+	stopCleanExpiredApps      chan struct{}
+	stopCleanReservations     chan struct{}
+	stopQueueSchedules        chan struct{}
+	stopBorrowing             chan struct{}
+	stopLifetimes             chan struct{}
+	cleanRootInterval         time.Duration
+	cleanExpiredAppsInterval  time.Duration
+	cleanReservationsInterval time.Duration
+	queueSchedulesInterval    time.Duration
+	borrowingInterval         time.Duration
+	lifetimeInterval          time.Duration
+	starvationDetector        *starvationDetector
 }
 
 func newPartitionManager(pc *PartitionContext, cc *ClusterContext) *partitionManager {
 	return &partitionManager{
-		pc:                       pc,
-		cc:                       cc,
-		stopCleanRoot:            make(chan struct{}),
-		stopCleanExpiredApps:     make(chan struct{}),
-		cleanRootInterval:        DefaultCleanRootInterval,
-		cleanExpiredAppsInterval: DefaultCleanExpiredAppsInterval,
+		pc:                        pc,
+		cc:                        cc,
+		stopCleanRoot:             make(chan struct{}),
+		stopCleanExpiredApps:      make(chan struct{}),
+		stopCleanReservations:     make(chan struct{}),
+		stopQueueSchedules:        make(chan struct{}),
+		stopBorrowing:             make(chan struct{}),
+		stopLifetimes:             make(chan struct{}),
+		cleanRootInterval:         DefaultCleanRootInterval,
+		cleanExpiredAppsInterval:  DefaultCleanExpiredAppsInterval,
+		cleanReservationsInterval: DefaultCleanReservationsInterval,
+		queueSchedulesInterval:    DefaultQueueSchedulesInterval,
+		borrowingInterval:         DefaultBorrowingInterval,
+		lifetimeInterval:          DefaultLifetimeInterval,
+		starvationDetector:        newStarvationDetector(pc),
 	}
 }
 
 // Run the manager for the partition.
-// The manager has four tasks:
+// The manager has nine tasks:
 // - clean up the managed queues that are empty and removed from the configuration
 // - remove empty unmanaged queues
 // - remove completed applications from the partition
 // - remove rejected applications from the partition
+// - run the starvation detector for the applications in the partition
+// - remove node reservations that have timed out
+// - apply the queue schedules when a window starts or ends
+// - track the guarantee borrowed between sibling queues and let the lender reclaim it when the deadline has passed
+// - fail the applications that passed the maximum lifetime from their quota or the dependency timeout
 // When the manager exits the partition is removed from the system and must be cleaned up
 func (manager *partitionManager) Run() {
 	log.Log(log.SchedPartition).Info("starting partition manager",
@@ -65,6 +92,11 @@ func (manager *partitionManager) Run() {
 		zap.Stringer("cleanRootInterval", manager.cleanRootInterval))
 	go manager.cleanExpiredApps()
 	go manager.cleanRoot()
+	go manager.starvationDetector.run()
+	go manager.cleanReservations()
+	go manager.applyQueueSchedules()
+	go manager.updateBorrowing()
+	go manager.checkLifetimes()
 }
 
 func (manager *partitionManager) cleanRoot() {
@@ -94,6 +126,11 @@ func (manager *partitionManager) Stop() {
 		zap.String("partition", manager.pc.Name))
 	close(manager.stopCleanExpiredApps)
 	close(manager.stopCleanRoot)
+	close(manager.stopCleanReservations)
+	close(manager.stopQueueSchedules)
+	close(manager.stopBorrowing)
+	close(manager.stopLifetimes)
+	manager.starvationDetector.Stop()
 	manager.remove()
 }
 
@@ -110,7 +147,8 @@ func (manager *partitionManager) cleanQueues(queue *objects.Queue) {
 		}
 	}
 	// when we have done the children (or have none) this queue might be removable
-	if queue.IsDraining() || !queue.IsManaged() {
+	// a queue drained by an operator is kept
+	if (queue.IsDraining() && !queue.IsAdminState()) || !queue.IsManaged() {
 		log.Log(log.SchedPartition).Debug("removing queue",
 			zap.String("queueName", queue.QueuePath),
 			zap.String("partitionName", manager.pc.Name))
@@ -183,3 +221,67 @@ func (manager *partitionManager) cleanExpiredApps() {
 		}
 	}
 }
+
+func (manager *partitionManager) cleanReservations() {
+	log.Log(log.SchedPartition).Info("Starting partition reservation cleaner")
+	for {
+		cleanReservationsInterval := manager.cleanReservationsInterval
+		if cleanReservationsInterval <= 0 {
+			cleanReservationsInterval = DefaultCleanReservationsInterval
+		}
+		select {
+		case <-manager.stopCleanReservations:
+			return
+		case <-time.After(cleanReservationsInterval):
+			manager.pc.removeExpiredReservations()
+		}
+	}
+}
+
+func (manager *partitionManager) applyQueueSchedules() {
+	log.Log(log.SchedPartition).Info("Starting partition queue schedule updater")
+	for {
+		queueSchedulesInterval := manager.queueSchedulesInterval
+		if queueSchedulesInterval <= 0 {
+			queueSchedulesInterval = DefaultQueueSchedulesInterval
+		}
+		select {
+		case <-manager.stopQueueSchedules:
+			return
+		case <-time.After(queueSchedulesInterval):
+			manager.pc.updateQueueSchedules(time.Now())
+		}
+	}
+}
+
+func (manager *partitionManager) updateBorrowing() {
+	log.Log(log.SchedPartition).Info("Starting partition borrow ledger updater")
+	for {
+		borrowingInterval := manager.borrowingInterval
+		if borrowingInterval <= 0 {
+			borrowingInterval = DefaultBorrowingInterval
+		}
+		select {
+		case <-manager.stopBorrowing:
+			return
+		case <-time.After(borrowingInterval):
+			manager.pc.updateBorrowing(time.Now())
+		}
+	}
+}
+
+func (manager *partitionManager) checkLifetimes() {
+	log.Log(log.SchedPartition).Info("Starting partition application timeout checker")
+	for {
+		lifetimeInterval := manager.lifetimeInterval
+		if lifetimeInterval <= 0 {
+			lifetimeInterval = DefaultLifetimeInterval
+		}
+		select {
+		case <-manager.stopLifetimes:
+			return
+		case <-time.After(lifetimeInterval):
+			manager.pc.failExpiredApplications(time.Now())
+		}
+	}
+}
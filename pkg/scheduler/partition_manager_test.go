@@ -56,6 +56,18 @@ func TestStopPartitionManager(t *testing.T) {
 
 	// this call should not be blocked forever
 	p.partitionManager.cleanRoot()
+
+	// this call should not be blocked forever
+	p.partitionManager.cleanReservations()
+
+	// this call should not be blocked forever
+	p.partitionManager.applyQueueSchedules()
+
+	// this call should not be blocked forever
+	p.partitionManager.updateBorrowing()
+
+	// this call should not be blocked forever
+	p.partitionManager.checkLifetimes()
 }
 
 func TestCleanQueues(t *testing.T) {
@@ -75,6 +87,22 @@ func TestCleanQueues(t *testing.T) {
 	assert.Equal(t, 0, len(p.root.GetCopyOfChildren()))
 }
 
+func TestCleanQueuesAdminDrained(t *testing.T) {
+	p := createPartitionContext(t)
+	queue, err := objects.NewConfiguredQueue(configs.QueueConfig{Name: "managed"}, p.root, false)
+	assert.NilError(t, err)
+	assert.NilError(t, queue.SetAdminState(objects.Remove, "maintenance"))
+
+	// a queue drained by an operator is not removed
+	p.partitionManager.cleanQueues(p.root)
+	assert.Equal(t, 1, len(p.root.GetCopyOfChildren()))
+
+	// removal from the config removes the drained queue
+	queue.MarkQueueForRemoval()
+	p.partitionManager.cleanQueues(p.root)
+	assert.Equal(t, 0, len(p.root.GetCopyOfChildren()))
+}
+
 func TestRemoveAll(t *testing.T) {
 	p := createPartitionContext(t)
 
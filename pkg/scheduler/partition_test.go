@@ -2784,6 +2784,70 @@ func TestCleanupRejectedApps(t *testing.T) {
 	assert.Equal(t, 0, len(partition.getRejectedAppsByState(objects.Expired.String())), "the partition should have 0 expired app")
 }
 
+func TestApplicationDependencies(t *testing.T) {
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "partition create failed")
+	upstream := newApplication(appID1, "default", defQueue)
+	upstream.SetState(objects.Completing.String())
+	assert.NilError(t, partition.AddApplication(upstream), "upstream application should have been added")
+	other := newApplication("other", "default", defQueue)
+	assert.NilError(t, partition.AddApplication(other), "upstream application should have been added")
+	app := newApplicationTags(appID2, "default", defQueue, map[string]string{common.AppTagDependencies: appID1 + ",other"})
+	assert.NilError(t, partition.AddApplication(app), "dependent application should have been added")
+	assert.DeepEqual(t, app.GetDependencies(), map[string]string{appID1: objects.DependencyWaiting, "other": objects.DependencyWaiting})
+
+	// completing the upstream application updates the dependent
+	assert.NilError(t, completeApplicationAndWait(upstream, partition), "the completed application should have been processed")
+	assert.Equal(t, app.GetDependencies()[appID1], objects.Completed.String())
+	assert.Assert(t, app.IsWaitingOnDependencies(), "application should still wait on the other dependency")
+
+	// a dependency that completed before the application was added is picked up on add
+	later := newApplicationTags(appID3, "default", defQueue, map[string]string{common.AppTagDependencies: appID1})
+	assert.NilError(t, partition.AddApplication(later), "dependent application should have been added")
+	assert.Assert(t, !later.IsWaitingOnDependencies(), "application should not wait on a completed dependency")
+
+	// an expired upstream application is satisfied, also after it is removed from the partition
+	assert.NilError(t, upstream.HandleApplicationEvent(objects.ExpireApplication))
+	expired := newApplicationTags("expired", "default", defQueue, map[string]string{common.AppTagDependencies: appID1})
+	assert.NilError(t, partition.AddApplication(expired), "dependent application should have been added")
+	assert.Assert(t, !expired.IsWaitingOnDependencies(), "application should not wait on an expired dependency")
+	partition.cleanupExpiredApps()
+	assert.Equal(t, len(partition.getCompletedAppsByState(objects.Expired.String())), 0, "expired application should be removed")
+	cleaned := newApplicationTags("cleaned", "default", defQueue, map[string]string{common.AppTagDependencies: appID1})
+	assert.NilError(t, partition.AddApplication(cleaned), "dependent application should have been added")
+	assert.Assert(t, !cleaned.IsWaitingOnDependencies(), "application should not wait on a removed completed dependency")
+
+	// an unknown dependency keeps the application waiting
+	unknown := newApplicationTags("unknown", "default", defQueue, map[string]string{common.AppTagDependencies: "missing"})
+	assert.NilError(t, partition.AddApplication(unknown), "application with an unknown dependency should have been added")
+	assert.DeepEqual(t, unknown.GetDependencies(), map[string]string{"missing": objects.DependencyWaiting})
+	assert.Assert(t, unknown.IsWaitingOnDependencies(), "application should wait on the unknown dependency")
+
+	// the waiting dependencies of an application must not lead back to the application
+	missing := newApplicationTags("missing", "default", defQueue, map[string]string{common.AppTagDependencies: "unknown"})
+	assert.ErrorContains(t, partition.AddApplication(missing), "dependencies of application missing form a cycle")
+	chained := newApplicationTags("chained", "default", defQueue, map[string]string{common.AppTagDependencies: "missing"})
+	assert.NilError(t, partition.AddApplication(chained), "application with an unknown dependency should have been added")
+	missing = newApplicationTags("missing", "default", defQueue, map[string]string{common.AppTagDependencies: "chained"})
+	assert.ErrorContains(t, partition.AddApplication(missing), "dependencies of application missing form a cycle")
+	assert.Assert(t, partition.isUpstreamApplication("other", []string{appID2}), "other is an upstream application of app-2")
+	assert.Assert(t, !partition.isUpstreamApplication(appID2, []string{"other"}), "app-2 is not an upstream application of other")
+	// completed dependencies are not followed
+	assert.Assert(t, !partition.isUpstreamApplication(appID1, []string{appID3}), "completed dependencies should not be followed")
+
+	// removing a dependency that did not complete fails the dependent application
+	partition.removeApplication("other")
+	assert.Equal(t, app.GetDependencies()["other"], objects.Failed.String())
+	assert.Assert(t, app.IsFailed(), "application should have failed: %s", app.CurrentState())
+
+	// an application with a failed or rejected dependency is not added
+	app = newApplicationTags("failed", "default", defQueue, map[string]string{common.AppTagDependencies: appID2})
+	assert.ErrorContains(t, partition.AddApplication(app), "dependency app-2 of application failed is failed")
+	partition.AddRejectedApplication(newApplication("rejected", "default", defQueue), "rejected")
+	app = newApplicationTags("dependent", "default", defQueue, map[string]string{common.AppTagDependencies: "rejected"})
+	assert.ErrorContains(t, partition.AddApplication(app), "dependency rejected of application dependent is rejected")
+}
+
 func TestUpdateNode(t *testing.T) {
 	partition, err := newBasePartition()
 	assert.NilError(t, err, "test partition create failed with error")
@@ -3791,6 +3855,134 @@ func TestUpdatePreemption(t *testing.T) {
 	assert.Assert(t, !partition.IsPreemptionEnabled(), "preeemption should be disabled by explicit false")
 }
 
+func TestUpdateAdvanceReservations(t *testing.T) {
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "Partition creation failed")
+	assert.Equal(t, len(partition.GetAdvanceReservations().GetReservations()), 0, "no reservations expected by default")
+
+	start := time.Now().Add(time.Hour)
+	reservationConf := func(name string) configs.AdvanceReservationConfig {
+		return configs.AdvanceReservationConfig{
+			Name:      name,
+			Queue:     "root.default",
+			Resources: map[string]string{"vcores": "1"},
+			Start:     start.Format(time.RFC3339),
+			End:       start.Add(time.Hour).Format(time.RFC3339),
+		}
+	}
+	runtime, err := objects.NewAdvanceReservation(reservationConf("runtime"), false)
+	assert.NilError(t, err, "advance reservation create failed")
+	assert.NilError(t, partition.GetAdvanceReservations().Add(runtime))
+
+	invalid := reservationConf("invalid")
+	invalid.End = invalid.Start
+	partition.updateAdvanceReservations(configs.PartitionConfig{Reservations: []configs.AdvanceReservationConfig{reservationConf("configured"), invalid}})
+	reservations := partition.GetAdvanceReservations().GetReservations()
+	assert.Equal(t, len(reservations), 2, "configured and runtime reservation expected")
+	assert.Equal(t, reservations[0].Name, "configured")
+	assert.Assert(t, reservations[0].FromConfig, "reservation should be from config")
+	assert.Equal(t, reservations[1].Name, "runtime")
+
+	partition.updateAdvanceReservations(configs.PartitionConfig{})
+	reservations = partition.GetAdvanceReservations().GetReservations()
+	assert.Equal(t, len(reservations), 1, "only runtime reservation expected")
+	assert.Equal(t, reservations[0].Name, "runtime")
+}
+
+func TestUpdateNodeReservation(t *testing.T) {
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "Partition creation failed")
+	timeout, cooldown := partition.getReservationTimeout()
+	assert.Equal(t, timeout, time.Duration(0), "reservations should not time out by default")
+	assert.Equal(t, cooldown, configs.DefaultNodeReservationCooldown, "unexpected default cooldown")
+	assert.Equal(t, partition.getMaxReservations(), 0, "reservations should not be limited by default")
+
+	maxReservations := 5
+	partition.updateNodeReservation(configs.PartitionConfig{NodeReservation: configs.PartitionNodeReservationConfig{
+		Timeout:         "10m",
+		Cooldown:        "30s",
+		MaxReservations: &maxReservations,
+	}})
+	timeout, cooldown = partition.getReservationTimeout()
+	assert.Equal(t, timeout, 10*time.Minute, "unexpected timeout")
+	assert.Equal(t, cooldown, 30*time.Second, "unexpected cooldown")
+	assert.Equal(t, partition.getMaxReservations(), maxReservations, "unexpected max reservations")
+}
+
+func TestMaxReservations(t *testing.T) {
+	setupUGM()
+	partition := createQueuesNodes(t)
+	assert.Assert(t, partition != nil, "partition create failed")
+	partition.maxReservations = 1
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "1"})
+	assert.NilError(t, err, "failed to create resource")
+	app := newApplication(appID1, "default", "root.parent.sub-leaf")
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	ask1 := newAllocationAsk(allocKey, appID1, res)
+	assert.NilError(t, app.AddAllocationAsk(ask1), "failed to add ask alloc-1 to app")
+	ask2 := newAllocationAsk(allocKey2, appID1, res)
+	assert.NilError(t, app.AddAllocationAsk(ask2), "failed to add ask alloc-2 to app")
+
+	partition.reserve(app, partition.GetNode(nodeID1), ask1)
+	assert.Equal(t, app.NodeReservedForAsk(allocKey), nodeID1, "reservation failure for alloc-1 and node-1")
+	partition.reserve(app, partition.GetNode(nodeID2), ask2)
+	assert.Equal(t, app.NodeReservedForAsk(allocKey2), "", "reservation limit not enforced for alloc-2")
+	assert.Equal(t, partition.getReservationCount(), 1, "unexpected reservation count")
+
+	// asks that require a node are not limited
+	ask2.SetRequiredNode(nodeID2)
+	partition.reserve(app, partition.GetNode(nodeID2), ask2)
+	assert.Equal(t, app.NodeReservedForAsk(allocKey2), nodeID2, "required node reservation should not be limited")
+	assert.Equal(t, partition.getReservationCount(), 2, "unexpected reservation count")
+}
+
+func TestRemoveExpiredReservations(t *testing.T) {
+	setupUGM()
+	partition := createQueuesNodes(t)
+	assert.Assert(t, partition != nil, "partition create failed")
+	res, err := resources.NewResourceFromConf(map[string]string{"vcore": "1"})
+	assert.NilError(t, err, "failed to create resource")
+	app := newApplication(appID1, "default", "root.parent.sub-leaf")
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app-1 to partition")
+	ask1 := newAllocationAsk(allocKey, appID1, res)
+	assert.NilError(t, app.AddAllocationAsk(ask1), "failed to add ask alloc-1 to app")
+	ask2 := newAllocationAsk(allocKey2, appID1, res)
+	ask2.SetRequiredNode(nodeID2)
+	assert.NilError(t, app.AddAllocationAsk(ask2), "failed to add ask alloc-2 to app")
+	partition.reserve(app, partition.GetNode(nodeID1), ask1)
+	partition.reserve(app, partition.GetNode(nodeID2), ask2)
+	assert.Equal(t, partition.getReservationCount(), 2, "unexpected reservation count")
+
+	// no timeout configured
+	partition.removeExpiredReservations()
+	assert.Equal(t, partition.getReservationCount(), 2, "reservations should not time out by default")
+
+	partition.reservationTimeout = time.Nanosecond
+	partition.reservationCooldown = time.Minute
+	time.Sleep(time.Millisecond)
+	partition.removeExpiredReservations()
+	assert.Equal(t, partition.getReservationCount(), 1, "unexpected reservation count")
+	assert.Equal(t, app.NodeReservedForAsk(allocKey), "", "reservation for alloc-1 should have timed out")
+	assert.Equal(t, app.NodeReservedForAsk(allocKey2), nodeID2, "required node reservation should not time out")
+	assert.Assert(t, !partition.GetNode(nodeID1).IsReserved(), "node-1 should not be reserved")
+	assert.Equal(t, len(app.GetQueue().GetReservedApps()), 1, "app should still be reserved in the queue")
+
+	// the ask of the timed out reservation cannot reserve again until the cooldown has passed
+	// all nodes are full and node-2 is still reserved: the ask can only reserve node-1
+	objects.SetReservationDelay(10 * time.Nanosecond)
+	defer objects.SetReservationDelay(2 * time.Second)
+	for _, node := range partition.GetNodes() {
+		node.SetOccupiedResource(node.GetCapacity())
+	}
+	assert.Assert(t, partition.tryAllocate() == nil, "ask should not be allocated")
+	assert.Equal(t, app.NodeReservedForAsk(allocKey), "", "ask should not reserve during the cooldown")
+	ask1.BlockReservation(time.Time{})
+	assert.Assert(t, partition.tryAllocate() == nil, "ask should not be allocated")
+	assert.Equal(t, app.NodeReservedForAsk(allocKey), nodeID1, "ask should reserve after the cooldown")
+}
+
 func TestUpdateNodeSortingPolicy(t *testing.T) {
 	partition, err := newBasePartition()
 	assert.NilError(t, err, "Partition creation failed unexpectedly")
@@ -3835,8 +4027,58 @@ func TestUpdateNodeSortingPolicy(t *testing.T) {
 			if ans != tt.want {
 				t.Errorf("got %s, want %s", ans, tt.want)
 			}
+			assert.Assert(t, partition.GetNodeSortingAgingDAOInfo() == nil, "aging should not be set for %s", ans)
 		})
 	}
+
+	weight := 0.5
+	partition.updateNodeSortingPolicy(configs.PartitionConfig{
+		Name: "test",
+		NodeSortPolicy: configs.NodeSortingPolicy{
+			Type: policies.FairWithAgingNodePolicy.String(),
+			Aging: configs.NodeSortingAging{
+				Weight: &weight,
+				Curve:  policies.LogarithmicAgingCurve.String(),
+				Period: "10m",
+			},
+		},
+	}, false)
+	assert.Equal(t, partition.GetNodeSortingPolicyType(), policies.FairWithAgingNodePolicy, "aging policy not set")
+	aging := partition.GetNodeSortingAgingDAOInfo()
+	assert.Assert(t, aging != nil, "aging should be set")
+	assert.Equal(t, aging.Weight, weight, "weight not set")
+	assert.Equal(t, aging.Cap, configs.DefaultNodeAgingCap, "cap not defaulted")
+	assert.Equal(t, aging.Curve, "logarithmic", "curve not set")
+	assert.Equal(t, aging.Period, "10m0s", "period not set")
+	assert.Equal(t, aging.HalfLife, "", "half-life should not be shown for logarithmic curve")
+	assert.Assert(t, partition.GetNodeSortingScorers() == nil, "scorers should not be set for built-in policy")
+
+	partition.updateNodeSortingPolicy(configs.PartitionConfig{
+		Name: "test",
+		NodeSortPolicy: configs.NodeSortingPolicy{
+			Type: policies.WeightedNodePolicy.String(),
+			Scorers: []configs.NodeSortingScorer{
+				{Name: policies.BinPackingPolicy.String(), Weight: 0.7},
+				{Name: policies.FairWithAgingNodePolicy.String(), Weight: 0.3},
+			},
+		},
+	}, false)
+	assert.Equal(t, partition.GetNodeSortingPolicyType(), policies.WeightedNodePolicy, "weighted policy not set")
+	assert.DeepEqual(t, partition.GetNodeSortingScorers(), map[string]float64{"binpacking": 0.7, "fairwithaging": 0.3})
+	assert.Assert(t, partition.GetNodeSortingAgingDAOInfo() == nil, "aging should not be set for weighted policy")
+	assert.Assert(t, partition.GetNodeTopologyDAOInfo() == nil, "topology should not be set")
+
+	partition.updateNodeSortingPolicy(configs.PartitionConfig{
+		Name: "test",
+		NodeSortPolicy: configs.NodeSortingPolicy{
+			Topology: configs.NodeSortingTopology{Keys: []string{"zone", "rack"}, MaxSkew: 1},
+		},
+	}, false)
+	topology := partition.GetNodeTopologyDAOInfo()
+	assert.Assert(t, topology != nil, "topology should be set")
+	assert.DeepEqual(t, topology.Keys, []string{"zone", "rack"})
+	assert.Equal(t, topology.Mode, "spread", "mode not defaulted")
+	assert.Equal(t, topology.MaxSkew, 1, "max skew not set")
 }
 
 // A Test Case of get function in object/node_cellection
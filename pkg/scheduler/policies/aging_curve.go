@@ -0,0 +1,81 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package policies
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Curve used to convert a waiting time into an aging factor between 0 and 1.
+type AgingCurve int
+
+const (
+	LinearAgingCurve      AgingCurve = iota // grows linearly, reaches 1 after one period
+	LogarithmicAgingCurve                   // grows fast initially and slows down, reaches 1 after one period
+	ExponentialAgingCurve                   // approaches 1, half of the remaining distance is covered every half-life
+)
+
+func (c AgingCurve) String() string {
+	return [...]string{"linear", "logarithmic", "exponential"}[c]
+}
+
+func AgingCurveFromString(str string) (AgingCurve, error) {
+	switch strings.ToLower(str) {
+	// linear is the default curve when not set
+	case LinearAgingCurve.String(), "":
+		return LinearAgingCurve, nil
+	case LogarithmicAgingCurve.String():
+		return LogarithmicAgingCurve, nil
+	case ExponentialAgingCurve.String():
+		return ExponentialAgingCurve, nil
+	default:
+		return LinearAgingCurve, fmt.Errorf("undefined aging curve: %s", str)
+	}
+}
+
+// Factor returns the aging factor for the waiting time. The factor is always between 0 and 1.
+// The period is used by the linear and logarithmic curves, the half-life by the exponential curve.
+// A zero or negative period or half-life returns the maximum factor for any positive wait.
+func (c AgingCurve) Factor(wait, period, halfLife time.Duration) float64 {
+	if wait <= 0 {
+		return 0
+	}
+	var factor float64
+	switch c {
+	case LogarithmicAgingCurve:
+		if period <= 0 {
+			return 1
+		}
+		factor = math.Log1p(float64(wait)/float64(period)) / math.Ln2
+	case ExponentialAgingCurve:
+		if halfLife <= 0 {
+			return 1
+		}
+		factor = 1 - math.Exp2(-float64(wait)/float64(halfLife))
+	default:
+		if period <= 0 {
+			return 1
+		}
+		factor = float64(wait) / float64(period)
+	}
+	return math.Min(factor, 1)
+}
@@ -0,0 +1,89 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package policies
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAgingCurveFromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    AgingCurve
+		wantErr bool
+	}{
+		{"EmptyString", "", LinearAgingCurve, false},
+		{"LinearString", "linear", LinearAgingCurve, false},
+		{"LogString", "logarithmic", LogarithmicAgingCurve, false},
+		{"ExpString", "Exponential", ExponentialAgingCurve, false},
+		{"UnknownString", "unknown", LinearAgingCurve, true},
+	}
+	for _, tt := range tests {
+		got, err := AgingCurveFromString(tt.arg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s unexpected error returned, expected error: %t, got error '%v'", tt.name, tt.wantErr, err)
+			return
+		}
+		if got != tt.want {
+			t.Errorf("%s unexpected curve returned, expected: '%s', got '%v'", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestAgingCurveFactor(t *testing.T) {
+	period := time.Minute
+	tests := []struct {
+		name  string
+		curve AgingCurve
+		wait  time.Duration
+		want  float64
+	}{
+		{"LinearNoWait", LinearAgingCurve, 0, 0},
+		{"LinearHalf", LinearAgingCurve, 30 * time.Second, 0.5},
+		{"LinearPeriod", LinearAgingCurve, period, 1},
+		{"LinearCapped", LinearAgingCurve, 2 * period, 1},
+		{"LogNoWait", LogarithmicAgingCurve, 0, 0},
+		{"LogPeriod", LogarithmicAgingCurve, period, 1},
+		{"LogCapped", LogarithmicAgingCurve, 3 * period, 1},
+		{"ExpNoWait", ExponentialAgingCurve, 0, 0},
+		{"ExpHalfLife", ExponentialAgingCurve, period, 0.5},
+		{"ExpTwoHalfLives", ExponentialAgingCurve, 2 * period, 0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.curve.Factor(tt.wait, period, period), tt.want)
+		})
+	}
+	// the logarithmic curve grows faster than linear before the period is reached
+	assert.Assert(t, LogarithmicAgingCurve.Factor(30*time.Second, period, period) > 0.5, "logarithmic curve should be above linear")
+	// zero period or half-life means full aging immediately
+	assert.Equal(t, LinearAgingCurve.Factor(time.Second, 0, 0), 1.0)
+	assert.Equal(t, ExponentialAgingCurve.Factor(time.Second, 0, 0), 1.0)
+}
+
+func TestAgingCurveToString(t *testing.T) {
+	var someCurve AgingCurve // since AgingCurve is an iota it defaults to first in the list
+	assert.Equal(t, someCurve.String(), "linear")
+	assert.Equal(t, LogarithmicAgingCurve.String(), "logarithmic")
+	assert.Equal(t, ExponentialAgingCurve.String(), "exponential")
+}
@@ -20,6 +20,8 @@ package policies
 
 import (
 	"fmt"
+
+	"github.com/apache/yunikorn-core/pkg/plugins"
 )
 
 type SortingPolicy int
@@ -28,9 +30,21 @@ const (
 	BinPackingPolicy SortingPolicy = iota
 	FairnessPolicy
 	FairWithAgingNodePolicy
+	WeightedNodePolicy // weighted combination of several node scorers
+	PluginNodePolicy   // node scorer registered in the plugins
 )
+
+// the names of the built-in node sorting policies cannot be used by a node scorer plugin
+func init() {
+	names := make([]string, 0, int(PluginNodePolicy))
+	for policy := BinPackingPolicy; policy < PluginNodePolicy; policy++ {
+		names = append(names, policy.String())
+	}
+	plugins.ReserveNodeScorerNames(names...)
+}
+
 func (nsp SortingPolicy) String() string {
-	return [...]string{"binpacking", "fair", "fairwithaging"}[nsp]
+	return [...]string{"binpacking", "fair", "fairwithaging", "weighted", "plugin"}[nsp]
 }
 
 func SortingPolicyFromString(str string) (SortingPolicy, error) {
@@ -42,7 +56,12 @@ func SortingPolicyFromString(str string) (SortingPolicy, error) {
 			return BinPackingPolicy, nil
 		case FairWithAgingNodePolicy.String():
 			return FairWithAgingNodePolicy, nil
+		case WeightedNodePolicy.String():
+			return WeightedNodePolicy, nil
 		default:
+			if plugins.IsNodeScorerRegistered(str) {
+				return PluginNodePolicy, nil
+			}
 			return FairnessPolicy, fmt.Errorf("undefined policy: %s", str)
 	}
 }
\ No newline at end of file
@@ -20,6 +20,8 @@ package policies
 
 import (
 	"testing"
+
+	"github.com/apache/yunikorn-core/pkg/plugins"
 )
 
 func TestSortingPolicyFromString(t *testing.T) {
@@ -33,8 +35,16 @@ func TestSortingPolicyFromString(t *testing.T) {
 		{"FairString", "fair", FairnessPolicy, false},
 		{"BinString", "binpacking", BinPackingPolicy, false},
 		{"AgingString", "fairwithaging", FairWithAgingNodePolicy, false},
+		{"WeightedString", "weighted", WeightedNodePolicy, false},
+		{"PluginString", "test-scorer", PluginNodePolicy, false},
+		{"PluginTypeString", "plugin", FairnessPolicy, true},
 		{"UnknownString", "unknown", FairnessPolicy, true},
 	}
+	err := plugins.RegisterNodeScorer("test-scorer", func(_ map[string]float64) plugins.NodeScorer { return nil })
+	if err != nil {
+		t.Fatalf("node scorer registration failed: %v", err)
+	}
+	defer plugins.UnregisterNodeScorer("test-scorer")
 	for _, tt := range tests {
 		got, err := SortingPolicyFromString(tt.arg)
 		if (err != nil) != tt.wantErr {
@@ -57,6 +67,8 @@ func TestSortingPolicyToString(t *testing.T) {
 		{"FairString", FairnessPolicy, "fair"},
 		{"BinString", BinPackingPolicy, "binpacking"},
 		{"AgingString", FairWithAgingNodePolicy, "fairwithaging"},
+		{"WeightedString", WeightedNodePolicy, "weighted"},
+		{"PluginString", PluginNodePolicy, "plugin"},
 		{"NoneString", someSP, "binpacking"},
 	}
 	for _, tt := range tests {
@@ -65,3 +77,13 @@ func TestSortingPolicyToString(t *testing.T) {
 		}
 	}
 }
+
+func TestBuiltinNodeScorerNames(t *testing.T) {
+	factory := func(_ map[string]float64) plugins.NodeScorer { return nil }
+	for policy := BinPackingPolicy; policy < PluginNodePolicy; policy++ {
+		if err := plugins.RegisterNodeScorer(policy.String(), factory); err == nil {
+			plugins.UnregisterNodeScorer(policy.String())
+			t.Errorf("node scorer registered under the built-in policy name %s", policy)
+		}
+	}
+}
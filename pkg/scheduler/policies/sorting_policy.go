@@ -30,13 +30,16 @@ type SortPolicy int
 const (
 	FifoSortPolicy             SortPolicy = iota // first in first out, submit time
 	FairSortPolicy                               // fair based on usage
-	/*FairWithAgingSortPolicy                      // fair with aging mechanism*/
+	FairWithAgingSortPolicy                      // fair based on usage, corrected for the time waiting
+	DrfSortPolicy                                // dominant resource fairness based on partition capacity
+	SjfSortPolicy                                // shortest job first, declared runtime estimate
+	EdfSortPolicy                                // earliest deadline first, declared deadline
 	deprecatedStateAwarePolicy                   // deprecated: now alias for FIFO
 	Undefined                                    // not initialised or parsing failed
 )
 
 func (s SortPolicy) String() string {
-	return [...]string{"fifo", "fair"/*, "fairwithaging"*/, "stateaware", "undefined"}[s]
+	return [...]string{"fifo", "fair", "fairwithaging", "drf", "sjf", "edf", "stateaware", "undefined"}[s]
 }
 
 func SortPolicyFromString(str string) (SortPolicy, error) {
@@ -46,8 +49,14 @@ func SortPolicyFromString(str string) (SortPolicy, error) {
 		return FifoSortPolicy, nil
 	case FairSortPolicy.String():
 		return FairSortPolicy, nil
-	// case FairWithAgingSortPolicy.String():
-	// 	return FairWithAgingSortPolicy, nil
+	case FairWithAgingSortPolicy.String():
+		return FairWithAgingSortPolicy, nil
+	case DrfSortPolicy.String():
+		return DrfSortPolicy, nil
+	case SjfSortPolicy.String():
+		return SjfSortPolicy, nil
+	case EdfSortPolicy.String():
+		return EdfSortPolicy, nil
 	case deprecatedStateAwarePolicy.String():
 		log.Log(log.Deprecation).Warn("Sort policy 'stateaware' is deprecated; using 'fifo' instead")
 		return FifoSortPolicy, nil
@@ -32,6 +32,10 @@ func TestAppFromString(t *testing.T) {
 		{"EmptyString", "", FifoSortPolicy, false},
 		{"FifoString", "fifo", FifoSortPolicy, false},
 		{"FairString", "fair", FairSortPolicy, false},
+		{"FairWithAgingString", "fairwithaging", FairWithAgingSortPolicy, false},
+		{"DrfString", "drf", DrfSortPolicy, false},
+		{"SjfString", "sjf", SjfSortPolicy, false},
+		{"EdfString", "edf", EdfSortPolicy, false},
 		{"StatusString", "stateaware", FifoSortPolicy, false},
 		{"UnknownString", "unknown", Undefined, true},
 	}
@@ -56,6 +60,10 @@ func TestAppToString(t *testing.T) {
 	}{
 		{"FifoString", FifoSortPolicy, "fifo"},
 		{"FairString", FairSortPolicy, "fair"},
+		{"FairWithAgingString", FairWithAgingSortPolicy, "fairwithaging"},
+		{"DrfString", DrfSortPolicy, "drf"},
+		{"SjfString", SjfSortPolicy, "sjf"},
+		{"EdfString", EdfSortPolicy, "edf"},
 		{"StatusString", deprecatedStateAwarePolicy, "stateaware"},
 		{"DefaultString", Undefined, "undefined"},
 		{"NoneString", someSP, "fifo"},
@@ -0,0 +1,51 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package policies
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Placement of the allocations of an application over the topology domains of the nodes.
+type TopologyMode int
+
+const (
+	SpreadTopologyMode   TopologyMode = iota // prefer nodes in the domains with the fewest allocations of the application
+	ColocateTopologyMode                     // prefer nodes in the domains with the most allocations of the application
+	NoTopologyMode                           // ignore the topology, use the node sorting policy only
+)
+
+func (m TopologyMode) String() string {
+	return [...]string{"spread", "colocate", "none"}[m]
+}
+
+func TopologyModeFromString(str string) (TopologyMode, error) {
+	switch strings.ToLower(str) {
+	// spread is the default mode when not set
+	case SpreadTopologyMode.String(), "":
+		return SpreadTopologyMode, nil
+	case ColocateTopologyMode.String():
+		return ColocateTopologyMode, nil
+	case NoTopologyMode.String():
+		return NoTopologyMode, nil
+	default:
+		return SpreadTopologyMode, fmt.Errorf("undefined topology mode: %s", str)
+	}
+}
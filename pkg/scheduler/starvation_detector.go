@@ -0,0 +1,100 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/apache/yunikorn-core/pkg/log"
+)
+
+const DefaultStarvationCheckInterval = 10 * time.Second // sleep between starvation checks
+
+// starvationDetector finds applications in the partition with an outstanding ask that has been waiting longer
+// than the configured threshold. The priority of these starved applications is temporarily boosted.
+// The application removes the boost itself as soon as it gets an allocation.
+type starvationDetector struct {
+	pc            *PartitionContext
+	stop          chan struct{}
+	checkInterval time.Duration
+}
+
+func newStarvationDetector(pc *PartitionContext) *starvationDetector {
+	return &starvationDetector{
+		pc:            pc,
+		stop:          make(chan struct{}),
+		checkInterval: DefaultStarvationCheckInterval,
+	}
+}
+
+// run the detector until it is stopped. Detection can be switched on and off via the configuration
+// while the detector is running.
+func (sd *starvationDetector) run() {
+	log.Log(log.SchedPartition).Info("Starting partition starvation detector",
+		zap.String("partition", sd.pc.Name))
+	for {
+		checkInterval := sd.checkInterval
+		if checkInterval <= 0 {
+			checkInterval = DefaultStarvationCheckInterval
+		}
+		select {
+		case <-sd.stop:
+			return
+		case <-time.After(checkInterval):
+			sd.runOnce()
+		}
+	}
+}
+
+// runOnce checks all applications in the partition once and boosts the starved applications.
+func (sd *starvationDetector) runOnce() {
+	enabled, threshold, boost := sd.pc.getStarvationSettings()
+	if !enabled {
+		return
+	}
+	now := time.Now()
+	boosted := 0
+	for _, app := range sd.pc.GetApplications() {
+		if current, _ := app.GetPriorityBoost(); current != 0 {
+			continue
+		}
+		oldest := app.GetOldestPendingAskTime()
+		if oldest.IsZero() {
+			continue
+		}
+		if waited := now.Sub(oldest); waited >= threshold && app.BoostPriority(boost, waited) {
+			boosted++
+		}
+	}
+	if boosted > 0 {
+		log.Log(log.SchedPartition).Debug("starved applications boosted",
+			zap.String("partition", sd.pc.Name),
+			zap.Int("boosted", boosted),
+			zap.Stringer("duration", time.Since(now)))
+	}
+}
+
+// Stop the starvation detector.
+func (sd *starvationDetector) Stop() {
+	log.Log(log.SchedPartition).Info("Stopping partition starvation detector",
+		zap.String("partition", sd.pc.Name))
+	close(sd.stop)
+}
@@ -0,0 +1,81 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/configs"
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+func TestStarvationDetector(t *testing.T) {
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "partition create failed")
+	enabled, threshold, boost := partition.getStarvationSettings()
+	assert.Assert(t, !enabled, "starvation detection should be disabled by default")
+	assert.Equal(t, threshold, configs.DefaultStarvationThreshold, "wrong default threshold")
+	assert.Equal(t, boost, configs.DefaultStarvationPriorityBoost, "wrong default boost")
+
+	app := newApplication(appID1, "default", defQueue)
+	assert.NilError(t, partition.AddApplication(app), "app should have been added")
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+	assert.NilError(t, app.AddAllocationAsk(newAllocationAskPriority(allocKey, appID1, res, 10)), "ask should have been added")
+
+	detector := newStarvationDetector(partition)
+	// disabled: nothing changes
+	detector.runOnce()
+	current, _ := app.GetPriorityBoost()
+	assert.Equal(t, current, int32(0), "app should not be boosted with detection disabled")
+
+	// enabled: the ask has not waited long enough
+	enable := true
+	priorityBoost := int32(50)
+	partition.updateStarvation(configs.PartitionConfig{
+		Starvation: configs.PartitionStarvationConfig{
+			Enabled:       &enable,
+			Threshold:     "1h",
+			PriorityBoost: &priorityBoost,
+		},
+	})
+	detector.runOnce()
+	current, _ = app.GetPriorityBoost()
+	assert.Equal(t, current, int32(0), "app should not be boosted before the threshold")
+
+	// threshold passed: boost applied
+	partition.starvationThreshold = time.Nanosecond
+	detector.runOnce()
+	current, _ = app.GetPriorityBoost()
+	assert.Equal(t, current, priorityBoost, "starved app should be boosted")
+	assert.Equal(t, app.GetEffectivePriority(), int32(60), "wrong boosted priority")
+	assert.Equal(t, partition.GetQueue(defQueue).GetCurrentPriority(), int32(60), "queue priority should include the boost")
+
+	// the allocation removes the boost
+	_, err = app.AllocateAsk(allocKey)
+	assert.NilError(t, err, "ask should have been allocated")
+	current, _ = app.GetPriorityBoost()
+	assert.Equal(t, current, int32(0), "boost should be removed after allocation")
+
+	// stopped detector must not block
+	detector.Stop()
+	detector.run()
+}
@@ -100,6 +100,14 @@ func (gt *GroupTracker) headroom(hierarchy []string) *resources.Resource {
 	return gt.queueTracker.headroom(hierarchy, group)
 }
 
+// moveHeadroom calculate the resource headroom for the group for usage that is moved into the hierarchy defined.
+// The usage is already tracked in the first shared levels of the hierarchy.
+func (gt *GroupTracker) moveHeadroom(hierarchy []string, shared int, usage *resources.Resource) *resources.Resource {
+	gt.Lock()
+	defer gt.Unlock()
+	return gt.queueTracker.moveHeadroom(hierarchy, shared, usage, group)
+}
+
 // GetResourceUsageDAOInfo returns the DAO object used in the REST API for this group tracker
 func (gt *GroupTracker) GetResourceUsageDAOInfo() *dao.GroupResourceUsageDAOInfo {
 	gt.RLock()
@@ -192,6 +192,74 @@ func (m *Manager) DecreaseTrackedResource(queuePath, applicationID string, usage
 	}
 }
 
+// MoveTrackedResource moves the resource usage of the application from one queue path to another. The usage must fit
+// in the user and group headroom of the target and the application must be allowed to run in the target. The tracked
+// usage is only changed after the checks passed: if dryRun is set only the checks are performed.
+func (m *Manager) MoveTrackedResource(fromPath, toPath, applicationID string, usage *resources.Resource, user security.UserGroup, dryRun bool) error {
+	if !resources.IsZero(usage) {
+		if headroom := m.moveHeadroom(fromPath, toPath, applicationID, usage, user); !headroom.FitInMaxUndef(usage) {
+			return fmt.Errorf("usage %s does not fit in the user and group headroom %s of queue %s", usage, headroom, toPath)
+		}
+	}
+	if !m.canRunMovedApp(toPath, applicationID, user) {
+		return fmt.Errorf("user and group maximum applications reached in queue %s", toPath)
+	}
+	if dryRun || resources.IsZero(usage) {
+		return nil
+	}
+	m.DecreaseTrackedResource(fromPath, applicationID, usage, user, true)
+	m.IncreaseTrackedResource(toPath, applicationID, usage, user)
+	return nil
+}
+
+// moveHeadroom calculates the user and group headroom in the target queue for the usage of an application that is
+// moved. The usage is already tracked in the queues shared by the source and target path, unless the application is
+// tracked against a different group in the target. The tracked usage and the group of the application are not changed.
+func (m *Manager) moveHeadroom(fromPath, toPath, applicationID string, usage *resources.Resource, user security.UserGroup) *resources.Resource {
+	hierarchy := strings.Split(toPath, configs.DOT)
+	shared := sharedDepth(strings.Split(fromPath, configs.DOT), hierarchy)
+	userTracker := m.getUserTracker(user.User)
+	userHeadroom := userTracker.moveHeadroom(hierarchy, shared, usage)
+	appGroup := m.ensureGroup(user, toPath)
+	if appGroup == common.Empty {
+		return userHeadroom
+	}
+	groupTracker := m.GetGroupTracker(appGroup)
+	if groupTracker == nil {
+		return userHeadroom
+	}
+	if userTracker.getGroupForApp(applicationID) != appGroup {
+		shared = 0
+	}
+	groupHeadroom := groupTracker.moveHeadroom(hierarchy, shared, usage)
+	return resources.ComponentWiseMin(userHeadroom, groupHeadroom)
+}
+
+// canRunMovedApp checks the maxApplications of the target queue for an application that is moved. Unlike CanRunApp
+// the group of the application is not changed.
+func (m *Manager) canRunMovedApp(toPath, applicationID string, user security.UserGroup) bool {
+	hierarchy := strings.Split(toPath, configs.DOT)
+	userCanRunApp := m.getUserTracker(user.User).canRunApp(hierarchy, applicationID)
+	appGroup := m.ensureGroup(user, toPath)
+	if appGroup == common.Empty {
+		return userCanRunApp
+	}
+	groupTracker := m.GetGroupTracker(appGroup)
+	if groupTracker == nil {
+		return userCanRunApp
+	}
+	return userCanRunApp && groupTracker.canRunApp(hierarchy, applicationID)
+}
+
+// sharedDepth returns the number of leading queues the two hierarchies have in common.
+func sharedDepth(left, right []string) int {
+	depth := 0
+	for depth < len(left) && depth < len(right) && left[depth] == right[depth] {
+		depth++
+	}
+	return depth
+}
+
 func (m *Manager) GetUserTrackers() []*UserTracker {
 	m.RLock()
 	defer m.RUnlock()
@@ -955,6 +955,125 @@ func TestCanRunApp(t *testing.T) {
 	}
 }
 
+func TestMoveTrackedResource(t *testing.T) {
+	setupUGM()
+	// Queue setup:
+	// root->parent->child1
+	// root->parent->child2 (limited)
+	conf := configs.PartitionConfig{
+		Name: "default",
+		Queues: []configs.QueueConfig{
+			{
+				Name:      "root",
+				Parent:    true,
+				SubmitACL: "*",
+				Queues: []configs.QueueConfig{
+					{
+						Name:      "parent",
+						Parent:    true,
+						SubmitACL: "*",
+						Queues: []configs.QueueConfig{
+							{
+								Name: "child1",
+							},
+							{
+								Name:   "child2",
+								Limits: []configs.Limit{createLimit([]string{"user1"}, nil, map[string]string{"memory": "60"}, 1)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	manager := GetUserManager()
+	assert.NilError(t, manager.UpdateConfig(conf.Queues[0], "root"))
+	user := security.UserGroup{User: "user1", Groups: []string{"group1"}}
+	usage := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 50})
+	manager.IncreaseTrackedResource(queuePath1, TestApp1, usage, user)
+	used := func(queuePath string) *resources.Resource {
+		return manager.GetUserTracker(user.User).queueTracker.getUsedResources()[queuePath]
+	}
+
+	// nothing to move
+	assert.NilError(t, manager.MoveTrackedResource(queuePath1, queuePath2, TestApp1, nil, user, false))
+
+	// dry run leaves the usage in the source
+	assert.NilError(t, manager.MoveTrackedResource(queuePath1, queuePath2, TestApp1, usage, user, true))
+	assert.Assert(t, resources.Equals(used(queuePath1), usage), "usage should not have moved on dry run")
+	assert.Assert(t, resources.IsZero(used(queuePath2)), "usage should not have moved on dry run")
+
+	// headroom of the target exceeded
+	other := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 20})
+	manager.IncreaseTrackedResource(queuePath2, TestApp2, other, user)
+	err := manager.MoveTrackedResource(queuePath1, queuePath2, TestApp1, usage, user, false)
+	assert.ErrorContains(t, err, "does not fit in the user and group headroom")
+	assert.Assert(t, resources.Equals(used(queuePath1), usage), "usage should not have moved on failure")
+
+	// maximum applications of the target reached
+	manager.DecreaseTrackedResource(queuePath2, TestApp2, resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 15}), user, false)
+	err = manager.MoveTrackedResource(queuePath1, queuePath2, TestApp1, usage, user, false)
+	assert.ErrorContains(t, err, "maximum applications reached")
+	assert.Assert(t, resources.Equals(used(queuePath1), usage), "usage should not have moved on failure")
+
+	// move succeeds
+	manager.DecreaseTrackedResource(queuePath2, TestApp2, resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 5}), user, true)
+	assert.NilError(t, manager.MoveTrackedResource(queuePath1, queuePath2, TestApp1, usage, user, false))
+	assert.Assert(t, resources.IsZero(used(queuePath1)), "usage should have moved from the source")
+	assert.Assert(t, resources.Equals(used(queuePath2), usage), "usage should have moved to the target")
+}
+
+func TestMoveTrackedResourceShared(t *testing.T) {
+	setupUGM()
+	// Queue setup:
+	// root->parent (limited)->child1
+	// root->parent (limited)->child2 (limited)
+	conf := configs.PartitionConfig{
+		Name: "default",
+		Queues: []configs.QueueConfig{
+			{
+				Name:      "root",
+				Parent:    true,
+				SubmitACL: "*",
+				Queues: []configs.QueueConfig{
+					{
+						Name:      "parent",
+						Parent:    true,
+						SubmitACL: "*",
+						Limits:    []configs.Limit{createLimit([]string{"user1"}, nil, map[string]string{"memory": "60"}, 1)},
+						Queues: []configs.QueueConfig{
+							{
+								Name: "child1",
+							},
+							{
+								Name:   "child2",
+								Limits: []configs.Limit{createLimit([]string{"user1"}, nil, nil, 1)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	manager := GetUserManager()
+	assert.NilError(t, manager.UpdateConfig(conf.Queues[0], "root"))
+	user := security.UserGroup{User: "user1", Groups: []string{"group1"}}
+	usage := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 50})
+	manager.IncreaseTrackedResource(queuePath1, TestApp1, usage, user)
+	used := func(queuePath string) *resources.Resource {
+		return manager.GetUserTracker(user.User).queueTracker.getUsedResources()[queuePath]
+	}
+
+	// the usage and the application are already tracked in the shared parent
+	assert.NilError(t, manager.MoveTrackedResource(queuePath1, queuePath2, TestApp1, usage, user, true))
+	assert.Assert(t, resources.Equals(used("root.parent"), usage), "dry run should not change the shared usage")
+	assert.Assert(t, resources.Equals(used(queuePath1), usage), "usage should not have moved on dry run")
+
+	// an application without usage is still checked against the maximum applications of the target
+	err := manager.MoveTrackedResource(queuePath1, queuePath2, TestApp2, nil, user, true)
+	assert.ErrorContains(t, err, "maximum applications reached")
+}
+
 func TestSeparateUserGroupHeadroom(t *testing.T) {
 	testCases := []struct {
 		name string
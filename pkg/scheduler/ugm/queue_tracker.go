@@ -231,6 +231,33 @@ func (qt *QueueTracker) headroom(hierarchy []string, trackType trackingType) *re
 	return resources.ComponentWiseMin(headroom, childHeadroom)
 }
 
+// moveHeadroom calculates the headroom for usage that is moved into the queue defined in hierarchy. The first shared
+// levels of the hierarchy already track the usage: it is added back to the headroom of those levels.
+// Note: Lock free call. The Lock of the linked tracker (UserTracker and GroupTracker) should be held before calling this function.
+// Note: moveHeadroom is not read-only, it also traverses the queue hierarchy and creates childQueueTracker if it does not exist.
+func (qt *QueueTracker) moveHeadroom(hierarchy []string, shared int, usage *resources.Resource, trackType trackingType) *resources.Resource {
+	var headroom, childHeadroom *resources.Resource
+	if len(hierarchy) > 1 {
+		childName := hierarchy[1]
+		if qt.childQueueTrackers[childName] == nil {
+			qt.childQueueTrackers[childName] = newQueueTracker(qt.queuePath, childName, trackType)
+		}
+		childHeadroom = qt.childQueueTrackers[childName].moveHeadroom(hierarchy[1:], shared-1, usage, trackType)
+	}
+
+	if !resources.IsZero(qt.maxResources) {
+		headroom = resources.SubOnlyExisting(qt.maxResources, qt.resourceUsage)
+		if shared > 0 {
+			headroom = resources.AddOnlyExisting(headroom, usage)
+		}
+	}
+
+	if headroom == nil {
+		return childHeadroom
+	}
+	return resources.ComponentWiseMin(headroom, childHeadroom)
+}
+
 // getResourceUsageDAOInfo returns the REST representation of the queue tracker
 // Note: Lock free call. The RLock of the linked tracker (UserTracker and GroupTracker) should be held before calling this function.
 func (qt *QueueTracker) getResourceUsageDAOInfo() *dao.ResourceUsageDAOInfo {
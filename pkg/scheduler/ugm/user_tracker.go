@@ -130,6 +130,14 @@ func (ut *UserTracker) headroom(hierarchy []string) *resources.Resource {
 	return ut.queueTracker.headroom(hierarchy, user)
 }
 
+// moveHeadroom calculate the resource headroom for the user for usage that is moved into the hierarchy defined.
+// The usage is already tracked in the first shared levels of the hierarchy.
+func (ut *UserTracker) moveHeadroom(hierarchy []string, shared int, usage *resources.Resource) *resources.Resource {
+	ut.Lock()
+	defer ut.Unlock()
+	return ut.queueTracker.moveHeadroom(hierarchy, shared, usage, user)
+}
+
 // GetResourceUsageDAOInfo returns the DAO object used in the REST API for this user tracker
 func (ut *UserTracker) GetResourceUsageDAOInfo() *dao.UserResourceUsageDAOInfo {
 	ut.RLock()
@@ -41,9 +41,14 @@ type ApplicationDAOInfo struct {
 	PlaceholderData    []*PlaceholderDAOInfo   `json:"placeholderData,omitempty"`
 	HasReserved        bool                    `json:"hasReserved,omitempty"`
 	Reservations       []string                `json:"reservations,omitempty"`
+	ReservationAges    map[string]int64        `json:"reservationAges,omitempty"` // age in milliseconds per reservation
 	MaxRequestPriority int32                   `json:"maxRequestPriority,omitempty"`
+	PriorityBoost      int32                   `json:"priorityBoost,omitempty"`
+	PriorityBoostTime  *int64                  `json:"priorityBoostTime,omitempty"`
 	StartTime          int64                   `json:"startTime,omitempty"`
 	ResourceHistory    ResourceHistory         `json:"resourceHistory,omitempty"`
+	Dependencies       map[string]string       `json:"dependencies,omitempty"` // state of the upstream applications by ID
+	WaitingOnDeps      bool                    `json:"waitingOnDependencies,omitempty"`
 }
 
 type StateDAOInfo struct {
@@ -57,6 +62,7 @@ type PlaceholderDAOInfo struct {
 	MinResource   map[string]int64 `json:"minResource,omitempty"`
 	Replaced      int64            `json:"replaced,omitempty"`
 	TimedOut      int64            `json:"timedout,omitempty"`
+	Stopped       int64            `json:"stopped,omitempty"`
 }
 
 type ResourceHistory struct {
@@ -64,3 +70,17 @@ type ResourceHistory struct {
 	PreemptedResource   map[string]map[string]int64 `json:"preemptedResource,omitempty"`
 	PlaceholderResource map[string]map[string]int64 `json:"placeholderResource,omitempty"`
 }
+
+// ApplicationMoveDAOInfo is the request to move an application to another leaf queue.
+// If dryRun is set the move is only checked.
+type ApplicationMoveDAOInfo struct {
+	Queue  string `json:"queue"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+// ApplicationSuspendDAOInfo is the request to suspend an application.
+// If release is set the allocations of the application are released.
+type ApplicationSuspendDAOInfo struct {
+	Release bool   `json:"release,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
@@ -38,4 +38,5 @@ type NodeDAOInfo struct {
 	Schedulable        bool                        `json:"schedulable"` // no omitempty, a false value gives a quick way to understand whether a node is schedulable.
 	IsReserved         bool                        `json:"isReserved"`  // no omitempty, a false value gives a quick way to understand whether a node is reserved.
 	Reservations       []string                    `json:"reservations,omitempty"`
+	ReservationAges    map[string]int64            `json:"reservationAges,omitempty"` // age in milliseconds per reservation
 }
@@ -38,6 +38,23 @@ type PartitionCapacity struct {
 }
 
 type NodeSortingPolicy struct {
-	Type            string             `json:"type,omitempty"`
-	ResourceWeights map[string]float64 `json:"resourceWeights,omitempty"`
+	Type            string               `json:"type,omitempty"`
+	ResourceWeights map[string]float64   `json:"resourceWeights,omitempty"`
+	Aging           *NodeSortingAging    `json:"aging,omitempty"`
+	Scorers         map[string]float64   `json:"scorers,omitempty"`
+	Topology        *NodeSortingTopology `json:"topology,omitempty"`
+}
+
+type NodeSortingTopology struct {
+	Keys    []string `json:"keys,omitempty"`
+	Mode    string   `json:"mode,omitempty"`
+	MaxSkew int      `json:"maxSkew,omitempty"`
+}
+
+type NodeSortingAging struct {
+	Weight   float64 `json:"weight"` // no omitempty, 0 disables aging
+	Cap      float64 `json:"cap"`    // no omitempty, 0 disables aging
+	Curve    string  `json:"curve,omitempty"`
+	Period   string  `json:"period,omitempty"`
+	HalfLife string  `json:"halfLife,omitempty"`
 }
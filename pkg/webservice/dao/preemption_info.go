@@ -0,0 +1,72 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dao
+
+type PreemptionDryRunDAOInfo struct {
+	ApplicationID       string                     `json:"applicationId"` // no omitempty, application id should not be empty
+	AllocationKey       string                     `json:"allocationKey"` // no omitempty, allocation key should not be empty
+	QueueName           string                     `json:"queueName,omitempty"`
+	CanPreempt          bool                       `json:"canPreempt"` // no omitempty, false shows the result better
+	Reason              string                     `json:"reason,omitempty"`
+	FailedPreconditions []string                   `json:"failedPreconditions,omitempty"`
+	GuaranteesMet       bool                       `json:"guaranteesMet"` // no omitempty, false shows the result better
+	Queues              []*PreemptionQueueDAOInfo  `json:"queues,omitempty"`
+	CandidateNodes      []*PreemptionNodeDAOInfo   `json:"candidateNodes,omitempty"`
+	NodeID              string                     `json:"nodeId,omitempty"`
+	Victims             []*PreemptionVictimDAOInfo `json:"victims,omitempty"`
+	Moved               []*PreemptionVictimDAOInfo `json:"moved,omitempty"`
+}
+
+type PreemptionQueueDAOInfo struct {
+	QueueName          string           `json:"queueName"` // no omitempty, queue name should not be empty
+	AllocatedResource  map[string]int64 `json:"allocatedResource,omitempty"`
+	PreemptingResource map[string]int64 `json:"preemptingResource,omitempty"`
+	GuaranteedResource map[string]int64 `json:"guaranteedResource,omitempty"`
+	MaxResource        map[string]int64 `json:"maxResource,omitempty"`
+	RemainingGuarantee map[string]int64 `json:"remainingGuarantee,omitempty"`
+	Preemptable        map[string]int64 `json:"preemptable,omitempty"`
+	PotentialVictims   int              `json:"potentialVictims,omitempty"`
+}
+
+type PreemptionNodeDAOInfo struct {
+	NodeID            string                     `json:"nodeId"` // no omitempty, node id should not be empty
+	AvailableResource map[string]int64           `json:"availableResource,omitempty"`
+	Eligible          bool                       `json:"eligible"` // no omitempty, false shows the node cannot be used
+	Victims           []*PreemptionVictimDAOInfo `json:"victims,omitempty"`
+}
+
+type PreemptionVictimDAOInfo struct {
+	AllocationKey    string           `json:"allocationKey"` // no omitempty, allocation key should not be empty
+	ApplicationID    string           `json:"applicationId,omitempty"`
+	QueueName        string           `json:"queueName,omitempty"`
+	NodeID           string           `json:"nodeId,omitempty"`
+	ResourcePerAlloc map[string]int64 `json:"resource,omitempty"`
+	Priority         string           `json:"priority,omitempty"`
+}
+
+type PreemptionRecordDAOInfo struct {
+	Timestamp         int64                      `json:"timestamp"`     // no omitempty, timestamp should not be empty
+	ApplicationID     string                     `json:"applicationId"` // no omitempty, application id should not be empty
+	AllocationKey     string                     `json:"allocationKey"` // no omitempty, allocation key should not be empty
+	QueueName         string                     `json:"queueName,omitempty"`
+	NodeID            string                     `json:"nodeId,omitempty"`
+	RequiredNode      bool                       `json:"requiredNode,omitempty"`
+	Victims           []*PreemptionVictimDAOInfo `json:"victims,omitempty"`
+	ReclaimedResource map[string]int64           `json:"reclaimedResource,omitempty"`
+}
@@ -26,32 +26,61 @@ type TemplateInfo struct {
 }
 
 type PartitionQueueDAOInfo struct {
-	QueueName              string                  `json:"queuename"` // no omitempty, queue name should not be empty
-	Status                 string                  `json:"status,omitempty"`
-	Partition              string                  `json:"partition"` // no omitempty, partition name should not be empty
-	PendingResource        map[string]int64        `json:"pendingResource,omitempty"`
-	MaxResource            map[string]int64        `json:"maxResource,omitempty"`
-	GuaranteedResource     map[string]int64        `json:"guaranteedResource,omitempty"`
-	AllocatedResource      map[string]int64        `json:"allocatedResource,omitempty"`
-	PreemptingResource     map[string]int64        `json:"preemptingResource,omitempty"`
-	HeadRoom               map[string]int64        `json:"headroom,omitempty"`
-	IsLeaf                 bool                    `json:"isLeaf"`    // no omitempty, a false value gives a quick way to understand whether it's leaf.
-	IsManaged              bool                    `json:"isManaged"` // no omitempty, a false value gives a quick way to understand whether it's managed.
-	Properties             map[string]string       `json:"properties,omitempty"`
-	Parent                 string                  `json:"parent,omitempty"`
-	TemplateInfo           *TemplateInfo           `json:"template,omitempty"`
-	Children               []PartitionQueueDAOInfo `json:"children,omitempty"`
-	ChildNames             []string                `json:"childNames,omitempty"`
-	AbsUsedCapacity        map[string]int64        `json:"absUsedCapacity,omitempty"`
-	MaxRunningApps         uint64                  `json:"maxRunningApps,omitempty"`
-	RunningApps            uint64                  `json:"runningApps,omitempty"`
-	CurrentPriority        int32                   `json:"currentPriority"` // no omitempty, as the current priority value may be 0, which is a valid priority level
-	AllocatingAcceptedApps []string                `json:"allocatingAcceptedApps,omitempty"`
-	SortingPolicy          string                  `json:"sortingPolicy,omitempty"`
-	PrioritySorting        bool                    `json:"prioritySorting"`   // no omitempty, false shows priority sorting status better
-	PreemptionEnabled      bool                    `json:"preemptionEnabled"` // no omitempty, false shows preemption status better
-	IsPreemptionFence      bool                    `json:"isPreemptionFence"` // no omitempty, a false value gives a quick way to understand whether it's fenced.
-	PreemptionDelay        string                  `json:"preemptionDelay,omitempty"`
-	IsPriorityFence        bool                    `json:"isPriorityFence"` // no omitempty, a false value gives a quick way to understand whether it's fenced.
-	PriorityOffset         int32                   `json:"priorityOffset,omitempty"`
+	QueueName              string                   `json:"queuename"` // no omitempty, queue name should not be empty
+	Status                 string                   `json:"status,omitempty"`
+	StateReason            string                   `json:"stateReason,omitempty"`
+	Partition              string                   `json:"partition"` // no omitempty, partition name should not be empty
+	PendingResource        map[string]int64         `json:"pendingResource,omitempty"`
+	MaxResource            map[string]int64         `json:"maxResource,omitempty"`
+	GuaranteedResource     map[string]int64         `json:"guaranteedResource,omitempty"`
+	AllocatedResource      map[string]int64         `json:"allocatedResource,omitempty"`
+	PreemptingResource     map[string]int64         `json:"preemptingResource,omitempty"`
+	HeadRoom               map[string]int64         `json:"headroom,omitempty"`
+	IsLeaf                 bool                     `json:"isLeaf"`    // no omitempty, a false value gives a quick way to understand whether it's leaf.
+	IsManaged              bool                     `json:"isManaged"` // no omitempty, a false value gives a quick way to understand whether it's managed.
+	Properties             map[string]string        `json:"properties,omitempty"`
+	Parent                 string                   `json:"parent,omitempty"`
+	TemplateInfo           *TemplateInfo            `json:"template,omitempty"`
+	Children               []PartitionQueueDAOInfo  `json:"children,omitempty"`
+	ChildNames             []string                 `json:"childNames,omitempty"`
+	AbsUsedCapacity        map[string]int64         `json:"absUsedCapacity,omitempty"`
+	MaxRunningApps         uint64                   `json:"maxRunningApps,omitempty"`
+	RunningApps            uint64                   `json:"runningApps,omitempty"`
+	CurrentPriority        int32                    `json:"currentPriority"` // no omitempty, as the current priority value may be 0, which is a valid priority level
+	AllocatingAcceptedApps []string                 `json:"allocatingAcceptedApps,omitempty"`
+	SortingPolicy          string                   `json:"sortingPolicy,omitempty"`
+	PrioritySorting        bool                     `json:"prioritySorting"`   // no omitempty, false shows priority sorting status better
+	PreemptionEnabled      bool                     `json:"preemptionEnabled"` // no omitempty, false shows preemption status better
+	IsPreemptionFence      bool                     `json:"isPreemptionFence"` // no omitempty, a false value gives a quick way to understand whether it's fenced.
+	PreemptionDelay        string                   `json:"preemptionDelay,omitempty"`
+	IsPriorityFence        bool                     `json:"isPriorityFence"` // no omitempty, a false value gives a quick way to understand whether it's fenced.
+	PriorityOffset         int32                    `json:"priorityOffset,omitempty"`
+	AgingPeriod            string                   `json:"agingPeriod,omitempty"`
+	FairShare              map[string]int64         `json:"fairShare,omitempty"`
+	WaitingTime            string                   `json:"waitingTime,omitempty"`
+	PreemptionBudget       *PreemptionBudgetDAOInfo `json:"preemptionBudget,omitempty"`
+	ActiveSchedule         string                   `json:"activeSchedule,omitempty"`
+	BorrowLedger           *BorrowLedgerDAOInfo     `json:"borrowLedger,omitempty"`
+}
+
+// QueueStateDAOInfo is the request to change the state of a queue: state is one of stop, drain or start.
+type QueueStateDAOInfo struct {
+	State  string `json:"state"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type BorrowLedgerDAOInfo struct {
+	Borrowed        map[string]map[string]int64 `json:"borrowed,omitempty"` // guarantee borrowed by the queue per lending sibling
+	Lent            map[string]map[string]int64 `json:"lent,omitempty"`     // guarantee lent by the queue per borrowing sibling
+	Reclaim         map[string]int64            `json:"reclaim,omitempty"`  // borrowed guarantee the lenders need back
+	ReclaimDeadline int64                       `json:"reclaimDeadline,omitempty"`
+}
+
+type PreemptionBudgetDAOInfo struct {
+	MaxAllocations       *int             `json:"maxAllocations,omitempty"`
+	MaxPercentage        float64          `json:"maxPercentage,omitempty"`
+	Window               string           `json:"window"`               // no omitempty, window is always set
+	PreemptedAllocations int              `json:"preemptedAllocations"` // no omitempty, 0 shows the budget is untouched
+	PreemptedResource    map[string]int64 `json:"preemptedResource,omitempty"`
+	Exhausted            bool             `json:"exhausted"` // no omitempty, false shows the budget status better
 }
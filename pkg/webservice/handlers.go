@@ -20,6 +20,7 @@ package webservice
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -40,6 +41,7 @@ import (
 	"github.com/apache/yunikorn-core/pkg/common"
 	"github.com/apache/yunikorn-core/pkg/common/configs"
 	"github.com/apache/yunikorn-core/pkg/common/resources"
+	"github.com/apache/yunikorn-core/pkg/common/security"
 	"github.com/apache/yunikorn-core/pkg/events"
 	"github.com/apache/yunikorn-core/pkg/locking"
 	"github.com/apache/yunikorn-core/pkg/log"
@@ -53,15 +55,19 @@ import (
 )
 
 const (
-	PartitionDoesNotExists   = "Partition not found"
-	MissingParamsName        = "Missing parameters"
-	QueueDoesNotExists       = "Queue not found"
-	InvalidUserName          = "Invalid user name"
-	InvalidGroupName         = "Invalid group name"
-	UserDoesNotExists        = "User not found"
-	GroupDoesNotExists       = "Group not found"
-	ApplicationDoesNotExists = "Application not found"
-	NodeDoesNotExists        = "Node not found"
+	PartitionDoesNotExists          = "Partition not found"
+	MissingParamsName               = "Missing parameters"
+	QueueDoesNotExists              = "Queue not found"
+	InvalidUserName                 = "Invalid user name"
+	InvalidGroupName                = "Invalid group name"
+	UserDoesNotExists               = "User not found"
+	GroupDoesNotExists              = "Group not found"
+	ApplicationDoesNotExists        = "Application not found"
+	NodeDoesNotExists               = "Node not found"
+	AdvanceReservationDoesNotExists = "Advance reservation not found"
+	MissingCallerIdentity           = "Caller identity missing, the user must be set in the " + UserHeader + " header"
+	AdminAccessDenied               = "User does not have admin access to the queue"
+	InvalidQueueState               = "Invalid queue state, must be one of: stop, drain, start"
 
 	AppStateActive    = "active"
 	AppStateRejected  = "rejected"
@@ -69,6 +75,10 @@ const (
 
 	WSBase    = "/ws/v1"
 	DebugBase = "/debug"
+
+	// caller identity set by the authenticating proxy in front of the REST API
+	UserHeader  = "X-Remote-User"
+	GroupHeader = "X-Remote-Group"
 )
 
 var allowedActiveStatusMsg string
@@ -85,6 +95,7 @@ func init() {
 	allowedAppActiveStatuses[strings.ToLower(objects.Completing.String())] = true
 	allowedAppActiveStatuses[strings.ToLower(objects.Failing.String())] = true
 	allowedAppActiveStatuses[strings.ToLower(objects.Resuming.String())] = true
+	allowedAppActiveStatuses[strings.ToLower(objects.Suspended.String())] = true
 
 	var activeStatuses []string
 	for k := range allowedAppActiveStatuses {
@@ -188,8 +199,11 @@ func writeHeaders(w http.ResponseWriter, method string) {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	methods := "GET, OPTIONS"
-	if method == http.MethodPost {
+	switch method {
+	case http.MethodPost:
 		methods = "OPTIONS, POST"
+	case http.MethodDelete:
+		methods = "DELETE, OPTIONS"
 	}
 	w.Header().Set("Access-Control-Allow-Methods", methods)
 	w.Header().Set("Access-Control-Allow-Headers", "X-Requested-With,Content-Type,Accept,Origin")
@@ -312,6 +326,7 @@ func getPlaceholderDAO(ph *objects.PlaceholderData) *dao.PlaceholderDAOInfo {
 		MinResource:   ph.MinResource.DAOMap(),
 		Replaced:      ph.Replaced,
 		TimedOut:      ph.TimedOut,
+		Stopped:       ph.Stopped,
 	}
 	return phDAO
 }
@@ -352,6 +367,8 @@ func getApplicationDAO(app *objects.Application) *dao.ApplicationDAOInfo {
 		PlaceholderResource: app.GetTrackedDAOMap("placeholderResource"),
 	}
 
+	boost, boostTime := app.GetPriorityBoost()
+
 	return &dao.ApplicationDAOInfo{
 		ApplicationID:      app.ApplicationID,
 		UsedResource:       app.GetAllocatedResource().DAOMap(),
@@ -371,9 +388,14 @@ func getApplicationDAO(app *objects.Application) *dao.ApplicationDAOInfo {
 		StateLog:           getStatesDAO(app.GetStateLog()),
 		HasReserved:        app.HasReserved(),
 		Reservations:       app.GetReservations(),
+		ReservationAges:    getReservationAges(app.GetReservationTimes()),
 		MaxRequestPriority: app.GetAskMaxPriority(),
+		PriorityBoost:      boost,
+		PriorityBoostTime:  common.ZeroTimeInUnixNano(boostTime),
 		StartTime:          app.StartTime().UnixMilli(),
 		ResourceHistory:    resHistory,
+		Dependencies:       app.GetDependencies(),
+		WaitingOnDeps:      app.IsWaitingOnDependencies(),
 	}
 }
 
@@ -437,9 +459,23 @@ func getNodeDAO(node *objects.Node) *dao.NodeDAOInfo {
 		Schedulable:        node.IsSchedulable(),
 		IsReserved:         node.IsReserved(),
 		Reservations:       node.GetReservationKeys(),
+		ReservationAges:    getReservationAges(node.GetReservationTimes()),
 	}
 }
 
+// getReservationAges converts the reservation times into the age of each reservation in milliseconds.
+func getReservationAges(times map[string]time.Time) map[string]int64 {
+	if len(times) == 0 {
+		return nil
+	}
+	now := time.Now()
+	ages := make(map[string]int64, len(times))
+	for key, created := range times {
+		ages[key] = now.Sub(created).Milliseconds()
+	}
+	return ages
+}
+
 func getNodesDAO(entries []*objects.Node) []*dao.NodeDAOInfo {
 	nodesDAO := make([]*dao.NodeDAOInfo, 0, len(entries))
 	for _, entry := range entries {
@@ -749,6 +785,110 @@ func getPartitionQueue(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// setPartitionQueueState changes the state of the queue and all queues below it. The caller must have admin access
+// to the queue.
+func setPartitionQueueState(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	user, ok := getCallerIdentity(w, r)
+	if !ok {
+		return
+	}
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	unescapedQueueName, err := url.QueryUnescape(vars.ByName("queue"))
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = validateQueue(unescapedQueueName); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	queue := partitionContext.GetQueue(unescapedQueueName)
+	if queue == nil {
+		buildJSONErrorResponse(w, QueueDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if !checkAdminAccess(w, user, queue) {
+		return
+	}
+	var request dao.QueueStateDAOInfo
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var event objects.ObjectEvent
+	switch strings.ToLower(request.State) {
+	case "stop":
+		event = objects.Stop
+	case "drain":
+		event = objects.Remove
+	case "start":
+		event = objects.Start
+	default:
+		buildJSONErrorResponse(w, InvalidQueueState, http.StatusBadRequest)
+		return
+	}
+	log.Log(log.REST).Info("queue state change requested",
+		zap.String("partition", partitionContext.Name),
+		zap.String("queue", queue.QueuePath),
+		zap.String("state", request.State),
+		zap.String("user", user.User),
+		zap.Strings("groups", user.Groups),
+		zap.String("reason", request.Reason))
+	if err = queue.SetAdminState(event, request.Reason); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(queue.GetPartitionQueueDAOInfo(false)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getCallerIdentity returns the user and groups of the caller. The REST API does not authenticate the caller: the
+// identity is set by the authenticating proxy in front of the REST API. Groups can be listed in one or more headers,
+// separated by commas. An error response is written if the user is not set.
+func getCallerIdentity(w http.ResponseWriter, r *http.Request) (security.UserGroup, bool) {
+	user := strings.TrimSpace(r.Header.Get(UserHeader))
+	if user == "" {
+		buildJSONErrorResponse(w, MissingCallerIdentity, http.StatusUnauthorized)
+		return security.UserGroup{}, false
+	}
+	var groups []string
+	for _, value := range r.Header.Values(GroupHeader) {
+		for _, group := range strings.Split(value, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return security.UserGroup{User: user, Groups: groups}, true
+}
+
+// checkAdminAccess returns true if the user has admin access to all the queues. An error response is written if
+// access is denied.
+func checkAdminAccess(w http.ResponseWriter, user security.UserGroup, queues ...*objects.Queue) bool {
+	for _, queue := range queues {
+		if !queue.CheckAdminAccess(user) {
+			log.Log(log.REST).Info("queue admin access denied",
+				zap.String("queue", queue.QueuePath),
+				zap.String("user", user.User),
+				zap.Strings("groups", user.Groups))
+			buildJSONErrorResponse(w, AdminAccessDenied+": "+queue.QueuePath, http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
 func getPartitionNodes(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -928,6 +1068,425 @@ func getApplication(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// moveApplication moves an application with all its allocations to another leaf queue. On a dry run the move is only
+// checked and the application is returned unchanged. The caller must have admin access to both queues.
+func moveApplication(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	user, ok := getCallerIdentity(w, r)
+	if !ok {
+		return
+	}
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	app := partitionContext.GetApplication(vars.ByName("application"))
+	if app == nil {
+		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
+		return
+	}
+	var request dao.ApplicationMoveDAOInfo
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateQueue(request.Queue); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	target := partitionContext.GetQueue(request.Queue)
+	if target == nil {
+		buildJSONErrorResponse(w, QueueDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if !checkAdminAccess(w, user, app.GetQueue(), target) {
+		return
+	}
+	log.Log(log.REST).Info("application move requested",
+		zap.String("partition", partitionContext.Name),
+		zap.String("applicationID", app.ApplicationID),
+		zap.String("fromQueue", app.GetQueuePath()),
+		zap.String("toQueue", target.QueuePath),
+		zap.Bool("dryRun", request.DryRun),
+		zap.String("user", user.User),
+		zap.Strings("groups", user.Groups))
+	if err := partitionContext.MoveApplication(app.ApplicationID, target.QueuePath, request.DryRun); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getApplicationDAO(app)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// suspendApplication stops the scheduling of an application, optionally releasing its allocations.
+func suspendApplication(w http.ResponseWriter, r *http.Request) {
+	partitionContext, app, user, ok := getApplicationForUpdate(w, r)
+	if !ok {
+		return
+	}
+	var request dao.ApplicationSuspendDAOInfo
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Log(log.REST).Info("application suspend requested",
+		zap.String("partition", partitionContext.Name),
+		zap.String("applicationID", app.ApplicationID),
+		zap.Bool("release", request.Release),
+		zap.String("user", user.User),
+		zap.Strings("groups", user.Groups),
+		zap.String("reason", request.Reason))
+	if err := partitionContext.SuspendApplication(app.ApplicationID, request.Release, request.Reason); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getApplicationDAO(app)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// resumeApplication puts a suspended application back in its queue for scheduling.
+func resumeApplication(w http.ResponseWriter, r *http.Request) {
+	partitionContext, app, user, ok := getApplicationForUpdate(w, r)
+	if !ok {
+		return
+	}
+	log.Log(log.REST).Info("application resume requested",
+		zap.String("partition", partitionContext.Name),
+		zap.String("applicationID", app.ApplicationID),
+		zap.String("user", user.User),
+		zap.Strings("groups", user.Groups))
+	if err := partitionContext.ResumeApplication(app.ApplicationID); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getApplicationDAO(app)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getApplicationForUpdate returns the partition, the application and the caller from the request if the caller has
+// admin access to the queue of the application. An error response is written if the checks fail.
+func getApplicationForUpdate(w http.ResponseWriter, r *http.Request) (*scheduler.PartitionContext, *objects.Application, security.UserGroup, bool) {
+	writeHeaders(w, r.Method)
+	user, ok := getCallerIdentity(w, r)
+	if !ok {
+		return nil, nil, user, false
+	}
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return nil, nil, user, false
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return nil, nil, user, false
+	}
+	app := partitionContext.GetApplication(vars.ByName("application"))
+	if app == nil {
+		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
+		return nil, nil, user, false
+	}
+	if !checkAdminAccess(w, user, app.GetQueue()) {
+		return nil, nil, user, false
+	}
+	return partitionContext, app, user, true
+}
+
+// getPreemptionDryRun runs the preemption checks for a pending ask without changing any state and
+// returns the nodes, victims and queue checks that were used.
+func getPreemptionDryRun(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	app := partitionContext.GetApplication(vars.ByName("application"))
+	if app == nil {
+		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
+		return
+	}
+	dryRun, err := app.PreemptionDryRun(vars.ByName("ask"), partitionContext.GetFullNodeIterator())
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = json.NewEncoder(w).Encode(getPreemptionDryRunDAO(dryRun)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func getPreemptionDryRunDAO(dryRun *objects.PreemptionDryRun) *dao.PreemptionDryRunDAOInfo {
+	dryRunDAO := &dao.PreemptionDryRunDAOInfo{
+		ApplicationID:       dryRun.ApplicationID,
+		AllocationKey:       dryRun.AllocationKey,
+		QueueName:           dryRun.QueuePath,
+		CanPreempt:          dryRun.Reason == "",
+		Reason:              dryRun.Reason,
+		FailedPreconditions: dryRun.FailedPreconditions,
+		GuaranteesMet:       dryRun.GuaranteesMet,
+		NodeID:              dryRun.NodeID,
+		Victims:             getPreemptionVictimsDAO(dryRun.Victims),
+		Moved:               getPreemptionVictimsDAO(dryRun.Moved),
+	}
+	for _, queue := range dryRun.Queues {
+		dryRunDAO.Queues = append(dryRunDAO.Queues, &dao.PreemptionQueueDAOInfo{
+			QueueName:          queue.QueuePath,
+			AllocatedResource:  queue.AllocatedResource.DAOMap(),
+			PreemptingResource: queue.PreemptingResource.DAOMap(),
+			GuaranteedResource: queue.GuaranteedResource.DAOMap(),
+			MaxResource:        queue.MaxResource.DAOMap(),
+			RemainingGuarantee: queue.RemainingGuarantee.DAOMap(),
+			Preemptable:        queue.Preemptable.DAOMap(),
+			PotentialVictims:   queue.PotentialVictims,
+		})
+	}
+	for _, node := range dryRun.Nodes {
+		dryRunDAO.CandidateNodes = append(dryRunDAO.CandidateNodes, &dao.PreemptionNodeDAOInfo{
+			NodeID:            node.NodeID,
+			AvailableResource: node.AvailableResource.DAOMap(),
+			Eligible:          node.Victims != nil,
+			Victims:           getPreemptionVictimsDAO(node.Victims),
+		})
+	}
+	return dryRunDAO
+}
+
+func getPreemptionVictimsDAO(victims []*objects.Allocation) []*dao.PreemptionVictimDAOInfo {
+	if len(victims) == 0 {
+		return nil
+	}
+	victimsDAO := make([]*dao.PreemptionVictimDAOInfo, 0, len(victims))
+	for _, victim := range victims {
+		victimsDAO = append(victimsDAO, &dao.PreemptionVictimDAOInfo{
+			AllocationKey:    victim.GetAllocationKey(),
+			ApplicationID:    victim.GetApplicationID(),
+			NodeID:           victim.GetNodeID(),
+			ResourcePerAlloc: victim.GetAllocatedResource().DAOMap(),
+			Priority:         strconv.Itoa(int(victim.GetPriority())),
+		})
+	}
+	return victimsDAO
+}
+
+func getPartitionPreemptions(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	var start, end time.Time
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{{"start", &start}, {"end", &end}} {
+		if str := r.URL.Query().Get(param.name); str != "" {
+			nanos, err := strconv.ParseInt(str, 10, 64)
+			if err != nil {
+				buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			*param.value = time.Unix(0, nanos)
+		}
+	}
+	records := partitionContext.GetPreemptionHistory().GetRecords(r.URL.Query().Get("queue"), start, end)
+	recordsDAO := make([]*dao.PreemptionRecordDAOInfo, 0, len(records))
+	for _, record := range records {
+		recordsDAO = append(recordsDAO, getPreemptionRecordDAO(record))
+	}
+	if err := json.NewEncoder(w).Encode(recordsDAO); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func getPreemptionRecordDAO(record *objects.PreemptionRecord) *dao.PreemptionRecordDAOInfo {
+	recordDAO := &dao.PreemptionRecordDAOInfo{
+		Timestamp:         record.Timestamp.UnixNano(),
+		ApplicationID:     record.ApplicationID,
+		AllocationKey:     record.AllocationKey,
+		QueueName:         record.QueuePath,
+		NodeID:            record.NodeID,
+		RequiredNode:      record.RequiredNode,
+		ReclaimedResource: record.Reclaimed.DAOMap(),
+	}
+	for _, victim := range record.Victims {
+		recordDAO.Victims = append(recordDAO.Victims, &dao.PreemptionVictimDAOInfo{
+			AllocationKey:    victim.AllocationKey,
+			ApplicationID:    victim.ApplicationID,
+			QueueName:        victim.QueuePath,
+			NodeID:           victim.NodeID,
+			ResourcePerAlloc: victim.Resource.DAOMap(),
+			Priority:         strconv.Itoa(int(victim.Priority)),
+		})
+	}
+	return recordDAO
+}
+
+func getAdvanceReservations(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	reservations := partitionContext.GetAdvanceReservations().GetReservations()
+	reservationsDAO := make([]*dao.AdvanceReservationDAOInfo, 0, len(reservations))
+	for _, reservation := range reservations {
+		reservationsDAO = append(reservationsDAO, getAdvanceReservationDAO(reservation))
+	}
+	if err := json.NewEncoder(w).Encode(reservationsDAO); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// addAdvanceReservation adds a reservation for a queue. The caller must have admin access to the queue.
+func addAdvanceReservation(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	user, ok := getCallerIdentity(w, r)
+	if !ok {
+		return
+	}
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	var conf configs.AdvanceReservationConfig
+	if err := json.NewDecoder(r.Body).Decode(&conf); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !checkAdminAccess(w, user, getReservationQueue(partitionContext, conf.Queue)) {
+		return
+	}
+	// nodes in the configuration may register later, nodes added at runtime must exist
+	for _, nodeID := range conf.Nodes {
+		if partitionContext.GetNode(nodeID) == nil {
+			buildJSONErrorResponse(w, NodeDoesNotExists+": "+nodeID, http.StatusBadRequest)
+			return
+		}
+	}
+	log.Log(log.REST).Info("advance reservation add requested",
+		zap.String("partition", partitionContext.Name),
+		zap.String("name", conf.Name),
+		zap.String("queue", conf.Queue),
+		zap.String("user", user.User),
+		zap.Strings("groups", user.Groups))
+	reservation, err := objects.NewAdvanceReservation(conf, false)
+	if err == nil {
+		err = partitionContext.GetAdvanceReservations().Add(reservation)
+	}
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(getAdvanceReservationDAO(reservation)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// deleteAdvanceReservation removes a reservation. The caller must have admin access to the queue of the reservation.
+func deleteAdvanceReservation(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w, r.Method)
+	user, ok := getCallerIdentity(w, r)
+	if !ok {
+		return
+	}
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	name := vars.ByName("name")
+	var reservation *objects.AdvanceReservation
+	for _, existing := range partitionContext.GetAdvanceReservations().GetReservations() {
+		if existing.Name == name {
+			reservation = existing
+			break
+		}
+	}
+	if reservation == nil {
+		buildJSONErrorResponse(w, AdvanceReservationDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if !checkAdminAccess(w, user, getReservationQueue(partitionContext, reservation.QueuePath)) {
+		return
+	}
+	log.Log(log.REST).Info("advance reservation delete requested",
+		zap.String("partition", partitionContext.Name),
+		zap.String("name", name),
+		zap.String("queue", reservation.QueuePath),
+		zap.String("user", user.User),
+		zap.Strings("groups", user.Groups))
+	if !partitionContext.GetAdvanceReservations().Remove(name) {
+		buildJSONErrorResponse(w, AdvanceReservationDoesNotExists, http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getReservationQueue returns the queue of a reservation. The queue might not exist yet, the access is then checked
+// on the root queue.
+func getReservationQueue(partitionContext *scheduler.PartitionContext, queuePath string) *objects.Queue {
+	if queue := partitionContext.GetQueue(queuePath); queue != nil {
+		return queue
+	}
+	return partitionContext.GetQueue(configs.RootQueue)
+}
+
+func getAdvanceReservationDAO(reservation *objects.AdvanceReservation) *dao.AdvanceReservationDAOInfo {
+	reservationDAO := &dao.AdvanceReservationDAOInfo{
+		Name:      reservation.Name,
+		QueueName: reservation.QueuePath,
+		Nodes:     reservation.Nodes,
+		Resource:  reservation.Resource.DAOMap(),
+		StartTime: reservation.Start.UnixNano(),
+		EndTime:   reservation.End.UnixNano(),
+		Lead:      reservation.Lead.String(),
+		Source:    "rest",
+	}
+	if reservation.FromConfig {
+		reservationDAO.Source = "config"
+	}
+	return reservationDAO
+}
+
 func getPartitionRules(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w, r.Method)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -1022,6 +1581,9 @@ func getPartitionInfoDAO(lists map[string]*scheduler.PartitionContext) []*dao.Pa
 		partitionInfo.NodeSortingPolicy = dao.NodeSortingPolicy{
 			Type:            partitionContext.GetNodeSortingPolicyType().String(),
 			ResourceWeights: partitionContext.GetNodeSortingResourceWeights(),
+			Aging:           partitionContext.GetNodeSortingAgingDAOInfo(),
+			Scorers:         partitionContext.GetNodeSortingScorers(),
+			Topology:        partitionContext.GetNodeTopologyDAOInfo(),
 		}
 
 		partitionInfo.TotalNodes = partitionContext.GetTotalNodeCount()
@@ -1967,6 +1967,515 @@ func TestGetApplicationHandler(t *testing.T) {
 	assert.Assert(t, appSummary.PlaceholderResource.EqualsDAO(appDao.ResourceHistory.PlaceholderResource))
 }
 
+func TestGetPartitionPreemptions(t *testing.T) {
+	part := setup(t, configDefault, 1)
+	NewWebApp(schedulerContext.Load(), nil)
+
+	now := time.Now()
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 2})
+	history := part.GetPreemptionHistory()
+	history.Add(&objects.PreemptionRecord{
+		Timestamp:     now.Add(-time.Hour),
+		ApplicationID: "app-1",
+		AllocationKey: "ask-1",
+		QueuePath:     "root.default",
+		NodeID:        "node-1",
+		Victims: []*objects.PreemptionVictimRecord{
+			{ApplicationID: "app-2", AllocationKey: "alloc-2", QueuePath: "root.other", NodeID: "node-1", Priority: 1, Resource: res},
+		},
+		Reclaimed: res,
+	})
+	history.Add(&objects.PreemptionRecord{
+		Timestamp:     now,
+		ApplicationID: "app-3",
+		AllocationKey: "ask-3",
+		QueuePath:     "root.third",
+		NodeID:        "node-2",
+		RequiredNode:  true,
+		Reclaimed:     resources.NewResource(),
+	})
+
+	getRecords := func(query string) []*dao.PreemptionRecordDAOInfo {
+		req, err := createRequest(t, "/ws/v1/partition/default/preemptions?"+query, map[string]string{"partition": partitionNameWithoutClusterID})
+		assert.NilError(t, err)
+		resp := &MockResponseWriter{}
+		getPartitionPreemptions(resp, req)
+		var records []*dao.PreemptionRecordDAOInfo
+		err = json.Unmarshal(resp.outputBytes, &records)
+		assert.NilError(t, err, unmarshalError)
+		return records
+	}
+
+	records := getRecords("")
+	assert.Equal(t, len(records), 2)
+	assert.Equal(t, records[0].Timestamp, now.Add(-time.Hour).UnixNano())
+	assert.Equal(t, records[0].ApplicationID, "app-1")
+	assert.Equal(t, records[0].AllocationKey, "ask-1")
+	assert.Equal(t, records[0].QueueName, "root.default")
+	assert.Equal(t, records[0].NodeID, "node-1")
+	assert.DeepEqual(t, records[0].ReclaimedResource, map[string]int64{"vcore": 2})
+	assert.Equal(t, len(records[0].Victims), 1)
+	assert.Equal(t, records[0].Victims[0].AllocationKey, "alloc-2")
+	assert.Equal(t, records[0].Victims[0].QueueName, "root.other")
+	assert.Equal(t, records[0].Victims[0].Priority, "1")
+	assert.Assert(t, records[1].RequiredNode, "required node flag not set")
+
+	// filter on the victim queue and on time
+	records = getRecords("queue=root.other")
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, records[0].AllocationKey, "ask-1")
+	records = getRecords(fmt.Sprintf("start=%d", now.Add(-time.Minute).UnixNano()))
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, records[0].AllocationKey, "ask-3")
+	records = getRecords(fmt.Sprintf("end=%d", now.Add(-time.Minute).UnixNano()))
+	assert.Equal(t, len(records), 1)
+	assert.Equal(t, records[0].AllocationKey, "ask-1")
+	assert.Equal(t, len(getRecords("queue=root.unknown")), 0)
+
+	// invalid time
+	req, err := createRequest(t, "/ws/v1/partition/default/preemptions?start=yesterday", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err)
+	resp := &MockResponseWriter{}
+	getPartitionPreemptions(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+
+	// test nonexistent partition
+	req, err = createRequest(t, "/ws/v1/partition/notexists/preemptions", map[string]string{"partition": "notexists"})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getPartitionPreemptions(resp, req)
+	assertPartitionNotExists(t, resp)
+
+	// test missing params name
+	req, err = createRequest(t, "/ws/v1/partition/default/preemptions", map[string]string{})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getPartitionPreemptions(resp, req)
+	assertParamsMissing(t, resp)
+}
+
+const configReservationAdmin = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        adminacl: "admin"
+        queues:
+          - name: default
+`
+
+func TestAdvanceReservationsHandlers(t *testing.T) {
+	part := setup(t, configReservationAdmin, 1)
+	NewWebApp(schedulerContext.Load(), nil)
+	node := objects.NewNode(&si.NodeInfo{NodeID: "node-1", SchedulableResource: &si.Resource{
+		Resources: map[string]*si.Quantity{"vcore": {Value: 10}}}})
+	assert.NilError(t, part.AddNode(node), "node add failed")
+
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	postReservationAs := func(user, body string) *MockResponseWriter {
+		req, err := http.NewRequest("POST", "/ws/v1/partition/default/advancereservations", strings.NewReader(body))
+		assert.NilError(t, err, "Handler request create failed")
+		setCaller(req, user)
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{{Key: "partition", Value: partitionNameWithoutClusterID}}))
+		resp := &MockResponseWriter{}
+		addAdvanceReservation(resp, req)
+		return resp
+	}
+	postReservation := func(body string) *MockResponseWriter {
+		return postReservationAs("admin", body)
+	}
+	getReservations := func() []*dao.AdvanceReservationDAOInfo {
+		req, err := createRequest(t, "/ws/v1/partition/default/advancereservations", map[string]string{"partition": partitionNameWithoutClusterID})
+		assert.NilError(t, err)
+		resp := &MockResponseWriter{}
+		getAdvanceReservations(resp, req)
+		var reservations []*dao.AdvanceReservationDAOInfo
+		err = json.Unmarshal(resp.outputBytes, &reservations)
+		assert.NilError(t, err, unmarshalError)
+		return reservations
+	}
+	assert.Equal(t, len(getReservations()), 0)
+
+	body := fmt.Sprintf(`{"name": "training", "queue": "root.default", "nodes": ["node-1"], "resources": {"vcore": "2"}, "start": %q, "end": %q, "lead": "30m"}`,
+		start.Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339))
+	// the caller must be known and have admin access
+	resp := postReservationAs("", body)
+	assert.Equal(t, resp.statusCode, http.StatusUnauthorized, statusCodeError)
+	resp = postReservationAs("bob", body)
+	assert.Equal(t, resp.statusCode, http.StatusForbidden, statusCodeError)
+	assert.Assert(t, strings.Contains(string(resp.outputBytes), AdminAccessDenied+": root.default"), "unexpected error message")
+	assert.Equal(t, len(getReservations()), 0)
+
+	resp = postReservation(body)
+	assert.Equal(t, resp.statusCode, http.StatusCreated, statusCodeError)
+	reservations := getReservations()
+	assert.Equal(t, len(reservations), 1)
+	assert.Equal(t, reservations[0].Name, "training")
+	assert.Equal(t, reservations[0].QueueName, "root.default")
+	assert.DeepEqual(t, reservations[0].Nodes, []string{"node-1"})
+	assert.DeepEqual(t, reservations[0].Resource, map[string]int64{"vcore": 2000})
+	assert.Equal(t, reservations[0].StartTime, start.UnixNano())
+	assert.Equal(t, reservations[0].EndTime, start.Add(time.Hour).UnixNano())
+	assert.Equal(t, reservations[0].Lead, "30m0s")
+	assert.Equal(t, reservations[0].Source, "rest")
+
+	// duplicate, invalid and malformed reservations
+	resp = postReservation(body)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	resp = postReservation(`{"name": "invalid", "queue": "default", "resources": {"vcore": "2"}}`)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	resp = postReservation(`{"name":`)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	resp = postReservation(strings.Replace(body, `"node-1"`, `"node-2"`, 1))
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	assert.Assert(t, strings.Contains(string(resp.outputBytes), NodeDoesNotExists+": node-2"), "unexpected error message")
+
+	// delete the reservation
+	req, err := createRequest(t, "/ws/v1/partition/default/advancereservations/training", map[string]string{"partition": partitionNameWithoutClusterID, "name": "training"})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	deleteAdvanceReservation(resp, req)
+	assert.Equal(t, resp.statusCode, http.StatusUnauthorized, statusCodeError)
+	setCaller(req, "bob")
+	resp = &MockResponseWriter{}
+	deleteAdvanceReservation(resp, req)
+	assert.Equal(t, resp.statusCode, http.StatusForbidden, statusCodeError)
+	assert.Equal(t, len(getReservations()), 1)
+	setCaller(req, "admin")
+	resp = &MockResponseWriter{}
+	deleteAdvanceReservation(resp, req)
+	assert.Equal(t, resp.statusCode, http.StatusNoContent, statusCodeError)
+	assert.Equal(t, len(getReservations()), 0)
+	resp = &MockResponseWriter{}
+	deleteAdvanceReservation(resp, req)
+	assert.Equal(t, resp.statusCode, http.StatusNotFound, statusCodeError)
+
+	// test nonexistent partition
+	req, err = createRequest(t, "/ws/v1/partition/notexists/advancereservations", map[string]string{"partition": "notexists"})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getAdvanceReservations(resp, req)
+	assertPartitionNotExists(t, resp)
+
+	// test missing params name
+	req, err = createRequest(t, "/ws/v1/partition/default/advancereservations", map[string]string{})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getAdvanceReservations(resp, req)
+	assertParamsMissing(t, resp)
+}
+
+const configQueueAdmin = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        adminacl: " admins"
+        queues:
+          - name: tenant
+            adminacl: "alice"
+            queues:
+              - name: batch
+`
+
+// setCaller sets the identity of the caller on the request, an empty user removes the identity.
+func setCaller(req *http.Request, user string, groups ...string) {
+	req.Header.Del(UserHeader)
+	req.Header.Del(GroupHeader)
+	if user != "" {
+		req.Header.Set(UserHeader, user)
+	}
+	if len(groups) > 0 {
+		req.Header.Set(GroupHeader, strings.Join(groups, ","))
+	}
+}
+
+func TestSetPartitionQueueState(t *testing.T) {
+	setup(t, configQueueAdmin, 1)
+	NewWebApp(schedulerContext.Load(), nil)
+
+	setStateAs := func(user, queue, body string, groups ...string) *MockResponseWriter {
+		req, err := http.NewRequest("POST", "/ws/v1/partition/default/queue/"+queue+"/state", strings.NewReader(body))
+		assert.NilError(t, err, "Handler request create failed")
+		setCaller(req, user, groups...)
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{
+			{Key: "partition", Value: partitionNameWithoutClusterID},
+			{Key: "queue", Value: queue},
+		}))
+		resp := &MockResponseWriter{}
+		setPartitionQueueState(resp, req)
+		return resp
+	}
+	setState := func(queue, body string) *MockResponseWriter {
+		return setStateAs("alice", queue, body)
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(partitionNameWithoutClusterID)
+	tenant := partitionContext.GetQueue("root.tenant")
+	batch := partitionContext.GetQueue("root.tenant.batch")
+
+	// the caller must be known and have admin access
+	resp := setStateAs("", "root.tenant", `{"state": "drain"}`)
+	assert.Equal(t, resp.statusCode, http.StatusUnauthorized, statusCodeError)
+	assert.Assert(t, strings.Contains(string(resp.outputBytes), MissingCallerIdentity), "unexpected error message")
+	resp = setStateAs("bob", "root.tenant", `{"state": "drain"}`, "users")
+	assert.Equal(t, resp.statusCode, http.StatusForbidden, statusCodeError)
+	assert.Assert(t, strings.Contains(string(resp.outputBytes), AdminAccessDenied+": root.tenant"), "unexpected error message")
+	resp = setStateAs("alice", "root", `{"state": "drain"}`)
+	assert.Equal(t, resp.statusCode, http.StatusForbidden, statusCodeError)
+	assert.Assert(t, tenant.IsRunning(), "queue state should not change")
+	// admin access is inherited from the parent queue
+	setStateAs("bob", "root.tenant.batch", `{"state": "drain"}`, "users", "admins")
+	assert.Assert(t, batch.IsDraining(), "queue should be draining")
+	setState("root.tenant.batch", `{"state": "start"}`)
+
+	resp = setState("root.tenant", `{"state": "drain", "reason": "node maintenance"}`)
+	var queueDao dao.PartitionQueueDAOInfo
+	err := json.Unmarshal(resp.outputBytes, &queueDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, queueDao.QueueName, "root.tenant")
+	assert.Equal(t, queueDao.Status, objects.Draining.String())
+	assert.Equal(t, queueDao.StateReason, "node maintenance")
+	assert.Assert(t, batch.IsDraining(), "child queue should be draining")
+
+	setState("root.tenant.batch", `{"state": "STOP"}`)
+	assert.Assert(t, batch.IsStopped(), "queue should be stopped")
+	assert.Assert(t, tenant.IsDraining(), "parent queue should not change")
+
+	setState("root.tenant", `{"state": "start"}`)
+	assert.Assert(t, tenant.IsRunning() && batch.IsRunning(), "queues should be running")
+
+	// invalid requests
+	resp = setState("root.tenant", `{"state": "pause"}`)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	assert.Assert(t, strings.Contains(string(resp.outputBytes), InvalidQueueState), "unexpected error message")
+	resp = setState("root.tenant", `{"state":`)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	resp = setState("root.unknown", `{"state": "stop"}`)
+	assert.Equal(t, resp.statusCode, http.StatusNotFound, statusCodeError)
+}
+
+const configAppMove = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: tenant
+            adminacl: "alice"
+            queues:
+              - name: batch
+              - name: adhoc
+          - name: shared
+`
+
+func TestMoveApplication(t *testing.T) {
+	part := setup(t, configAppMove, 1)
+	NewWebApp(schedulerContext.Load(), nil)
+	app := addApp(t, "app-1", part, "root.tenant.batch", false)
+
+	moveAs := func(user, appID, body string) *MockResponseWriter {
+		req, err := http.NewRequest("POST", "/ws/v1/partition/default/application/"+appID+"/move", strings.NewReader(body))
+		assert.NilError(t, err, "Handler request create failed")
+		setCaller(req, user)
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{
+			{Key: "partition", Value: partitionNameWithoutClusterID},
+			{Key: "application", Value: appID},
+		}))
+		resp := &MockResponseWriter{}
+		moveApplication(resp, req)
+		return resp
+	}
+	move := func(appID, body string) *MockResponseWriter {
+		return moveAs("alice", appID, body)
+	}
+
+	// the caller must be known and have admin access to the source and target queue
+	resp := moveAs("", "app-1", `{"queue": "root.tenant.adhoc"}`)
+	assert.Equal(t, resp.statusCode, http.StatusUnauthorized, statusCodeError)
+	resp = moveAs("bob", "app-1", `{"queue": "root.tenant.adhoc"}`)
+	assert.Equal(t, resp.statusCode, http.StatusForbidden, statusCodeError)
+	resp = move("app-1", `{"queue": "root.shared"}`)
+	assert.Equal(t, resp.statusCode, http.StatusForbidden, statusCodeError)
+	assert.Assert(t, strings.Contains(string(resp.outputBytes), AdminAccessDenied+": root.shared"), "unexpected error message")
+	assert.Equal(t, app.GetQueuePath(), "root.tenant.batch", "application should not move")
+
+	// dry run returns the application unchanged
+	resp = move("app-1", `{"queue": "root.tenant.adhoc", "dryRun": true}`)
+	var appDao dao.ApplicationDAOInfo
+	err := json.Unmarshal(resp.outputBytes, &appDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, appDao.QueueName, "root.tenant.batch")
+	assert.Equal(t, app.GetQueuePath(), "root.tenant.batch", "application should not move on dry run")
+
+	resp = move("app-1", `{"queue": "root.tenant.adhoc"}`)
+	err = json.Unmarshal(resp.outputBytes, &appDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, appDao.QueueName, "root.tenant.adhoc")
+	assert.Equal(t, app.GetQueuePath(), "root.tenant.adhoc", "application should have moved")
+
+	// invalid requests
+	resp = move("app-1", `{"queue": "root.tenant.adhoc"}`)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	resp = move("app-1", `{"queue": "root.tenant"}`)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	resp = move("app-1", `{"queue":`)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	resp = move("app-1", `{"queue": "root.tenant.unknown"}`)
+	assert.Equal(t, resp.statusCode, http.StatusNotFound, statusCodeError)
+	resp = move("app-2", `{"queue": "root.tenant.batch"}`)
+	assert.Equal(t, resp.statusCode, http.StatusNotFound, statusCodeError)
+}
+
+func TestSuspendResumeApplication(t *testing.T) {
+	part := setup(t, configAppMove, 1)
+	NewWebApp(schedulerContext.Load(), nil)
+	app := addApp(t, "app-1", part, "root.tenant.batch", false)
+	assert.NilError(t, app.HandleApplicationEvent(objects.RunApplication))
+
+	updateAs := func(user, action, appID, body string) *MockResponseWriter {
+		req, err := http.NewRequest("POST", "/ws/v1/partition/default/application/"+appID+"/"+action, strings.NewReader(body))
+		assert.NilError(t, err, "Handler request create failed")
+		setCaller(req, user)
+		req = req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, httprouter.Params{
+			{Key: "partition", Value: partitionNameWithoutClusterID},
+			{Key: "application", Value: appID},
+		}))
+		resp := &MockResponseWriter{}
+		if action == "suspend" {
+			suspendApplication(resp, req)
+		} else {
+			resumeApplication(resp, req)
+		}
+		return resp
+	}
+	update := func(action, appID, body string) *MockResponseWriter {
+		return updateAs("alice", action, appID, body)
+	}
+
+	// the caller must be known and have admin access
+	resp := updateAs("", "suspend", "app-1", "")
+	assert.Equal(t, resp.statusCode, http.StatusUnauthorized, statusCodeError)
+	resp = updateAs("bob", "suspend", "app-1", "")
+	assert.Equal(t, resp.statusCode, http.StatusForbidden, statusCodeError)
+	assert.Assert(t, app.IsAccepted(), "application state should not change")
+
+	resp = update("suspend", "app-1", `{"release": true, "reason": "maintenance"}`)
+	var appDao dao.ApplicationDAOInfo
+	err := json.Unmarshal(resp.outputBytes, &appDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, appDao.State, objects.Suspended.String())
+	resp = update("suspend", "app-1", "")
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+
+	resp = update("resume", "app-1", "")
+	err = json.Unmarshal(resp.outputBytes, &appDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, appDao.State, objects.Completing.String())
+	resp = update("resume", "app-1", "")
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+
+	// invalid requests
+	resp = update("suspend", "app-1", `{"release":`)
+	assert.Equal(t, resp.statusCode, http.StatusBadRequest, statusCodeError)
+	resp = update("resume", "app-2", "")
+	assert.Equal(t, resp.statusCode, http.StatusNotFound, statusCodeError)
+}
+
+func TestReservationAgesDAO(t *testing.T) {
+	part := setup(t, configDefault, 1)
+	app := addApp(t, "app-1", part, "root.default", false)
+	ask := objects.NewAllocationFromSI(&si.Allocation{
+		AllocationKey:    "alloc-1",
+		ApplicationID:    "app-1",
+		PartitionName:    part.Name,
+		ResourcePerAlloc: &si.Resource{Resources: map[string]*si.Quantity{"vcore": {Value: 1}}}})
+	assert.NilError(t, app.AddAllocationAsk(ask), "ask should have been added to app")
+	node := objects.NewNode(&si.NodeInfo{NodeID: "node-1", SchedulableResource: &si.Resource{
+		Resources: map[string]*si.Quantity{"vcore": {Value: 10}}}})
+	assert.NilError(t, part.AddNode(node), "node add failed")
+	assert.Assert(t, getNodeDAO(node).ReservationAges == nil, "node without reservations should not have ages")
+	assert.Assert(t, getApplicationDAO(app).ReservationAges == nil, "app without reservations should not have ages")
+
+	assert.NilError(t, app.Reserve(node, ask), "reservation failed")
+	time.Sleep(5 * time.Millisecond)
+	nodeAges := getNodeDAO(node).ReservationAges
+	assert.Equal(t, len(nodeAges), 1, "node should have one reservation age")
+	assert.Assert(t, nodeAges["alloc-1"] >= 5, "node reservation age too low: %d", nodeAges["alloc-1"])
+	appAges := getApplicationDAO(app).ReservationAges
+	assert.Equal(t, len(appAges), 1, "app should have one reservation age")
+	assert.Assert(t, appAges["alloc-1"] >= 5, "app reservation age too low: %d", appAges["alloc-1"])
+}
+
+func TestGetPreemptionDryRunHandler(t *testing.T) {
+	part := setup(t, configDefault, 1)
+
+	app := addApp(t, "app-1", part, "root.default", false)
+	res := &si.Resource{
+		Resources: map[string]*si.Quantity{"vcore": {Value: 1}},
+	}
+	ask := objects.NewAllocationFromSI(&si.Allocation{
+		AllocationKey:    "alloc-1",
+		ApplicationID:    "app-1",
+		PartitionName:    part.Name,
+		ResourcePerAlloc: res})
+	err := app.AddAllocationAsk(ask)
+	assert.NilError(t, err, "ask should have been added to app")
+
+	NewWebApp(schedulerContext.Load(), nil)
+
+	var req *http.Request
+	req, err = createRequest(t, "/ws/v1/partition/default/application/app-1/ask/alloc-1/preemption", map[string]string{"partition": partitionNameWithoutClusterID, "application": "app-1", "ask": "alloc-1"})
+	assert.NilError(t, err)
+	resp := &MockResponseWriter{}
+	var dryRunDao *dao.PreemptionDryRunDAOInfo
+	getPreemptionDryRun(resp, req)
+	err = json.Unmarshal(resp.outputBytes, &dryRunDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, dryRunDao.ApplicationID, "app-1")
+	assert.Equal(t, dryRunDao.AllocationKey, "alloc-1")
+	assert.Equal(t, dryRunDao.QueueName, "root.default")
+	assert.Assert(t, !dryRunDao.CanPreempt, "ask without preemption allowed should not preempt")
+	assert.Equal(t, dryRunDao.Reason, common.PreemptionPreconditionsFailed)
+	assert.Assert(t, len(dryRunDao.FailedPreconditions) > 0, "failed preconditions should be reported")
+	assert.Equal(t, len(dryRunDao.Victims), 0)
+	assert.Assert(t, !ask.HasTriggeredPreemption(), "dry run should not change the ask")
+
+	// test nonexistent ask
+	req, err = createRequest(t, "/ws/v1/partition/default/application/app-1/ask/unknown/preemption", map[string]string{"partition": partitionNameWithoutClusterID, "application": "app-1", "ask": "unknown"})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getPreemptionDryRun(resp, req)
+	var errInfo dao.YAPIError
+	err = json.Unmarshal(resp.outputBytes, &errInfo)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	assert.Equal(t, errInfo.Message, "ask unknown not found for application app-1", jsonMessageError)
+
+	// test nonexistent partition
+	req, err = createRequest(t, "/ws/v1/partition/notexists/application/app-1/ask/alloc-1/preemption", map[string]string{"partition": "notexists", "application": "app-1", "ask": "alloc-1"})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getPreemptionDryRun(resp, req)
+	assertPartitionNotExists(t, resp)
+
+	// test nonexistent application
+	req, err = createRequest(t, "/ws/v1/partition/default/application/app-2/ask/alloc-1/preemption", map[string]string{"partition": partitionNameWithoutClusterID, "application": "app-2", "ask": "alloc-1"})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getPreemptionDryRun(resp, req)
+	assertApplicationNotExists(t, resp)
+
+	// test missing params name
+	req, err = createRequest(t, "/ws/v1/partition/default/application/app-1/ask/alloc-1/preemption", map[string]string{})
+	assert.NilError(t, err)
+	resp = &MockResponseWriter{}
+	getPreemptionDryRun(resp, req)
+	assertParamsMissing(t, resp)
+}
+
 func assertParamsMissing(t *testing.T, resp *MockResponseWriter) {
 	var errInfo dao.YAPIError
 	err := json.Unmarshal(resp.outputBytes, &errInfo)
@@ -108,6 +108,12 @@ var webRoutes = routes{
 		"/ws/v1/partition/:partition/node/:node",
 		getPartitionNode,
 	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/queue/:queue/state",
+		setPartitionQueueState,
+	},
 	route{
 		"Scheduler",
 		"GET",
@@ -126,6 +132,54 @@ var webRoutes = routes{
 		"/ws/v1/partition/:partition/application/:application",
 		getApplication,
 	},
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/partition/:partition/application/:application/ask/:ask/preemption",
+		getPreemptionDryRun,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/application/:application/move",
+		moveApplication,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/application/:application/suspend",
+		suspendApplication,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/application/:application/resume",
+		resumeApplication,
+	},
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/partition/:partition/preemptions",
+		getPartitionPreemptions,
+	},
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/partition/:partition/advancereservations",
+		getAdvanceReservations,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/advancereservations",
+		addAdvanceReservation,
+	},
+	route{
+		"Scheduler",
+		"DELETE",
+		"/ws/v1/partition/:partition/advancereservations/:name",
+		deleteAdvanceReservation,
+	},
 	route{
 		"Scheduler",
 		"GET",